@@ -10,6 +10,7 @@ import (
 	"gitea/pcp-inariam/inariam/core/services/api"
 	"gitea/pcp-inariam/inariam/core/services/api/routes"
 	"gitea/pcp-inariam/inariam/pkgs/log"
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/db"
 )
 
 const (
@@ -28,6 +29,13 @@ var serverCmd = &cobra.Command{
 
 		api := api.New(cfg)
 
+		database, err := db.ConnectDB(&cfg.DBConfig)
+		if err != nil {
+			log.Logger.Warnf("database unavailable, running without persistence: %v", err)
+		} else {
+			api.DB = database
+		}
+
 		routes.ConfigureRoutes(api)
 
 		data, err := json.MarshalIndent(api.Echo.Routes(), "", "  ")
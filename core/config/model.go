@@ -13,6 +13,8 @@ type AWSConfig struct {
 	SecretAccessKey string `mapstructure:"secret_access_key" yaml:"secret_access_key" json:"secret_access_key"`
 	Region          string `mapstructure:"region" yaml:"region" json:"region"`
 	SessionToken    string `mapstructure:"session_token" yaml:"session_token" json:"session_token"`
+	// RequiredRolePermissionsBoundary is the ARN of the permissions boundary every role created through Inariam must carry.
+	RequiredRolePermissionsBoundary string `mapstructure:"required_role_permissions_boundary" yaml:"required_role_permissions_boundary" json:"required_role_permissions_boundary"`
 	// Add other AWS-specific fields here
 }
 
@@ -0,0 +1,537 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	accessAnalyzerReq "gitea/pcp-inariam/inariam/core/services/api/requests/aws/accessanalyzer"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	accessAnalyzerResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/accessanalyzer"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+// retrieveAccessAnalyzerSvc opens an AWS session and its Access Analyzer service.
+func (awsHandler *Handler) retrieveAccessAnalyzerSvc() (*inaAws.AccessAnalyzerSvc, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession.CreateAccessAnalyzerSvc()
+	return awsSession.AccessAnalyzerSvc, nil
+}
+
+func analyzerResponse(analyzer inaAws.Analyzer) accessAnalyzerResp.AnalyzerResponse {
+	analyzerDetails := accessAnalyzerResp.AnalyzerResponse{
+		Arn:                  analyzer.Arn,
+		Name:                 analyzer.Name,
+		Type:                 analyzer.Type,
+		Status:               analyzer.Status,
+		StatusReason:         analyzer.StatusReason,
+		CreatedAt:            analyzer.CreatedAt,
+		LastResourceAnalyzed: analyzer.LastResourceAnalyzed,
+	}
+	if !analyzer.LastResourceAnalyzedAt.IsZero() {
+		analyzerDetails.LastResourceAnalyzedAt = &analyzer.LastResourceAnalyzedAt
+	}
+
+	return analyzerDetails
+}
+
+func accessAnalyzerFindingResponse(finding inaAws.AccessAnalyzerFinding) accessAnalyzerResp.FindingResponse {
+	findingResponse := accessAnalyzerResp.FindingResponse{
+		ID:                   finding.ID,
+		Resource:             finding.Resource,
+		ResourceType:         finding.ResourceType,
+		ResourceOwnerAccount: finding.ResourceOwnerAccount,
+		Principal:            finding.Principal,
+		Condition:            finding.Condition,
+		Actions:              finding.Actions,
+		IsPublic:             finding.IsPublic,
+		Status:               finding.Status,
+		Error:                finding.Error,
+		Sources:              finding.Sources,
+		AnalyzedAt:           finding.AnalyzedAt,
+		CreatedAt:            finding.CreatedAt,
+		UpdatedAt:            finding.UpdatedAt,
+	}
+	if findingResponse.Actions == nil {
+		findingResponse.Actions = []string{}
+	}
+
+	return findingResponse
+}
+
+func archiveRuleResponse(rule inaAws.ArchiveRule) accessAnalyzerResp.ArchiveRuleResponse {
+	ruleResponse := accessAnalyzerResp.ArchiveRuleResponse{
+		Name:      rule.Name,
+		Filter:    make(map[string]accessAnalyzerResp.CriterionResponse, len(rule.Filter)),
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+	for property, criterion := range rule.Filter {
+		ruleResponse.Filter[property] = accessAnalyzerResp.CriterionResponse{
+			Eq:       criterion.Eq,
+			Neq:      criterion.Neq,
+			Contains: criterion.Contains,
+			Exists:   criterion.Exists,
+		}
+	}
+
+	return ruleResponse
+}
+
+// archiveRuleFilter converts the filter of an archive rule request.
+func archiveRuleFilter(filter map[string]accessAnalyzerReq.CriterionRequest) map[string]inaAws.AccessAnalyzerCriterion {
+	criteria := make(map[string]inaAws.AccessAnalyzerCriterion, len(filter))
+	for property, criterion := range filter {
+		criteria[property] = inaAws.AccessAnalyzerCriterion{
+			Eq:       criterion.Eq,
+			Neq:      criterion.Neq,
+			Contains: criterion.Contains,
+			Exists:   criterion.Exists,
+		}
+	}
+
+	return criteria
+}
+
+// ListAnalyzers @Summary List Access Analyzers
+// @Description Get the Access Analyzer analyzers of the region
+// @ID aws-access-analyzers-list
+// @Produce json
+// @Success 200 {array} accessAnalyzerResp.AnalyzerResponse
+// @Router /aws/access-analyzer/analyzers [get]
+func (awsHandler *Handler) ListAnalyzers(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	analyzers, err := accessAnalyzerSvc.ListAccessAnalyzers()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	analyzersList := make([]accessAnalyzerResp.AnalyzerResponse, 0, len(analyzers))
+	for _, analyzer := range analyzers {
+		analyzersList = append(analyzersList, analyzerResponse(analyzer))
+	}
+
+	return responses.Response(c, http.StatusOK, analyzersList)
+}
+
+// GetAnalyzer @Summary Get Access Analyzer
+// @Description Get an Access Analyzer analyzer
+// @ID aws-access-analyzer-get
+// @Param name path string true "Analyzer name"
+// @Produce json
+// @Success 200 {object} accessAnalyzerResp.AnalyzerResponse
+// @Router /aws/access-analyzer/analyzers/{name} [get]
+func (awsHandler *Handler) GetAnalyzer(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	analyzer, err := accessAnalyzerSvc.GetAnalyzer(c.Param("name"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, analyzerResponse(*analyzer))
+}
+
+// CreateAnalyzer @Summary Create Access Analyzer
+// @Description Create an analyzer for an ACCOUNT or ORGANIZATION zone of trust, along with its archive rules.
+// @Description An organization analyzer must be created from the management account or a delegated administrator.
+// @ID aws-access-analyzer-create
+// @Accept json
+// @Produce json
+// @Param body body accessAnalyzerReq.CreateAnalyzerRequest true "Analyzer"
+// @Success 200 {object} accessAnalyzerResp.AnalyzerResponse
+// @Router /aws/access-analyzer/analyzers [post]
+func (awsHandler *Handler) CreateAnalyzer(c echo.Context) error {
+	createAnalyzerRequest := accessAnalyzerReq.CreateAnalyzerRequest{}
+
+	if err := c.Bind(&createAnalyzerRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createAnalyzerRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	archiveRules := make([]inaAws.ArchiveRule, 0, len(createAnalyzerRequest.ArchiveRules))
+	for _, rule := range createAnalyzerRequest.ArchiveRules {
+		archiveRules = append(archiveRules, inaAws.ArchiveRule{
+			Name:   rule.Name,
+			Filter: archiveRuleFilter(rule.Filter),
+		})
+	}
+
+	analyzer, err := accessAnalyzerSvc.CreateAnalyzer(createAnalyzerRequest.Name, createAnalyzerRequest.Type,
+		archiveRules, createAnalyzerRequest.Tags)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, analyzerResponse(*analyzer))
+}
+
+// DeleteAnalyzer @Summary Delete Access Analyzer
+// @Description Delete an analyzer along with its findings and archive rules
+// @ID aws-access-analyzer-delete
+// @Param name path string true "Analyzer name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/access-analyzer/analyzers/{name} [delete]
+func (awsHandler *Handler) DeleteAnalyzer(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = accessAnalyzerSvc.DeleteAnalyzer(c.Param("name")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListAnalyzerFindings @Summary List Access Analyzer Findings
+// @Description Get a page of the findings of an analyzer, the resources shared outside of its zone of trust.
+// @Description The status, resource type, resource and is_public criteria are applied by Access Analyzer, the
+// @Description filter on the listed findings. Only active findings are listed by default.
+// @ID aws-access-analyzer-findings-list
+// @Param name path string true "Analyzer name"
+// @Param status query []string false "Finding statuses" collectionFormat(multi)
+// @Param resource_type query []string false "Resource types, such as AWS::S3::Bucket" collectionFormat(multi)
+// @Param resource query string false "Part of the resource ARN"
+// @Param is_public query bool false "Public access findings only, or non public ones only"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the resource or resource owner account"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(resource, -resource, updated_at, -updated_at)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]accessAnalyzerResp.FindingResponse}
+// @Router /aws/access-analyzer/analyzers/{name}/findings [get]
+func (awsHandler *Handler) ListAnalyzerFindings(c echo.Context) error {
+	listFindingsRequest := accessAnalyzerReq.ListFindingsRequest{}
+
+	if err := c.Bind(&listFindingsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := listFindingsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	analyzer, err := accessAnalyzerSvc.GetAnalyzer(c.Param("name"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	filter := inaAws.AccessAnalyzerFindingFilter{
+		Statuses:      listFindingsRequest.Statuses,
+		ResourceTypes: listFindingsRequest.ResourceTypes,
+		Resource:      listFindingsRequest.Resource,
+		IsPublic:      listFindingsRequest.IsPublicValue(),
+	}
+
+	page, err := pagination.Lister[inaAws.AccessAnalyzerFinding]{
+		Fetch: func(marker string, pageSize int64) ([]inaAws.AccessAnalyzerFinding, string, error) {
+			return accessAnalyzerSvc.ListFindings(analyzer.Arn, filter, marker, pageSize)
+		},
+		Match: func(finding inaAws.AccessAnalyzerFinding) (bool, error) {
+			return pagination.Contains(params.Filter, finding.Resource, finding.ResourceOwnerAccount), nil
+		},
+		Less: map[string]func(a, b inaAws.AccessAnalyzerFinding) bool{
+			"resource": func(a, b inaAws.AccessAnalyzerFinding) bool {
+				return a.Resource < b.Resource
+			},
+			"updated_at": func(a, b inaAws.AccessAnalyzerFinding) bool {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	findingsList := make([]accessAnalyzerResp.FindingResponse, 0, len(page.Items))
+	for _, finding := range page.Items {
+		findingsList = append(findingsList, accessAnalyzerFindingResponse(finding))
+	}
+
+	return responses.ListResponse(c, findingsList, page.NextCursor)
+}
+
+// GetAnalyzerFinding @Summary Get Access Analyzer Finding
+// @Description Get a finding of an analyzer along with the principal, conditions and actions of the access
+// @ID aws-access-analyzer-finding-get
+// @Param name path string true "Analyzer name"
+// @Param id path string true "Finding ID"
+// @Produce json
+// @Success 200 {object} accessAnalyzerResp.FindingResponse
+// @Router /aws/access-analyzer/analyzers/{name}/findings/{id} [get]
+func (awsHandler *Handler) GetAnalyzerFinding(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	analyzer, err := accessAnalyzerSvc.GetAnalyzer(c.Param("name"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	finding, err := accessAnalyzerSvc.GetFinding(analyzer.Arn, c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, accessAnalyzerFindingResponse(*finding))
+}
+
+// ArchiveAnalyzerFindings @Summary Archive Access Analyzer Findings
+// @Description Archive findings of an analyzer, marking the access they report as intended
+// @ID aws-access-analyzer-findings-archive
+// @Accept json
+// @Produce json
+// @Param name path string true "Analyzer name"
+// @Param body body accessAnalyzerReq.UpdateFindingsRequest true "Findings"
+// @Success 200 {boolean} boolean
+// @Router /aws/access-analyzer/analyzers/{name}/findings/archive [post]
+func (awsHandler *Handler) ArchiveAnalyzerFindings(c echo.Context) error {
+	return awsHandler.updateAnalyzerFindings(c, (*inaAws.AccessAnalyzerSvc).ArchiveFindings)
+}
+
+// UnarchiveAnalyzerFindings @Summary Unarchive Access Analyzer Findings
+// @Description Make archived findings of an analyzer active again
+// @ID aws-access-analyzer-findings-unarchive
+// @Accept json
+// @Produce json
+// @Param name path string true "Analyzer name"
+// @Param body body accessAnalyzerReq.UpdateFindingsRequest true "Findings"
+// @Success 200 {boolean} boolean
+// @Router /aws/access-analyzer/analyzers/{name}/findings/unarchive [post]
+func (awsHandler *Handler) UnarchiveAnalyzerFindings(c echo.Context) error {
+	return awsHandler.updateAnalyzerFindings(c, (*inaAws.AccessAnalyzerSvc).UnarchiveFindings)
+}
+
+func (awsHandler *Handler) updateAnalyzerFindings(c echo.Context,
+	update func(accessAnalyzerSvc *inaAws.AccessAnalyzerSvc, analyzerArn string, findingIds []string) error,
+) error {
+	updateFindingsRequest := accessAnalyzerReq.UpdateFindingsRequest{}
+
+	if err := c.Bind(&updateFindingsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateFindingsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	analyzer, err := accessAnalyzerSvc.GetAnalyzer(c.Param("name"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err = update(accessAnalyzerSvc, analyzer.Arn, updateFindingsRequest.FindingIds); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListArchiveRules @Summary List Archive Rules
+// @Description Get the archive rules of an analyzer
+// @ID aws-access-analyzer-archive-rules-list
+// @Param name path string true "Analyzer name"
+// @Produce json
+// @Success 200 {array} accessAnalyzerResp.ArchiveRuleResponse
+// @Router /aws/access-analyzer/analyzers/{name}/archive-rules [get]
+func (awsHandler *Handler) ListArchiveRules(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	rules, err := accessAnalyzerSvc.ListArchiveRules(c.Param("name"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	rulesList := make([]accessAnalyzerResp.ArchiveRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		rulesList = append(rulesList, archiveRuleResponse(rule))
+	}
+
+	return responses.Response(c, http.StatusOK, rulesList)
+}
+
+// GetArchiveRule @Summary Get Archive Rule
+// @Description Get an archive rule of an analyzer
+// @ID aws-access-analyzer-archive-rule-get
+// @Param name path string true "Analyzer name"
+// @Param rule path string true "Archive rule name"
+// @Produce json
+// @Success 200 {object} accessAnalyzerResp.ArchiveRuleResponse
+// @Router /aws/access-analyzer/analyzers/{name}/archive-rules/{rule} [get]
+func (awsHandler *Handler) GetArchiveRule(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	rule, err := accessAnalyzerSvc.GetArchiveRule(c.Param("name"), c.Param("rule"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, archiveRuleResponse(*rule))
+}
+
+// CreateArchiveRule @Summary Create Archive Rule
+// @Description Create an archive rule on an analyzer. It only archives the new findings, apply it to archive the
+// @Description existing ones.
+// @ID aws-access-analyzer-archive-rule-create
+// @Accept json
+// @Produce json
+// @Param name path string true "Analyzer name"
+// @Param body body accessAnalyzerReq.ArchiveRuleRequest true "Archive rule"
+// @Success 200 {object} accessAnalyzerResp.ArchiveRuleResponse
+// @Router /aws/access-analyzer/analyzers/{name}/archive-rules [post]
+func (awsHandler *Handler) CreateArchiveRule(c echo.Context) error {
+	archiveRuleRequest := accessAnalyzerReq.ArchiveRuleRequest{}
+
+	if err := c.Bind(&archiveRuleRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := archiveRuleRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	rule, err := accessAnalyzerSvc.CreateArchiveRule(c.Param("name"), inaAws.ArchiveRule{
+		Name:   archiveRuleRequest.Name,
+		Filter: archiveRuleFilter(archiveRuleRequest.Filter),
+	})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, archiveRuleResponse(*rule))
+}
+
+// UpdateArchiveRule @Summary Update Archive Rule
+// @Description Replace the filter of an archive rule
+// @ID aws-access-analyzer-archive-rule-update
+// @Accept json
+// @Produce json
+// @Param name path string true "Analyzer name"
+// @Param rule path string true "Archive rule name"
+// @Param body body accessAnalyzerReq.UpdateArchiveRuleRequest true "Archive rule filter"
+// @Success 200 {object} accessAnalyzerResp.ArchiveRuleResponse
+// @Router /aws/access-analyzer/analyzers/{name}/archive-rules/{rule} [put]
+func (awsHandler *Handler) UpdateArchiveRule(c echo.Context) error {
+	updateArchiveRuleRequest := accessAnalyzerReq.UpdateArchiveRuleRequest{}
+
+	if err := c.Bind(&updateArchiveRuleRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateArchiveRuleRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	rule, err := accessAnalyzerSvc.UpdateArchiveRule(c.Param("name"), inaAws.ArchiveRule{
+		Name:   c.Param("rule"),
+		Filter: archiveRuleFilter(updateArchiveRuleRequest.Filter),
+	})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, archiveRuleResponse(*rule))
+}
+
+// DeleteArchiveRule @Summary Delete Archive Rule
+// @Description Delete an archive rule, the findings it archived staying archived
+// @ID aws-access-analyzer-archive-rule-delete
+// @Param name path string true "Analyzer name"
+// @Param rule path string true "Archive rule name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/access-analyzer/analyzers/{name}/archive-rules/{rule} [delete]
+func (awsHandler *Handler) DeleteArchiveRule(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = accessAnalyzerSvc.DeleteArchiveRule(c.Param("name"), c.Param("rule")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ApplyArchiveRule @Summary Apply Archive Rule
+// @Description Archive the existing findings of an analyzer matching an archive rule
+// @ID aws-access-analyzer-archive-rule-apply
+// @Param name path string true "Analyzer name"
+// @Param rule path string true "Archive rule name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/access-analyzer/analyzers/{name}/archive-rules/{rule}/apply [post]
+func (awsHandler *Handler) ApplyArchiveRule(c echo.Context) error {
+	accessAnalyzerSvc, err := awsHandler.retrieveAccessAnalyzerSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	analyzer, err := accessAnalyzerSvc.GetAnalyzer(c.Param("name"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err = accessAnalyzerSvc.ApplyArchiveRule(analyzer.Arn, c.Param("rule")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
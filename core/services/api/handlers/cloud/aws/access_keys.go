@@ -0,0 +1,267 @@
+package aws
+
+import (
+	"net/http"
+	"time"
+
+	awsSdk "github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// createdAccessKeyResponse converts a newly created access key to its HTTP representation.
+func createdAccessKeyResponse(accessKey *awsIam.AccessKey) resp.CreatedAccessKeyResponse {
+	return resp.CreatedAccessKeyResponse{
+		Username:        awsSdk.StringValue(accessKey.UserName),
+		AccessKeyId:     awsSdk.StringValue(accessKey.AccessKeyId),
+		SecretAccessKey: awsSdk.StringValue(accessKey.SecretAccessKey),
+		Status:          awsSdk.StringValue(accessKey.Status),
+		CreateDate:      awsSdk.TimeValue(accessKey.CreateDate),
+	}
+}
+
+// ListAccessKeys @Summary List User Access Keys
+// @Description Get a page of the access keys of an IAM user with their age and last-used information
+// @ID aws-user-access-keys-list
+// @Param id path string true "Username"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the access key ID or status"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(created_date, -created_date, last_used_date, -last_used_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.AccessKeyResponse}
+// @Router /aws/iam/users/{id}/access-keys [get]
+func (awsHandler *Handler) ListAccessKeys(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	username := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	accessKeys, err := awsSession.IamSvc.ListAccessKeys(username)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[iam.AccessKey]{
+		Fetch: pagination.Single(accessKeys),
+		Match: func(accessKey iam.AccessKey) (bool, error) {
+			return pagination.Contains(params.Filter, accessKey.AccessKeyId, accessKey.Status), nil
+		},
+		Less: map[string]func(a, b iam.AccessKey) bool{
+			"created_date": func(a, b iam.AccessKey) bool {
+				return a.CreateDate.Before(b.CreateDate)
+			},
+			"last_used_date": func(a, b iam.AccessKey) bool {
+				return awsSdk.TimeValue(a.LastUsedDate).Before(awsSdk.TimeValue(b.LastUsedDate))
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accessKeysList := make([]resp.AccessKeyResponse, 0, len(page.Items))
+	for _, accessKey := range page.Items {
+		accessKeysList = append(accessKeysList, resp.AccessKeyResponse{
+			AccessKeyId:     accessKey.AccessKeyId,
+			Status:          accessKey.Status,
+			CreateDate:      accessKey.CreateDate,
+			AgeDays:         int(accessKey.Age().Hours() / 24),
+			LastUsedDate:    accessKey.LastUsedDate,
+			LastUsedService: accessKey.LastUsedService,
+			LastUsedRegion:  accessKey.LastUsedRegion,
+		})
+	}
+
+	return responses.ListResponse(c, accessKeysList, page.NextCursor)
+}
+
+// CreateAccessKey @Summary Create User Access Key
+// @Description Create an access key for an IAM user, the secret is only returned once
+// @ID aws-user-access-key-create
+// @Param id path string true "Username"
+// @Produce json
+// @Success 201 {object} resp.CreatedAccessKeyResponse
+// @Router /aws/iam/users/{id}/access-keys [post]
+func (awsHandler *Handler) CreateAccessKey(c echo.Context) error {
+	username := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	accessKey, err := awsSession.IamSvc.CreateAccessKey(username)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusCreated, createdAccessKeyResponse(accessKey))
+}
+
+// UpdateAccessKey @Summary Update User Access Key
+// @Description Activate or deactivate an access key of an IAM user
+// @ID aws-user-access-key-update
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param key path string true "Access Key ID"
+// @Param body body req.UpdateAccessKeyRequest true "New status"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/access-keys/{key} [put]
+func (awsHandler *Handler) UpdateAccessKey(c echo.Context) error {
+	updateAccessKeyRequest := req.UpdateAccessKeyRequest{}
+
+	if err := c.Bind(&updateAccessKeyRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateAccessKeyRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.UpdateAccessKeyStatus(updateAccessKeyRequest.Username, updateAccessKeyRequest.AccessKeyId,
+		updateAccessKeyRequest.Status)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DeleteAccessKey @Summary Delete User Access Key
+// @Description Delete an access key of an IAM user
+// @ID aws-user-access-key-delete
+// @Param id path string true "Username"
+// @Param key path string true "Access Key ID"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/access-keys/{key} [delete]
+func (awsHandler *Handler) DeleteAccessKey(c echo.Context) error {
+	username := c.Param("id")
+	accessKeyId := c.Param("key")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeleteAccessKey(username, accessKeyId)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// StartAccessKeyRotation @Summary Start Access Key Rotation
+// @Description Create the replacement of an active access key, the secret is only returned once
+// @ID aws-user-access-key-rotate-start
+// @Param id path string true "Username"
+// @Param key path string true "Access Key ID to rotate"
+// @Produce json
+// @Success 201 {object} resp.CreatedAccessKeyResponse
+// @Router /aws/iam/users/{id}/access-keys/{key}/rotate [post]
+func (awsHandler *Handler) StartAccessKeyRotation(c echo.Context) error {
+	username := c.Param("id")
+	accessKeyId := c.Param("key")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	accessKey, err := awsSession.IamSvc.StartAccessKeyRotation(username, accessKeyId)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusCreated, createdAccessKeyResponse(accessKey))
+}
+
+// ConfirmAccessKeyRotation @Summary Confirm Access Key Rotation
+// @Description Confirm the new key is in use, deactivate the old key and schedule its deletion
+// @ID aws-user-access-key-rotate-confirm
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param key path string true "Access Key ID being rotated"
+// @Param body body req.ConfirmAccessKeyRotationRequest true "New key and grace period"
+// @Success 200 {object} resp.AccessKeyRotationResponse
+// @Router /aws/iam/users/{id}/access-keys/{key}/rotate/confirm [post]
+func (awsHandler *Handler) ConfirmAccessKeyRotation(c echo.Context) error {
+	confirmRequest := req.ConfirmAccessKeyRotationRequest{}
+
+	if err := c.Bind(&confirmRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := confirmRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	gracePeriod := iam.DefaultAccessKeyGracePeriod
+	if confirmRequest.GracePeriodHours != nil {
+		gracePeriod = time.Duration(*confirmRequest.GracePeriodHours) * time.Hour
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	deleteAfter, err := awsSession.IamSvc.ConfirmAccessKeyRotation(confirmRequest.Username, confirmRequest.OldAccessKeyId,
+		confirmRequest.NewAccessKeyId, gracePeriod)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.AccessKeyRotationResponse{
+		OldAccessKeyId: confirmRequest.OldAccessKeyId,
+		NewAccessKeyId: confirmRequest.NewAccessKeyId,
+		DeleteAfter:    deleteAfter,
+	})
+}
+
+// CompleteAccessKeyRotation @Summary Complete Access Key Rotation
+// @Description Delete the rotated-out access key once its grace period has elapsed
+// @ID aws-user-access-key-rotate-complete
+// @Param id path string true "Username"
+// @Param key path string true "Access Key ID being rotated"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/access-keys/{key}/rotate/complete [post]
+func (awsHandler *Handler) CompleteAccessKeyRotation(c echo.Context) error {
+	username := c.Param("id")
+	accessKeyId := c.Param("key")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.CompleteAccessKeyRotation(username, accessKeyId)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
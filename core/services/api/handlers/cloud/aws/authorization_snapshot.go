@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+
+	uuid "github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/db"
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/entites"
+)
+
+// authorizationSnapshotProvider is the provider stored along with AWS authorization snapshots.
+const authorizationSnapshotProvider = "aws"
+
+// authorizationSnapshotSummaryResponse converts a stored authorization snapshot to its HTTP summary.
+func authorizationSnapshotSummaryResponse(snapshot *entites.AuthorizationSnapshots) resp.AuthorizationSnapshotSummaryResponse {
+	return resp.AuthorizationSnapshotSummaryResponse{
+		SnapshotID:  snapshot.SnapshotID.String(),
+		AccountID:   snapshot.AccountID,
+		TakenAt:     snapshot.TakenAt,
+		UserCount:   snapshot.UserCount,
+		GroupCount:  snapshot.GroupCount,
+		RoleCount:   snapshot.RoleCount,
+		PolicyCount: snapshot.PolicyCount,
+	}
+}
+
+// TakeAuthorizationSnapshot @Summary Take Authorization Snapshot
+// @Description Pull every IAM user, group, role and managed policy, with all policy versions and relationships,
+// @Description and store them as a point-in-time snapshot
+// @ID aws-authorization-snapshot-take
+// @Produce json
+// @Success 200 {object} resp.AuthorizationSnapshotSummaryResponse
+// @Router /aws/iam/snapshots [post]
+func (awsHandler *Handler) TakeAuthorizationSnapshot(c echo.Context) error {
+	if awsHandler.api.DB == nil {
+		return responses.ErrorResponse(c, http.StatusServiceUnavailable, resp.HttpErrSnapshotsStore)
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	accountID := awsSession.IamSvc.AccountID()
+	if accountID == "" {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, iam.ErrIamAccountUnknown)
+	}
+
+	snapshot, err := awsSession.IamSvc.FetchAuthorizationSnapshot()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	storedSnapshot := entites.AuthorizationSnapshots{
+		Provider:    authorizationSnapshotProvider,
+		AccountID:   accountID,
+		TakenAt:     snapshot.TakenAt,
+		UserCount:   len(snapshot.Users),
+		GroupCount:  len(snapshot.Groups),
+		RoleCount:   len(snapshot.Roles),
+		PolicyCount: len(snapshot.Policies),
+		Content:     string(content),
+	}
+	if err := db.SaveAuthorizationSnapshot(awsHandler.api.DB, &storedSnapshot); err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, authorizationSnapshotSummaryResponse(&storedSnapshot))
+}
+
+// ListAuthorizationSnapshots @Summary List Authorization Snapshots
+// @Description Get a page of the stored authorization snapshots, newest first
+// @ID aws-authorization-snapshots-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(taken_at, -taken_at)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.AuthorizationSnapshotSummaryResponse}
+// @Router /aws/iam/snapshots [get]
+func (awsHandler *Handler) ListAuthorizationSnapshots(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if awsHandler.api.DB == nil {
+		return responses.ErrorResponse(c, http.StatusServiceUnavailable, resp.HttpErrSnapshotsStore)
+	}
+
+	accountID, err := awsHandler.accountID()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	snapshots, err := db.ListAuthorizationSnapshots(awsHandler.api.DB, authorizationSnapshotProvider, accountID)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	summaries := make([]resp.AuthorizationSnapshotSummaryResponse, 0, len(snapshots))
+	for i := range snapshots {
+		summaries = append(summaries, authorizationSnapshotSummaryResponse(&snapshots[i]))
+	}
+
+	page, err := pagination.Lister[resp.AuthorizationSnapshotSummaryResponse]{
+		Fetch: pagination.Single(summaries),
+		Less: map[string]func(a, b resp.AuthorizationSnapshotSummaryResponse) bool{
+			"taken_at": func(a, b resp.AuthorizationSnapshotSummaryResponse) bool {
+				return a.TakenAt.Before(b.TakenAt)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.ListResponse(c, page.Items, page.NextCursor)
+}
+
+// GetAuthorizationSnapshot @Summary Get Authorization Snapshot
+// @Description Get the content of a stored authorization snapshot, in the GetAccountAuthorizationDetails format
+// @ID aws-authorization-snapshot-get
+// @Param id path string true "Snapshot ID"
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /aws/iam/snapshots/{id} [get]
+func (awsHandler *Handler) GetAuthorizationSnapshot(c echo.Context) error {
+	snapshotID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, resp.HttpErrInvalidSnapshotID)
+	}
+
+	if awsHandler.api.DB == nil {
+		return responses.ErrorResponse(c, http.StatusServiceUnavailable, resp.HttpErrSnapshotsStore)
+	}
+
+	accountID, err := awsHandler.accountID()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	snapshot, err := db.GetAuthorizationSnapshot(awsHandler.api.DB, snapshotID, accountID)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	return c.JSONBlob(http.StatusOK, []byte(snapshot.Content))
+}
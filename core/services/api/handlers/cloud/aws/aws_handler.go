@@ -1,16 +1,26 @@
 package aws
 
 import (
+	"errors"
+	"sync"
+
 	"gitea/pcp-inariam/inariam/core/services/api"
 	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/log"
 )
 
 type Handler struct {
 	api *api.API
+
+	// identity is the account and principal of the configured credentials. It is resolved with STS by the first
+	// session that succeeds and shared by the following ones.
+	identity      inaAws.CallerIdentity
+	identityMutex sync.Mutex
 }
 
 func NewAwsHandler(api *api.API) *Handler {
-	return &Handler{api}
+	return &Handler{api: api}
 }
 
 // RetrieveAwsSession retrieve an IAM AwsSession with the config passed to the handler
@@ -18,6 +28,8 @@ func (awsHandler *Handler) RetrieveAwsIamSession() (*inaAws.Session, error) {
 	creds := inaAws.Credentials{
 		AccessKeyID:     awsHandler.api.Config.AWS.AccessKeyID,
 		SecretAccessKey: awsHandler.api.Config.AWS.SecretAccessKey,
+		SessionToken:    awsHandler.api.Config.AWS.SessionToken,
+		Region:          awsHandler.api.Config.AWS.Region,
 	}
 	awsSession, err := inaAws.OpenSession(&creds)
 
@@ -25,6 +37,54 @@ func (awsHandler *Handler) RetrieveAwsIamSession() (*inaAws.Session, error) {
 		return nil, err
 	}
 
+	awsSession.Identity = awsHandler.callerIdentity(awsSession)
 	awsSession.OpenIamService()
 	return awsSession, nil
 }
+
+// callerIdentity returns the identity of the configured credentials, calling STS only until it has been resolved
+// once. A failed resolution is logged and retried by the next session, the session is still opened without the
+// account ID so only the operations building ARNs fail.
+func (awsHandler *Handler) callerIdentity(awsSession *inaAws.Session) inaAws.CallerIdentity {
+	awsHandler.identityMutex.Lock()
+	defer awsHandler.identityMutex.Unlock()
+
+	if awsHandler.identity.AccountID != "" {
+		return awsHandler.identity
+	}
+
+	identity, err := awsSession.ResolveCallerIdentity()
+	if err != nil {
+		log.Logger.Warnln(err.Error())
+		return inaAws.CallerIdentity{}
+	}
+
+	awsHandler.identity = identity
+	return identity
+}
+
+// accountID returns the ID of the AWS account the configured credentials belong to, the stored reports and
+// snapshots are scoped to it.
+func (awsHandler *Handler) accountID() (string, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return "", err
+	}
+
+	accountID := awsSession.IamSvc.AccountID()
+	if accountID == "" {
+		return "", errors.New(iam.ErrIamAccountUnknown)
+	}
+
+	return accountID, nil
+}
+
+// requiredRolePermissionsBoundary returns the permissions boundary every role created through Inariam must carry,
+// or an empty string when no such rule is configured.
+func (awsHandler *Handler) requiredRolePermissionsBoundary() string {
+	if awsHandler.api.Config.AWS == nil {
+		return ""
+	}
+
+	return awsHandler.api.Config.AWS.RequiredRolePermissionsBoundary
+}
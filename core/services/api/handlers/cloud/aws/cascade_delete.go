@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// boolQueryParam tells whether the named query parameter is set to a true value.
+func boolQueryParam(c echo.Context, name string) bool {
+	value, err := strconv.ParseBool(c.QueryParam(name))
+	return err == nil && value
+}
+
+// cascadeRequested tells whether a delete request asks to remove the dependencies of the resource.
+func cascadeRequested(c echo.Context) bool {
+	return boolQueryParam(c, "cascade")
+}
+
+// cascadeDelete computes the delete plan of a resource and returns it as is on a dry run, or executes it.
+// The returned status is 207 Multi-Status when at least one step failed.
+func cascadeDelete(c echo.Context, planDeletion func() (*iam.DeletePlan, error)) error {
+	plan, err := planDeletion()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	dryRun := boolQueryParam(c, "dry_run")
+	if dryRun {
+		return responses.Response(c, http.StatusOK, deletePlanResponse(plan, dryRun))
+	}
+
+	statusCode := http.StatusOK
+	if err = plan.Execute(); err != nil {
+		statusCode = http.StatusMultiStatus
+	}
+
+	return responses.Response(c, statusCode, deletePlanResponse(plan, dryRun))
+}
+
+// deletePlanResponse converts a delete plan, executed or not, to its HTTP representation.
+func deletePlanResponse(plan *iam.DeletePlan, dryRun bool) resp.DeletePlanResponse {
+	planResponse := resp.DeletePlanResponse{
+		ResourceType: plan.ResourceType,
+		Resource:     plan.Resource,
+		DryRun:       dryRun,
+		Completed:    !dryRun,
+		Steps:        make([]resp.DeleteStepResponse, 0, len(plan.Steps)),
+	}
+
+	for _, step := range plan.Steps {
+		stepResponse := resp.DeleteStepResponse{
+			Action: step.Action,
+			Target: step.Target,
+			Status: step.Status,
+		}
+		if step.Err != nil {
+			stepResponse.Error = step.Err.Error()
+		}
+		if !dryRun && step.Status != iam.DeleteStepSucceeded {
+			planResponse.Completed = false
+		}
+		planResponse.Steps = append(planResponse.Steps, stepResponse)
+	}
+
+	return planResponse
+}
@@ -0,0 +1,146 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	cloudTrailReq "gitea/pcp-inariam/inariam/core/services/api/requests/aws/cloudtrail"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	cloudTrailResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/cloudtrail"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+// changeHistoryLimit is the number of changes returned by the detail endpoints of the IAM entities.
+const changeHistoryLimit = 20
+
+// retrieveCloudTrailSvc opens an AWS session and its CloudTrail service.
+func (awsHandler *Handler) retrieveCloudTrailSvc() (*inaAws.CloudTrailSvc, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession.CreateCloudTrailSvc()
+	return awsSession.CloudTrailSvc, nil
+}
+
+func iamEventResponse(event inaAws.IamEvent) cloudTrailResp.IamEventResponse {
+	eventResponse := cloudTrailResp.IamEventResponse{
+		ID:           event.ID,
+		EventName:    event.Name,
+		EventTime:    event.Time,
+		Username:     event.Username,
+		PrincipalArn: event.PrincipalArn,
+		AccessKeyID:  event.AccessKeyID,
+		SourceIP:     event.SourceIP,
+		UserAgent:    event.UserAgent,
+		Region:       event.Region,
+		ReadOnly:     event.ReadOnly,
+		ErrorCode:    event.ErrorCode,
+		ErrorMessage: event.ErrorMessage,
+		Resources:    make([]cloudTrailResp.IamEventResourceResponse, 0, len(event.Resources)),
+	}
+	for _, resource := range event.Resources {
+		eventResponse.Resources = append(eventResponse.Resources, cloudTrailResp.IamEventResourceResponse{
+			Type: resource.Type,
+			Name: resource.Name,
+		})
+	}
+
+	return eventResponse
+}
+
+// changeHistory returns the latest changes of an IAM resource of a type such as AWS::IAM::Role when the history
+// query parameter is set, and nil otherwise so that the detail endpoints only query CloudTrail on demand.
+func (awsHandler *Handler) changeHistory(c echo.Context, resourceType, resource string,
+) ([]cloudTrailResp.IamEventResponse, error) {
+	if !boolQueryParam(c, "history") {
+		return nil, nil
+	}
+
+	cloudTrailSvc, err := awsHandler.retrieveCloudTrailSvc()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := cloudTrailSvc.IamChangeHistory(resourceType, resource, changeHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]cloudTrailResp.IamEventResponse, 0, len(events))
+	for _, event := range events {
+		history = append(history, iamEventResponse(event))
+	}
+
+	return history, nil
+}
+
+// ListIamEvents @Summary List IAM Events
+// @Description Get a page of the IAM management events recorded by CloudTrail over the last 90 days, the latest
+// @Description first. Read-only calls are excluded by default. Events are not sortable, sorting would read the
+// @Description whole 90 days of events.
+// @ID aws-cloudtrail-iam-events-list
+// @Param principal query string false "User name recorded for the caller, the IAM user or the role session name"
+// @Param resource query string false "Name of a referenced user, group or role, or ARN of a referenced policy"
+// @Param event_name query string false "Event name, such as CreateUser or AttachRolePolicy"
+// @Param start_time query string false "RFC 3339 time the events occurred after"
+// @Param end_time query string false "RFC 3339 time the events occurred before"
+// @Param include_read_only query bool false "List the read-only calls as well"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the event name, user name or source IP"
+// @Produce json
+// @Success 200 {object} responses.List{items=[]cloudTrailResp.IamEventResponse}
+// @Router /aws/cloudtrail/iam-events [get]
+func (awsHandler *Handler) ListIamEvents(c echo.Context) error {
+	listIamEventsRequest := cloudTrailReq.ListIamEventsRequest{}
+
+	if err := c.Bind(&listIamEventsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := listIamEventsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	cloudTrailSvc, err := awsHandler.retrieveCloudTrailSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	filter := inaAws.IamEventFilter{
+		Principal:       listIamEventsRequest.Principal,
+		Resource:        listIamEventsRequest.Resource,
+		EventName:       listIamEventsRequest.EventName,
+		StartTime:       listIamEventsRequest.StartTimeValue(),
+		EndTime:         listIamEventsRequest.EndTimeValue(),
+		IncludeReadOnly: listIamEventsRequest.IncludeReadOnly,
+	}
+
+	page, err := pagination.Lister[inaAws.IamEvent]{
+		Fetch: func(marker string, pageSize int64) ([]inaAws.IamEvent, string, error) {
+			return cloudTrailSvc.LookupIamEvents(filter, marker, pageSize)
+		},
+		Match: func(event inaAws.IamEvent) (bool, error) {
+			return pagination.Contains(params.Filter, event.Name, event.Username, event.SourceIP), nil
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	eventsList := make([]cloudTrailResp.IamEventResponse, 0, len(page.Items))
+	for _, event := range page.Items {
+		eventsList = append(eventsList, iamEventResponse(event))
+	}
+
+	return responses.ListResponse(c, eventsList, page.NextCursor)
+}
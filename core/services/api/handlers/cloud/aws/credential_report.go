@@ -0,0 +1,267 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/log"
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/db"
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/entites"
+)
+
+const (
+	// credentialReportProvider is the provider stored along with AWS credential reports.
+	credentialReportProvider = "aws"
+
+	credentialReportFormatJSON = "json"
+	credentialReportFormatCSV  = "csv"
+)
+
+// credentialReportFormatParam returns the format query parameter of a credential report request, json by default.
+func credentialReportFormatParam(c echo.Context) (string, error) {
+	switch format := c.QueryParam("format"); format {
+	case "", credentialReportFormatJSON:
+		return credentialReportFormatJSON, nil
+	case credentialReportFormatCSV:
+		return credentialReportFormatCSV, nil
+	default:
+		return "", errors.New(resp.HttpErrInvalidReportFormat)
+	}
+}
+
+// ageDays converts an age to whole days.
+func ageDays(age *time.Duration) *int {
+	if age == nil {
+		return nil
+	}
+
+	days := int(*age / (24 * time.Hour))
+	return &days
+}
+
+// credentialReportResponse converts a credential report to its HTTP representation.
+func credentialReportResponse(reportID string, report *iam.CredentialReport) resp.CredentialReportResponse {
+	rows := make([]resp.CredentialReportRowResponse, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		rowResponse := resp.CredentialReportRowResponse{
+			User:                 row.User,
+			Arn:                  row.Arn,
+			Root:                 row.IsRoot(),
+			UserCreationTime:     row.UserCreationTime,
+			PasswordEnabled:      row.PasswordEnabled,
+			PasswordLastUsed:     row.PasswordLastUsed,
+			PasswordLastChanged:  row.PasswordLastChanged,
+			PasswordAgeDays:      ageDays(row.PasswordAge(report.GeneratedTime)),
+			PasswordNextRotation: row.PasswordNextRotation,
+			MFAActive:            row.MFAActive,
+		}
+		for index, accessKey := range row.AccessKeys {
+			rowResponse.AccessKeys = append(rowResponse.AccessKeys, resp.CredentialReportAccessKeyResponse{
+				Active:          accessKey.Active,
+				LastRotated:     accessKey.LastRotated,
+				AgeDays:         ageDays(row.AccessKeyAge(index, report.GeneratedTime)),
+				LastUsedDate:    accessKey.LastUsedDate,
+				LastUsedRegion:  accessKey.LastUsedRegion,
+				LastUsedService: accessKey.LastUsedService,
+			})
+		}
+		for _, certificate := range row.Certificates {
+			rowResponse.Certificates = append(rowResponse.Certificates, resp.CredentialReportCertificateResponse{
+				Active:      certificate.Active,
+				LastRotated: certificate.LastRotated,
+			})
+		}
+		rows = append(rows, rowResponse)
+	}
+
+	return resp.CredentialReportResponse{
+		ReportID:      reportID,
+		GeneratedTime: report.GeneratedTime,
+		Rows:          rows,
+	}
+}
+
+// credentialReportResult writes a credential report in the requested format.
+func credentialReportResult(c echo.Context, format string, reportID string, report *iam.CredentialReport) error {
+	if format == credentialReportFormatCSV {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q",
+			fmt.Sprintf("credential-report-%s.csv", report.GeneratedTime.UTC().Format("20060102T150405Z"))))
+		return c.Blob(http.StatusOK, "text/csv", report.Content)
+	}
+
+	return responses.Response(c, http.StatusOK, credentialReportResponse(reportID, report))
+}
+
+// storeCredentialReport stores a copy of a credential report of an account and returns its ID. AWS returns the same
+// report until it is generated again, up to 4 hours later, so a report already stored is not stored twice and the ID
+// of the stored copy is returned.
+func (awsHandler *Handler) storeCredentialReport(accountID string, report *iam.CredentialReport) (string, error) {
+	if accountID == "" {
+		return "", errors.New(iam.ErrIamAccountUnknown)
+	}
+
+	storedReport, err := db.FindCredentialReport(awsHandler.api.DB, accountID, report.GeneratedTime)
+	if err != nil {
+		return "", err
+	}
+
+	if storedReport == nil {
+		storedReport = &entites.CredentialReports{
+			Provider:      credentialReportProvider,
+			AccountID:     accountID,
+			GeneratedTime: report.GeneratedTime,
+			RowCount:      len(report.Rows),
+			Content:       string(report.Content),
+		}
+		if err := db.SaveCredentialReport(awsHandler.api.DB, storedReport); err != nil {
+			return "", err
+		}
+	}
+
+	return storedReport.ReportID.String(), nil
+}
+
+// GetCredentialReport @Summary Get Credential Report
+// @Description Generate the IAM credential report, wait until it completes and return it as JSON or CSV.
+// @Description A copy of each generated report is stored for historical comparison when the database is connected.
+// @ID aws-credential-report-get
+// @Param format query string false "Report format, json or csv" Enums(json, csv)
+// @Produce json
+// @Produce text/csv
+// @Success 200 {object} resp.CredentialReportResponse
+// @Router /aws/iam/credential-report [get]
+func (awsHandler *Handler) GetCredentialReport(c echo.Context) error {
+	format, err := credentialReportFormatParam(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	report, err := awsSession.IamSvc.FetchCredentialReport()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	var reportID string
+	if awsHandler.api.DB != nil {
+		reportID, err = awsHandler.storeCredentialReport(awsSession.IamSvc.AccountID(), report)
+		if err != nil {
+			log.Logger.Warnf("credential report not stored: %v", err)
+		}
+	}
+
+	return credentialReportResult(c, format, reportID, report)
+}
+
+// ListCredentialReportHistory @Summary List Credential Report History
+// @Description Get a page of the stored IAM credential reports, newest first
+// @ID aws-credential-report-history-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(generated_time, -generated_time)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.CredentialReportSummaryResponse}
+// @Router /aws/iam/credential-report/history [get]
+func (awsHandler *Handler) ListCredentialReportHistory(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if awsHandler.api.DB == nil {
+		return responses.ErrorResponse(c, http.StatusServiceUnavailable, resp.HttpErrCredentialReportsStore)
+	}
+
+	accountID, err := awsHandler.accountID()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	reports, err := db.ListCredentialReports(awsHandler.api.DB, credentialReportProvider, accountID)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	summaries := make([]resp.CredentialReportSummaryResponse, 0, len(reports))
+	for _, report := range reports {
+		summaries = append(summaries, resp.CredentialReportSummaryResponse{
+			ReportID:      report.ReportID.String(),
+			AccountID:     report.AccountID,
+			GeneratedTime: report.GeneratedTime,
+			RowCount:      report.RowCount,
+		})
+	}
+
+	page, err := pagination.Lister[resp.CredentialReportSummaryResponse]{
+		Fetch: pagination.Single(summaries),
+		Less: map[string]func(a, b resp.CredentialReportSummaryResponse) bool{
+			"generated_time": func(a, b resp.CredentialReportSummaryResponse) bool {
+				return a.GeneratedTime.Before(b.GeneratedTime)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.ListResponse(c, page.Items, page.NextCursor)
+}
+
+// GetStoredCredentialReport @Summary Get Stored Credential Report
+// @Description Get a stored IAM credential report as JSON or CSV
+// @ID aws-credential-report-history-get
+// @Param id path string true "Report ID"
+// @Param format query string false "Report format, json or csv" Enums(json, csv)
+// @Produce json
+// @Produce text/csv
+// @Success 200 {object} resp.CredentialReportResponse
+// @Router /aws/iam/credential-report/history/{id} [get]
+func (awsHandler *Handler) GetStoredCredentialReport(c echo.Context) error {
+	format, err := credentialReportFormatParam(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	reportID, err := uuid.FromString(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, resp.HttpErrInvalidReportID)
+	}
+
+	if awsHandler.api.DB == nil {
+		return responses.ErrorResponse(c, http.StatusServiceUnavailable, resp.HttpErrCredentialReportsStore)
+	}
+
+	accountID, err := awsHandler.accountID()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	storedReport, err := db.GetCredentialReport(awsHandler.api.DB, reportID, accountID)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusNotFound, err.Error())
+	}
+
+	rows, err := iam.ParseCredentialReport([]byte(storedReport.Content))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return credentialReportResult(c, format, storedReport.ReportID.String(), &iam.CredentialReport{
+		GeneratedTime: storedReport.GeneratedTime,
+		Content:       []byte(storedReport.Content),
+		Rows:          rows,
+	})
+}
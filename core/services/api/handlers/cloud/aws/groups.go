@@ -3,33 +3,60 @@ package aws
 import (
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
 	"github.com/labstack/echo/v4"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 )
 
 // ListGroups
 // @Summary AWS List Groups
-// @Description Get a list of AWS IAM groups
+// @Description Get a page of AWS IAM groups
 // @ID aws-list-groups
-// @Produce plain
-// @Success 200 {string} string "Group names separated by newline"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the group name, ID or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.Group}
 // @Router /aws/iam/groups [get]
 func (awsHandler *Handler) ListGroups(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
 	awsSession, err := awsHandler.RetrieveAwsIamSession()
 	if err != nil {
 		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrOpenedSession)
 	}
 
-	groups, err := awsSession.IamSvc.ListIamGroups()
+	page, err := pagination.Lister[*awsIam.Group]{
+		Fetch: awsSession.IamSvc.ListIamGroupsPage,
+		Match: func(group *awsIam.Group) (bool, error) {
+			return pagination.Contains(params.Filter, aws.StringValue(group.GroupName), aws.StringValue(group.GroupId),
+				aws.StringValue(group.Arn)), nil
+		},
+		Less: map[string]func(a, b *awsIam.Group) bool{
+			"name": func(a, b *awsIam.Group) bool {
+				return aws.StringValue(a.GroupName) < aws.StringValue(b.GroupName)
+			},
+			"created_date": func(a, b *awsIam.Group) bool {
+				return aws.TimeValue(a.CreateDate).Before(aws.TimeValue(b.CreateDate))
+			},
+		},
+	}.List(params)
 	if err != nil {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, responses.HttpErrServerFailed)
 	}
 
-	var groupsList []resp.Group
-	for _, group := range groups {
+	groupsList := make([]resp.Group, 0, len(page.Items))
+	for _, group := range page.Items {
 		groupsList = append(groupsList, resp.Group{
 			Arn:        *group.Arn,
 			CreateDate: *group.CreateDate,
@@ -39,7 +66,7 @@ func (awsHandler *Handler) ListGroups(ctx echo.Context) error {
 		})
 	}
 
-	return responses.Response(ctx, http.StatusOK, groupsList)
+	return responses.ListResponse(ctx, groupsList, page.NextCursor)
 }
 
 // GetGroup
@@ -63,12 +90,23 @@ func (awsHandler *Handler) GetGroup(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
+	members, err := awsSession.IamSvc.ListGroupMembers(groupName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	memberNames := make([]string, 0, len(members))
+	for _, member := range members {
+		memberNames = append(memberNames, *member.UserName)
+	}
+
 	return responses.Response(c, http.StatusOK, resp.Group{
 		Name:       *groupDetails.GroupName,
 		Id:         *groupDetails.GroupId,
 		CreateDate: *groupDetails.CreateDate,
 		Arn:        *groupDetails.Arn,
-		Path:       groupName,
+		Path:       *groupDetails.Path,
+		Members:    memberNames,
 	},
 	)
 }
@@ -156,8 +194,11 @@ func (awsHandler *Handler) UpdateGroup(ctx echo.Context) error {
 // @Description Delete an AWS IAM group by group name
 // @ID delete-aws-group
 // @Param groupName path string true "Group Name"
+// @Param cascade query bool false "Remove the members and policies of the group first"
+// @Param dry_run query bool false "With cascade, only return the planned steps"
 // @Produce json
 // @Success 200 {string} string "AWS Group deleted successfully"
+// @Success 207 {object} resp.DeletePlanResponse "Cascading delete with failed steps"
 // @Router /aws/iam/groups/{groupName} [delete]
 func (awsHandler *Handler) DeleteGroup(ctx echo.Context) error {
 	groupName := ctx.Param("id")
@@ -167,6 +208,12 @@ func (awsHandler *Handler) DeleteGroup(ctx echo.Context) error {
 		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrServerFailed)
 	}
 
+	if cascadeRequested(ctx) {
+		return cascadeDelete(ctx, func() (*iam.DeletePlan, error) {
+			return awsSession.IamSvc.PlanGroupDeletion(groupName)
+		})
+	}
+
 	err = awsSession.IamSvc.DeleteIamGroup(groupName)
 
 	if err != nil {
@@ -175,3 +222,180 @@ func (awsHandler *Handler) DeleteGroup(ctx echo.Context) error {
 
 	return responses.Response(ctx, http.StatusOK, true)
 }
+
+// membershipResponse converts the outcome of a bulk membership operation to its HTTP representation.
+// The returned status is 207 Multi-Status when at least one user failed.
+func membershipResponse(results []iam.GroupMembershipResult) (int, []resp.GroupMembershipResponse) {
+	statusCode := http.StatusOK
+	membershipList := make([]resp.GroupMembershipResponse, 0, len(results))
+
+	for _, result := range results {
+		membership := resp.GroupMembershipResponse{
+			Username: result.Username,
+			Success:  result.Err == nil,
+		}
+		if result.Err != nil {
+			membership.Error = result.Err.Error()
+			statusCode = http.StatusMultiStatus
+		}
+		membershipList = append(membershipList, membership)
+	}
+
+	return statusCode, membershipList
+}
+
+// ListGroupMembers
+// @Summary List AWS Group Members
+// @Description Get a page of the IAM users that are members of an AWS IAM group
+// @ID aws-list-group-members
+// @Param id path string true "Group Name"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the username, user ID or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.UserDetailResponse}
+// @Router /aws/iam/groups/{id}/members [get]
+func (awsHandler *Handler) ListGroupMembers(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	groupName := ctx.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	members, err := awsSession.IamSvc.ListGroupMembers(groupName)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[*awsIam.User]{
+		Fetch: pagination.Single(members),
+		Match: func(member *awsIam.User) (bool, error) {
+			return pagination.Contains(params.Filter, aws.StringValue(member.UserName), aws.StringValue(member.UserId),
+				aws.StringValue(member.Arn)), nil
+		},
+		Less: map[string]func(a, b *awsIam.User) bool{
+			"name": func(a, b *awsIam.User) bool {
+				return aws.StringValue(a.UserName) < aws.StringValue(b.UserName)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	usersDetails := make([]resp.UserDetailResponse, 0, len(page.Items))
+	for _, member := range page.Items {
+		usersDetails = append(usersDetails, resp.UserDetailResponse{
+			Username: *member.UserName,
+			UserID:   *member.UserId,
+			UserArn:  *member.Arn,
+		})
+	}
+
+	return responses.ListResponse(ctx, usersDetails, page.NextCursor)
+}
+
+// AddGroupMembers
+// @Summary Add AWS Group Members
+// @Description Add one or more IAM users to an AWS IAM group
+// @ID aws-add-group-members
+// @Accept json
+// @Produce json
+// @Param id path string true "Group Name"
+// @Param body body req.GroupMembersRequest true "Usernames to add"
+// @Success 200 {array} resp.GroupMembershipResponse
+// @Success 207 {array} resp.GroupMembershipResponse
+// @Router /aws/iam/groups/{id}/members [post]
+func (awsHandler *Handler) AddGroupMembers(ctx echo.Context) error {
+	var groupMembersRequest = new(req.GroupMembersRequest)
+	if err := ctx.Bind(groupMembersRequest); err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := groupMembersRequest.Validate(); err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if _, err = awsSession.IamSvc.CheckIfGroupExists(groupMembersRequest.GroupName); err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	statusCode, membershipList := membershipResponse(
+		awsSession.IamSvc.AddUsersToGroup(groupMembersRequest.GroupName, groupMembersRequest.Usernames))
+
+	return responses.Response(ctx, statusCode, membershipList)
+}
+
+// RemoveGroupMembers
+// @Summary Remove AWS Group Members
+// @Description Remove one or more IAM users from an AWS IAM group
+// @ID aws-remove-group-members
+// @Accept json
+// @Produce json
+// @Param id path string true "Group Name"
+// @Param body body req.GroupMembersRequest true "Usernames to remove"
+// @Success 200 {array} resp.GroupMembershipResponse
+// @Success 207 {array} resp.GroupMembershipResponse
+// @Router /aws/iam/groups/{id}/members [delete]
+func (awsHandler *Handler) RemoveGroupMembers(ctx echo.Context) error {
+	var groupMembersRequest = new(req.GroupMembersRequest)
+	if err := ctx.Bind(groupMembersRequest); err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := groupMembersRequest.Validate(); err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if _, err = awsSession.IamSvc.CheckIfGroupExists(groupMembersRequest.GroupName); err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	statusCode, membershipList := membershipResponse(
+		awsSession.IamSvc.RemoveUsersFromGroup(groupMembersRequest.GroupName, groupMembersRequest.Usernames))
+
+	return responses.Response(ctx, statusCode, membershipList)
+}
+
+// RemoveGroupMember
+// @Summary Remove AWS Group Member
+// @Description Remove a single IAM user from an AWS IAM group
+// @ID aws-remove-group-member
+// @Param id path string true "Group Name"
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/groups/{id}/members/{username} [delete]
+func (awsHandler *Handler) RemoveGroupMember(ctx echo.Context) error {
+	groupName := ctx.Param("id")
+	username := ctx.Param("username")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.RemoveUserFromGroup(groupName, username)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(ctx, http.StatusOK, true)
+}
@@ -0,0 +1,169 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	guardDutyReq "gitea/pcp-inariam/inariam/core/services/api/requests/aws/guardduty"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	guardDutyResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/guardduty"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+// retrieveGuardDutySvc opens an AWS session and its GuardDuty service.
+func (awsHandler *Handler) retrieveGuardDutySvc() (*inaAws.GuardDutySvc, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession.CreateGuarddutySvc()
+	return awsSession.GuarddutySvc, nil
+}
+
+func guardDutyFindingResponse(finding inaAws.GuardDutyFinding) guardDutyResp.FindingResponse {
+	return guardDutyResp.FindingResponse{
+		ID:            finding.ID,
+		DetectorID:    finding.DetectorID,
+		Arn:           finding.Arn,
+		AccountID:     finding.AccountID,
+		Region:        finding.Region,
+		Type:          finding.Type,
+		Title:         finding.Title,
+		Description:   finding.Description,
+		Severity:      finding.Severity,
+		SeverityLabel: finding.SeverityLabel,
+		ResourceType:  finding.ResourceType,
+		Count:         finding.Count,
+		Archived:      finding.Archived,
+		CreatedAt:     finding.CreatedAt,
+		UpdatedAt:     finding.UpdatedAt,
+	}
+}
+
+// ListGuardDutyFindings @Summary List GuardDuty Findings
+// @Description Get a page of the findings of every GuardDuty detector of the region. The severity, type, update
+// @Description time and archived criteria are applied by GuardDuty, the filter on the listed findings.
+// @ID aws-guardduty-findings-list
+// @Param min_severity query int false "Lowest severity score, inclusive"
+// @Param max_severity query int false "Highest severity score, exclusive"
+// @Param type query []string false "Finding types" collectionFormat(multi)
+// @Param updated_after query string false "RFC 3339 time the findings were last updated after"
+// @Param updated_before query string false "RFC 3339 time the findings were last updated before"
+// @Param archived query bool false "List the archived findings instead of the active ones"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the finding title, type or resource type"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(severity, -severity, updated_at, -updated_at)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]guardDutyResp.FindingResponse}
+// @Router /aws/guardduty/findings [get]
+func (awsHandler *Handler) ListGuardDutyFindings(c echo.Context) error {
+	listFindingsRequest := guardDutyReq.ListFindingsRequest{}
+
+	if err := c.Bind(&listFindingsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := listFindingsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	guardDutySvc, err := awsHandler.retrieveGuardDutySvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	filter := inaAws.GuardDutyFindingFilter{
+		MinSeverity:   listFindingsRequest.MinSeverity,
+		MaxSeverity:   listFindingsRequest.MaxSeverity,
+		Types:         listFindingsRequest.Types,
+		UpdatedAfter:  listFindingsRequest.UpdatedAfterTime(),
+		UpdatedBefore: listFindingsRequest.UpdatedBeforeTime(),
+		Archived:      listFindingsRequest.Archived,
+	}
+
+	page, err := pagination.Lister[inaAws.GuardDutyFinding]{
+		Fetch: func(marker string, pageSize int64) ([]inaAws.GuardDutyFinding, string, error) {
+			return guardDutySvc.ListFindings(filter, marker, pageSize)
+		},
+		Match: func(finding inaAws.GuardDutyFinding) (bool, error) {
+			return pagination.Contains(params.Filter, finding.Title, finding.Type, finding.ResourceType), nil
+		},
+		Less: map[string]func(a, b inaAws.GuardDutyFinding) bool{
+			"severity": func(a, b inaAws.GuardDutyFinding) bool {
+				return a.Severity < b.Severity
+			},
+			"updated_at": func(a, b inaAws.GuardDutyFinding) bool {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	findingsList := make([]guardDutyResp.FindingResponse, 0, len(page.Items))
+	for _, finding := range page.Items {
+		findingsList = append(findingsList, guardDutyFindingResponse(finding))
+	}
+
+	return responses.ListResponse(c, findingsList, page.NextCursor)
+}
+
+// ArchiveGuardDutyFindings @Summary Archive GuardDuty Findings
+// @Description Archive findings of a GuardDuty detector
+// @ID aws-guardduty-findings-archive
+// @Accept json
+// @Produce json
+// @Param body body guardDutyReq.ArchiveFindingsRequest true "Findings"
+// @Success 200 {boolean} boolean
+// @Router /aws/guardduty/findings/archive [post]
+func (awsHandler *Handler) ArchiveGuardDutyFindings(c echo.Context) error {
+	return awsHandler.updateGuardDutyFindings(c, (*inaAws.GuardDutySvc).ArchiveFindings)
+}
+
+// UnarchiveGuardDutyFindings @Summary Unarchive GuardDuty Findings
+// @Description Restore archived findings of a GuardDuty detector
+// @ID aws-guardduty-findings-unarchive
+// @Accept json
+// @Produce json
+// @Param body body guardDutyReq.ArchiveFindingsRequest true "Findings"
+// @Success 200 {boolean} boolean
+// @Router /aws/guardduty/findings/unarchive [post]
+func (awsHandler *Handler) UnarchiveGuardDutyFindings(c echo.Context) error {
+	return awsHandler.updateGuardDutyFindings(c, (*inaAws.GuardDutySvc).UnarchiveFindings)
+}
+
+func (awsHandler *Handler) updateGuardDutyFindings(c echo.Context,
+	update func(guardDutySvc *inaAws.GuardDutySvc, detectorID string, findingIds []string) error,
+) error {
+	archiveFindingsRequest := guardDutyReq.ArchiveFindingsRequest{}
+
+	if err := c.Bind(&archiveFindingsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := archiveFindingsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	guardDutySvc, err := awsHandler.retrieveGuardDutySvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = update(guardDutySvc, archiveFindingsRequest.DetectorID, archiveFindingsRequest.FindingIds)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
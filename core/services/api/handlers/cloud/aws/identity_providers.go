@@ -0,0 +1,423 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// oidcProviderResponse converts an IAM OpenID Connect identity provider to its HTTP representation.
+func oidcProviderResponse(provider *iam.OIDCProvider) resp.OIDCProviderResponse {
+	return resp.OIDCProviderResponse{
+		Arn:         provider.Arn,
+		URL:         provider.URL,
+		ClientIDs:   provider.ClientIDs,
+		Thumbprints: provider.Thumbprints,
+		CreateDate:  provider.CreateDate,
+		Tags:        provider.Tags,
+	}
+}
+
+// samlProviderResponse converts an IAM SAML 2.0 identity provider to its HTTP representation.
+func samlProviderResponse(provider *iam.SAMLProvider) resp.SAMLProviderResponse {
+	return resp.SAMLProviderResponse{
+		Arn:              provider.Arn,
+		Name:             provider.Name,
+		MetadataDocument: provider.MetadataDocument,
+		CreateDate:       provider.CreateDate,
+		ValidUntil:       provider.ValidUntil,
+		Tags:             provider.Tags,
+	}
+}
+
+// ListOIDCProviders @Summary List OIDC Providers
+// @Description Get a page of the IAM OpenID Connect identity providers of the account
+// @ID aws-oidc-providers-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the provider URL or ARN"
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.OIDCProviderResponse}
+// @Router /aws/iam/oidc-providers [get]
+func (awsHandler *Handler) ListOIDCProviders(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	providers, err := awsSession.IamSvc.ListOpenIDConnectProviders()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[*iam.OIDCProvider]{
+		Fetch: pagination.Single(providers),
+		Match: func(provider *iam.OIDCProvider) (bool, error) {
+			return pagination.Contains(params.Filter, provider.URL, provider.Arn), nil
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	providersList := make([]resp.OIDCProviderResponse, 0, len(page.Items))
+	for _, provider := range page.Items {
+		providersList = append(providersList, oidcProviderResponse(provider))
+	}
+
+	return responses.ListResponse(c, providersList, page.NextCursor)
+}
+
+// GetOIDCProvider @Summary Get OIDC Provider
+// @Description Get an IAM OpenID Connect identity provider
+// @ID aws-oidc-provider-get
+// @Param arn path string true "URL-encoded OIDC Provider ARN"
+// @Produce json
+// @Success 200 {object} resp.OIDCProviderResponse
+// @Router /aws/iam/oidc-providers/{arn} [get]
+func (awsHandler *Handler) GetOIDCProvider(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	provider, err := awsSession.IamSvc.GetOpenIDConnectProvider(arnParam(c))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, oidcProviderResponse(provider))
+}
+
+// CreateOIDCProvider @Summary Create OIDC Provider
+// @Description Create an IAM OpenID Connect identity provider, such as https://token.actions.githubusercontent.com
+// @ID aws-oidc-provider-create
+// @Accept json
+// @Produce json
+// @Param body body req.CreateOIDCProviderRequest true "OIDC provider"
+// @Success 200 {object} resp.OIDCProviderResponse
+// @Router /aws/iam/oidc-providers [post]
+func (awsHandler *Handler) CreateOIDCProvider(c echo.Context) error {
+	createOIDCProviderRequest := req.CreateOIDCProviderRequest{}
+
+	if err := c.Bind(&createOIDCProviderRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createOIDCProviderRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	provider, err := awsSession.IamSvc.CreateOpenIDConnectProvider(createOIDCProviderRequest.URL,
+		createOIDCProviderRequest.ClientIDs, createOIDCProviderRequest.Thumbprints, createOIDCProviderRequest.Tags)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, oidcProviderResponse(provider))
+}
+
+// UpdateOIDCProvider @Summary Update OIDC Provider
+// @Description Replace the client IDs or the thumbprints of an IAM OpenID Connect identity provider.
+// @Description Omitted lists are left unchanged.
+// @ID aws-oidc-provider-update
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL-encoded OIDC Provider ARN"
+// @Param body body req.UpdateOIDCProviderRequest true "OIDC provider update"
+// @Success 200 {object} resp.OIDCProviderResponse
+// @Router /aws/iam/oidc-providers/{arn} [put]
+func (awsHandler *Handler) UpdateOIDCProvider(c echo.Context) error {
+	updateOIDCProviderRequest := req.UpdateOIDCProviderRequest{}
+
+	if err := c.Bind(&updateOIDCProviderRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateOIDCProviderRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	provider, err := awsSession.IamSvc.UpdateOpenIDConnectProvider(arnParam(c), updateOIDCProviderRequest.ClientIDs,
+		updateOIDCProviderRequest.Thumbprints)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, oidcProviderResponse(provider))
+}
+
+// DeleteOIDCProvider @Summary Delete OIDC Provider
+// @Description Delete an IAM OpenID Connect identity provider
+// @ID aws-oidc-provider-delete
+// @Param arn path string true "URL-encoded OIDC Provider ARN"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/oidc-providers/{arn} [delete]
+func (awsHandler *Handler) DeleteOIDCProvider(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = awsSession.IamSvc.DeleteOpenIDConnectProvider(arnParam(c)); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListSAMLProviders @Summary List SAML Providers
+// @Description Get a page of the IAM SAML 2.0 identity providers of the account
+// @ID aws-saml-providers-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the provider name or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, valid_until, -valid_until)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.SAMLProviderResponse}
+// @Router /aws/iam/saml-providers [get]
+func (awsHandler *Handler) ListSAMLProviders(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	providers, err := awsSession.IamSvc.ListSAMLProviders()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[*iam.SAMLProvider]{
+		Fetch: pagination.Single(providers),
+		Match: func(provider *iam.SAMLProvider) (bool, error) {
+			return pagination.Contains(params.Filter, provider.Name, provider.Arn), nil
+		},
+		Less: map[string]func(a, b *iam.SAMLProvider) bool{
+			"name": func(a, b *iam.SAMLProvider) bool {
+				return a.Name < b.Name
+			},
+			"valid_until": func(a, b *iam.SAMLProvider) bool {
+				return a.ValidUntil.Before(b.ValidUntil)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	providersList := make([]resp.SAMLProviderResponse, 0, len(page.Items))
+	for _, provider := range page.Items {
+		providersList = append(providersList, samlProviderResponse(provider))
+	}
+
+	return responses.ListResponse(c, providersList, page.NextCursor)
+}
+
+// GetSAMLProvider @Summary Get SAML Provider
+// @Description Get an IAM SAML 2.0 identity provider along with its metadata document
+// @ID aws-saml-provider-get
+// @Param arn path string true "URL-encoded SAML Provider ARN"
+// @Produce json
+// @Success 200 {object} resp.SAMLProviderResponse
+// @Router /aws/iam/saml-providers/{arn} [get]
+func (awsHandler *Handler) GetSAMLProvider(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	provider, err := awsSession.IamSvc.GetSAMLProvider(arnParam(c))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, samlProviderResponse(provider))
+}
+
+// CreateSAMLProvider @Summary Create SAML Provider
+// @Description Create an IAM SAML 2.0 identity provider from the metadata document of the IdP
+// @ID aws-saml-provider-create
+// @Accept json
+// @Produce json
+// @Param body body req.CreateSAMLProviderRequest true "SAML provider"
+// @Success 200 {object} resp.SAMLProviderResponse
+// @Router /aws/iam/saml-providers [post]
+func (awsHandler *Handler) CreateSAMLProvider(c echo.Context) error {
+	createSAMLProviderRequest := req.CreateSAMLProviderRequest{}
+
+	if err := c.Bind(&createSAMLProviderRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createSAMLProviderRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	provider, err := awsSession.IamSvc.CreateSAMLProvider(createSAMLProviderRequest.Name,
+		createSAMLProviderRequest.MetadataDocument, createSAMLProviderRequest.Tags)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, samlProviderResponse(provider))
+}
+
+// UpdateSAMLProvider @Summary Update SAML Provider
+// @Description Replace the metadata document of an IAM SAML 2.0 identity provider
+// @ID aws-saml-provider-update
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL-encoded SAML Provider ARN"
+// @Param body body req.UpdateSAMLProviderRequest true "Metadata document"
+// @Success 200 {object} resp.SAMLProviderResponse
+// @Router /aws/iam/saml-providers/{arn} [put]
+func (awsHandler *Handler) UpdateSAMLProvider(c echo.Context) error {
+	updateSAMLProviderRequest := req.UpdateSAMLProviderRequest{}
+
+	if err := c.Bind(&updateSAMLProviderRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateSAMLProviderRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	provider, err := awsSession.IamSvc.UpdateSAMLProvider(arnParam(c), updateSAMLProviderRequest.MetadataDocument)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, samlProviderResponse(provider))
+}
+
+// DeleteSAMLProvider @Summary Delete SAML Provider
+// @Description Delete an IAM SAML 2.0 identity provider
+// @ID aws-saml-provider-delete
+// @Param arn path string true "URL-encoded SAML Provider ARN"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/saml-providers/{arn} [delete]
+func (awsHandler *Handler) DeleteSAMLProvider(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = awsSession.IamSvc.DeleteSAMLProvider(arnParam(c)); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// CreateGitHubActionsRole @Summary Create GitHub Actions Role
+// @Description Create an IAM role assumable through AssumeRoleWithWebIdentity by the GitHub Actions workflows
+// @Description of a repository running on a branch. The trust policy is generated for the GitHub Actions OIDC
+// @Description provider, which must exist.
+// @ID aws-role-github-actions-create
+// @Accept json
+// @Produce json
+// @Param body body req.CreateGitHubActionsRoleRequest true "GitHub Actions role"
+// @Success 200 {object} resp.GitHubActionsRoleResponse
+// @Router /aws/iam/roles/github-actions [post]
+func (awsHandler *Handler) CreateGitHubActionsRole(c echo.Context) error {
+	createGitHubActionsRoleRequest := req.CreateGitHubActionsRoleRequest{}
+
+	if err := c.Bind(&createGitHubActionsRoleRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createGitHubActionsRoleRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	providerArn := createGitHubActionsRoleRequest.ProviderArn
+	if providerArn == "" {
+		providerArn, err = awsSession.IamSvc.GitHubActionsProviderArn()
+		if err != nil {
+			return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+	}
+
+	provider, err := awsSession.IamSvc.GetOpenIDConnectProvider(providerArn)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	trustPolicy, err := iam.GitHubActionsTrustPolicy(provider.Arn, createGitHubActionsRoleRequest.Repository,
+		createGitHubActionsRoleRequest.Branch)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	trustPolicyJSON, err := json.Marshal(trustPolicy)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, resp.ErrorConvertingTrustPolicyToJSON)
+	}
+
+	boundaryArn, err := awsHandler.checkRolePermissionsBoundary(awsSession,
+		createGitHubActionsRoleRequest.PermissionsBoundary)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	createdRole, err := awsSession.IamSvc.CreateIAMRole(createGitHubActionsRoleRequest.RoleName,
+		string(trustPolicyJSON), iam.RoleOptions{
+			Path:                createGitHubActionsRoleRequest.Path,
+			Description:         createGitHubActionsRoleRequest.Description,
+			MaxSessionDuration:  createGitHubActionsRoleRequest.MaxSessionDuration,
+			PermissionsBoundary: boundaryArn,
+			Tags:                createGitHubActionsRoleRequest.Tags,
+		})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.GitHubActionsRoleResponse{
+		Role:        roleDetailResponse(createdRole),
+		TrustPolicy: trustPolicyJSON,
+	})
+}
@@ -0,0 +1,409 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// inlinePolicyResponse converts an inline policy to its HTTP representation.
+func inlinePolicyResponse(policy *iam.InlinePolicy) resp.InlinePolicyResponse {
+	return resp.InlinePolicyResponse{
+		PrincipalName: policy.PrincipalName,
+		PolicyName:    policy.PolicyName,
+		Document:      json.RawMessage(policy.Document),
+	}
+}
+
+// bindPutInlinePolicyRequest binds and validates a PutInlinePolicyRequest.
+func bindPutInlinePolicyRequest(c echo.Context) (*req.PutInlinePolicyRequest, error) {
+	putInlinePolicyRequest := req.PutInlinePolicyRequest{}
+
+	if err := c.Bind(&putInlinePolicyRequest); err != nil {
+		return nil, err
+	}
+
+	if err := putInlinePolicyRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &putInlinePolicyRequest, nil
+}
+
+// inlinePoliciesListResponse writes the page of inline policy names selected by the request parameters.
+func inlinePoliciesListResponse(c echo.Context, params pagination.Params, policyNames []string) error {
+	page, err := pagination.Lister[string]{
+		Fetch: pagination.Single(policyNames),
+		Match: func(policyName string) (bool, error) {
+			return pagination.Contains(params.Filter, policyName), nil
+		},
+		Less: map[string]func(a, b string) bool{
+			"name": func(a, b string) bool {
+				return a < b
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.ListResponse(c, page.Items, page.NextCursor)
+}
+
+// ListUserInlinePolicies @Summary List User Inline Policies
+// @Description Get a page of the names of the inline policies embedded in an IAM user
+// @ID aws-user-inline-policies-list
+// @Param id path string true "Username"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the policy name"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]string}
+// @Router /aws/iam/users/{id}/inline-policies [get]
+func (awsHandler *Handler) ListUserInlinePolicies(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	username := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyNames, err := awsSession.IamSvc.ListUserInlinePolicies(username)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return inlinePoliciesListResponse(c, params, policyNames)
+}
+
+// GetUserInlinePolicy @Summary Get User Inline Policy
+// @Description Get an inline policy embedded in an IAM user
+// @ID aws-user-inline-policy-get
+// @Param id path string true "Username"
+// @Param name path string true "Policy Name"
+// @Produce json
+// @Success 200 {object} resp.InlinePolicyResponse
+// @Router /aws/iam/users/{id}/inline-policies/{name} [get]
+func (awsHandler *Handler) GetUserInlinePolicy(c echo.Context) error {
+	username := c.Param("id")
+	policyName := c.Param("name")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := awsSession.IamSvc.GetUserInlinePolicy(username, policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, inlinePolicyResponse(policy))
+}
+
+// PutUserInlinePolicy @Summary Put User Inline Policy
+// @Description Create or replace an inline policy embedded in an IAM user
+// @ID aws-user-inline-policy-put
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param name path string true "Policy Name"
+// @Param body body req.PutInlinePolicyRequest true "Policy document"
+// @Success 200 {object} resp.InlinePolicyResponse
+// @Router /aws/iam/users/{id}/inline-policies/{name} [put]
+func (awsHandler *Handler) PutUserInlinePolicy(c echo.Context) error {
+	putInlinePolicyRequest, err := bindPutInlinePolicyRequest(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.PutUserInlinePolicy(putInlinePolicyRequest.PrincipalName, putInlinePolicyRequest.PolicyName,
+		putInlinePolicyRequest.PolicyDocument)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	policy, err := awsSession.IamSvc.GetUserInlinePolicy(putInlinePolicyRequest.PrincipalName, putInlinePolicyRequest.PolicyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, inlinePolicyResponse(policy))
+}
+
+// DeleteUserInlinePolicy @Summary Delete User Inline Policy
+// @Description Delete an inline policy embedded in an IAM user
+// @ID aws-user-inline-policy-delete
+// @Param id path string true "Username"
+// @Param name path string true "Policy Name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/inline-policies/{name} [delete]
+func (awsHandler *Handler) DeleteUserInlinePolicy(c echo.Context) error {
+	username := c.Param("id")
+	policyName := c.Param("name")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeleteUserInlinePolicy(username, policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListGroupInlinePolicies @Summary List Group Inline Policies
+// @Description Get a page of the names of the inline policies embedded in an IAM group
+// @ID aws-group-inline-policies-list
+// @Param id path string true "Group Name"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the policy name"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]string}
+// @Router /aws/iam/groups/{id}/inline-policies [get]
+func (awsHandler *Handler) ListGroupInlinePolicies(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	groupName := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyNames, err := awsSession.IamSvc.ListGroupInlinePolicies(groupName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return inlinePoliciesListResponse(c, params, policyNames)
+}
+
+// GetGroupInlinePolicy @Summary Get Group Inline Policy
+// @Description Get an inline policy embedded in an IAM group
+// @ID aws-group-inline-policy-get
+// @Param id path string true "Group Name"
+// @Param name path string true "Policy Name"
+// @Produce json
+// @Success 200 {object} resp.InlinePolicyResponse
+// @Router /aws/iam/groups/{id}/inline-policies/{name} [get]
+func (awsHandler *Handler) GetGroupInlinePolicy(c echo.Context) error {
+	groupName := c.Param("id")
+	policyName := c.Param("name")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := awsSession.IamSvc.GetGroupInlinePolicy(groupName, policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, inlinePolicyResponse(policy))
+}
+
+// PutGroupInlinePolicy @Summary Put Group Inline Policy
+// @Description Create or replace an inline policy embedded in an IAM group
+// @ID aws-group-inline-policy-put
+// @Accept json
+// @Produce json
+// @Param id path string true "Group Name"
+// @Param name path string true "Policy Name"
+// @Param body body req.PutInlinePolicyRequest true "Policy document"
+// @Success 200 {object} resp.InlinePolicyResponse
+// @Router /aws/iam/groups/{id}/inline-policies/{name} [put]
+func (awsHandler *Handler) PutGroupInlinePolicy(c echo.Context) error {
+	putInlinePolicyRequest, err := bindPutInlinePolicyRequest(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.PutGroupInlinePolicy(putInlinePolicyRequest.PrincipalName, putInlinePolicyRequest.PolicyName,
+		putInlinePolicyRequest.PolicyDocument)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	policy, err := awsSession.IamSvc.GetGroupInlinePolicy(putInlinePolicyRequest.PrincipalName, putInlinePolicyRequest.PolicyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, inlinePolicyResponse(policy))
+}
+
+// DeleteGroupInlinePolicy @Summary Delete Group Inline Policy
+// @Description Delete an inline policy embedded in an IAM group
+// @ID aws-group-inline-policy-delete
+// @Param id path string true "Group Name"
+// @Param name path string true "Policy Name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/groups/{id}/inline-policies/{name} [delete]
+func (awsHandler *Handler) DeleteGroupInlinePolicy(c echo.Context) error {
+	groupName := c.Param("id")
+	policyName := c.Param("name")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeleteGroupInlinePolicy(groupName, policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListRoleInlinePolicies @Summary List Role Inline Policies
+// @Description Get a page of the names of the inline policies embedded in an IAM role
+// @ID aws-role-inline-policies-list
+// @Param id path string true "Role Name"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the policy name"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]string}
+// @Router /aws/iam/roles/{id}/inline-policies [get]
+func (awsHandler *Handler) ListRoleInlinePolicies(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	roleName := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyNames, err := awsSession.IamSvc.ListRoleInlinePolicies(roleName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return inlinePoliciesListResponse(c, params, policyNames)
+}
+
+// GetRoleInlinePolicy @Summary Get Role Inline Policy
+// @Description Get an inline policy embedded in an IAM role
+// @ID aws-role-inline-policy-get
+// @Param id path string true "Role Name"
+// @Param name path string true "Policy Name"
+// @Produce json
+// @Success 200 {object} resp.InlinePolicyResponse
+// @Router /aws/iam/roles/{id}/inline-policies/{name} [get]
+func (awsHandler *Handler) GetRoleInlinePolicy(c echo.Context) error {
+	roleName := c.Param("id")
+	policyName := c.Param("name")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := awsSession.IamSvc.GetRoleInlinePolicy(roleName, policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, inlinePolicyResponse(policy))
+}
+
+// PutRoleInlinePolicy @Summary Put Role Inline Policy
+// @Description Create or replace an inline policy embedded in an IAM role
+// @ID aws-role-inline-policy-put
+// @Accept json
+// @Produce json
+// @Param id path string true "Role Name"
+// @Param name path string true "Policy Name"
+// @Param body body req.PutInlinePolicyRequest true "Policy document"
+// @Success 200 {object} resp.InlinePolicyResponse
+// @Router /aws/iam/roles/{id}/inline-policies/{name} [put]
+func (awsHandler *Handler) PutRoleInlinePolicy(c echo.Context) error {
+	putInlinePolicyRequest, err := bindPutInlinePolicyRequest(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.PutRoleInlinePolicy(putInlinePolicyRequest.PrincipalName, putInlinePolicyRequest.PolicyName,
+		putInlinePolicyRequest.PolicyDocument)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	policy, err := awsSession.IamSvc.GetRoleInlinePolicy(putInlinePolicyRequest.PrincipalName, putInlinePolicyRequest.PolicyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, inlinePolicyResponse(policy))
+}
+
+// DeleteRoleInlinePolicy @Summary Delete Role Inline Policy
+// @Description Delete an inline policy embedded in an IAM role
+// @ID aws-role-inline-policy-delete
+// @Param id path string true "Role Name"
+// @Param name path string true "Policy Name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/roles/{id}/inline-policies/{name} [delete]
+func (awsHandler *Handler) DeleteRoleInlinePolicy(c echo.Context) error {
+	roleName := c.Param("id")
+	policyName := c.Param("name")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeleteRoleInlinePolicy(roleName, policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
@@ -0,0 +1,264 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// instanceProfileResponse converts an IAM instance profile to its HTTP representation.
+func instanceProfileResponse(instanceProfile *awsIam.InstanceProfile) resp.InstanceProfileResponse {
+	roles := make([]string, 0, len(instanceProfile.Roles))
+	for _, role := range instanceProfile.Roles {
+		roles = append(roles, aws.StringValue(role.RoleName))
+	}
+
+	return resp.InstanceProfileResponse{
+		Name:       aws.StringValue(instanceProfile.InstanceProfileName),
+		ID:         aws.StringValue(instanceProfile.InstanceProfileId),
+		Arn:        aws.StringValue(instanceProfile.Arn),
+		Path:       aws.StringValue(instanceProfile.Path),
+		CreateDate: aws.TimeValue(instanceProfile.CreateDate),
+		Roles:      roles,
+		Tags:       iam.FromIamTags(instanceProfile.Tags),
+	}
+}
+
+// instanceProfilesResponse converts IAM instance profiles to their HTTP representation.
+func instanceProfilesResponse(instanceProfiles []*awsIam.InstanceProfile) []resp.InstanceProfileResponse {
+	instanceProfilesList := make([]resp.InstanceProfileResponse, 0, len(instanceProfiles))
+	for _, instanceProfile := range instanceProfiles {
+		instanceProfilesList = append(instanceProfilesList, instanceProfileResponse(instanceProfile))
+	}
+
+	return instanceProfilesList
+}
+
+// ListInstanceProfiles @Summary List Instance Profiles
+// @Description Get a page of IAM instance profiles and the role each one contains
+// @ID aws-instance-profiles-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the instance profile name, ID or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.InstanceProfileResponse}
+// @Router /aws/iam/instance-profiles [get]
+func (awsHandler *Handler) ListInstanceProfiles(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	page, err := pagination.Lister[*awsIam.InstanceProfile]{
+		Fetch: awsSession.IamSvc.ListInstanceProfilesPage,
+		Match: func(instanceProfile *awsIam.InstanceProfile) (bool, error) {
+			return pagination.Contains(params.Filter, aws.StringValue(instanceProfile.InstanceProfileName),
+				aws.StringValue(instanceProfile.InstanceProfileId), aws.StringValue(instanceProfile.Arn)), nil
+		},
+		Less: map[string]func(a, b *awsIam.InstanceProfile) bool{
+			"name": func(a, b *awsIam.InstanceProfile) bool {
+				return aws.StringValue(a.InstanceProfileName) < aws.StringValue(b.InstanceProfileName)
+			},
+			"created_date": func(a, b *awsIam.InstanceProfile) bool {
+				return aws.TimeValue(a.CreateDate).Before(aws.TimeValue(b.CreateDate))
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.ListResponse(c, instanceProfilesResponse(page.Items), page.NextCursor)
+}
+
+// GetInstanceProfile @Summary Get Instance Profile
+// @Description Get an IAM instance profile by name
+// @ID aws-instance-profile-get
+// @Param id path string true "Instance Profile Name"
+// @Produce json
+// @Success 200 {object} resp.InstanceProfileResponse
+// @Router /aws/iam/instance-profiles/{id} [get]
+func (awsHandler *Handler) GetInstanceProfile(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceProfile, err := awsSession.IamSvc.GetInstanceProfile(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, instanceProfileResponse(instanceProfile))
+}
+
+// CreateInstanceProfile @Summary Create Instance Profile
+// @Description Create an IAM instance profile, optionally holding a role
+// @ID aws-instance-profile-create
+// @Accept json
+// @Produce json
+// @Param body body req.CreateInstanceProfileRequest true "Instance profile"
+// @Success 200 {object} resp.InstanceProfileResponse
+// @Router /aws/iam/instance-profiles [post]
+func (awsHandler *Handler) CreateInstanceProfile(c echo.Context) error {
+	createInstanceProfileRequest := req.CreateInstanceProfileRequest{}
+
+	if err := c.Bind(&createInstanceProfileRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createInstanceProfileRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceProfile, err := awsSession.IamSvc.CreateInstanceProfile(createInstanceProfileRequest.Name,
+		createInstanceProfileRequest.Path, createInstanceProfileRequest.Tags)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if createInstanceProfileRequest.RoleName != "" {
+		err = awsSession.IamSvc.AddRoleToInstanceProfile(createInstanceProfileRequest.Name,
+			createInstanceProfileRequest.RoleName)
+		if err != nil {
+			return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+
+		instanceProfile, err = awsSession.IamSvc.GetInstanceProfile(createInstanceProfileRequest.Name)
+		if err != nil {
+			return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+	}
+
+	return responses.Response(c, http.StatusOK, instanceProfileResponse(instanceProfile))
+}
+
+// DeleteInstanceProfile @Summary Delete Instance Profile
+// @Description Delete an IAM instance profile after removing the role it contains
+// @ID aws-instance-profile-delete
+// @Param id path string true "Instance Profile Name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/instance-profiles/{id} [delete]
+func (awsHandler *Handler) DeleteInstanceProfile(c echo.Context) error {
+	name := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceProfile, err := awsSession.IamSvc.GetInstanceProfile(name)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	for _, role := range instanceProfile.Roles {
+		err = awsSession.IamSvc.RemoveRoleFromInstanceProfile(name, aws.StringValue(role.RoleName))
+		if err != nil {
+			return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := awsSession.IamSvc.DeleteInstanceProfile(name); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// AddRoleToInstanceProfile @Summary Add Role To Instance Profile
+// @Description Add an IAM role to an instance profile. An instance profile holds at most one role.
+// @ID aws-instance-profile-role-add
+// @Param id path string true "Instance Profile Name"
+// @Param role path string true "Role Name"
+// @Produce json
+// @Success 200 {object} resp.InstanceProfileResponse
+// @Router /aws/iam/instance-profiles/{id}/roles/{role} [put]
+func (awsHandler *Handler) AddRoleToInstanceProfile(c echo.Context) error {
+	name := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err := awsSession.IamSvc.AddRoleToInstanceProfile(name, c.Param("role")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	instanceProfile, err := awsSession.IamSvc.GetInstanceProfile(name)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, instanceProfileResponse(instanceProfile))
+}
+
+// RemoveRoleFromInstanceProfile @Summary Remove Role From Instance Profile
+// @Description Remove an IAM role from an instance profile
+// @ID aws-instance-profile-role-remove
+// @Param id path string true "Instance Profile Name"
+// @Param role path string true "Role Name"
+// @Produce json
+// @Success 200 {object} resp.InstanceProfileResponse
+// @Router /aws/iam/instance-profiles/{id}/roles/{role} [delete]
+func (awsHandler *Handler) RemoveRoleFromInstanceProfile(c echo.Context) error {
+	name := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err := awsSession.IamSvc.RemoveRoleFromInstanceProfile(name, c.Param("role")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	instanceProfile, err := awsSession.IamSvc.GetInstanceProfile(name)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, instanceProfileResponse(instanceProfile))
+}
+
+// ListRoleInstanceProfiles @Summary List Role Instance Profiles
+// @Description Get the IAM instance profiles containing a role
+// @ID aws-role-instance-profiles-list
+// @Param id path string true "Role Name"
+// @Produce json
+// @Success 200 {array} resp.InstanceProfileResponse
+// @Router /aws/iam/roles/{id}/instance-profiles [get]
+func (awsHandler *Handler) ListRoleInstanceProfiles(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceProfiles, err := awsSession.IamSvc.ListInstanceProfilesForRole(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, instanceProfilesResponse(instanceProfiles))
+}
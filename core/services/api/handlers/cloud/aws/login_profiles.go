@@ -0,0 +1,236 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// loginProfileResponse converts a login profile to its HTTP representation.
+func loginProfileResponse(loginProfile *iam.LoginProfile) resp.LoginProfileResponse {
+	return resp.LoginProfileResponse{
+		Username:              loginProfile.Username,
+		PasswordResetRequired: loginProfile.PasswordResetRequired,
+		CreateDate:            loginProfile.CreateDate,
+		TemporaryPassword:     loginProfile.Password,
+	}
+}
+
+// passwordPolicyResponse converts an account password policy to its HTTP representation.
+func passwordPolicyResponse(policy *iam.PasswordPolicy) resp.PasswordPolicyResponse {
+	return resp.PasswordPolicyResponse{
+		MinimumPasswordLength:      policy.MinimumPasswordLength,
+		RequireSymbols:             policy.RequireSymbols,
+		RequireNumbers:             policy.RequireNumbers,
+		RequireUppercaseCharacters: policy.RequireUppercaseCharacters,
+		RequireLowercaseCharacters: policy.RequireLowercaseCharacters,
+		AllowUsersToChangePassword: policy.AllowUsersToChangePassword,
+		MaxPasswordAge:             policy.MaxPasswordAge,
+		PasswordReusePrevention:    policy.PasswordReusePrevention,
+		HardExpiry:                 policy.HardExpiry,
+	}
+}
+
+// GetLoginProfile @Summary Get User Login Profile
+// @Description Get the console access of an IAM user
+// @ID aws-user-login-profile-get
+// @Param id path string true "Username"
+// @Produce json
+// @Success 200 {object} resp.LoginProfileResponse
+// @Router /aws/iam/users/{id}/login-profile [get]
+func (awsHandler *Handler) GetLoginProfile(c echo.Context) error {
+	username := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	loginProfile, err := awsSession.IamSvc.GetLoginProfile(username)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, loginProfileResponse(loginProfile))
+}
+
+// CreateLoginProfile @Summary Create User Login Profile
+// @Description Give console access to an IAM user, a temporary password is generated when none is provided
+// @ID aws-user-login-profile-create
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.LoginProfileRequest false "Password settings"
+// @Success 201 {object} resp.LoginProfileResponse
+// @Router /aws/iam/users/{id}/login-profile [post]
+func (awsHandler *Handler) CreateLoginProfile(c echo.Context) error {
+	loginProfileRequest := req.LoginProfileRequest{}
+
+	if err := c.Bind(&loginProfileRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := loginProfileRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	loginProfile, err := awsSession.IamSvc.CreateLoginProfile(loginProfileRequest.Username, loginProfileRequest.Password,
+		loginProfileRequest.ResetRequired())
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusCreated, loginProfileResponse(loginProfile))
+}
+
+// UpdateLoginProfile @Summary Reset User Password
+// @Description Reset the console password of an IAM user, a temporary password is generated when none is provided
+// @ID aws-user-login-profile-update
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.LoginProfileRequest false "Password settings"
+// @Success 200 {object} resp.LoginProfileResponse
+// @Router /aws/iam/users/{id}/login-profile [put]
+func (awsHandler *Handler) UpdateLoginProfile(c echo.Context) error {
+	loginProfileRequest := req.LoginProfileRequest{}
+
+	if err := c.Bind(&loginProfileRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := loginProfileRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	loginProfile, err := awsSession.IamSvc.UpdateLoginProfile(loginProfileRequest.Username, loginProfileRequest.Password,
+		loginProfileRequest.ResetRequired())
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, loginProfileResponse(loginProfile))
+}
+
+// DeleteLoginProfile @Summary Delete User Login Profile
+// @Description Remove the console access of an IAM user
+// @ID aws-user-login-profile-delete
+// @Param id path string true "Username"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/login-profile [delete]
+func (awsHandler *Handler) DeleteLoginProfile(c echo.Context) error {
+	username := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeleteLoginProfile(username)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// GetPasswordPolicy @Summary Get Account Password Policy
+// @Description Get the password policy applied to the IAM users of the account
+// @ID aws-password-policy-get
+// @Produce json
+// @Success 200 {object} resp.PasswordPolicyResponse
+// @Router /aws/iam/password-policy [get]
+func (awsHandler *Handler) GetPasswordPolicy(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := awsSession.IamSvc.GetAccountPasswordPolicy()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	if policy == nil {
+		return responses.ErrorResponse(c, http.StatusNotFound, resp.HttpErrPasswordPolicyNotFound)
+	}
+
+	return responses.Response(c, http.StatusOK, passwordPolicyResponse(policy))
+}
+
+// UpdatePasswordPolicy @Summary Update Account Password Policy
+// @Description Replace the password policy applied to the IAM users of the account
+// @ID aws-password-policy-update
+// @Accept json
+// @Produce json
+// @Param body body req.UpdatePasswordPolicyRequest true "Password policy"
+// @Success 200 {object} resp.PasswordPolicyResponse
+// @Router /aws/iam/password-policy [put]
+func (awsHandler *Handler) UpdatePasswordPolicy(c echo.Context) error {
+	updatePasswordPolicyRequest := req.UpdatePasswordPolicyRequest{}
+
+	if err := c.Bind(&updatePasswordPolicyRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updatePasswordPolicyRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := awsSession.IamSvc.UpdateAccountPasswordPolicy(iam.PasswordPolicy{
+		MinimumPasswordLength:      updatePasswordPolicyRequest.MinimumPasswordLength,
+		RequireSymbols:             updatePasswordPolicyRequest.RequireSymbols,
+		RequireNumbers:             updatePasswordPolicyRequest.RequireNumbers,
+		RequireUppercaseCharacters: updatePasswordPolicyRequest.RequireUppercaseCharacters,
+		RequireLowercaseCharacters: updatePasswordPolicyRequest.RequireLowercaseCharacters,
+		AllowUsersToChangePassword: updatePasswordPolicyRequest.AllowUsersToChangePassword,
+		MaxPasswordAge:             updatePasswordPolicyRequest.MaxPasswordAge,
+		PasswordReusePrevention:    updatePasswordPolicyRequest.PasswordReusePrevention,
+		HardExpiry:                 updatePasswordPolicyRequest.HardExpiry,
+	})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, passwordPolicyResponse(policy))
+}
+
+// DeletePasswordPolicy @Summary Delete Account Password Policy
+// @Description Remove the custom password policy of the account, reverting to the AWS default
+// @ID aws-password-policy-delete
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/password-policy [delete]
+func (awsHandler *Handler) DeletePasswordPolicy(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeleteAccountPasswordPolicy()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
@@ -0,0 +1,210 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// ListMFADevices @Summary List User MFA Devices
+// @Description Get a page of the MFA devices assigned to an IAM user
+// @ID aws-user-mfa-list
+// @Param id path string true "Username"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the device serial number"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(enable_date, -enable_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.MFADeviceResponse}
+// @Router /aws/iam/users/{id}/mfa [get]
+func (awsHandler *Handler) ListMFADevices(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	username := c.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	devices, err := awsSession.IamSvc.ListMFADevices(username)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[iam.MFADevice]{
+		Fetch: pagination.Single(devices),
+		Match: func(device iam.MFADevice) (bool, error) {
+			return pagination.Contains(params.Filter, device.SerialNumber), nil
+		},
+		Less: map[string]func(a, b iam.MFADevice) bool{
+			"enable_date": func(a, b iam.MFADevice) bool {
+				return a.EnableDate.Before(b.EnableDate)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	devicesList := make([]resp.MFADeviceResponse, 0, len(page.Items))
+	for _, device := range page.Items {
+		devicesList = append(devicesList, resp.MFADeviceResponse{
+			SerialNumber: device.SerialNumber,
+			EnableDate:   device.EnableDate,
+			Virtual:      device.Virtual,
+		})
+	}
+
+	return responses.ListResponse(c, devicesList, page.NextCursor)
+}
+
+// CreateVirtualMFADevice @Summary Create User Virtual MFA Device
+// @Description Create a virtual MFA device and return its seed as a QR code, it must then be enabled
+// @ID aws-user-mfa-create
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.CreateVirtualMFADeviceRequest false "Device details"
+// @Success 201 {object} resp.VirtualMFADeviceResponse
+// @Router /aws/iam/users/{id}/mfa [post]
+func (awsHandler *Handler) CreateVirtualMFADevice(c echo.Context) error {
+	createRequest := req.CreateVirtualMFADeviceRequest{}
+
+	if err := c.Bind(&createRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if _, err = awsSession.IamSvc.GetIamUser(createRequest.Username); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	device, err := awsSession.IamSvc.CreateVirtualMFADevice(createRequest.Username, createRequest.DeviceName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusCreated, resp.VirtualMFADeviceResponse{
+		SerialNumber: device.SerialNumber,
+		Seed:         device.Seed,
+		ImageQrCode:  device.QrCode,
+	})
+}
+
+// EnableMFADevice @Summary Enable User MFA Device
+// @Description Assign an MFA device to an IAM user using two consecutive codes
+// @ID aws-user-mfa-enable
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.EnableMFADeviceRequest true "Device and codes"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/mfa/enable [post]
+func (awsHandler *Handler) EnableMFADevice(c echo.Context) error {
+	enableRequest := req.EnableMFADeviceRequest{}
+
+	if err := c.Bind(&enableRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := enableRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.EnableMFADevice(enableRequest.Username, enableRequest.SerialNumber,
+		enableRequest.AuthenticationCode1, enableRequest.AuthenticationCode2)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DeactivateMFADevice @Summary Deactivate User MFA Device
+// @Description Unassign an MFA device from an IAM user without deleting it
+// @ID aws-user-mfa-deactivate
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.MFADeviceRequest true "Device"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/mfa/deactivate [post]
+func (awsHandler *Handler) DeactivateMFADevice(c echo.Context) error {
+	mfaDeviceRequest := req.MFADeviceRequest{}
+
+	if err := c.Bind(&mfaDeviceRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := mfaDeviceRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.DeactivateMFADevice(mfaDeviceRequest.Username, mfaDeviceRequest.SerialNumber)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DeleteMFADevice @Summary Delete User Virtual MFA Device
+// @Description Deactivate a virtual MFA device if needed and delete it
+// @ID aws-user-mfa-delete
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.MFADeviceRequest true "Device"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/mfa [delete]
+func (awsHandler *Handler) DeleteMFADevice(c echo.Context) error {
+	mfaDeviceRequest := req.MFADeviceRequest{}
+
+	if err := c.Bind(&mfaDeviceRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := mfaDeviceRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	err = awsSession.IamSvc.RemoveVirtualMFADevice(mfaDeviceRequest.Username, mfaDeviceRequest.SerialNumber)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
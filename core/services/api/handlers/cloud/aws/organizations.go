@@ -0,0 +1,470 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	orgReq "gitea/pcp-inariam/inariam/core/services/api/requests/aws/organizations"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	orgResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/organizations"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+// retrieveOrganizationsSvc opens an AWS session and its Organizations service.
+func (awsHandler *Handler) retrieveOrganizationsSvc() (*inaAws.OrganizationsSvc, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession.CreateOrganizationsSvc()
+	return awsSession.OrganizationsSvc, nil
+}
+
+func accountResponse(account inaAws.OrganizationAccount) orgResp.AccountResponse {
+	return orgResp.AccountResponse{
+		ID:           account.ID,
+		Arn:          account.Arn,
+		Name:         account.Name,
+		Email:        account.Email,
+		Status:       account.Status,
+		JoinedMethod: account.JoinedMethod,
+		JoinedDate:   account.JoinedTimestamp,
+	}
+}
+
+func organizationalUnitResponse(unit *inaAws.OrganizationalUnit) orgResp.OrganizationalUnitResponse {
+	unitResponse := orgResp.OrganizationalUnitResponse{
+		ID:       unit.ID,
+		Arn:      unit.Arn,
+		Name:     unit.Name,
+		Type:     unit.Type,
+		Accounts: make([]orgResp.AccountResponse, 0, len(unit.Accounts)),
+		Children: make([]orgResp.OrganizationalUnitResponse, 0, len(unit.Children)),
+	}
+
+	for _, account := range unit.Accounts {
+		unitResponse.Accounts = append(unitResponse.Accounts, accountResponse(account))
+	}
+	for _, child := range unit.Children {
+		unitResponse.Children = append(unitResponse.Children, organizationalUnitResponse(child))
+	}
+
+	return unitResponse
+}
+
+func scpResponse(policy inaAws.ServiceControlPolicy) orgResp.ScpResponse {
+	policyResponse := orgResp.ScpResponse{
+		ID:          policy.ID,
+		Arn:         policy.Arn,
+		Name:        policy.Name,
+		Description: policy.Description,
+		AwsManaged:  policy.AwsManaged,
+	}
+	if policy.Content != nil {
+		policyResponse.Content, _ = json.Marshal(policy.Content)
+	}
+
+	return policyResponse
+}
+
+func scpsResponse(policies []inaAws.ServiceControlPolicy) []orgResp.ScpResponse {
+	policiesList := make([]orgResp.ScpResponse, 0, len(policies))
+	for _, policy := range policies {
+		policiesList = append(policiesList, scpResponse(policy))
+	}
+
+	return policiesList
+}
+
+func policyTargetResponse(target inaAws.PolicyTarget) orgResp.PolicyTargetResponse {
+	return orgResp.PolicyTargetResponse{
+		ID:   target.ID,
+		Arn:  target.Arn,
+		Name: target.Name,
+		Type: target.Type,
+	}
+}
+
+// GetOrganization @Summary Get Organization
+// @Description Get the AWS organization the account belongs to
+// @ID aws-organization-get
+// @Produce json
+// @Success 200 {object} orgResp.OrganizationResponse
+// @Router /aws/organizations [get]
+func (awsHandler *Handler) GetOrganization(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	organization, err := organizationsSvc.DescribeOrganization()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, orgResp.OrganizationResponse{
+		ID:                    organization.ID,
+		Arn:                   organization.Arn,
+		FeatureSet:            organization.FeatureSet,
+		ManagementAccountID:   organization.MasterAccountID,
+		ManagementAccountMail: organization.MasterAccountEmail,
+	})
+}
+
+// GetOrganizationTree @Summary Get Organization Tree
+// @Description Get the roots of the AWS organization with their organizational units and accounts
+// @ID aws-organization-tree
+// @Produce json
+// @Success 200 {array} orgResp.OrganizationalUnitResponse
+// @Router /aws/organizations/tree [get]
+func (awsHandler *Handler) GetOrganizationTree(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	roots, err := organizationsSvc.GetOrganizationTree()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	tree := make([]orgResp.OrganizationalUnitResponse, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, organizationalUnitResponse(root))
+	}
+
+	return responses.Response(c, http.StatusOK, tree)
+}
+
+// ListOrganizationAccounts @Summary List Organization Accounts
+// @Description Get a page of the accounts of the AWS organization
+// @ID aws-organization-accounts-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the account ID, name or email"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, joined_date, -joined_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]orgResp.AccountResponse}
+// @Router /aws/organizations/accounts [get]
+func (awsHandler *Handler) ListOrganizationAccounts(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	accounts, err := organizationsSvc.ListAccounts()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[inaAws.OrganizationAccount]{
+		Fetch: pagination.Single(accounts),
+		Match: func(account inaAws.OrganizationAccount) (bool, error) {
+			return pagination.Contains(params.Filter, account.ID, account.Name, account.Email), nil
+		},
+		Less: map[string]func(a, b inaAws.OrganizationAccount) bool{
+			"name": func(a, b inaAws.OrganizationAccount) bool {
+				return a.Name < b.Name
+			},
+			"joined_date": func(a, b inaAws.OrganizationAccount) bool {
+				return a.JoinedTimestamp.Before(b.JoinedTimestamp)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accountsList := make([]orgResp.AccountResponse, 0, len(page.Items))
+	for _, account := range page.Items {
+		accountsList = append(accountsList, accountResponse(account))
+	}
+
+	return responses.ListResponse(c, accountsList, page.NextCursor)
+}
+
+// GetOrganizationAccount @Summary Get Organization Account
+// @Description Get an account of the AWS organization
+// @ID aws-organization-account-get
+// @Param id path string true "Account ID"
+// @Produce json
+// @Success 200 {object} orgResp.AccountResponse
+// @Router /aws/organizations/accounts/{id} [get]
+func (awsHandler *Handler) GetOrganizationAccount(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	account, err := organizationsSvc.GetAccount(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, accountResponse(*account))
+}
+
+// GetEffectiveScps @Summary Get Effective SCPs
+// @Description Get the service control policies applying to an account, level by level from the root of the
+// @Description organization down to the account. An action is only allowed when every level allows it.
+// @ID aws-organization-account-effective-scps
+// @Param id path string true "Account ID"
+// @Produce json
+// @Success 200 {object} orgResp.EffectiveScpsResponse
+// @Router /aws/organizations/accounts/{id}/effective-scps [get]
+func (awsHandler *Handler) GetEffectiveScps(c echo.Context) error {
+	accountID := c.Param("id")
+
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	levels, err := organizationsSvc.GetEffectiveServiceControlPolicies(accountID)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	effectiveScps := orgResp.EffectiveScpsResponse{
+		AccountID: accountID,
+		Levels:    make([]orgResp.EffectiveScpLevelResponse, 0, len(levels)),
+	}
+	for _, level := range levels {
+		effectiveScps.Levels = append(effectiveScps.Levels, orgResp.EffectiveScpLevelResponse{
+			Target:   policyTargetResponse(level.Target),
+			Policies: scpsResponse(level.Policies),
+		})
+	}
+
+	return responses.Response(c, http.StatusOK, effectiveScps)
+}
+
+// ListScps @Summary List SCPs
+// @Description Get a page of the service control policies of the AWS organization
+// @ID aws-organization-scps-list
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the policy ID, name or description"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]orgResp.ScpResponse}
+// @Router /aws/organizations/scps [get]
+func (awsHandler *Handler) ListScps(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policies, err := organizationsSvc.ListServiceControlPolicies()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[inaAws.ServiceControlPolicy]{
+		Fetch: pagination.Single(policies),
+		Match: func(policy inaAws.ServiceControlPolicy) (bool, error) {
+			return pagination.Contains(params.Filter, policy.ID, policy.Name, policy.Description), nil
+		},
+		Less: map[string]func(a, b inaAws.ServiceControlPolicy) bool{
+			"name": func(a, b inaAws.ServiceControlPolicy) bool {
+				return a.Name < b.Name
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.ListResponse(c, scpsResponse(page.Items), page.NextCursor)
+}
+
+// GetScp @Summary Get SCP
+// @Description Get a service control policy along with its content
+// @ID aws-organization-scp-get
+// @Param id path string true "Policy ID"
+// @Produce json
+// @Success 200 {object} orgResp.ScpResponse
+// @Router /aws/organizations/scps/{id} [get]
+func (awsHandler *Handler) GetScp(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := organizationsSvc.GetServiceControlPolicy(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, scpResponse(*policy))
+}
+
+// CreateScp @Summary Create SCP
+// @Description Create a service control policy, detached from every target
+// @ID aws-organization-scp-create
+// @Accept json
+// @Produce json
+// @Param body body orgReq.CreateScpRequest true "Service control policy"
+// @Success 200 {object} orgResp.ScpResponse
+// @Router /aws/organizations/scps [post]
+func (awsHandler *Handler) CreateScp(c echo.Context) error {
+	createScpRequest := orgReq.CreateScpRequest{}
+
+	if err := c.Bind(&createScpRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createScpRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := organizationsSvc.CreateServiceControlPolicy(createScpRequest.Name, createScpRequest.Description,
+		createScpRequest.Content, createScpRequest.Tags)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, scpResponse(*policy))
+}
+
+// UpdateScp @Summary Update SCP
+// @Description Update the name, description or content of a service control policy.
+// @Description Omitted fields are left unchanged.
+// @ID aws-organization-scp-update
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Param body body orgReq.UpdateScpRequest true "Service control policy update"
+// @Success 200 {object} orgResp.ScpResponse
+// @Router /aws/organizations/scps/{id} [put]
+func (awsHandler *Handler) UpdateScp(c echo.Context) error {
+	updateScpRequest := orgReq.UpdateScpRequest{}
+
+	if err := c.Bind(&updateScpRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateScpRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policy, err := organizationsSvc.UpdateServiceControlPolicy(updateScpRequest.PolicyID, updateScpRequest.Name,
+		updateScpRequest.Description, updateScpRequest.Content)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, scpResponse(*policy))
+}
+
+// DeleteScp @Summary Delete SCP
+// @Description Delete a service control policy, which must be detached from every target
+// @ID aws-organization-scp-delete
+// @Param id path string true "Policy ID"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/organizations/scps/{id} [delete]
+func (awsHandler *Handler) DeleteScp(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = organizationsSvc.DeleteServiceControlPolicy(c.Param("id")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListScpTargets @Summary List SCP Targets
+// @Description Get the roots, organizational units and accounts a service control policy is attached to
+// @ID aws-organization-scp-targets-list
+// @Param id path string true "Policy ID"
+// @Produce json
+// @Success 200 {array} orgResp.PolicyTargetResponse
+// @Router /aws/organizations/scps/{id}/targets [get]
+func (awsHandler *Handler) ListScpTargets(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	targets, err := organizationsSvc.ListServiceControlPolicyTargets(c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	targetsList := make([]orgResp.PolicyTargetResponse, 0, len(targets))
+	for _, target := range targets {
+		targetsList = append(targetsList, policyTargetResponse(target))
+	}
+
+	return responses.Response(c, http.StatusOK, targetsList)
+}
+
+// AttachScp @Summary Attach SCP
+// @Description Attach a service control policy to a root, an organizational unit or an account
+// @ID aws-organization-scp-attach
+// @Param id path string true "Policy ID"
+// @Param target path string true "Root, organizational unit or account ID"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/organizations/scps/{id}/targets/{target} [put]
+func (awsHandler *Handler) AttachScp(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = organizationsSvc.AttachServiceControlPolicy(c.Param("id"), c.Param("target")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DetachScp @Summary Detach SCP
+// @Description Detach a service control policy from a root, an organizational unit or an account
+// @ID aws-organization-scp-detach
+// @Param id path string true "Policy ID"
+// @Param target path string true "Root, organizational unit or account ID"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/organizations/scps/{id}/targets/{target} [delete]
+func (awsHandler *Handler) DetachScp(c echo.Context) error {
+	organizationsSvc, err := awsHandler.retrieveOrganizationsSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err = organizationsSvc.DetachServiceControlPolicy(c.Param("id"), c.Param("target")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
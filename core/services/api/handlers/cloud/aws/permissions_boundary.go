@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// bindPutPermissionsBoundaryRequest binds and validates a PutPermissionsBoundaryRequest.
+func bindPutPermissionsBoundaryRequest(c echo.Context) (*req.PutPermissionsBoundaryRequest, error) {
+	putPermissionsBoundaryRequest := req.PutPermissionsBoundaryRequest{}
+
+	if err := c.Bind(&putPermissionsBoundaryRequest); err != nil {
+		return nil, err
+	}
+
+	if err := putPermissionsBoundaryRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &putPermissionsBoundaryRequest, nil
+}
+
+// checkRolePermissionsBoundary resolves the ARN of a role permissions boundary given by policy name or ARN and
+// checks it against the required role permissions boundary. An empty boundary stands for no boundary.
+func (awsHandler *Handler) checkRolePermissionsBoundary(awsSession *inaAws.Session, boundary string) (string, error) {
+	var err error
+
+	if boundary != "" {
+		boundary, err = awsSession.IamSvc.PolicyArn(boundary)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	required := awsHandler.requiredRolePermissionsBoundary()
+	if required != "" {
+		required, err = awsSession.IamSvc.PolicyArn(required)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err = iam.CheckRequiredPermissionsBoundary(required, boundary); err != nil {
+		return "", err
+	}
+
+	return boundary, nil
+}
+
+// PutUserPermissionsBoundary @Summary Put User Permissions Boundary
+// @Description Set or replace the permissions boundary of an IAM user
+// @ID aws-user-permissions-boundary-put
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.PutPermissionsBoundaryRequest true "Permissions boundary"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/permissions-boundary [put]
+func (awsHandler *Handler) PutUserPermissionsBoundary(c echo.Context) error {
+	putPermissionsBoundaryRequest, err := bindPutPermissionsBoundaryRequest(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	boundaryArn, err := awsSession.IamSvc.PolicyArn(putPermissionsBoundaryRequest.PermissionsBoundary)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	err = awsSession.IamSvc.PutUserPermissionsBoundary(c.Param("id"), boundaryArn)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DeleteUserPermissionsBoundary @Summary Delete User Permissions Boundary
+// @Description Remove the permissions boundary of an IAM user
+// @ID aws-user-permissions-boundary-delete
+// @Param id path string true "Username"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/permissions-boundary [delete]
+func (awsHandler *Handler) DeleteUserPermissionsBoundary(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err := awsSession.IamSvc.DeleteUserPermissionsBoundary(c.Param("id")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// PutRolePermissionsBoundary @Summary Put Role Permissions Boundary
+// @Description Set or replace the permissions boundary of an IAM role.
+// @Description When a required role permissions boundary is configured, only that boundary is accepted.
+// @ID aws-role-permissions-boundary-put
+// @Accept json
+// @Produce json
+// @Param id path string true "Role Name"
+// @Param body body req.PutPermissionsBoundaryRequest true "Permissions boundary"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/roles/{id}/permissions-boundary [put]
+func (awsHandler *Handler) PutRolePermissionsBoundary(c echo.Context) error {
+	putPermissionsBoundaryRequest, err := bindPutPermissionsBoundaryRequest(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	boundaryArn, err := awsHandler.checkRolePermissionsBoundary(awsSession,
+		putPermissionsBoundaryRequest.PermissionsBoundary)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	err = awsSession.IamSvc.PutRolePermissionsBoundary(c.Param("id"), boundaryArn)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DeleteRolePermissionsBoundary @Summary Delete Role Permissions Boundary
+// @Description Remove the permissions boundary of an IAM role.
+// @Description Refused when a required role permissions boundary is configured.
+// @ID aws-role-permissions-boundary-delete
+// @Param id path string true "Role Name"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/roles/{id}/permissions-boundary [delete]
+func (awsHandler *Handler) DeleteRolePermissionsBoundary(c echo.Context) error {
+	err := iam.CheckRequiredPermissionsBoundary(awsHandler.requiredRolePermissionsBoundary(), "")
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err := awsSession.IamSvc.DeleteRolePermissionsBoundary(c.Param("id")); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
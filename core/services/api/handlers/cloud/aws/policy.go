@@ -2,52 +2,120 @@ package aws
 
 import (
 	"net/http"
+	"net/url"
 
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
 	"github.com/labstack/echo/v4"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
 	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 )
 
+// arnParam returns the ARN path parameter of a policy or an identity provider.
+// ARNs contain slashes, so clients send them URL-encoded.
+func arnParam(c echo.Context) string {
+	arn, err := url.PathUnescape(c.Param("arn"))
+	if err != nil {
+		return c.Param("arn")
+	}
+
+	return arn
+}
+
+// policyArnParam returns the ARN of the policy given by the arn path parameter, which holds either the policy
+// ARN or the name of a customer managed policy of the account.
+func policyArnParam(c echo.Context, awsSession *inaAws.Session) (string, error) {
+	return awsSession.IamSvc.PolicyArn(arnParam(c))
+}
+
 // ListPolicies @Summary List Policies
-// @Description Get a list of IAM policies
+// @Description Get a page of IAM policies. With a tag filter, only customer managed policies are listed since AWS
+// @Description managed policies cannot be tagged.
 // @ID list-policies
+// @Param tag query []string false "Tag filter, key=value, key:value or key" collectionFormat(multi)
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the policy name, ID or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date, updated_date, -updated_date)
 // @Produce json
-// @Success 200 {array} resp.PolicyDetailResponse
+// @Success 200 {object} responses.List{items=[]resp.PolicyDetailResponse}
 // @Router /aws/policies [get]
 func (awsHandler *Handler) ListPolicies(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	tagFilters, err := tagFiltersParam(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
 	awsSession, err := awsHandler.RetrieveAwsIamSession()
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusInternalServerError, "Failed to retrieve IAM session")
 	}
 
-	policies, err := awsSession.IamSvc.ListIamPolicies()
-	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	// AWS managed policies carry no tags, listing them would cost a ListPolicyTags call each for no match
+	scope := awsIam.PolicyScopeTypeAll
+	if len(tagFilters) > 0 {
+		scope = awsIam.PolicyScopeTypeLocal
 	}
 
-	// var policiesList []resp.PolicyDetailResponse
-	// for _, policy := range policies {
-	// 	policiesList = append(policiesList, resp.PolicyDetailResponse{
-	// 		PolicyName: *policy.PolicyName,
-	// 		PolicyID:   *policy.PolicyId,
-	// 	})
-	// }
+	page, err := pagination.Lister[*awsIam.Policy]{
+		Fetch: func(marker string, pageSize int64) ([]*awsIam.Policy, string, error) {
+			return awsSession.IamSvc.ListIamPoliciesPage(scope, marker, pageSize)
+		},
+		Match: func(policy *awsIam.Policy) (bool, error) {
+			if params.Filter != "" &&
+				!pagination.Contains(params.Filter, aws.StringValue(policy.PolicyName), aws.StringValue(policy.PolicyId),
+					aws.StringValue(policy.Arn)) {
+				return false, nil
+			}
+			if len(tagFilters) == 0 {
+				return true, nil
+			}
+
+			tags, err := awsSession.IamSvc.ListPolicyTags(aws.StringValue(policy.Arn))
+			if err != nil {
+				return false, err
+			}
+			return iam.MatchTagFilters(tagFilters, tags), nil
+		},
+		Less: map[string]func(a, b *awsIam.Policy) bool{
+			"name": func(a, b *awsIam.Policy) bool {
+				return aws.StringValue(a.PolicyName) < aws.StringValue(b.PolicyName)
+			},
+			"created_date": func(a, b *awsIam.Policy) bool {
+				return aws.TimeValue(a.CreateDate).Before(aws.TimeValue(b.CreateDate))
+			},
+			"updated_date": func(a, b *awsIam.Policy) bool {
+				return aws.TimeValue(a.UpdateDate).Before(aws.TimeValue(b.UpdateDate))
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
 
-	return responses.Response(c, http.StatusOK, policies)
+	return responses.ListResponse(c, page.Items, page.NextCursor)
 }
 
 // GetPolicy @Summary Get Policy by Name
-// @Description Get IAM policy details by policy name
+// @Description Get IAM policy details by policy name or ARN
 // @ID aws-policy-by-name
-// @Param policyName path string true "Policy Name"
+// @Param policyName path string true "Policy name or URL-encoded ARN"
+// @Param history query bool false "Add the latest changes of the policy recorded by CloudTrail"
 // @Produce json
-// @Success 200 {object} resp.PolicyDetailResponse
+// @Success 200 {object} resp.PolicyHistoryResponse
 // @Router /aws/policies/{policyName} [get]
 func (awsHandler *Handler) GetPolicy(c echo.Context) error {
-	policyName := c.Param("arn")
+	policyName := arnParam(c)
 	if policyName == "" {
 		return responses.ErrorResponse(c, http.StatusBadRequest, "Policy ARN is required")
 	}
@@ -57,7 +125,12 @@ func (awsHandler *Handler) GetPolicy(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve IAM session")
 	}
 
-	policyDetails, err := openedSession.IamSvc.GetIamPolicy(policyName)
+	policyARN, err := openedSession.IamSvc.PolicyArn(policyName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	policyDetails, err := openedSession.IamSvc.GetIamPolicy(policyARN)
 
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -66,14 +139,23 @@ func (awsHandler *Handler) GetPolicy(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusNotFound, "Policy not found")
 	}
 
-	return responses.Response(c, http.StatusOK, policyDetails)
+	changeHistory, err := awsHandler.changeHistory(c, inaAws.IamResourceTypePolicy, policyARN)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.PolicyHistoryResponse{
+		Policy:        policyDetails,
+		ChangeHistory: changeHistory,
+	})
 }
 
 // CreatePolicy @Summary Create Policy
-// @Description Create a new IAM policy
+// @Description Create a new IAM policy, the document must pass the lint checks
 // @ID aws-policy-create
 // @Accept json
 // @Produce json
+// @Param strict query bool false "Lint warnings block the creation"
 // @Param body body req.CreatePolicyRequest true "Policy details"
 // @Success 200 {object} resp.PolicyDetailResponse
 // @Router /aws/policies [post]
@@ -87,7 +169,7 @@ func (awsHandler *Handler) CreatePolicy(c echo.Context) error {
 	err := createPolicyRequest.Validate()
 
 	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, resp.ErrorMissingPolicyARN)
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
 	// TODO TO CHANGE ALL THE CODE BELOW IT'S BLACK MAGIC
@@ -97,20 +179,13 @@ func (awsHandler *Handler) CreatePolicy(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
-	statementsEntries := []iam.StatementEntry{}
-
-	for _, statementEntry := range createPolicyRequest.PolicyDocument.Statements {
-		statementsEntries = append(statementsEntries, iam.StatementEntry{
-			Effect:   statementEntry.Effect,
-			Action:   statementEntry.Action,
-			Resource: statementEntry.Resource,
-		})
+	findings := lintPolicy(awsSession, createPolicyRequest.PolicyDocument)
+	if iam.LintBlocks(findings, strictQueryParam(c)) {
+		return lintErrorResponse(c, findings)
 	}
 
-	awsPolicy, err := awsSession.IamSvc.CreateIamPolicy(createPolicyRequest.PolicyName, createPolicyRequest.Description, iam.PolicyDocument{
-		Version:   createPolicyRequest.PolicyDocument.Version,
-		Statement: statementsEntries,
-	})
+	awsPolicy, err := awsSession.IamSvc.CreateIamPolicy(createPolicyRequest.PolicyName, createPolicyRequest.Description,
+		createPolicyRequest.PolicyDocument, createPolicyRequest.Tags)
 
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -120,10 +195,11 @@ func (awsHandler *Handler) CreatePolicy(c echo.Context) error {
 }
 
 // UpdatePolicy @Summary Update Policy
-// @Description Update an existing IAM policy
+// @Description Update an existing IAM policy, the document must pass the lint checks
 // @ID aws-policy-update
 // @Accept json
 // @Produce json
+// @Param strict query bool false "Lint warnings block the update"
 // @Param body body req.UpdatePolicyRequest true "Updated policy details"
 // @Success 200 {object} resp.PolicyDetailResponse
 // @Router /aws/policies [put]
@@ -134,11 +210,15 @@ func (awsHandler *Handler) UpdatePolicy(c echo.Context) error {
 	if err := c.Bind(&updatePolicyRequest); err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
 	}
+	updatePolicyRequest.PolicyARN = arnParam(c)
+	if updatePolicyRequest.PolicyARN == "" {
+		return responses.ErrorResponse(c, http.StatusBadRequest, resp.ErrorMissingPolicyARN)
+	}
 
 	err := updatePolicyRequest.Validate()
 
 	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, resp.ErrorMissingPolicyARN)
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
 	awsSession, err := awsHandler.RetrieveAwsIamSession()
@@ -146,21 +226,17 @@ func (awsHandler *Handler) UpdatePolicy(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
-	statementsEntries := []iam.StatementEntry{}
-
-	for _, statementEntry := range updatePolicyRequest.PolicyDocument.Statements {
+	findings := lintPolicy(awsSession, updatePolicyRequest.PolicyDocument)
+	if iam.LintBlocks(findings, strictQueryParam(c)) {
+		return lintErrorResponse(c, findings)
+	}
 
-		statementsEntries = append(statementsEntries, iam.StatementEntry{
-			Effect:   statementEntry.Effect,
-			Action:   statementEntry.Action,
-			Resource: statementEntry.Resource,
-		})
+	policyARN, err := awsSession.IamSvc.PolicyArn(updatePolicyRequest.PolicyARN)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	updatedPolicy, err := awsSession.IamSvc.UpdateIamPolicy(updatePolicyRequest.PolicyARN, iam.PolicyDocument{
-		Version:   updatePolicyRequest.PolicyDocument.Version,
-		Statement: statementsEntries,
-	})
+	updatedPolicy, err := awsSession.IamSvc.UpdateIamPolicy(policyARN, updatePolicyRequest.PolicyDocument)
 
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
@@ -170,21 +246,40 @@ func (awsHandler *Handler) UpdatePolicy(c echo.Context) error {
 }
 
 // DeletePolicy @Summary Delete Policy
-// @Description Delete an IAM policy by policy name
+// @Description Delete an IAM policy by policy name or ARN
 // @ID aws-delete-policy
-// @Param policyName path string true "Policy Name"
+// @Param policyName path string true "Policy name or URL-encoded ARN"
+// @Param cascade query bool false "Detach the policy from its users, groups, roles and permissions boundaries first, refused for AWS managed policies and the required role permissions boundary"
+// @Param dry_run query bool false "With cascade, only return the planned steps"
 // @Produce json
 // @Success 200 {string} string "Policy deleted successfully"
+// @Success 207 {object} resp.DeletePlanResponse "Cascading delete with failed steps"
 // @Router /aws/policies/{policyName} [delete]
 func (awsHandler *Handler) DeletePolicy(c echo.Context) error {
-	// TODO
-	policyARN := c.Param("arn")
-
 	awsSession, err := awsHandler.RetrieveAwsIamSession()
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
+	policyARN, err := policyArnParam(c, awsSession)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if cascadeRequested(c) {
+		requiredRoleBoundary := awsHandler.requiredRolePermissionsBoundary()
+		if requiredRoleBoundary != "" {
+			requiredRoleBoundary, err = awsSession.IamSvc.PolicyArn(requiredRoleBoundary)
+			if err != nil {
+				return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			}
+		}
+
+		return cascadeDelete(c, func() (*iam.DeletePlan, error) {
+			return awsSession.IamSvc.PlanPolicyDeletion(policyARN, requiredRoleBoundary)
+		})
+	}
+
 	err = awsSession.IamSvc.DeleteIamPolicy(policyARN)
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
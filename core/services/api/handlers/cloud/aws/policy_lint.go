@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+// strictQueryParam reports whether the caller asked for warnings to block the deployment of a policy.
+func strictQueryParam(c echo.Context) bool {
+	return boolQueryParam(c, "strict")
+}
+
+// lintPolicy runs the local lint checks on a policy document, then the Access Analyzer checks when the
+// document is well-formed and the service is available to the session.
+func lintPolicy(awsSession *inaAws.Session, policy iam.PolicyDocument) []iam.LintFinding {
+	findings := iam.LintPolicyDocument(policy)
+	if iam.LintBlocks(findings, false) {
+		return findings
+	}
+
+	awsSession.CreateAccessAnalyzerSvc()
+	analyzerFindings, err := awsSession.AccessAnalyzerSvc.ValidatePolicy(policy.String())
+	if err != nil {
+		log.Logger.Warnf("Access Analyzer policy validation skipped: %s", err)
+		return findings
+	}
+
+	return append(findings, analyzerFindings...)
+}
+
+// lintFindingsResponse converts lint findings to their HTTP representation.
+func lintFindingsResponse(findings []iam.LintFinding) []resp.LintFindingResponse {
+	findingsList := make([]resp.LintFindingResponse, 0, len(findings))
+	for _, finding := range findings {
+		findingsList = append(findingsList, resp.LintFindingResponse{
+			Severity: string(finding.Severity),
+			Code:     finding.Code,
+			Message:  finding.Message,
+			Pointer:  finding.Pointer,
+		})
+	}
+
+	return findingsList
+}
+
+// lintErrorResponse rejects a policy deployment that did not pass the lint checks.
+func lintErrorResponse(c echo.Context, findings []iam.LintFinding) error {
+	return responses.Response(c, http.StatusUnprocessableEntity, resp.PolicyLintErrorResponse{
+		Code:     http.StatusUnprocessableEntity,
+		Error:    resp.ErrorPolicyLintFailed,
+		Findings: lintFindingsResponse(findings),
+	})
+}
+
+// LintPolicy @Summary Lint Policy
+// @Description Check a policy document for grammar errors, risky permissions, unknown services,
+// @Description redundant statements and size-limit overflow without deploying it
+// @ID aws-policy-lint
+// @Accept json
+// @Produce json
+// @Param strict query bool false "Warnings block the deployment"
+// @Param body body req.LintPolicyRequest true "Policy document"
+// @Success 200 {object} resp.PolicyLintResponse
+// @Router /aws/iam/policies/lint [post]
+func (awsHandler *Handler) LintPolicy(c echo.Context) error {
+	lintPolicyRequest := req.LintPolicyRequest{}
+
+	if err := c.Bind(&lintPolicyRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := lintPolicyRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	findings := lintPolicy(awsSession, lintPolicyRequest.PolicyDocument)
+
+	return responses.Response(c, http.StatusOK, resp.PolicyLintResponse{
+		Blocked:  iam.LintBlocks(findings, strictQueryParam(c)),
+		Findings: lintFindingsResponse(findings),
+	})
+}
@@ -0,0 +1,213 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// statementsJSON encodes the statements of a policy diff.
+func statementsJSON(statements []iam.Statement) []json.RawMessage {
+	encoded := make([]json.RawMessage, 0, len(statements))
+	for _, statement := range statements {
+		encoded = append(encoded, statementJSON(statement))
+	}
+
+	return encoded
+}
+
+// statementJSON encodes a statement of a policy diff, statements always being encodable.
+func statementJSON(statement iam.Statement) json.RawMessage {
+	encoded, _ := json.Marshal(statement)
+	return encoded
+}
+
+// ListPolicyVersions @Summary List Policy Versions
+// @Description Get a page of the versions of an IAM managed policy, newest first
+// @ID aws-policy-versions-list
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the version ID"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(created_date, -created_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.PolicyVersionResponse}
+// @Router /aws/iam/policies/{arn}/versions [get]
+func (awsHandler *Handler) ListPolicyVersions(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyARN, err := policyArnParam(c, awsSession)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	versions, err := awsSession.IamSvc.ListPolicyVersions(policyARN)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[iam.PolicyVersion]{
+		Fetch: pagination.Single(versions),
+		Match: func(version iam.PolicyVersion) (bool, error) {
+			return pagination.Contains(params.Filter, version.VersionId), nil
+		},
+		Less: map[string]func(a, b iam.PolicyVersion) bool{
+			"created_date": func(a, b iam.PolicyVersion) bool {
+				return a.CreateDate.Before(b.CreateDate)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	versionsList := make([]resp.PolicyVersionResponse, 0, len(page.Items))
+	for _, version := range page.Items {
+		versionsList = append(versionsList, resp.PolicyVersionResponse{
+			VersionId:  version.VersionId,
+			IsDefault:  version.IsDefault,
+			CreateDate: version.CreateDate,
+		})
+	}
+
+	return responses.ListResponse(c, versionsList, page.NextCursor)
+}
+
+// GetPolicyVersion @Summary Get Policy Version
+// @Description Get a version of an IAM managed policy with its document
+// @ID aws-policy-version-get
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Param version path string true "Version ID"
+// @Produce json
+// @Success 200 {object} resp.PolicyVersionResponse
+// @Router /aws/iam/policies/{arn}/versions/{version} [get]
+func (awsHandler *Handler) GetPolicyVersion(c echo.Context) error {
+	versionId := c.Param("version")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyARN, err := policyArnParam(c, awsSession)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	version, err := awsSession.IamSvc.GetPolicyVersion(policyARN, versionId)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.PolicyVersionResponse{
+		VersionId:  version.VersionId,
+		IsDefault:  version.IsDefault,
+		CreateDate: version.CreateDate,
+		Document:   json.RawMessage(version.Document),
+	})
+}
+
+// SetDefaultPolicyVersion @Summary Set Default Policy Version
+// @Description Roll an IAM managed policy back to one of its existing versions
+// @ID aws-policy-version-set-default
+// @Accept json
+// @Produce json
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Param body body req.SetDefaultPolicyVersionRequest true "Version to make default"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/policies/{arn}/default-version [put]
+func (awsHandler *Handler) SetDefaultPolicyVersion(c echo.Context) error {
+	setDefaultRequest := req.SetDefaultPolicyVersionRequest{}
+	if err := c.Bind(&setDefaultRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := setDefaultRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyARN, err := policyArnParam(c, awsSession)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	err = awsSession.IamSvc.SetDefaultPolicyVersion(policyARN, setDefaultRequest.VersionId)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DiffPolicyVersions @Summary Diff Policy Versions
+// @Description Get the statements added, removed and changed between two versions of an IAM managed policy
+// @ID aws-policy-versions-diff
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Param from query string true "Base version ID"
+// @Param to query string true "Compared version ID"
+// @Produce json
+// @Success 200 {object} resp.PolicyDiffResponse
+// @Router /aws/iam/policies/{arn}/diff [get]
+func (awsHandler *Handler) DiffPolicyVersions(c echo.Context) error {
+	diffRequest := req.DiffPolicyVersionsRequest{}
+	if err := c.Bind(&diffRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := diffRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	policyARN, err := policyArnParam(c, awsSession)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	diff, err := awsSession.IamSvc.DiffPolicyVersions(policyARN, diffRequest.FromVersionId, diffRequest.ToVersionId)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	diffResponse := resp.PolicyDiffResponse{
+		FromVersionId: diffRequest.FromVersionId,
+		ToVersionId:   diffRequest.ToVersionId,
+		Added:         statementsJSON(diff.Added),
+		Removed:       statementsJSON(diff.Removed),
+		Changed:       make([]resp.StatementChangeResponse, 0, len(diff.Changed)),
+		Unchanged:     diff.Unchanged,
+	}
+	for _, change := range diff.Changed {
+		diffResponse.Changed = append(diffResponse.Changed, resp.StatementChangeResponse{
+			Sid:    change.Sid,
+			Before: statementJSON(change.Before),
+			After:  statementJSON(change.After),
+		})
+	}
+
+	return responses.Response(c, http.StatusOK, diffResponse)
+}
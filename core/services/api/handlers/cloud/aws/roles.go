@@ -4,50 +4,110 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
 	"github.com/labstack/echo/v4"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
-	"gitea/pcp-inariam/inariam/pkgs/log"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 )
 
+// roleDetailResponse converts an IAM role to its HTTP representation.
+func roleDetailResponse(role *awsIam.Role) resp.RoleDetailResponse {
+	return resp.RoleDetailResponse{
+		RoleName:               aws.StringValue(role.RoleName),
+		RoleID:                 aws.StringValue(role.RoleId),
+		RoleArn:                aws.StringValue(role.Arn),
+		Path:                   aws.StringValue(role.Path),
+		Description:            aws.StringValue(role.Description),
+		MaxSessionDuration:     aws.Int64Value(role.MaxSessionDuration),
+		PermissionsBoundaryArn: iam.PermissionsBoundaryArn(role.PermissionsBoundary),
+		Tags:                   iam.FromIamTags(role.Tags),
+	}
+}
+
 // ListRoles @Summary List Roles
-// @Description Get a list of IAM roles
+// @Description Get a page of IAM roles
 // @ID aws-roles-list
+// @Param tag query []string false "Tag filter, key=value, key:value or key" collectionFormat(multi)
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the role name, ID, ARN or description"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date)
 // @Produce json
-// @Success 200 {array} resp.RoleDetailResponse
+// @Success 200 {object} responses.List{items=[]resp.RoleDetailResponse}
 // @Router /aws/roles [get]
 func (awsHandler *Handler) ListRoles(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	tagFilters, err := tagFiltersParam(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
 	openedSession, err := awsHandler.RetrieveAwsIamSession()
 
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
 	}
 
-	roles, err := openedSession.IamSvc.ListIamRoles()
+	roleTags := make(map[string]map[string]string)
+	page, err := pagination.Lister[*awsIam.Role]{
+		Fetch: openedSession.IamSvc.ListIamRolesPage,
+		Match: func(role *awsIam.Role) (bool, error) {
+			if params.Filter != "" &&
+				!pagination.Contains(params.Filter, aws.StringValue(role.RoleName), aws.StringValue(role.RoleId),
+					aws.StringValue(role.Arn), aws.StringValue(role.Description)) {
+				return false, nil
+			}
+			if len(tagFilters) == 0 {
+				return true, nil
+			}
+
+			tags, err := openedSession.IamSvc.ListRoleTags(aws.StringValue(role.RoleName))
+			if err != nil {
+				return false, err
+			}
+			roleTags[aws.StringValue(role.RoleName)] = tags
+			return iam.MatchTagFilters(tagFilters, tags), nil
+		},
+		Less: map[string]func(a, b *awsIam.Role) bool{
+			"name": func(a, b *awsIam.Role) bool {
+				return aws.StringValue(a.RoleName) < aws.StringValue(b.RoleName)
+			},
+			"created_date": func(a, b *awsIam.Role) bool {
+				return aws.TimeValue(a.CreateDate).Before(aws.TimeValue(b.CreateDate))
+			},
+		},
+	}.List(params)
 	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrOpenedSession)
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	var rolesList []resp.RoleDetailResponse
-
-	for _, role := range roles {
-		detail := resp.RoleDetailResponse{
-			RoleName: *role.RoleName,
-			RoleID:   *role.RoleId,
-			RoleArn:  *role.Arn,
+	rolesList := make([]resp.RoleDetailResponse, 0, len(page.Items))
+	for _, role := range page.Items {
+		detail := roleDetailResponse(role)
+		if tags, ok := roleTags[aws.StringValue(role.RoleName)]; ok {
+			detail.Tags = tags
 		}
 		rolesList = append(rolesList, detail)
 	}
 
-	return responses.Response(c, http.StatusOK, rolesList)
+	return responses.ListResponse(c, rolesList, page.NextCursor)
 }
 
 // GetRole @Summary Get Role by Name
 // @Description Get IAM role details by role name
 // @ID aws-role-by-name
 // @Param roleName path string true "Role Name"
+// @Param history query bool false "Add the latest changes of the role recorded by CloudTrail"
 // @Produce json
 // @Success 200 {object} resp.RoleDetailResponse
 // @Router /aws/roles/{roleName} [get]
@@ -67,12 +127,20 @@ func (awsHandler *Handler) GetRole(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusNotFound, resp.HttpErrRoleNotFound)
 	}
 
-	return responses.Response(c, http.StatusOK, resp.RoleDetailResponse{
-		RoleName: *roleDetails.RoleName,
-		RoleID:   *roleDetails.RoleId,
-		RoleArn:  *roleDetails.Arn,
-	},
-	)
+	inlinePolicies, err := awsSession.IamSvc.ListRoleInlinePolicies(roleName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	roleDetail := roleDetailResponse(roleDetails)
+	roleDetail.InlinePolicies = inlinePolicies
+
+	roleDetail.ChangeHistory, err = awsHandler.changeHistory(c, inaAws.IamResourceTypeRole, roleName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, roleDetail)
 }
 
 // CreateRole @Summary Create Role
@@ -80,8 +148,7 @@ func (awsHandler *Handler) GetRole(c echo.Context) error {
 // @ID aws-role-create
 // @Accept json
 // @Produce json
-// @Param roleName body string true "Role Name"
-// @Param trustPolicy body requests.CreateRoleRequest.TrustPolicy true "Trust Policy"
+// @Param body body req.CreateRoleRequest true "Role"
 // @Success 200 {object} resp.RoleDetailResponse
 // @Router /aws/roles [post]
 func (awsHandler *Handler) CreateRole(c echo.Context) error {
@@ -94,7 +161,7 @@ func (awsHandler *Handler) CreateRole(c echo.Context) error {
 
 	err := createRoleRequest.Validate()
 	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, resp.HttpErrMissingRoleName)
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
 	trustPolicyJSON, err := json.Marshal(createRoleRequest.TrustPolicy)
@@ -107,27 +174,36 @@ func (awsHandler *Handler) CreateRole(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
-	createdRole, err := openedSession.IamSvc.CreateIAMRole(createRoleRequest.RoleName, string(trustPolicyJSON))
+	boundaryArn, err := awsHandler.checkRolePermissionsBoundary(openedSession, createRoleRequest.PermissionsBoundary)
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	return responses.Response(c, http.StatusOK, resp.RoleDetailResponse{
-		RoleName: createRoleRequest.RoleName,
-		RoleArn:  *createdRole.Arn,
-		RoleID:   *createdRole.RoleId,
-	})
+	createdRole, err := openedSession.IamSvc.CreateIAMRole(createRoleRequest.RoleName, string(trustPolicyJSON),
+		iam.RoleOptions{
+			Path:                createRoleRequest.Path,
+			Description:         createRoleRequest.Description,
+			MaxSessionDuration:  createRoleRequest.MaxSessionDuration,
+			PermissionsBoundary: boundaryArn,
+			Tags:                createRoleRequest.Tags,
+		})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, roleDetailResponse(createdRole))
 }
 
 // UpdateRole @Summary Update Role
-// @Description Update an existing IAM role's trust policy
+// @Description Update an existing IAM role's trust policy, description and maximum session duration.
+// @Description Omitted fields are left unchanged.
 // @ID aws-role-update
 // @Accept json
 // @Produce json
-// @Param roleName body string true "Role Name"
-// @Param trustPolicy body requests.UpdateRoleRequest.TrustPolicy true "Updated Trust Policy"
+// @Param id path string true "Role Name"
+// @Param body body req.UpdateRoleRequest true "Role update"
 // @Success 200 {object} resp.RoleDetailResponse
-// @Router /aws/roles [put]
+// @Router /aws/roles/{id} [put]
 func (awsHandler *Handler) UpdateRole(c echo.Context) error {
 	updateRoleRequest := req.UpdateRoleRequest{}
 
@@ -135,15 +211,9 @@ func (awsHandler *Handler) UpdateRole(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
 	}
 
-	log.Logger.Infoln(updateRoleRequest)
 	err := updateRoleRequest.Validate()
 	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, resp.HttpErrMissingRoleName)
-	}
-
-	trustPolicyJSON, err := json.Marshal(updateRoleRequest.TrustPolicy)
-	if err != nil {
-		return responses.ErrorResponse(c, http.StatusBadRequest, "Error converting trust policy to JSON")
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
 	openedSession, err := awsHandler.RetrieveAwsIamSession()
@@ -151,24 +221,44 @@ func (awsHandler *Handler) UpdateRole(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve IAM session")
 	}
 
-	roleDetails, err := openedSession.IamSvc.ModifyIAMRoleTrustPolicy(updateRoleRequest.Name, string(trustPolicyJSON))
+	if updateRoleRequest.TrustPolicy != nil {
+		trustPolicyJSON, err := json.Marshal(updateRoleRequest.TrustPolicy)
+		if err != nil {
+			return responses.ErrorResponse(c, http.StatusBadRequest, resp.ErrorConvertingTrustPolicyToJSON)
+		}
+
+		_, err = openedSession.IamSvc.ModifyIAMRoleTrustPolicy(updateRoleRequest.Name, string(trustPolicyJSON))
+		if err != nil {
+			return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
+	}
+
+	var roleDetails *awsIam.Role
+	if updateRoleRequest.Description != nil || updateRoleRequest.MaxSessionDuration != nil {
+		roleDetails, err = openedSession.IamSvc.UpdateIAMRole(updateRoleRequest.Name, updateRoleRequest.Description,
+			updateRoleRequest.MaxSessionDuration)
+	} else {
+		roleDetails, err = openedSession.IamSvc.GetIamRole(updateRoleRequest.Name)
+	}
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
 	}
+	if roleDetails == nil {
+		return responses.ErrorResponse(c, http.StatusNotFound, resp.HttpErrRoleNotFound)
+	}
 
-	return responses.Response(c, http.StatusOK, resp.RoleDetailResponse{
-		RoleName: *roleDetails.RoleName,
-		RoleID:   *roleDetails.RoleId,
-		RoleArn:  *roleDetails.Arn,
-	})
+	return responses.Response(c, http.StatusOK, roleDetailResponse(roleDetails))
 }
 
 // DeleteRole @Summary Delete Role
 // @Description Delete an IAM role by role name
 // @ID aws-role-delete
 // @Param roleName path string true "Role Name"
+// @Param cascade query bool false "Remove the role from its instance profiles and its policies first"
+// @Param dry_run query bool false "With cascade, only return the planned steps"
 // @Produce json
 // @Success 200 {string} string "Role deleted successfully"
+// @Success 207 {object} resp.DeletePlanResponse "Cascading delete with failed steps"
 // @Router /aws/roles/{roleName} [delete]
 func (awsHandler *Handler) DeleteRole(c echo.Context) error {
 	roleName := c.Param("id")
@@ -177,6 +267,12 @@ func (awsHandler *Handler) DeleteRole(c echo.Context) error {
 		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
+	if cascadeRequested(c) {
+		return cascadeDelete(c, func() (*iam.DeletePlan, error) {
+			return awsSession.IamSvc.PlanRoleDeletion(roleName)
+		})
+	}
+
 	err = awsSession.IamSvc.DeleteIAMRole(roleName)
 	if err != nil {
 		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
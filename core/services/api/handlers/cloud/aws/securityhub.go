@@ -0,0 +1,213 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	securityHubReq "gitea/pcp-inariam/inariam/core/services/api/requests/aws/securityhub"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	securityHubResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/securityhub"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+// retrieveSecurityHubSvc opens an AWS session and its Security Hub service.
+func (awsHandler *Handler) retrieveSecurityHubSvc() (*inaAws.SecurityHubSvc, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession.CreateSecurityHubSvc()
+	return awsSession.SecurityHubSvc, nil
+}
+
+func securityHubFindingResponse(finding inaAws.SecurityHubFinding) securityHubResp.FindingResponse {
+	findingResponse := securityHubResp.FindingResponse{
+		ID:               finding.ID,
+		ProductArn:       finding.ProductArn,
+		ProductName:      finding.ProductName,
+		GeneratorID:      finding.GeneratorID,
+		AccountID:        finding.AccountID,
+		Region:           finding.Region,
+		Types:            finding.Types,
+		Title:            finding.Title,
+		Description:      finding.Description,
+		SeverityLabel:    finding.SeverityLabel,
+		Severity:         finding.Severity,
+		ComplianceStatus: finding.ComplianceStatus,
+		WorkflowStatus:   finding.WorkflowStatus,
+		RecordState:      finding.RecordState,
+		ResourceType:     finding.ResourceType,
+		ResourceID:       finding.ResourceID,
+		CreatedAt:        finding.CreatedAt,
+		UpdatedAt:        finding.UpdatedAt,
+	}
+	if findingResponse.Types == nil {
+		findingResponse.Types = []string{}
+	}
+	if finding.Note != nil {
+		findingResponse.Note = &securityHubResp.NoteResponse{
+			Text:      finding.Note.Text,
+			UpdatedBy: finding.Note.UpdatedBy,
+			UpdatedAt: finding.Note.UpdatedAt,
+		}
+	}
+
+	return findingResponse
+}
+
+// findingUpdatesResponse converts the outcome of a findings update to its HTTP representation.
+// The returned status is 207 Multi-Status when at least one finding failed.
+func findingUpdatesResponse(results []inaAws.SecurityHubUpdateResult) (int, []securityHubResp.FindingUpdateResponse) {
+	statusCode := http.StatusOK
+	updatesList := make([]securityHubResp.FindingUpdateResponse, 0, len(results))
+
+	for _, result := range results {
+		update := securityHubResp.FindingUpdateResponse{
+			ID:         result.Finding.ID,
+			ProductArn: result.Finding.ProductArn,
+			Success:    result.Err == nil,
+		}
+		if result.Err != nil {
+			update.Error = result.Err.Error()
+			statusCode = http.StatusMultiStatus
+		}
+		updatesList = append(updatesList, update)
+	}
+
+	return statusCode, updatesList
+}
+
+// ListSecurityHubFindings @Summary List Security Hub Findings
+// @Description Get a page of the Security Hub findings. The AWS Security Finding Format criteria are applied by
+// @Description Security Hub, the filter on the listed findings. Only active findings are listed by default.
+// @ID aws-securityhub-findings-list
+// @Param severity query []string false "Severity labels" collectionFormat(multi)
+// @Param workflow_status query []string false "Workflow statuses" collectionFormat(multi)
+// @Param compliance_status query []string false "Compliance statuses" collectionFormat(multi)
+// @Param product_name query []string false "Product names, such as GuardDuty or Inspector" collectionFormat(multi)
+// @Param resource_type query []string false "Resource types, such as AwsIamRole" collectionFormat(multi)
+// @Param account_id query []string false "Account IDs" collectionFormat(multi)
+// @Param type query []string false "Finding type prefixes" collectionFormat(multi)
+// @Param record_state query string false "Record state" Enums(ACTIVE, ARCHIVED)
+// @Param updated_after query string false "RFC 3339 time the findings were last updated after"
+// @Param updated_before query string false "RFC 3339 time the findings were last updated before"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the finding title, resource ID or generator ID"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(severity, -severity, updated_at, -updated_at)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]securityHubResp.FindingResponse}
+// @Router /aws/securityhub/findings [get]
+func (awsHandler *Handler) ListSecurityHubFindings(c echo.Context) error {
+	listFindingsRequest := securityHubReq.ListFindingsRequest{}
+
+	if err := c.Bind(&listFindingsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := listFindingsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	securityHubSvc, err := awsHandler.retrieveSecurityHubSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	filter := inaAws.SecurityHubFindingFilter{
+		SeverityLabels:     listFindingsRequest.SeverityLabels,
+		WorkflowStatuses:   listFindingsRequest.WorkflowStatuses,
+		ComplianceStatuses: listFindingsRequest.ComplianceStatuses,
+		ProductNames:       listFindingsRequest.ProductNames,
+		ResourceTypes:      listFindingsRequest.ResourceTypes,
+		AccountIDs:         listFindingsRequest.AccountIDs,
+		Types:              listFindingsRequest.Types,
+		RecordState:        listFindingsRequest.RecordState,
+		UpdatedAfter:       listFindingsRequest.UpdatedAfterTime(),
+		UpdatedBefore:      listFindingsRequest.UpdatedBeforeTime(),
+	}
+
+	page, err := pagination.Lister[inaAws.SecurityHubFinding]{
+		Fetch: func(marker string, pageSize int64) ([]inaAws.SecurityHubFinding, string, error) {
+			return securityHubSvc.GetFindings(filter, marker, pageSize)
+		},
+		Match: func(finding inaAws.SecurityHubFinding) (bool, error) {
+			return pagination.Contains(params.Filter, finding.Title, finding.ResourceID, finding.GeneratorID), nil
+		},
+		Less: map[string]func(a, b inaAws.SecurityHubFinding) bool{
+			"severity": func(a, b inaAws.SecurityHubFinding) bool {
+				return a.Severity < b.Severity
+			},
+			"updated_at": func(a, b inaAws.SecurityHubFinding) bool {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	findingsList := make([]securityHubResp.FindingResponse, 0, len(page.Items))
+	for _, finding := range page.Items {
+		findingsList = append(findingsList, securityHubFindingResponse(finding))
+	}
+
+	return responses.ListResponse(c, findingsList, page.NextCursor)
+}
+
+// UpdateSecurityHubFindings @Summary Update Security Hub Findings
+// @Description Set the workflow status or the note of Security Hub findings. Security Hub rejects findings
+// @Description individually, the status is 207 when at least one finding was not updated.
+// @ID aws-securityhub-findings-update
+// @Accept json
+// @Produce json
+// @Param body body securityHubReq.UpdateFindingsRequest true "Findings update"
+// @Success 200 {array} securityHubResp.FindingUpdateResponse
+// @Success 207 {array} securityHubResp.FindingUpdateResponse
+// @Router /aws/securityhub/findings [put]
+func (awsHandler *Handler) UpdateSecurityHubFindings(c echo.Context) error {
+	updateFindingsRequest := securityHubReq.UpdateFindingsRequest{}
+
+	if err := c.Bind(&updateFindingsRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updateFindingsRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	securityHubSvc, err := awsHandler.retrieveSecurityHubSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	findings := make([]inaAws.SecurityHubFindingIdentifier, 0, len(updateFindingsRequest.Findings))
+	for _, finding := range updateFindingsRequest.Findings {
+		findings = append(findings, inaAws.SecurityHubFindingIdentifier{
+			ID:         finding.ID,
+			ProductArn: finding.ProductArn,
+		})
+	}
+
+	update := inaAws.SecurityHubFindingUpdate{WorkflowStatus: updateFindingsRequest.WorkflowStatus}
+	if updateFindingsRequest.Note != nil {
+		update.NoteText = updateFindingsRequest.Note.Text
+		update.NoteUpdatedBy = updateFindingsRequest.Note.UpdatedBy
+	}
+
+	results, err := securityHubSvc.BatchUpdateFindings(findings, update)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	statusCode, updatesList := findingUpdatesResponse(results)
+	return responses.Response(c, statusCode, updatesList)
+}
@@ -0,0 +1,203 @@
+package aws
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/labstack/echo/v4"
+
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// defaultUnusedDays is the number of days without use after which a granted service is considered unused.
+const defaultUnusedDays = 90
+
+// unusedDaysParam returns the days query parameter of an unused services request.
+func unusedDaysParam(c echo.Context) (int, error) {
+	value := c.QueryParam("days")
+	if value == "" {
+		return defaultUnusedDays, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 {
+		return 0, errors.New(resp.HttpErrInvalidUnusedDays)
+	}
+
+	return days, nil
+}
+
+// servicesLastAccessedResponse converts service last accessed details to their HTTP representation.
+func servicesLastAccessedResponse(services []iam.ServiceLastAccessed) []resp.ServiceLastAccessedResponse {
+	servicesList := make([]resp.ServiceLastAccessedResponse, 0, len(services))
+	for _, service := range services {
+		serviceResponse := resp.ServiceLastAccessedResponse{
+			ServiceName:                service.ServiceName,
+			ServiceNamespace:           service.ServiceNamespace,
+			LastAuthenticated:          service.LastAuthenticated,
+			LastAuthenticatedBy:        service.LastAuthenticatedBy,
+			LastAuthenticatedRegion:    service.LastAuthenticatedRegion,
+			TotalAuthenticatedEntities: service.TotalAuthenticatedEntities,
+		}
+		for _, action := range service.TrackedActions {
+			serviceResponse.TrackedActions = append(serviceResponse.TrackedActions, resp.ActionLastAccessedResponse{
+				ActionName:     action.ActionName,
+				LastAccessed:   action.LastAccessed,
+				LastAccessedBy: action.LastAccessedBy,
+				Region:         action.Region,
+			})
+		}
+		servicesList = append(servicesList, serviceResponse)
+	}
+
+	return servicesList
+}
+
+// GenerateLastAccessedDetails @Summary Generate Service Last Accessed Details
+// @Description Start a job reporting when the services granted to a user, group, role or policy were last used
+// @ID aws-last-accessed-generate
+// @Accept json
+// @Produce json
+// @Param body body req.GenerateLastAccessedRequest true "Entity ARN and granularity"
+// @Success 200 {object} resp.LastAccessedJobResponse
+// @Router /aws/iam/last-accessed [post]
+func (awsHandler *Handler) GenerateLastAccessedDetails(c echo.Context) error {
+	generateLastAccessedRequest := req.GenerateLastAccessedRequest{}
+
+	if err := c.Bind(&generateLastAccessedRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := generateLastAccessedRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	granularity := generateLastAccessedRequest.Granularity
+	if granularity == "" {
+		granularity = awsIam.AccessAdvisorUsageGranularityTypeServiceLevel
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	jobId, err := awsSession.IamSvc.GenerateServiceLastAccessedDetails(generateLastAccessedRequest.Arn, granularity)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.LastAccessedJobResponse{JobID: jobId})
+}
+
+// GetLastAccessedDetails @Summary Get Service Last Accessed Details
+// @Description Get the status of a service last accessed details job, with every service once completed
+// @ID aws-last-accessed-get
+// @Param job path string true "Job ID"
+// @Produce json
+// @Success 200 {object} resp.ServiceLastAccessedJobResponse
+// @Router /aws/iam/last-accessed/{job} [get]
+func (awsHandler *Handler) GetLastAccessedDetails(c echo.Context) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	job, err := awsSession.IamSvc.GetServiceLastAccessedDetails(c.Param("job"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.ServiceLastAccessedJobResponse{
+		JobID:          job.JobId,
+		Status:         job.Status,
+		Granularity:    job.Granularity,
+		CreationDate:   job.CreationDate,
+		CompletionDate: job.CompletionDate,
+		Error:          job.Error,
+		Services:       servicesLastAccessedResponse(job.Services),
+	})
+}
+
+// ListRoleUnusedServices @Summary List Role Unused Services
+// @Description List the services granted to a role but not used in the last days, with a suggested policy
+// @Description combining the role policies without those services
+// @ID aws-role-unused-services
+// @Param id path string true "Role Name"
+// @Param days query int false "Days without use, 90 by default"
+// @Produce json
+// @Success 200 {object} resp.UnusedServicesResponse
+// @Router /aws/iam/roles/{id}/unused-services [get]
+func (awsHandler *Handler) ListRoleUnusedServices(c echo.Context) error {
+	roleName := c.Param("id")
+
+	days, err := unusedDaysParam(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	role, err := awsSession.IamSvc.GetIamRole(roleName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	if role == nil {
+		return responses.ErrorResponse(c, http.StatusNotFound, resp.HttpErrRoleNotFound)
+	}
+
+	job, err := awsSession.IamSvc.FetchServiceLastAccessedDetails(aws.StringValue(role.Arn),
+		awsIam.AccessAdvisorUsageGranularityTypeServiceLevel)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	policies, err := awsSession.IamSvc.ListRoleIdentityPolicies(roleName)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	unusedServices := iam.UnusedServices(job.Services, since)
+
+	namespaces := make([]string, 0, len(unusedServices))
+	for _, service := range unusedServices {
+		namespaces = append(namespaces, service.ServiceNamespace)
+	}
+
+	removedActions := make(map[string][]string)
+	trimmedPolicies := make([]iam.PolicyDocument, 0, len(policies))
+	for _, policy := range policies {
+		trimmed, removed := iam.TrimPolicyDocument(policy.Document, namespaces)
+		if len(removed) > 0 {
+			removedActions[policy.PolicyName] = removed
+		}
+		trimmedPolicies = append(trimmedPolicies, trimmed)
+	}
+
+	suggestedPolicy, err := json.Marshal(iam.MergePolicyDocuments(trimmedPolicies...))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, resp.ErrorMarshalingPolicyDocument)
+	}
+
+	return responses.Response(c, http.StatusOK, resp.UnusedServicesResponse{
+		RoleName:        roleName,
+		RoleArn:         aws.StringValue(role.Arn),
+		Days:            days,
+		Since:           since,
+		UnusedServices:  servicesLastAccessedResponse(unusedServices),
+		RemovedActions:  removedActions,
+		SuggestedPolicy: suggestedPolicy,
+	})
+}
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// SimulatePolicies @Summary Simulate Request
+// @Description Evaluate offline whether a principal can perform an action on a resource given its policies,
+// @Description permissions boundary, service control policies and the request context
+// @ID aws-iam-simulate
+// @Accept json
+// @Produce json
+// @Param body body req.SimulateRequest true "Request and policies"
+// @Success 200 {object} resp.SimulateResponse
+// @Router /aws/iam/simulate [post]
+func (awsHandler *Handler) SimulatePolicies(c echo.Context) error {
+	simulateRequest := req.SimulateRequest{}
+
+	if err := c.Bind(&simulateRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := simulateRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	result, err := iam.EvaluatePolicies(
+		iam.EvaluationRequest{
+			Principal: simulateRequest.Principal,
+			Action:    simulateRequest.Action,
+			Resource:  simulateRequest.Resource,
+			Context:   simulateRequest.Context,
+		},
+		iam.PolicySet{
+			IdentityPolicies:       simulateRequest.IdentityPolicies,
+			ResourcePolicies:       simulateRequest.ResourcePolicies,
+			PermissionsBoundary:    simulateRequest.PermissionsBoundary,
+			ServiceControlPolicies: simulateRequest.ServiceControlPolicies,
+		},
+	)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	matchedStatements := make([]resp.MatchedStatementResponse, 0, len(result.MatchedStatements))
+	for _, statement := range result.MatchedStatements {
+		matchedStatements = append(matchedStatements, resp.MatchedStatementResponse{
+			PolicyType:     string(statement.PolicyType),
+			PolicyIndex:    statement.PolicyIndex,
+			StatementIndex: statement.StatementIndex,
+			Sid:            statement.Sid,
+			Effect:         statement.Effect,
+		})
+	}
+
+	return responses.Response(c, http.StatusOK, resp.SimulateResponse{
+		Decision:          string(result.Decision),
+		Reason:            result.Reason,
+		MatchedStatements: matchedStatements,
+	})
+}
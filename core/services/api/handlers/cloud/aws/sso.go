@@ -0,0 +1,763 @@
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+	ssoReq "gitea/pcp-inariam/inariam/core/services/api/requests/aws/sso"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	ssoResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/sso"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+// retrieveSSOAdminSvc opens an AWS session and its IAM Identity Center service.
+func (awsHandler *Handler) retrieveSSOAdminSvc() (*inaAws.SSOAdminSvc, error) {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return nil, err
+	}
+
+	awsSession.CreateSSOAdminSvc()
+	return awsSession.SSOAdminSvc, nil
+}
+
+// ssoInstanceArn returns the IAM Identity Center instance given by the instance_arn query parameter, or the
+// instance of the organization when omitted.
+func ssoInstanceArn(c echo.Context, ssoAdminSvc *inaAws.SSOAdminSvc) (string, error) {
+	if instanceArn := c.QueryParam("instance_arn"); instanceArn != "" {
+		return instanceArn, nil
+	}
+
+	return ssoAdminSvc.DefaultInstanceArn()
+}
+
+// waitForSSOOperation waits for an asynchronous operation to be over when the wait query parameter is set.
+func waitForSSOOperation(c echo.Context, ssoAdminSvc *inaAws.SSOAdminSvc, instanceArn string,
+	operation *inaAws.SSOOperation,
+) (*inaAws.SSOOperation, error) {
+	if !boolQueryParam(c, "wait") {
+		return operation, nil
+	}
+
+	return ssoAdminSvc.WaitForOperation(instanceArn, operation)
+}
+
+func permissionSetResponse(permissionSet *inaAws.PermissionSet) ssoResp.PermissionSetResponse {
+	return ssoResp.PermissionSetResponse{
+		Arn:             permissionSet.Arn,
+		Name:            permissionSet.Name,
+		Description:     permissionSet.Description,
+		SessionDuration: permissionSet.SessionDuration,
+		RelayState:      permissionSet.RelayState,
+		CreatedDate:     permissionSet.CreatedDate,
+	}
+}
+
+func permissionSetPoliciesResponse(policies *inaAws.PermissionSetPolicies) *ssoResp.PermissionSetPoliciesResponse {
+	policiesResponse := &ssoResp.PermissionSetPoliciesResponse{
+		ManagedPolicies:         make([]ssoResp.ManagedPolicyResponse, 0, len(policies.ManagedPolicies)),
+		CustomerManagedPolicies: make([]ssoResp.CustomerManagedPolicyResponse, 0, len(policies.CustomerManagedPolicies)),
+	}
+
+	for _, policy := range policies.ManagedPolicies {
+		policiesResponse.ManagedPolicies = append(policiesResponse.ManagedPolicies, ssoResp.ManagedPolicyResponse{
+			Arn:  policy.Arn,
+			Name: policy.Name,
+		})
+	}
+	for _, policy := range policies.CustomerManagedPolicies {
+		policiesResponse.CustomerManagedPolicies = append(policiesResponse.CustomerManagedPolicies,
+			ssoResp.CustomerManagedPolicyResponse{
+				Name: policy.Name,
+				Path: policy.Path,
+			})
+	}
+	if policies.InlinePolicy != nil {
+		policiesResponse.InlinePolicy, _ = json.Marshal(policies.InlinePolicy)
+	}
+
+	return policiesResponse
+}
+
+func accountAssignmentResponse(assignment inaAws.AccountAssignment) ssoResp.AccountAssignmentResponse {
+	return ssoResp.AccountAssignmentResponse{
+		AccountID:        assignment.AccountID,
+		PermissionSetArn: assignment.PermissionSetArn,
+		PrincipalType:    assignment.PrincipalType,
+		PrincipalID:      assignment.PrincipalID,
+	}
+}
+
+func ssoOperationResponse(operation *inaAws.SSOOperation) ssoResp.OperationResponse {
+	return ssoResp.OperationResponse{
+		RequestID:        operation.RequestID,
+		Kind:             operation.Kind,
+		Status:           operation.Status,
+		FailureReason:    operation.FailureReason,
+		PermissionSetArn: operation.PermissionSetArn,
+		AccountID:        operation.AccountID,
+		PrincipalType:    operation.PrincipalType,
+		PrincipalID:      operation.PrincipalID,
+		CreatedDate:      operation.CreatedDate,
+	}
+}
+
+// ListSSOInstances @Summary List IAM Identity Center Instances
+// @Description Get the IAM Identity Center instances, an organization has at most one
+// @ID aws-sso-instances-list
+// @Produce json
+// @Success 200 {array} ssoResp.InstanceResponse
+// @Router /aws/sso/instances [get]
+func (awsHandler *Handler) ListSSOInstances(c echo.Context) error {
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instances, err := ssoAdminSvc.ListInstances()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	instancesList := make([]ssoResp.InstanceResponse, 0, len(instances))
+	for _, instance := range instances {
+		instancesList = append(instancesList, ssoResp.InstanceResponse{
+			InstanceArn:     instance.InstanceArn,
+			IdentityStoreID: instance.IdentityStoreID,
+		})
+	}
+
+	return responses.Response(c, http.StatusOK, instancesList)
+}
+
+// ListPermissionSets @Summary List Permission Sets
+// @Description Get a page of the permission sets of an IAM Identity Center instance
+// @ID aws-sso-permission-sets-list
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the permission set name or description"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]ssoResp.PermissionSetResponse}
+// @Router /aws/sso/permission-sets [get]
+func (awsHandler *Handler) ListPermissionSets(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	permissionSets, err := ssoAdminSvc.ListPermissionSets(instanceArn)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[*inaAws.PermissionSet]{
+		Fetch: pagination.Single(permissionSets),
+		Match: func(permissionSet *inaAws.PermissionSet) (bool, error) {
+			return pagination.Contains(params.Filter, permissionSet.Name, permissionSet.Description), nil
+		},
+		Less: map[string]func(a, b *inaAws.PermissionSet) bool{
+			"name": func(a, b *inaAws.PermissionSet) bool {
+				return a.Name < b.Name
+			},
+			"created_date": func(a, b *inaAws.PermissionSet) bool {
+				return a.CreatedDate.Before(b.CreatedDate)
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	permissionSetsList := make([]ssoResp.PermissionSetResponse, 0, len(page.Items))
+	for _, permissionSet := range page.Items {
+		permissionSetsList = append(permissionSetsList, permissionSetResponse(permissionSet))
+	}
+
+	return responses.ListResponse(c, permissionSetsList, page.NextCursor)
+}
+
+// GetPermissionSet @Summary Get Permission Set
+// @Description Get a permission set along with its managed policies, customer managed policy references and
+// @Description inline policy
+// @ID aws-sso-permission-set-get
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Produce json
+// @Success 200 {object} ssoResp.PermissionSetResponse
+// @Router /aws/sso/permission-sets/{arn} [get]
+func (awsHandler *Handler) GetPermissionSet(c echo.Context) error {
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	permissionSet, err := ssoAdminSvc.GetPermissionSet(instanceArn, arnParam(c))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	policies, err := ssoAdminSvc.GetPermissionSetPolicies(instanceArn, permissionSet.Arn)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	permissionSetDetails := permissionSetResponse(permissionSet)
+	permissionSetDetails.Policies = permissionSetPoliciesResponse(policies)
+
+	return responses.Response(c, http.StatusOK, permissionSetDetails)
+}
+
+// CreatePermissionSet @Summary Create Permission Set
+// @Description Create a permission set without any policy
+// @ID aws-sso-permission-set-create
+// @Accept json
+// @Produce json
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.CreatePermissionSetRequest true "Permission set"
+// @Success 200 {object} ssoResp.PermissionSetResponse
+// @Router /aws/sso/permission-sets [post]
+func (awsHandler *Handler) CreatePermissionSet(c echo.Context) error {
+	createPermissionSetRequest := ssoReq.CreatePermissionSetRequest{}
+
+	if err := c.Bind(&createPermissionSetRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := createPermissionSetRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	permissionSet, err := ssoAdminSvc.CreatePermissionSet(instanceArn, createPermissionSetRequest.Name,
+		createPermissionSetRequest.Description, createPermissionSetRequest.SessionDuration,
+		createPermissionSetRequest.RelayState, createPermissionSetRequest.Tags)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, permissionSetResponse(permissionSet))
+}
+
+// UpdatePermissionSet @Summary Update Permission Set
+// @Description Update the description, session duration or relay state of a permission set. Omitted fields are
+// @Description left unchanged. The permission set must be provisioned again for the change to reach the accounts.
+// @ID aws-sso-permission-set-update
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.UpdatePermissionSetRequest true "Permission set update"
+// @Success 200 {object} ssoResp.PermissionSetResponse
+// @Router /aws/sso/permission-sets/{arn} [put]
+func (awsHandler *Handler) UpdatePermissionSet(c echo.Context) error {
+	updatePermissionSetRequest := ssoReq.UpdatePermissionSetRequest{}
+
+	if err := c.Bind(&updatePermissionSetRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := updatePermissionSetRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	permissionSet, err := ssoAdminSvc.UpdatePermissionSet(instanceArn, arnParam(c),
+		updatePermissionSetRequest.Description, updatePermissionSetRequest.SessionDuration,
+		updatePermissionSetRequest.RelayState)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, permissionSetResponse(permissionSet))
+}
+
+// DeletePermissionSet @Summary Delete Permission Set
+// @Description Delete a permission set, which must not be assigned to any account
+// @ID aws-sso-permission-set-delete
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn} [delete]
+func (awsHandler *Handler) DeletePermissionSet(c echo.Context) error {
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err = ssoAdminSvc.DeletePermissionSet(instanceArn, arnParam(c)); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// AttachPermissionSetManagedPolicy @Summary Attach Managed Policy To Permission Set
+// @Description Attach an AWS managed policy to a permission set
+// @ID aws-sso-permission-set-managed-policy-attach
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.ManagedPolicyRequest true "Managed policy"
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn}/managed-policies [post]
+func (awsHandler *Handler) AttachPermissionSetManagedPolicy(c echo.Context) error {
+	return awsHandler.updatePermissionSetManagedPolicy(c, (*inaAws.SSOAdminSvc).AttachManagedPolicyToPermissionSet)
+}
+
+// DetachPermissionSetManagedPolicy @Summary Detach Managed Policy From Permission Set
+// @Description Detach an AWS managed policy from a permission set
+// @ID aws-sso-permission-set-managed-policy-detach
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.ManagedPolicyRequest true "Managed policy"
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn}/managed-policies [delete]
+func (awsHandler *Handler) DetachPermissionSetManagedPolicy(c echo.Context) error {
+	return awsHandler.updatePermissionSetManagedPolicy(c, (*inaAws.SSOAdminSvc).DetachManagedPolicyFromPermissionSet)
+}
+
+func (awsHandler *Handler) updatePermissionSetManagedPolicy(c echo.Context,
+	update func(ssoAdminSvc *inaAws.SSOAdminSvc, instanceArn string, permissionSetArn string, policyArn string) error,
+) error {
+	managedPolicyRequest := ssoReq.ManagedPolicyRequest{}
+
+	if err := c.Bind(&managedPolicyRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := managedPolicyRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err = update(ssoAdminSvc, instanceArn, arnParam(c), managedPolicyRequest.PolicyArn); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// AttachPermissionSetCustomerManagedPolicy @Summary Attach Customer Managed Policy To Permission Set
+// @Description Add a customer managed policy reference to a permission set. The policy must exist with this name
+// @Description and path in every account the permission set is provisioned to.
+// @ID aws-sso-permission-set-customer-managed-policy-attach
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.CustomerManagedPolicyRequest true "Customer managed policy reference"
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn}/customer-managed-policies [post]
+func (awsHandler *Handler) AttachPermissionSetCustomerManagedPolicy(c echo.Context) error {
+	return awsHandler.updatePermissionSetCustomerManagedPolicy(c,
+		(*inaAws.SSOAdminSvc).AttachCustomerManagedPolicyToPermissionSet)
+}
+
+// DetachPermissionSetCustomerManagedPolicy @Summary Detach Customer Managed Policy From Permission Set
+// @Description Remove a customer managed policy reference from a permission set
+// @ID aws-sso-permission-set-customer-managed-policy-detach
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.CustomerManagedPolicyRequest true "Customer managed policy reference"
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn}/customer-managed-policies [delete]
+func (awsHandler *Handler) DetachPermissionSetCustomerManagedPolicy(c echo.Context) error {
+	return awsHandler.updatePermissionSetCustomerManagedPolicy(c,
+		(*inaAws.SSOAdminSvc).DetachCustomerManagedPolicyFromPermissionSet)
+}
+
+func (awsHandler *Handler) updatePermissionSetCustomerManagedPolicy(c echo.Context,
+	update func(ssoAdminSvc *inaAws.SSOAdminSvc, instanceArn string, permissionSetArn string,
+		reference inaAws.CustomerManagedPolicyReference) error,
+) error {
+	customerManagedPolicyRequest := ssoReq.CustomerManagedPolicyRequest{}
+
+	if err := c.Bind(&customerManagedPolicyRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := customerManagedPolicyRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	err = update(ssoAdminSvc, instanceArn, arnParam(c), inaAws.CustomerManagedPolicyReference{
+		Name: customerManagedPolicyRequest.Name,
+		Path: customerManagedPolicyRequest.Path,
+	})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// PutPermissionSetInlinePolicy @Summary Put Permission Set Inline Policy
+// @Description Set or replace the inline policy of a permission set
+// @ID aws-sso-permission-set-inline-policy-put
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param body body ssoReq.InlinePolicyRequest true "Inline policy"
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn}/inline-policy [put]
+func (awsHandler *Handler) PutPermissionSetInlinePolicy(c echo.Context) error {
+	inlinePolicyRequest := ssoReq.InlinePolicyRequest{}
+
+	if err := c.Bind(&inlinePolicyRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := inlinePolicyRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	err = ssoAdminSvc.PutPermissionSetInlinePolicy(instanceArn, arnParam(c), inlinePolicyRequest.Document)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// DeletePermissionSetInlinePolicy @Summary Delete Permission Set Inline Policy
+// @Description Remove the inline policy of a permission set
+// @ID aws-sso-permission-set-inline-policy-delete
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Produce json
+// @Success 200 {boolean} boolean
+// @Router /aws/sso/permission-sets/{arn}/inline-policy [delete]
+func (awsHandler *Handler) DeletePermissionSetInlinePolicy(c echo.Context) error {
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err = ssoAdminSvc.DeletePermissionSetInlinePolicy(instanceArn, arnParam(c)); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListPermissionSetAccounts @Summary List Permission Set Accounts
+// @Description Get the IDs of the accounts a permission set is provisioned to
+// @ID aws-sso-permission-set-accounts-list
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Produce json
+// @Success 200 {array} string
+// @Router /aws/sso/permission-sets/{arn}/accounts [get]
+func (awsHandler *Handler) ListPermissionSetAccounts(c echo.Context) error {
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accountIDs, err := ssoAdminSvc.ListPermissionSetAccounts(instanceArn, arnParam(c))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if accountIDs == nil {
+		accountIDs = []string{}
+	}
+
+	return responses.Response(c, http.StatusOK, accountIDs)
+}
+
+// ProvisionPermissionSet @Summary Provision Permission Set
+// @Description Provision the current version of a permission set to an account, or to every account it is
+// @Description already provisioned to when the account ID is omitted. With wait, the call returns once the
+// @Description provisioning is over.
+// @ID aws-sso-permission-set-provision
+// @Accept json
+// @Produce json
+// @Param arn path string true "URL encoded permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param wait query bool false "Wait for the provisioning to be over"
+// @Param body body ssoReq.ProvisionRequest false "Target account"
+// @Success 200 {object} ssoResp.OperationResponse
+// @Router /aws/sso/permission-sets/{arn}/provision [post]
+func (awsHandler *Handler) ProvisionPermissionSet(c echo.Context) error {
+	provisionRequest := ssoReq.ProvisionRequest{}
+
+	if err := c.Bind(&provisionRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := provisionRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	operation, err := ssoAdminSvc.ProvisionPermissionSet(instanceArn, arnParam(c), provisionRequest.AccountID)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if operation, err = waitForSSOOperation(c, ssoAdminSvc, instanceArn, operation); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, ssoOperationResponse(operation))
+}
+
+// ListAccountAssignments @Summary List Account Assignments
+// @Description Get a page of the users and groups a permission set is assigned to in an account
+// @ID aws-sso-assignments-list
+// @Param account_id query string true "Account ID"
+// @Param permission_set_arn query string true "Permission set ARN"
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the principal ID"
+// @Produce json
+// @Success 200 {object} responses.List{items=[]ssoResp.AccountAssignmentResponse}
+// @Router /aws/sso/assignments [get]
+func (awsHandler *Handler) ListAccountAssignments(c echo.Context) error {
+	params, err := pagination.ParseParams(c)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	accountID, permissionSetArn := c.QueryParam("account_id"), c.QueryParam("permission_set_arn")
+	if accountID == "" || permissionSetArn == "" {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	assignments, err := ssoAdminSvc.ListAccountAssignments(instanceArn, accountID, permissionSetArn)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[inaAws.AccountAssignment]{
+		Fetch: pagination.Single(assignments),
+		Match: func(assignment inaAws.AccountAssignment) (bool, error) {
+			return pagination.Contains(params.Filter, assignment.PrincipalID), nil
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	assignmentsList := make([]ssoResp.AccountAssignmentResponse, 0, len(page.Items))
+	for _, assignment := range page.Items {
+		assignmentsList = append(assignmentsList, accountAssignmentResponse(assignment))
+	}
+
+	return responses.ListResponse(c, assignmentsList, page.NextCursor)
+}
+
+// CreateAccountAssignment @Summary Create Account Assignment
+// @Description Assign a permission set to a user or a group in an account. With wait, the call returns once the
+// @Description assignment is over.
+// @ID aws-sso-assignment-create
+// @Accept json
+// @Produce json
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param wait query bool false "Wait for the assignment to be over"
+// @Param body body ssoReq.AccountAssignmentRequest true "Account assignment"
+// @Success 200 {object} ssoResp.OperationResponse
+// @Router /aws/sso/assignments [post]
+func (awsHandler *Handler) CreateAccountAssignment(c echo.Context) error {
+	return awsHandler.updateAccountAssignment(c, (*inaAws.SSOAdminSvc).CreateAccountAssignment)
+}
+
+// DeleteAccountAssignment @Summary Delete Account Assignment
+// @Description Remove the assignment of a permission set to a user or a group in an account. With wait, the
+// @Description call returns once the removal is over.
+// @ID aws-sso-assignment-delete
+// @Accept json
+// @Produce json
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param wait query bool false "Wait for the removal to be over"
+// @Param body body ssoReq.AccountAssignmentRequest true "Account assignment"
+// @Success 200 {object} ssoResp.OperationResponse
+// @Router /aws/sso/assignments [delete]
+func (awsHandler *Handler) DeleteAccountAssignment(c echo.Context) error {
+	return awsHandler.updateAccountAssignment(c, (*inaAws.SSOAdminSvc).DeleteAccountAssignment)
+}
+
+func (awsHandler *Handler) updateAccountAssignment(c echo.Context,
+	update func(ssoAdminSvc *inaAws.SSOAdminSvc, instanceArn string,
+		assignment inaAws.AccountAssignment) (*inaAws.SSOOperation, error),
+) error {
+	accountAssignmentRequest := ssoReq.AccountAssignmentRequest{}
+
+	if err := c.Bind(&accountAssignmentRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := accountAssignmentRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	operation, err := update(ssoAdminSvc, instanceArn, inaAws.AccountAssignment{
+		AccountID:        accountAssignmentRequest.AccountID,
+		PermissionSetArn: accountAssignmentRequest.PermissionSetArn,
+		PrincipalType:    accountAssignmentRequest.PrincipalType,
+		PrincipalID:      accountAssignmentRequest.PrincipalID,
+	})
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if operation, err = waitForSSOOperation(c, ssoAdminSvc, instanceArn, operation); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, ssoOperationResponse(operation))
+}
+
+// GetSSOOperation @Summary Get IAM Identity Center Operation
+// @Description Get the status of an asynchronous account assignment or provisioning operation. With wait, the
+// @Description call returns once the operation is over.
+// @ID aws-sso-operation-get
+// @Param id path string true "Request ID of the operation"
+// @Param kind query string true "Kind of the operation" Enums(assignment-creation, assignment-deletion, provisioning)
+// @Param instance_arn query string false "Instance ARN, the instance of the organization by default"
+// @Param wait query bool false "Wait for the operation to be over"
+// @Produce json
+// @Success 200 {object} ssoResp.OperationResponse
+// @Router /aws/sso/operations/{id} [get]
+func (awsHandler *Handler) GetSSOOperation(c echo.Context) error {
+	ssoAdminSvc, err := awsHandler.retrieveSSOAdminSvc()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	instanceArn, err := ssoInstanceArn(c, ssoAdminSvc)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	operation, err := ssoAdminSvc.GetOperation(instanceArn, c.QueryParam("kind"), c.Param("id"))
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if operation, err = waitForSSOOperation(c, ssoAdminSvc, instanceArn, operation); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, ssoOperationResponse(operation))
+}
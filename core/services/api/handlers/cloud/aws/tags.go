@@ -0,0 +1,315 @@
+package aws
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
+	"gitea/pcp-inariam/inariam/core/services/api/responses"
+	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// tagFiltersParam parses the repeated tag query parameters of a list request.
+func tagFiltersParam(c echo.Context) ([]iam.TagFilter, error) {
+	var filters []iam.TagFilter
+	for _, value := range c.QueryParams()["tag"] {
+		filter, err := iam.ParseTagFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// listResourceTags lists the tags of the IAM resource identified by id.
+func (awsHandler *Handler) listResourceTags(c echo.Context, id string,
+	list func(*iam.Svc, string) (map[string]string, error),
+) error {
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	tags, err := list(awsSession.IamSvc, id)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.TagsResponse{Tags: tags})
+}
+
+// tagResource adds the tags of a TagResourceRequest to the IAM resource identified by id and returns its tags.
+func (awsHandler *Handler) tagResource(c echo.Context, id string,
+	tag func(*iam.Svc, string, map[string]string) error,
+	list func(*iam.Svc, string) (map[string]string, error),
+) error {
+	tagResourceRequest := req.TagResourceRequest{}
+	if err := c.Bind(&tagResourceRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := tagResourceRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err := tag(awsSession.IamSvc, id, tagResourceRequest.Tags); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	tags, err := list(awsSession.IamSvc, id)
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, resp.TagsResponse{Tags: tags})
+}
+
+// untagResource removes the keys of an UntagResourceRequest from the IAM resource identified by id.
+func (awsHandler *Handler) untagResource(c echo.Context, id string,
+	untag func(*iam.Svc, string, []string) error,
+) error {
+	untagResourceRequest := req.UntagResourceRequest{}
+	if err := c.Bind(&untagResourceRequest); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, responses.HttpErrBadRequest)
+	}
+
+	if err := untagResourceRequest.Validate(); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(c, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	if err := untag(awsSession.IamSvc, id, untagResourceRequest.Keys); err != nil {
+		return responses.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	return responses.Response(c, http.StatusOK, true)
+}
+
+// ListUserTags @Summary List User Tags
+// @Description Get the tags of an IAM user
+// @ID aws-user-tags-list
+// @Param id path string true "Username"
+// @Produce json
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/users/{id}/tags [get]
+func (awsHandler *Handler) ListUserTags(c echo.Context) error {
+	return awsHandler.listResourceTags(c, c.Param("id"), (*iam.Svc).ListUserTags)
+}
+
+// TagUser @Summary Tag User
+// @Description Add or overwrite tags on an IAM user
+// @ID aws-user-tag
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.TagResourceRequest true "Tags"
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/users/{id}/tags [post]
+func (awsHandler *Handler) TagUser(c echo.Context) error {
+	return awsHandler.tagResource(c, c.Param("id"), (*iam.Svc).TagUser, (*iam.Svc).ListUserTags)
+}
+
+// UntagUser @Summary Untag User
+// @Description Remove tags from an IAM user
+// @ID aws-user-untag
+// @Accept json
+// @Produce json
+// @Param id path string true "Username"
+// @Param body body req.UntagResourceRequest true "Tag keys"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/users/{id}/tags [delete]
+func (awsHandler *Handler) UntagUser(c echo.Context) error {
+	return awsHandler.untagResource(c, c.Param("id"), (*iam.Svc).UntagUser)
+}
+
+// ListRoleTags @Summary List Role Tags
+// @Description Get the tags of an IAM role
+// @ID aws-role-tags-list
+// @Param id path string true "Role Name"
+// @Produce json
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/roles/{id}/tags [get]
+func (awsHandler *Handler) ListRoleTags(c echo.Context) error {
+	return awsHandler.listResourceTags(c, c.Param("id"), (*iam.Svc).ListRoleTags)
+}
+
+// TagRole @Summary Tag Role
+// @Description Add or overwrite tags on an IAM role
+// @ID aws-role-tag
+// @Accept json
+// @Produce json
+// @Param id path string true "Role Name"
+// @Param body body req.TagResourceRequest true "Tags"
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/roles/{id}/tags [post]
+func (awsHandler *Handler) TagRole(c echo.Context) error {
+	return awsHandler.tagResource(c, c.Param("id"), (*iam.Svc).TagRole, (*iam.Svc).ListRoleTags)
+}
+
+// UntagRole @Summary Untag Role
+// @Description Remove tags from an IAM role
+// @ID aws-role-untag
+// @Accept json
+// @Produce json
+// @Param id path string true "Role Name"
+// @Param body body req.UntagResourceRequest true "Tag keys"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/roles/{id}/tags [delete]
+func (awsHandler *Handler) UntagRole(c echo.Context) error {
+	return awsHandler.untagResource(c, c.Param("id"), (*iam.Svc).UntagRole)
+}
+
+// listPolicyTags lists the tags of a managed IAM policy given by name or ARN.
+func listPolicyTags(iamSvc *iam.Svc, policy string) (map[string]string, error) {
+	policyArn, err := iamSvc.PolicyArn(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return iamSvc.ListPolicyTags(policyArn)
+}
+
+// tagPolicy tags a managed IAM policy given by name or ARN.
+func tagPolicy(iamSvc *iam.Svc, policy string, tags map[string]string) error {
+	policyArn, err := iamSvc.PolicyArn(policy)
+	if err != nil {
+		return err
+	}
+
+	return iamSvc.TagPolicy(policyArn, tags)
+}
+
+// untagPolicy untags a managed IAM policy given by name or ARN.
+func untagPolicy(iamSvc *iam.Svc, policy string, keys []string) error {
+	policyArn, err := iamSvc.PolicyArn(policy)
+	if err != nil {
+		return err
+	}
+
+	return iamSvc.UntagPolicy(policyArn, keys)
+}
+
+// ListPolicyTags @Summary List Policy Tags
+// @Description Get the tags of a managed IAM policy
+// @ID aws-policy-tags-list
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Produce json
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/policies/{arn}/tags [get]
+func (awsHandler *Handler) ListPolicyTags(c echo.Context) error {
+	return awsHandler.listResourceTags(c, arnParam(c), listPolicyTags)
+}
+
+// TagPolicy @Summary Tag Policy
+// @Description Add or overwrite tags on a managed IAM policy
+// @ID aws-policy-tag
+// @Accept json
+// @Produce json
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Param body body req.TagResourceRequest true "Tags"
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/policies/{arn}/tags [post]
+func (awsHandler *Handler) TagPolicy(c echo.Context) error {
+	return awsHandler.tagResource(c, arnParam(c), tagPolicy, listPolicyTags)
+}
+
+// UntagPolicy @Summary Untag Policy
+// @Description Remove tags from a managed IAM policy
+// @ID aws-policy-untag
+// @Accept json
+// @Produce json
+// @Param arn path string true "Policy name or URL-encoded ARN"
+// @Param body body req.UntagResourceRequest true "Tag keys"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/policies/{arn}/tags [delete]
+func (awsHandler *Handler) UntagPolicy(c echo.Context) error {
+	return awsHandler.untagResource(c, arnParam(c), untagPolicy)
+}
+
+// ListInstanceProfileTags @Summary List Instance Profile Tags
+// @Description Get the tags of an IAM instance profile
+// @ID aws-instance-profile-tags-list
+// @Param id path string true "Instance Profile Name"
+// @Produce json
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/instance-profiles/{id}/tags [get]
+func (awsHandler *Handler) ListInstanceProfileTags(c echo.Context) error {
+	return awsHandler.listResourceTags(c, c.Param("id"), (*iam.Svc).ListInstanceProfileTags)
+}
+
+// TagInstanceProfile @Summary Tag Instance Profile
+// @Description Add or overwrite tags on an IAM instance profile
+// @ID aws-instance-profile-tag
+// @Accept json
+// @Produce json
+// @Param id path string true "Instance Profile Name"
+// @Param body body req.TagResourceRequest true "Tags"
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/instance-profiles/{id}/tags [post]
+func (awsHandler *Handler) TagInstanceProfile(c echo.Context) error {
+	return awsHandler.tagResource(c, c.Param("id"), (*iam.Svc).TagInstanceProfile, (*iam.Svc).ListInstanceProfileTags)
+}
+
+// UntagInstanceProfile @Summary Untag Instance Profile
+// @Description Remove tags from an IAM instance profile
+// @ID aws-instance-profile-untag
+// @Accept json
+// @Produce json
+// @Param id path string true "Instance Profile Name"
+// @Param body body req.UntagResourceRequest true "Tag keys"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/instance-profiles/{id}/tags [delete]
+func (awsHandler *Handler) UntagInstanceProfile(c echo.Context) error {
+	return awsHandler.untagResource(c, c.Param("id"), (*iam.Svc).UntagInstanceProfile)
+}
+
+// ListOIDCProviderTags @Summary List OIDC Provider Tags
+// @Description Get the tags of an IAM OpenID Connect identity provider
+// @ID aws-oidc-provider-tags-list
+// @Param arn path string true "OIDC Provider ARN"
+// @Produce json
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/oidc-providers/{arn}/tags [get]
+func (awsHandler *Handler) ListOIDCProviderTags(c echo.Context) error {
+	return awsHandler.listResourceTags(c, arnParam(c), (*iam.Svc).ListOpenIDConnectProviderTags)
+}
+
+// TagOIDCProvider @Summary Tag OIDC Provider
+// @Description Add or overwrite tags on an IAM OpenID Connect identity provider
+// @ID aws-oidc-provider-tag
+// @Accept json
+// @Produce json
+// @Param arn path string true "OIDC Provider ARN"
+// @Param body body req.TagResourceRequest true "Tags"
+// @Success 200 {object} resp.TagsResponse
+// @Router /aws/iam/oidc-providers/{arn}/tags [post]
+func (awsHandler *Handler) TagOIDCProvider(c echo.Context) error {
+	return awsHandler.tagResource(c, arnParam(c), (*iam.Svc).TagOpenIDConnectProvider,
+		(*iam.Svc).ListOpenIDConnectProviderTags)
+}
+
+// UntagOIDCProvider @Summary Untag OIDC Provider
+// @Description Remove tags from an IAM OpenID Connect identity provider
+// @ID aws-oidc-provider-untag
+// @Accept json
+// @Produce json
+// @Param arn path string true "OIDC Provider ARN"
+// @Param body body req.UntagResourceRequest true "Tag keys"
+// @Success 200 {boolean} boolean
+// @Router /aws/iam/oidc-providers/{arn}/tags [delete]
+func (awsHandler *Handler) UntagOIDCProvider(c echo.Context) error {
+	return awsHandler.untagResource(c, arnParam(c), (*iam.Svc).UntagOpenIDConnectProvider)
+}
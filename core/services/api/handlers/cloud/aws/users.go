@@ -1,49 +1,99 @@
 package aws
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
 	"github.com/labstack/echo/v4"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/aws/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/iam"
+	inaAws "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 )
 
 // @Summary List Users
-// @Description Get a list of IAM users
+// @Description Get a page of IAM users
 // @ID list-users
+// @Param tag query []string false "Tag filter, key=value, key:value or key" collectionFormat(multi)
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the user name, ID or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date)
 // @Produce json
-// @Success 200 {array} resp.UserDetailResponse
+// @Success 200 {object} responses.List{items=[]resp.UserDetailResponse}
 // @Router /users [get]
 func (awsHandler *Handler) ListUsers(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	tagFilters, err := tagFiltersParam(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
 	openedSession, err := awsHandler.RetrieveAwsIamSession()
 	if err != nil {
 		return responses.ErrorResponse(ctx, http.StatusInternalServerError, "Failed to retrieve IAM session")
 	}
 
-	users, err := openedSession.IamSvc.ListIamUsers()
+	userTags := make(map[string]map[string]string)
+	page, err := pagination.Lister[*awsIam.User]{
+		Fetch: openedSession.IamSvc.ListIamUsersPage,
+		Match: func(user *awsIam.User) (bool, error) {
+			if params.Filter != "" &&
+				!pagination.Contains(params.Filter, aws.StringValue(user.UserName), aws.StringValue(user.UserId),
+					aws.StringValue(user.Arn)) {
+				return false, nil
+			}
+			if len(tagFilters) == 0 {
+				return true, nil
+			}
+
+			tags, err := openedSession.IamSvc.ListUserTags(aws.StringValue(user.UserName))
+			if err != nil {
+				return false, err
+			}
+			userTags[aws.StringValue(user.UserName)] = tags
+			return iam.MatchTagFilters(tagFilters, tags), nil
+		},
+		Less: map[string]func(a, b *awsIam.User) bool{
+			"name": func(a, b *awsIam.User) bool {
+				return aws.StringValue(a.UserName) < aws.StringValue(b.UserName)
+			},
+			"created_date": func(a, b *awsIam.User) bool {
+				return aws.TimeValue(a.CreateDate).Before(aws.TimeValue(b.CreateDate))
+			},
+		},
+	}.List(params)
 	if err != nil {
-		return responses.ErrorResponse(ctx, http.StatusBadRequest, resp.HttpErrMissingUserName)
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	var usersDetails []resp.UserDetailResponse
-	for _, user := range users {
-		detail := resp.UserDetailResponse{
-			Username: *user.UserName,
-			UserID:   *user.UserId,
-			UserArn:  *user.Arn,
-		}
-		usersDetails = append(usersDetails, detail)
+	usersDetails := make([]resp.UserDetailResponse, 0, len(page.Items))
+	for _, user := range page.Items {
+		usersDetails = append(usersDetails, resp.UserDetailResponse{
+			Username: aws.StringValue(user.UserName),
+			UserID:   aws.StringValue(user.UserId),
+			UserArn:  aws.StringValue(user.Arn),
+			Tags:     userTags[aws.StringValue(user.UserName)],
+		})
 	}
 
-	return responses.Response(ctx, http.StatusOK, usersDetails)
+	return responses.ListResponse(ctx, usersDetails, page.NextCursor)
 }
 
 // @Summary Get User by ID
 // @Description Get IAM user details by username
 // @ID get-user-by-id
 // @Param id path string true "Username"
+// @Param history query bool false "Add the latest changes of the user recorded by CloudTrail"
 // @Produce json
 // @Success 200 {object} resp.UserDetailResponse
 // @Router /users/{id} [get]
@@ -63,16 +113,31 @@ func (awsHandler *Handler) GetUser(ctx echo.Context) error {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, resp.HttpErrMissingUserName)
 	}
 
+	inlinePolicies, err := awsSession.IamSvc.ListUserInlinePolicies(username)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	changeHistory, err := awsHandler.changeHistory(ctx, inaAws.IamResourceTypeUser, username)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
 	return responses.Response(ctx, http.StatusOK, resp.UserDetailResponse{
-		Username: *userDetails.UserName,
-		UserID:   *userDetails.UserId,
-		UserArn:  *userDetails.Arn,
+		Username:               *userDetails.UserName,
+		UserID:                 *userDetails.UserId,
+		UserArn:                *userDetails.Arn,
+		InlinePolicies:         inlinePolicies,
+		PermissionsBoundaryArn: iam.PermissionsBoundaryArn(userDetails.PermissionsBoundary),
+		Tags:                   iam.FromIamTags(userDetails.Tags),
+		ChangeHistory:          changeHistory,
 	},
 	)
 }
 
 // @Summary Create User
-// @Description Create a new IAM user
+// @Description Create a new IAM user. With console access, the user is deleted again when its login profile
+// @Description cannot be created.
 // @ID create-user
 // @Accept json
 // @Produce json
@@ -97,15 +162,30 @@ func (awsHandler *Handler) CreateUser(ctx echo.Context) error {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
-	createdUser, err := awsSess.IamSvc.CreateIamUser(createUserRequest.Username)
+	createdUser, err := awsSess.IamSvc.CreateIamUser(createUserRequest.Username, createUserRequest.Tags)
 	if err != nil {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
 	}
 
+	temporaryPassword := ""
+	if createUserRequest.ConsoleAccess {
+		loginProfile, err := awsSess.IamSvc.CreateLoginProfile(createUserRequest.Username, "", true)
+		if err != nil {
+			// The user is removed so that a failed creation does not leave a user without console access behind.
+			if deleteErr := awsSess.IamSvc.DeleteIamUser(createUserRequest.Username); deleteErr != nil {
+				err = errors.Join(err, deleteErr)
+			}
+			return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		temporaryPassword = loginProfile.Password
+	}
+
 	return responses.Response(ctx, http.StatusCreated, resp.UserDetailResponse{
-		Username: createUserRequest.Username,
-		UserID:   *createdUser.UserId,
-		UserArn:  *createdUser.Arn,
+		Username:          createUserRequest.Username,
+		UserID:            *createdUser.UserId,
+		UserArn:           *createdUser.Arn,
+		Tags:              createUserRequest.Tags,
+		TemporaryPassword: temporaryPassword,
 	},
 	)
 }
@@ -114,8 +194,11 @@ func (awsHandler *Handler) CreateUser(ctx echo.Context) error {
 // @Description Delete an IAM user by username
 // @ID delete-user
 // @Param id path string true "Username"
+// @Param cascade query bool false "Remove the credentials, memberships and policies of the user first"
+// @Param dry_run query bool false "With cascade, only return the planned steps"
 // @Produce json
 // @Success 200 {string} string "IAM user deleted successfully"
+// @Success 207 {object} resp.DeletePlanResponse "Cascading delete with failed steps"
 // @Router /users/{id} [delete]
 func (awsHandler *Handler) DeleteUser(ctx echo.Context) error {
 	deleteUserRequest := req.DeleteUserRequest{}
@@ -134,6 +217,12 @@ func (awsHandler *Handler) DeleteUser(ctx echo.Context) error {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, responses.HttpErrOpenedSession)
 	}
 
+	if cascadeRequested(ctx) {
+		return cascadeDelete(ctx, func() (*iam.DeletePlan, error) {
+			return awsSess.IamSvc.PlanUserDeletion(deleteUserRequest.Username)
+		})
+	}
+
 	err = awsSess.IamSvc.DeleteIamUser(deleteUserRequest.Username)
 	if err != nil {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, responses.HttpErrOpenedSession)
@@ -181,3 +270,65 @@ func (awsHandler *Handler) UpdateUser(ctx echo.Context) error {
 		UserArn:  *updatedUser.Arn,
 	})
 }
+
+// @Summary List User Groups
+// @Description Get a page of the IAM groups an IAM user belongs to
+// @ID list-user-groups
+// @Param id path string true "Username"
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the group name, ID or ARN"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, created_date, -created_date)
+// @Produce json
+// @Success 200 {object} responses.List{items=[]resp.Group}
+// @Router /aws/iam/users/{id}/groups [get]
+func (awsHandler *Handler) ListUserGroups(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	username := ctx.Param("id")
+
+	awsSession, err := awsHandler.RetrieveAwsIamSession()
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusInternalServerError, responses.HttpErrOpenedSession)
+	}
+
+	groups, err := awsSession.IamSvc.ListGroupsForUser(username)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	page, err := pagination.Lister[*awsIam.Group]{
+		Fetch: pagination.Single(groups),
+		Match: func(group *awsIam.Group) (bool, error) {
+			return pagination.Contains(params.Filter, aws.StringValue(group.GroupName), aws.StringValue(group.GroupId),
+				aws.StringValue(group.Arn)), nil
+		},
+		Less: map[string]func(a, b *awsIam.Group) bool{
+			"name": func(a, b *awsIam.Group) bool {
+				return aws.StringValue(a.GroupName) < aws.StringValue(b.GroupName)
+			},
+			"created_date": func(a, b *awsIam.Group) bool {
+				return aws.TimeValue(a.CreateDate).Before(aws.TimeValue(b.CreateDate))
+			},
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	groupsList := make([]resp.Group, 0, len(page.Items))
+	for _, group := range page.Items {
+		groupsList = append(groupsList, resp.Group{
+			Arn:        *group.Arn,
+			CreateDate: *group.CreateDate,
+			Id:         *group.GroupId,
+			Name:       *group.GroupName,
+			Path:       *group.Path,
+		})
+	}
+
+	return responses.ListResponse(ctx, groupsList, page.NextCursor)
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	admin "google.golang.org/api/admin/directory/v1"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/gcp/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/gcp"
@@ -13,12 +15,21 @@ import (
 
 // ListGroups
 // @Summary Get a list of IAM GCP groups
-// @Description Retrieves a list of IAM GCP groups using the Google Cloud IAM service.
+// @Description Retrieves a page of IAM GCP groups using the Google Workspace Admin SDK.
 // @ID gcp-list-groups
 // @Produce json
-// @Success 200 {array} []resp.GroupDetails
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the group name, email or description"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, email, -email)
+// @Success 200 {object} responses.List{items=[]resp.GroupDetails}
 // @Router /gcp/iam/groups [get]
 func (handler *Handler) ListGroups(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
 	gcpSession, err := handler.openAdminIamSession()
 	if err != nil {
 		return responses.ErrorResponse(
@@ -28,13 +39,22 @@ func (handler *Handler) ListGroups(ctx echo.Context) error {
 		)
 	}
 
-	groups, err := gcpSession.IamAdminGCPService.ListGroups()
+	page, err := pagination.Lister[*admin.Group]{
+		Fetch: gcpSession.IamAdminGCPService.ListGroupsPage,
+		Match: func(group *admin.Group) (bool, error) {
+			return pagination.Contains(params.Filter, group.Name, group.Email, group.Description), nil
+		},
+		Less: map[string]func(a, b *admin.Group) bool{
+			"name":  func(a, b *admin.Group) bool { return a.Name < b.Name },
+			"email": func(a, b *admin.Group) bool { return a.Email < b.Email },
+		},
+	}.List(params)
 	if err != nil {
 		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	var groupsList []resp.GroupDetails
-	for _, group := range groups {
+	groupsList := make([]resp.GroupDetails, 0, len(page.Items))
+	for _, group := range page.Items {
 		groupsList = append(groupsList, resp.GroupDetails{
 			Name:        group.Name,
 			Id:          group.Id,
@@ -42,7 +62,7 @@ func (handler *Handler) ListGroups(ctx echo.Context) error {
 		})
 	}
 
-	return responses.Response(ctx, http.StatusOK, groupsList)
+	return responses.ListResponse(ctx, groupsList, page.NextCursor)
 }
 
 // GetGroup
@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	gcpIam "google.golang.org/api/iam/v1"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/gcp/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/gcp"
@@ -161,13 +163,21 @@ func (handler *Handler) DeleteIamRole(ctx echo.Context) error {
 
 // ListRoles
 // @Summary List IAM Roles
-// @Description List all IAM roles the project
+// @Description List a page of the IAM roles of the project
 // @ID gcp-list-role
 // @Accept json
 // @Produce json
-// @Success 200 {array}  []resp.RoleResponse
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the role name, title or description"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, title, -title)
+// @Success 200 {object} responses.List{items=[]resp.RoleResponse}
 // @Router /gcp/roles [get]
 func (handler *Handler) ListRoles(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
 
 	iamSession, err := handler.openIamSession()
 
@@ -179,10 +189,22 @@ func (handler *Handler) ListRoles(ctx echo.Context) error {
 		)
 	}
 
-	roles, err := iamSession.IamGCPService.ListIamRoles()
+	page, err := pagination.Lister[*gcpIam.Role]{
+		Fetch: iamSession.IamGCPService.ListIamRolesPage,
+		Match: func(role *gcpIam.Role) (bool, error) {
+			return pagination.Contains(params.Filter, role.Name, role.Title, role.Description), nil
+		},
+		Less: map[string]func(a, b *gcpIam.Role) bool{
+			"name":  func(a, b *gcpIam.Role) bool { return a.Name < b.Name },
+			"title": func(a, b *gcpIam.Role) bool { return a.Title < b.Title },
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
 
-	rolesResp := make([]*resp.RoleResponse, len(roles))
-	for i, role := range roles {
+	rolesResp := make([]*resp.RoleResponse, len(page.Items))
+	for i, role := range page.Items {
 		rolesResp[i] = &resp.RoleResponse{
 			Name:        role.Name,
 			Title:       role.Title,
@@ -192,11 +214,7 @@ func (handler *Handler) ListRoles(ctx echo.Context) error {
 		}
 	}
 
-	if err != nil {
-		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
-	}
-
-	return responses.Response(ctx, http.StatusOK, rolesResp)
+	return responses.ListResponse(ctx, rolesResp, page.NextCursor)
 }
 
 // GetRole
@@ -6,6 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 	iam "google.golang.org/api/iam/v1"
 
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
 	req "gitea/pcp-inariam/inariam/core/services/api/requests/gcp/iam"
 	"gitea/pcp-inariam/inariam/core/services/api/responses"
 	resp "gitea/pcp-inariam/inariam/core/services/api/responses/gcp"
@@ -58,13 +59,21 @@ func (handler *Handler) CreateServiceAccount(ctx echo.Context) error {
 
 // ListServiceAccounts
 // @Summary List Service Accounts
-// @Description Get a list of IAM service accounts
+// @Description Get a page of IAM service accounts
 // @ID list-service-accounts
 // @Produce json
 // @Param project_id path string true "Project ID"
-// @Success 200 {array} resp.ServiceAccountDetails
+// @Param limit query int false "Page size, 50 by default and 1000 at most"
+// @Param cursor query string false "Cursor of the page, from the next_cursor of the previous page"
+// @Param filter query string false "Case insensitive filter on the service account name, email or display name"
+// @Param sort query string false "Sort field, prefixed with - for a descending order" Enums(name, -name, email, -email, display_name, -display_name)
+// @Success 200 {object} responses.List{items=[]resp.ServiceAccountDetails}
 // @Router /service-accounts/{project_id} [get]
 func (handler *Handler) ListServiceAccounts(ctx echo.Context) error {
+	params, err := pagination.ParseParams(ctx)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
 
 	iamSession, err := handler.openIamSession()
 	if err != nil {
@@ -75,11 +84,24 @@ func (handler *Handler) ListServiceAccounts(ctx echo.Context) error {
 		)
 	}
 
-	serviceAccs, err := iamSession.IamGCPService.ListIamServiceAccounts()
+	page, err := pagination.Lister[*iam.ServiceAccount]{
+		Fetch: iamSession.IamGCPService.ListIamServiceAccountsPage,
+		Match: func(account *iam.ServiceAccount) (bool, error) {
+			return pagination.Contains(params.Filter, account.Name, account.Email, account.DisplayName), nil
+		},
+		Less: map[string]func(a, b *iam.ServiceAccount) bool{
+			"name":         func(a, b *iam.ServiceAccount) bool { return a.Name < b.Name },
+			"email":        func(a, b *iam.ServiceAccount) bool { return a.Email < b.Email },
+			"display_name": func(a, b *iam.ServiceAccount) bool { return a.DisplayName < b.DisplayName },
+		},
+	}.List(params)
+	if err != nil {
+		return responses.ErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
 
 	// Map the response to the desired format
-	var details []resp.ServiceAccountDetails
-	for _, account := range serviceAccs {
+	details := make([]resp.ServiceAccountDetails, 0, len(page.Items))
+	for _, account := range page.Items {
 		detail := resp.ServiceAccountDetails{
 			Name:        account.Name,
 			Description: account.Description,
@@ -93,7 +115,7 @@ func (handler *Handler) ListServiceAccounts(ctx echo.Context) error {
 	}
 
 	// Return the response
-	return responses.Response(ctx, http.StatusOK, details)
+	return responses.ListResponse(ctx, details, page.NextCursor)
 }
 
 // EnableServiceAccount
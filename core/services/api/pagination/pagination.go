@@ -0,0 +1,270 @@
+// Package pagination implements the cursor pagination, filtering and sorting shared by the list endpoints.
+//
+// A list request accepts the limit, cursor, filter and sort query parameters. Without sort, items are streamed
+// from the provider one page at a time and the opaque cursor wraps the provider marker or page token, with the
+// position reached inside that page. With sort, every page is fetched so that the items can be ordered, and the
+// cursor holds the position reached in the sorted list.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DefaultLimit is the number of items returned when the limit query parameter is missing.
+	DefaultLimit = 50
+	// MaxLimit is the largest accepted limit query parameter.
+	MaxLimit = 1000
+	// ProviderPageSize is the number of items requested from the provider per call. It does not depend on the
+	// requested limit so that a cursor stays valid when the limit changes between two requests.
+	ProviderPageSize int64 = 100
+)
+
+const (
+	ErrInvalidLimit   = "invalid limit, expected a number between 1 and 1000"
+	ErrInvalidCursor  = "invalid cursor"
+	ErrCursorMismatch = "cursor was issued for another filter or sort"
+	ErrInvalidSort    = "invalid sort field"
+)
+
+// Params are the pagination, filter and sort query parameters of a list request.
+type Params struct {
+	Limit  int
+	Cursor Cursor
+	// Filter is matched against the items of the list, an empty filter matching every item.
+	Filter string
+	// Sort is the field the items are sorted by, prefixed with - for a descending order. Items are returned in
+	// the provider order when empty.
+	Sort string
+}
+
+// Cursor is the position reached by a list request. It is sent to clients as an opaque string.
+type Cursor struct {
+	// Marker is the provider marker or page token of the page holding the next item.
+	Marker string `json:"m,omitempty"`
+	// Offset is the position of the next item in the page starting at Marker, or in the sorted list.
+	Offset int    `json:"o,omitempty"`
+	Filter string `json:"f,omitempty"`
+	Sort   string `json:"s,omitempty"`
+}
+
+// Encode returns the opaque representation of the cursor.
+func (cursor Cursor) Encode() string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// DecodeCursor parses a cursor returned by Encode.
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, fmt.Errorf("DecodeCursor: %s %w", ErrInvalidCursor, err)
+	}
+	if err = json.Unmarshal(content, &cursor); err != nil {
+		return cursor, fmt.Errorf("DecodeCursor: %s %w", ErrInvalidCursor, err)
+	}
+	if cursor.Offset < 0 {
+		return cursor, fmt.Errorf("DecodeCursor: %w", errors.New(ErrInvalidCursor))
+	}
+
+	return cursor, nil
+}
+
+// ParseParams reads the limit, cursor, filter and sort query parameters of a list request. A cursor is only
+// accepted with the filter and sort of the request that issued it.
+func ParseParams(c echo.Context) (Params, error) {
+	params := Params{
+		Limit:  DefaultLimit,
+		Filter: c.QueryParam("filter"),
+		Sort:   c.QueryParam("sort"),
+	}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return params, errors.New(ErrInvalidLimit)
+		}
+		params.Limit = limit
+	}
+
+	if value := c.QueryParam("cursor"); value != "" {
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return params, errors.New(ErrInvalidCursor)
+		}
+		if cursor.Filter != params.Filter || cursor.Sort != params.Sort {
+			return params, errors.New(ErrCursorMismatch)
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// Contains reports whether one of the values contains the filter, ignoring case.
+func Contains(filter string, values ...string) bool {
+	filter = strings.ToLower(filter)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Page is one page of a list, NextCursor being empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// Lister describes how a list endpoint fetches, filters and sorts its items.
+type Lister[T any] struct {
+	// Fetch retrieves at most pageSize items starting at marker, an empty marker starting at the first item.
+	// It returns the marker of the next page, empty on the last page.
+	Fetch func(marker string, pageSize int64) ([]T, string, error)
+	// Match reports whether an item is part of the list. Every item is part of the list when nil.
+	Match func(item T) (bool, error)
+	// Less maps the fields accepted by the sort query parameter to an ascending comparison of two items.
+	Less map[string]func(a, b T) bool
+}
+
+// List returns the page of items selected by the request parameters.
+func (lister Lister[T]) List(params Params) (Page[T], error) {
+	if params.Sort != "" {
+		return lister.listSorted(params)
+	}
+
+	page := Page[T]{Items: make([]T, 0, params.Limit)}
+
+	marker, offset := params.Cursor.Marker, params.Cursor.Offset
+	for {
+		items, nextMarker, err := lister.Fetch(marker, ProviderPageSize)
+		if err != nil {
+			return page, fmt.Errorf("List: %w", err)
+		}
+
+		for i := offset; i < len(items); i++ {
+			ok, err := lister.match(items[i])
+			if err != nil {
+				return page, fmt.Errorf("List: %w", err)
+			}
+			if !ok {
+				continue
+			}
+
+			page.Items = append(page.Items, items[i])
+			if len(page.Items) < params.Limit {
+				continue
+			}
+
+			switch {
+			case i+1 < len(items):
+				page.NextCursor = params.cursor(marker, i+1)
+			case nextMarker != "":
+				page.NextCursor = params.cursor(nextMarker, 0)
+			}
+			return page, nil
+		}
+
+		if nextMarker == "" {
+			return page, nil
+		}
+		marker, offset = nextMarker, 0
+	}
+}
+
+// listSorted fetches every item to return the requested page of the sorted list.
+func (lister Lister[T]) listSorted(params Params) (Page[T], error) {
+	page := Page[T]{Items: []T{}}
+
+	field, descending := strings.CutPrefix(params.Sort, "-")
+	less, ok := lister.Less[field]
+	if !ok {
+		return page, fmt.Errorf("List: %w", errors.New(ErrInvalidSort))
+	}
+
+	var items []T
+	marker := ""
+	for {
+		fetched, nextMarker, err := lister.Fetch(marker, ProviderPageSize)
+		if err != nil {
+			return page, fmt.Errorf("List: %w", err)
+		}
+
+		for _, item := range fetched {
+			ok, err := lister.match(item)
+			if err != nil {
+				return page, fmt.Errorf("List: %w", err)
+			}
+			if ok {
+				items = append(items, item)
+			}
+		}
+
+		if nextMarker == "" {
+			break
+		}
+		marker = nextMarker
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if descending {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+
+	start := params.Cursor.Offset
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + params.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page.Items = items[start:end]
+	if end < len(items) {
+		page.NextCursor = params.cursor("", end)
+	}
+
+	return page, nil
+}
+
+// match reports whether an item is part of the list.
+func (lister Lister[T]) match(item T) (bool, error) {
+	if lister.Match == nil {
+		return true, nil
+	}
+
+	return lister.Match(item)
+}
+
+// cursor returns the encoded cursor of the given position for the filter and sort of the request.
+func (params Params) cursor(marker string, offset int) string {
+	return Cursor{
+		Marker: marker,
+		Offset: offset,
+		Filter: params.Filter,
+		Sort:   params.Sort,
+	}.Encode()
+}
+
+// Single returns a Fetch function serving items already retrieved as a single page.
+func Single[T any](items []T) func(marker string, pageSize int64) ([]T, string, error) {
+	return func(string, int64) ([]T, string, error) {
+		return items, "", nil
+	}
+}
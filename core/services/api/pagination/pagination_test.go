@@ -0,0 +1,143 @@
+package pagination_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/core/services/api/pagination"
+)
+
+// fetchNumbers serves the numbers from 1 to count in provider pages of pageSize items, the marker being the
+// index of the first item of a page.
+func fetchNumbers(count int) func(marker string, pageSize int64) ([]int, string, error) {
+	return func(marker string, pageSize int64) ([]int, string, error) {
+		start := 0
+		if marker != "" {
+			start, _ = strconv.Atoi(marker)
+		}
+
+		var items []int
+		for i := start; i < count && len(items) < int(pageSize); i++ {
+			items = append(items, i+1)
+		}
+
+		end := start + len(items)
+		if end >= count {
+			return items, "", nil
+		}
+		return items, strconv.Itoa(end), nil
+	}
+}
+
+func newContext(query string) echo.Context {
+	request := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return echo.New().NewContext(request, httptest.NewRecorder())
+}
+
+func TestParseParams(t *testing.T) {
+	params, err := pagination.ParseParams(newContext(""))
+	assert.NoError(t, err)
+	assert.Equal(t, pagination.DefaultLimit, params.Limit)
+
+	params, err = pagination.ParseParams(newContext("limit=5&filter=admin&sort=-name"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, params.Limit)
+	assert.Equal(t, "admin", params.Filter)
+	assert.Equal(t, "-name", params.Sort)
+
+	for _, query := range []string{"limit=0", "limit=1001", "limit=ten"} {
+		_, err = pagination.ParseParams(newContext(query))
+		assert.EqualError(t, err, pagination.ErrInvalidLimit, query)
+	}
+
+	_, err = pagination.ParseParams(newContext("cursor=%21%21"))
+	assert.EqualError(t, err, pagination.ErrInvalidCursor)
+
+	cursor := pagination.Cursor{Marker: "next", Offset: 3, Filter: "admin"}.Encode()
+	params, err = pagination.ParseParams(newContext("filter=admin&cursor=" + cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "next", params.Cursor.Marker)
+	assert.Equal(t, 3, params.Cursor.Offset)
+
+	_, err = pagination.ParseParams(newContext("filter=other&cursor=" + cursor))
+	assert.EqualError(t, err, pagination.ErrCursorMismatch)
+}
+
+func TestListFollowsProviderPages(t *testing.T) {
+	lister := pagination.Lister[int]{
+		Fetch: fetchNumbers(250),
+		Match: func(item int) (bool, error) { return item%2 == 0, nil },
+	}
+
+	var collected []int
+	params := pagination.Params{Limit: 30}
+	for pages := 0; pages < 10; pages++ {
+		page, err := lister.List(params)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Items), params.Limit)
+		collected = append(collected, page.Items...)
+
+		if page.NextCursor == "" {
+			break
+		}
+		params.Cursor, err = pagination.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, collected, 125)
+	for i, item := range collected {
+		assert.Equal(t, 2*(i+1), item)
+	}
+}
+
+func TestListSorted(t *testing.T) {
+	lister := pagination.Lister[int]{
+		Fetch: fetchNumbers(250),
+		Less: map[string]func(a, b int) bool{
+			"value": func(a, b int) bool { return a < b },
+		},
+	}
+
+	page, err := lister.List(pagination.Params{Limit: 3, Sort: "-value"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{250, 249, 248}, page.Items)
+
+	cursor, err := pagination.DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "-value", cursor.Sort)
+
+	page, err = lister.List(pagination.Params{Limit: 3, Sort: "-value", Cursor: cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{247, 246, 245}, page.Items)
+
+	page, err = lister.List(pagination.Params{Limit: 300, Sort: "value"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 250)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = lister.List(pagination.Params{Limit: 3, Sort: "unknown"})
+	assert.ErrorContains(t, err, pagination.ErrInvalidSort)
+}
+
+func TestListLastItemOfProviderPage(t *testing.T) {
+	lister := pagination.Lister[int]{Fetch: fetchNumbers(200)}
+
+	page, err := lister.List(pagination.Params{Limit: 100})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 100)
+
+	cursor, err := pagination.DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", cursor.Marker)
+	assert.Zero(t, cursor.Offset)
+
+	page, err = lister.List(pagination.Params{Limit: 100, Cursor: cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, 101, page.Items[0])
+	assert.Empty(t, page.NextCursor)
+}
@@ -0,0 +1,86 @@
+// Package accessanalyzer provides structures and functionality related to AWS IAM Access Analyzer analyzers,
+// findings and archive rules.
+package accessanalyzer
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// CriterionRequest represents a criterion of an archive rule, matching a finding property against values.
+// At least one of eq, neq, contains or exists is required.
+type CriterionRequest struct {
+	Eq       []string `json:"eq" validate:"required_without_all=Neq Contains Exists,max=20,dive,required"`
+	Neq      []string `json:"neq" validate:"max=20,dive,required"`
+	Contains []string `json:"contains" validate:"max=20,dive,required"`
+	Exists   *bool    `json:"exists"`
+}
+
+// validateFilter validates every criterion of an archive rule filter, the validator only checking its keys.
+func validateFilter(validate *validator.Validate, filter map[string]CriterionRequest) error {
+	for _, criterion := range filter {
+		if err := validate.Struct(criterion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ArchiveRuleRequest represents an archive rule, archiving the new findings matching every criterion of its
+// filter. The filter is keyed by finding property, such as resourceType, principal.AWS or isPublic.
+type ArchiveRuleRequest struct {
+	Name   string                      `json:"name" validate:"required,max=255"`
+	Filter map[string]CriterionRequest `json:"filter" validate:"required,min=1,dive,keys,required,endkeys"`
+}
+
+// Validate validates the ArchiveRuleRequest structure using the go-playground/validator library.
+func (archiveRuleRequest *ArchiveRuleRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(archiveRuleRequest); err != nil {
+		return err
+	}
+
+	return validateFilter(validate, archiveRuleRequest.Filter)
+}
+
+// UpdateArchiveRuleRequest represents a request to replace the filter of an archive rule.
+type UpdateArchiveRuleRequest struct {
+	Filter map[string]CriterionRequest `json:"filter" validate:"required,min=1,dive,keys,required,endkeys"`
+}
+
+// Validate validates the UpdateArchiveRuleRequest structure using the go-playground/validator library.
+func (updateArchiveRuleRequest *UpdateArchiveRuleRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(updateArchiveRuleRequest); err != nil {
+		return err
+	}
+
+	return validateFilter(validate, updateArchiveRuleRequest.Filter)
+}
+
+// CreateAnalyzerRequest represents a request to create an analyzer for an ACCOUNT or ORGANIZATION zone of trust.
+type CreateAnalyzerRequest struct {
+	Name         string               `json:"name" validate:"required,max=255"`
+	Type         string               `json:"type" validate:"required,oneof=ACCOUNT ORGANIZATION"`
+	ArchiveRules []ArchiveRuleRequest `json:"archive_rules" validate:"dive"`
+	Tags         map[string]string    `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,endkeys,max=256"`
+}
+
+// Validate validates the CreateAnalyzerRequest structure using the go-playground/validator library.
+func (createAnalyzerRequest *CreateAnalyzerRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(createAnalyzerRequest); err != nil {
+		return err
+	}
+
+	for _, archiveRule := range createAnalyzerRequest.ArchiveRules {
+		if err := validateFilter(validate, archiveRule.Filter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package accessanalyzer
+
+import (
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ListFindingsRequest represents the criteria of an Access Analyzer findings list. Only active findings are
+// listed when no status is given.
+type ListFindingsRequest struct {
+	Statuses      []string `query:"status" validate:"dive,oneof=ACTIVE ARCHIVED RESOLVED"`
+	ResourceTypes []string `query:"resource_type" validate:"max=20,dive,required"`
+	Resource      string   `query:"resource" validate:"max=2048"`
+	IsPublic      string   `query:"is_public" validate:"omitempty,oneof=true false"`
+}
+
+// Validate validates the ListFindingsRequest structure using the go-playground/validator library.
+func (listFindingsRequest *ListFindingsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(listFindingsRequest)
+}
+
+// IsPublicValue returns the parsed is_public criterion, nil when omitted.
+func (listFindingsRequest *ListFindingsRequest) IsPublicValue() *bool {
+	isPublic, err := strconv.ParseBool(listFindingsRequest.IsPublic)
+	if err != nil {
+		return nil
+	}
+
+	return &isPublic
+}
+
+// UpdateFindingsRequest represents a request to archive or unarchive findings of an analyzer.
+type UpdateFindingsRequest struct {
+	FindingIds []string `json:"finding_ids" validate:"required,min=1,max=1000,dive,required"`
+}
+
+// Validate validates the UpdateFindingsRequest structure using the go-playground/validator library.
+func (updateFindingsRequest *UpdateFindingsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(updateFindingsRequest)
+}
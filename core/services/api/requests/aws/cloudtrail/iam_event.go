@@ -0,0 +1,51 @@
+// Package cloudtrail provides structures and functionality related to the IAM events recorded by AWS CloudTrail.
+package cloudtrail
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ErrInvalidTimeRange = "start_time must be before end_time"
+)
+
+// ListIamEventsRequest represents the criteria of an IAM events list. Times are RFC 3339 timestamps.
+type ListIamEventsRequest struct {
+	Principal       string `query:"principal" validate:"max=128"`
+	Resource        string `query:"resource" validate:"max=2048"`
+	EventName       string `query:"event_name" validate:"max=128"`
+	StartTime       string `query:"start_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndTime         string `query:"end_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	IncludeReadOnly bool   `query:"include_read_only"`
+}
+
+// Validate validates the ListIamEventsRequest structure using the go-playground/validator library.
+func (listIamEventsRequest *ListIamEventsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(listIamEventsRequest); err != nil {
+		return err
+	}
+
+	start, end := listIamEventsRequest.StartTimeValue(), listIamEventsRequest.EndTimeValue()
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return errors.New(ErrInvalidTimeRange)
+	}
+
+	return nil
+}
+
+// StartTimeValue returns the parsed start_time criterion, zero when omitted.
+func (listIamEventsRequest *ListIamEventsRequest) StartTimeValue() time.Time {
+	start, _ := time.Parse(time.RFC3339, listIamEventsRequest.StartTime)
+	return start
+}
+
+// EndTimeValue returns the parsed end_time criterion, zero when omitted.
+func (listIamEventsRequest *ListIamEventsRequest) EndTimeValue() time.Time {
+	end, _ := time.Parse(time.RFC3339, listIamEventsRequest.EndTime)
+	return end
+}
@@ -0,0 +1,70 @@
+// Package guardduty provides structures and functionality related to AWS GuardDuty findings.
+package guardduty
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ErrInvalidSeverityRange = "min_severity must be lower than max_severity"
+	ErrInvalidTimeRange     = "updated_after must be before updated_before"
+)
+
+// ListFindingsRequest represents the criteria of a GuardDuty findings list. Times are RFC 3339 timestamps.
+type ListFindingsRequest struct {
+	MinSeverity   int64    `query:"min_severity" validate:"min=0,max=10"`
+	MaxSeverity   int64    `query:"max_severity" validate:"min=0,max=10"`
+	Types         []string `query:"type" validate:"max=50,dive,required"`
+	UpdatedAfter  string   `query:"updated_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	UpdatedBefore string   `query:"updated_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Archived      bool     `query:"archived"`
+}
+
+// Validate validates the ListFindingsRequest structure using the go-playground/validator library.
+func (listFindingsRequest *ListFindingsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(listFindingsRequest); err != nil {
+		return err
+	}
+
+	if listFindingsRequest.MinSeverity > 0 && listFindingsRequest.MaxSeverity > 0 &&
+		listFindingsRequest.MinSeverity >= listFindingsRequest.MaxSeverity {
+		return errors.New(ErrInvalidSeverityRange)
+	}
+
+	after, before := listFindingsRequest.UpdatedAfterTime(), listFindingsRequest.UpdatedBeforeTime()
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return errors.New(ErrInvalidTimeRange)
+	}
+
+	return nil
+}
+
+// UpdatedAfterTime returns the parsed updated_after criterion, zero when omitted.
+func (listFindingsRequest *ListFindingsRequest) UpdatedAfterTime() time.Time {
+	after, _ := time.Parse(time.RFC3339, listFindingsRequest.UpdatedAfter)
+	return after
+}
+
+// UpdatedBeforeTime returns the parsed updated_before criterion, zero when omitted.
+func (listFindingsRequest *ListFindingsRequest) UpdatedBeforeTime() time.Time {
+	before, _ := time.Parse(time.RFC3339, listFindingsRequest.UpdatedBefore)
+	return before
+}
+
+// ArchiveFindingsRequest represents a request to archive or unarchive findings of a GuardDuty detector.
+type ArchiveFindingsRequest struct {
+	DetectorID string   `json:"detector_id" validate:"required,max=300"`
+	FindingIds []string `json:"finding_ids" validate:"required,min=1,max=1000,dive,required,max=300"`
+}
+
+// Validate validates the ArchiveFindingsRequest structure using the go-playground/validator library.
+func (archiveFindingsRequest *ArchiveFindingsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(archiveFindingsRequest)
+}
@@ -0,0 +1,33 @@
+// Package aws provides structures and functionality related to AWS IAM access keys.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// UpdateAccessKeyRequest represents a request to activate or deactivate an IAM access key.
+type UpdateAccessKeyRequest struct {
+	Username    string `param:"id" validate:"required"`
+	AccessKeyId string `param:"key" validate:"required"`
+	Status      string `json:"status" validate:"required,oneof=Active Inactive"`
+}
+
+// Validate validates the UpdateAccessKeyRequest structure using the go-playground/validator library.
+func (updateAccessKeyRequest *UpdateAccessKeyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(updateAccessKeyRequest)
+}
+
+// ConfirmAccessKeyRotationRequest represents a request confirming that the new key of a rotation is in use.
+type ConfirmAccessKeyRotationRequest struct {
+	Username         string `param:"id" validate:"required"`
+	OldAccessKeyId   string `param:"key" validate:"required"`
+	NewAccessKeyId   string `json:"new_access_key_id" validate:"required,nefield=OldAccessKeyId"`
+	GracePeriodHours *uint  `json:"grace_period_hours"`
+}
+
+// Validate validates the ConfirmAccessKeyRotationRequest structure using the go-playground/validator library.
+func (confirmAccessKeyRotationRequest *ConfirmAccessKeyRotationRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(confirmAccessKeyRotationRequest)
+}
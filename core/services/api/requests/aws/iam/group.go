@@ -39,3 +39,16 @@ func (updateGroupRequest *UpdateGroupRequest) Validate() error {
 
 	return validate.Struct(updateGroupRequest)
 }
+
+// GroupMembersRequest represents a request to add or remove users in an IAM group.
+type GroupMembersRequest struct {
+	GroupName string   `param:"id" validate:"required"`
+	Usernames []string `json:"usernames" validate:"required,min=1,dive,required"`
+}
+
+// Validate validates the GroupMembersRequest structure using the go-playground/validator library.
+func (groupMembersRequest *GroupMembersRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(groupMembersRequest)
+}
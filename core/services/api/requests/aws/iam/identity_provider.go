@@ -0,0 +1,101 @@
+// Package aws provides structures and functionality related to AWS IAM identity providers.
+package aws
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ErrEmptyOIDCProviderUpdate = "at least one of client_ids or thumbprints is required"
+)
+
+// CreateOIDCProviderRequest represents a request to create an IAM OpenID Connect identity provider.
+// Thumbprints are the SHA-1 fingerprints of the issuer certificate, IAM retrieves it when none is given.
+type CreateOIDCProviderRequest struct {
+	URL         string            `json:"url" validate:"required,max=255,url,startswith=https://"`
+	ClientIDs   []string          `json:"client_ids" validate:"required,min=1,max=100,dive,min=1,max=255"`
+	Thumbprints []string          `json:"thumbprints" validate:"max=5,dive,len=40,hexadecimal"`
+	Tags        map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
+}
+
+// Validate validates the CreateOIDCProviderRequest structure using the go-playground/validator library.
+func (createOIDCProviderRequest *CreateOIDCProviderRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(createOIDCProviderRequest)
+}
+
+// UpdateOIDCProviderRequest represents a request to replace the client IDs or the thumbprints of an IAM OpenID
+// Connect identity provider. Omitted lists are left unchanged.
+type UpdateOIDCProviderRequest struct {
+	ClientIDs   []string `json:"client_ids" validate:"omitempty,max=100,dive,min=1,max=255"`
+	Thumbprints []string `json:"thumbprints" validate:"omitempty,max=5,dive,len=40,hexadecimal"`
+}
+
+// Validate validates the UpdateOIDCProviderRequest structure using the go-playground/validator library.
+func (updateOIDCProviderRequest *UpdateOIDCProviderRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(updateOIDCProviderRequest); err != nil {
+		return err
+	}
+
+	if updateOIDCProviderRequest.ClientIDs == nil && updateOIDCProviderRequest.Thumbprints == nil {
+		return errors.New(ErrEmptyOIDCProviderUpdate)
+	}
+
+	return nil
+}
+
+// CreateSAMLProviderRequest represents a request to create an IAM SAML 2.0 identity provider from the
+// metadata document exported by the IdP.
+type CreateSAMLProviderRequest struct {
+	Name             string            `json:"name" validate:"required,max=128"`
+	MetadataDocument string            `json:"metadata_document" validate:"required,min=1000,max=10000000"`
+	Tags             map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
+}
+
+// Validate validates the CreateSAMLProviderRequest structure using the go-playground/validator library.
+func (createSAMLProviderRequest *CreateSAMLProviderRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(createSAMLProviderRequest)
+}
+
+// UpdateSAMLProviderRequest represents a request to replace the metadata document of an IAM SAML 2.0 identity
+// provider.
+type UpdateSAMLProviderRequest struct {
+	MetadataDocument string `json:"metadata_document" validate:"required,min=1000,max=10000000"`
+}
+
+// Validate validates the UpdateSAMLProviderRequest structure using the go-playground/validator library.
+func (updateSAMLProviderRequest *UpdateSAMLProviderRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(updateSAMLProviderRequest)
+}
+
+// CreateGitHubActionsRoleRequest represents a request to create an IAM role assumable by the GitHub Actions
+// workflows of a repository running on a branch, which may hold wildcards such as release/*.
+type CreateGitHubActionsRoleRequest struct {
+	RoleName string `json:"name" validate:"required,max=64"`
+	// ProviderArn is the GitHub Actions OIDC provider, the one of the account when omitted.
+	ProviderArn string `json:"provider_arn" validate:"omitempty,startswith=arn:"`
+	// Repository is given as owner/name.
+	Repository          string            `json:"repository" validate:"required"`
+	Branch              string            `json:"branch" validate:"required"`
+	Path                string            `json:"path" validate:"omitempty,max=512,startswith=/,endswith=/"`
+	Description         string            `json:"description" validate:"max=1000"`
+	MaxSessionDuration  int64             `json:"max_session_duration" validate:"omitempty,min=3600,max=43200"`
+	PermissionsBoundary string            `json:"permissions_boundary_arn"`
+	Tags                map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
+}
+
+// Validate validates the CreateGitHubActionsRoleRequest structure using the go-playground/validator library.
+func (createGitHubActionsRoleRequest *CreateGitHubActionsRoleRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(createGitHubActionsRoleRequest)
+}
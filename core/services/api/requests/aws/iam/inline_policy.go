@@ -0,0 +1,27 @@
+// Package aws provides structures and functionality related to AWS inline policies.
+package aws
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// PutInlinePolicyRequest represents a request to create or replace an inline policy
+// embedded in an IAM user, group or role.
+type PutInlinePolicyRequest struct {
+	PrincipalName  string             `param:"id" validate:"required"`
+	PolicyName     string             `param:"name" validate:"required"`
+	PolicyDocument iam.PolicyDocument `json:"document" validate:"required"`
+}
+
+// Validate validates the PutInlinePolicyRequest structure using the go-playground/validator library.
+func (putInlinePolicyRequest *PutInlinePolicyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(putInlinePolicyRequest); err != nil {
+		return err
+	}
+
+	return putInlinePolicyRequest.PolicyDocument.Validate()
+}
@@ -0,0 +1,20 @@
+// Package aws provides structures and functionality related to AWS IAM instance profiles.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// CreateInstanceProfileRequest represents a request to create an IAM instance profile.
+type CreateInstanceProfileRequest struct {
+	Name string `json:"name" validate:"required,max=128"`
+	Path string `json:"path" validate:"omitempty,max=512,startswith=/,endswith=/"`
+	// RoleName is added to the instance profile once created, when set.
+	RoleName string            `json:"role_name" validate:"max=64"`
+	Tags     map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
+}
+
+// Validate validates the CreateInstanceProfileRequest structure using the go-playground/validator library.
+func (createInstanceProfileRequest *CreateInstanceProfileRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(createInstanceProfileRequest)
+}
@@ -0,0 +1,44 @@
+// Package aws provides structures and functionality related to AWS IAM console access.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// LoginProfileRequest represents a request to create or reset the console password of an IAM user.
+// When Password is empty a temporary password is generated.
+type LoginProfileRequest struct {
+	Username              string `param:"id" validate:"required"`
+	Password              string `json:"password"`
+	PasswordResetRequired *bool  `json:"password_reset_required"`
+}
+
+// Validate validates the LoginProfileRequest structure using the go-playground/validator library.
+func (loginProfileRequest *LoginProfileRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(loginProfileRequest)
+}
+
+// ResetRequired returns whether the user must change the password at next sign-in, which defaults to true.
+func (loginProfileRequest *LoginProfileRequest) ResetRequired() bool {
+	return loginProfileRequest.PasswordResetRequired == nil || *loginProfileRequest.PasswordResetRequired
+}
+
+// UpdatePasswordPolicyRequest represents a request to replace the account password policy.
+type UpdatePasswordPolicyRequest struct {
+	MinimumPasswordLength      int64 `json:"minimum_password_length" validate:"required,min=6,max=128"`
+	RequireSymbols             bool  `json:"require_symbols"`
+	RequireNumbers             bool  `json:"require_numbers"`
+	RequireUppercaseCharacters bool  `json:"require_uppercase_characters"`
+	RequireLowercaseCharacters bool  `json:"require_lowercase_characters"`
+	AllowUsersToChangePassword bool  `json:"allow_users_to_change_password"`
+	MaxPasswordAge             int64 `json:"max_password_age" validate:"min=0,max=1095"`
+	PasswordReusePrevention    int64 `json:"password_reuse_prevention" validate:"min=0,max=24"`
+	HardExpiry                 bool  `json:"hard_expiry"`
+}
+
+// Validate validates the UpdatePasswordPolicyRequest structure using the go-playground/validator library.
+func (updatePasswordPolicyRequest *UpdatePasswordPolicyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(updatePasswordPolicyRequest)
+}
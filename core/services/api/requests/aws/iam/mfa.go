@@ -0,0 +1,45 @@
+// Package aws provides structures and functionality related to AWS IAM MFA devices.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// CreateVirtualMFADeviceRequest represents a request to create a virtual MFA device for an IAM user.
+type CreateVirtualMFADeviceRequest struct {
+	Username   string `param:"id" validate:"required"`
+	DeviceName string `json:"device_name"`
+}
+
+// Validate validates the CreateVirtualMFADeviceRequest structure using the go-playground/validator library.
+func (createVirtualMFADeviceRequest *CreateVirtualMFADeviceRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(createVirtualMFADeviceRequest)
+}
+
+// EnableMFADeviceRequest represents a request to enable an MFA device with two consecutive codes.
+type EnableMFADeviceRequest struct {
+	Username            string `param:"id" validate:"required"`
+	SerialNumber        string `json:"serial_number" validate:"required"`
+	AuthenticationCode1 string `json:"authentication_code_1" validate:"required,len=6,numeric"`
+	AuthenticationCode2 string `json:"authentication_code_2" validate:"required,len=6,numeric,nefield=AuthenticationCode1"`
+}
+
+// Validate validates the EnableMFADeviceRequest structure using the go-playground/validator library.
+func (enableMFADeviceRequest *EnableMFADeviceRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(enableMFADeviceRequest)
+}
+
+// MFADeviceRequest represents a request targeting an MFA device of an IAM user.
+type MFADeviceRequest struct {
+	Username     string `param:"id" validate:"required"`
+	SerialNumber string `json:"serial_number" validate:"required"`
+}
+
+// Validate validates the MFADeviceRequest structure using the go-playground/validator library.
+func (mfaDeviceRequest *MFADeviceRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(mfaDeviceRequest)
+}
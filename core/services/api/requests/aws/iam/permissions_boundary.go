@@ -0,0 +1,17 @@
+// Package aws provides structures and functionality related to AWS IAM permissions boundaries.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// PutPermissionsBoundaryRequest represents a request to set the permissions boundary of an IAM user or role.
+// The boundary is given by the ARN of a managed policy or the name of a customer managed policy.
+type PutPermissionsBoundaryRequest struct {
+	PermissionsBoundary string `json:"permissions_boundary_arn" validate:"required"`
+}
+
+// Validate validates the PutPermissionsBoundaryRequest structure using the go-playground/validator library.
+func (putPermissionsBoundaryRequest *PutPermissionsBoundaryRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(putPermissionsBoundaryRequest)
+}
@@ -1,44 +1,81 @@
 // Package aws provides structures and functionality related to AWS policies.
 package aws
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/go-playground/validator/v10"
 
-// PolicyDocument represents the structure of an AWS policy document.
-type PolicyDocument struct {
-	Version    string           `json:"version"`
-	Statements []StatementEntry `json:"statements"`
-}
-
-// StatementEntry represents an entry in an AWS policy statement.
-type StatementEntry struct {
-	Effect   string `json:"effect" validate:"required"`
-	Action   string `json:"action" validate:"required"`
-	Resource string `json:"resource" validate:"required"`
-}
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
 
 // CreatePolicyRequest represents a request to create an AWS policy.
 type CreatePolicyRequest struct {
-	PolicyName     string         `json:"name" validate:"required"`
-	Description    string         `json:"description" validate:"required"`
-	PolicyDocument PolicyDocument `json:"document" validate:"required"`
+	PolicyName     string             `json:"name" validate:"required"`
+	Description    string             `json:"description" validate:"required"`
+	PolicyDocument iam.PolicyDocument `json:"document" validate:"required"`
+	Tags           map[string]string  `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
 }
 
 // Validate validates the CreatePolicyRequest structure using the go-playground/validator library.
 func (createPolicyRequest *CreatePolicyRequest) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
-	return validate.Struct(createPolicyRequest)
+	if err := validate.Struct(createPolicyRequest); err != nil {
+		return err
+	}
+
+	return createPolicyRequest.PolicyDocument.Validate()
 }
 
 // UpdatePolicyRequest represents a request to update an AWS policy.
 type UpdatePolicyRequest struct {
-	PolicyARN      string         `param:"arn" validate:"required"`
-	PolicyDocument PolicyDocument `json:"document"`
+	PolicyARN      string             `param:"arn" validate:"required"`
+	PolicyDocument iam.PolicyDocument `json:"document" validate:"required"`
 }
 
 // Validate validates the UpdatePolicyRequest structure using the go-playground/validator library.
 func (updatePolicyRequest *UpdatePolicyRequest) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
-	return validate.Struct(updatePolicyRequest)
+	if err := validate.Struct(updatePolicyRequest); err != nil {
+		return err
+	}
+
+	return updatePolicyRequest.PolicyDocument.Validate()
+}
+
+// LintPolicyRequest represents a request to lint an AWS policy document without deploying it.
+type LintPolicyRequest struct {
+	PolicyDocument iam.PolicyDocument `json:"document" validate:"required"`
+}
+
+// Validate validates the LintPolicyRequest structure using the go-playground/validator library.
+func (lintPolicyRequest *LintPolicyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(lintPolicyRequest)
+}
+
+// SetDefaultPolicyVersionRequest represents a request to make an existing version the default version of an AWS policy.
+type SetDefaultPolicyVersionRequest struct {
+	VersionId string `json:"version_id" validate:"required"`
+}
+
+// Validate validates the SetDefaultPolicyVersionRequest structure using the go-playground/validator library.
+func (setDefaultPolicyVersionRequest *SetDefaultPolicyVersionRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(setDefaultPolicyVersionRequest)
+}
+
+// DiffPolicyVersionsRequest represents a request to compare two versions of an AWS policy.
+type DiffPolicyVersionsRequest struct {
+	FromVersionId string `query:"from" validate:"required"`
+	ToVersionId   string `query:"to" validate:"required"`
+}
+
+// Validate validates the DiffPolicyVersionsRequest structure using the go-playground/validator library.
+func (diffPolicyVersionsRequest *DiffPolicyVersionsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(diffPolicyVersionsRequest)
 }
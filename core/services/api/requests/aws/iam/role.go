@@ -1,31 +1,69 @@
 // Package aws provides structures and functionality related to AWS IAM roles.
 package aws
 
-import "github.com/go-playground/validator/v10"
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+const (
+	ErrEmptyRoleUpdate = "at least one of trust_policy, description or max_session_duration is required"
+)
 
 // CreateRoleRequest represents a request to create an IAM role.
 type CreateRoleRequest struct {
-	Id          string                 `json:"id" validate:"required"`
-	RoleName    string                 `json:"name" validate:"required"`
-	TrustPolicy map[string]interface{} `json:"trust_policy" validate:"required"`
+	RoleName    string             `json:"name" validate:"required,max=64"`
+	TrustPolicy iam.PolicyDocument `json:"trust_policy" validate:"required"`
+	// Path groups roles, such as /service-role/. It cannot be changed once the role exists.
+	Path        string `json:"path" validate:"omitempty,max=512,startswith=/,endswith=/"`
+	Description string `json:"description" validate:"max=1000"`
+	// MaxSessionDuration is the maximum session duration in seconds, between one and twelve hours.
+	MaxSessionDuration int64 `json:"max_session_duration" validate:"omitempty,min=3600,max=43200"`
+	// PermissionsBoundary is the ARN, or the customer managed policy name, of the policy set as the role
+	// permissions boundary.
+	PermissionsBoundary string            `json:"permissions_boundary_arn"`
+	Tags                map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
 }
 
 // Validate validates the CreateRoleRequest structure using the go-playground/validator library.
 func (awsIamRoleRequest *CreateRoleRequest) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
-	return validate.Struct(awsIamRoleRequest)
+	if err := validate.Struct(awsIamRoleRequest); err != nil {
+		return err
+	}
+
+	return awsIamRoleRequest.TrustPolicy.Validate()
 }
 
 // UpdateRoleRequest represents a request to update information about an IAM role.
+// Omitted fields are left unchanged.
 type UpdateRoleRequest struct {
-	Name        string                 `param:"id" validate:"required"`
-	TrustPolicy map[string]interface{} `json:"trust_policy" validate:"required"`
+	Name               string              `param:"id" validate:"required"`
+	TrustPolicy        *iam.PolicyDocument `json:"trust_policy"`
+	Description        *string             `json:"description" validate:"omitempty,max=1000"`
+	MaxSessionDuration *int64              `json:"max_session_duration" validate:"omitempty,min=3600,max=43200"`
 }
 
 // Validate validates the UpdateRoleRequest structure using the go-playground/validator library.
 func (awsIamRoleRequest *UpdateRoleRequest) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 
-	return validate.Struct(awsIamRoleRequest)
+	if err := validate.Struct(awsIamRoleRequest); err != nil {
+		return err
+	}
+
+	if awsIamRoleRequest.TrustPolicy == nil && awsIamRoleRequest.Description == nil &&
+		awsIamRoleRequest.MaxSessionDuration == nil {
+		return errors.New(ErrEmptyRoleUpdate)
+	}
+
+	if awsIamRoleRequest.TrustPolicy != nil {
+		return awsIamRoleRequest.TrustPolicy.Validate()
+	}
+
+	return nil
 }
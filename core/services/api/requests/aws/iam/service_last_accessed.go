@@ -0,0 +1,19 @@
+// Package aws provides structures and functionality related to AWS IAM service last accessed details.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// GenerateLastAccessedRequest represents a request to start a service last accessed details job for a user,
+// group, role or policy.
+type GenerateLastAccessedRequest struct {
+	Arn string `json:"arn" validate:"required,startswith=arn:"`
+	// Granularity is SERVICE_LEVEL, the default, or ACTION_LEVEL.
+	Granularity string `json:"granularity" validate:"omitempty,oneof=SERVICE_LEVEL ACTION_LEVEL"`
+}
+
+// Validate validates the GenerateLastAccessedRequest structure using the go-playground/validator library.
+func (generateLastAccessedRequest *GenerateLastAccessedRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(generateLastAccessedRequest)
+}
@@ -0,0 +1,29 @@
+// Package aws provides structures and functionality related to the offline evaluation of AWS IAM policies.
+package aws
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// SimulateRequest represents a request to evaluate whether a principal can perform an action on a resource,
+// given the policies collected for that principal and the context of the request.
+type SimulateRequest struct {
+	Principal           string               `json:"principal"`
+	Action              string               `json:"action" validate:"required"`
+	Resource            string               `json:"resource"`
+	Context             map[string][]string  `json:"context"`
+	IdentityPolicies    []iam.PolicyDocument `json:"identity_policies"`
+	ResourcePolicies    []iam.PolicyDocument `json:"resource_policies"`
+	PermissionsBoundary *iam.PolicyDocument  `json:"permissions_boundary"`
+	// ServiceControlPolicies holds the SCPs attached at each level of the organization, from the root to the account.
+	ServiceControlPolicies [][]iam.PolicyDocument `json:"service_control_policies"`
+}
+
+// Validate validates the SimulateRequest structure using the go-playground/validator library.
+func (simulateRequest *SimulateRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(simulateRequest)
+}
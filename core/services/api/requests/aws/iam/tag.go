@@ -0,0 +1,31 @@
+// Package aws provides structures and functionality related to AWS IAM tags.
+package aws
+
+import "github.com/go-playground/validator/v10"
+
+// TagResourceRequest represents a request to add or overwrite tags on an IAM resource.
+// IAM accepts at most 50 tags, keys of 1 to 128 characters outside of the reserved aws: prefix
+// and values of at most 256 characters. The inariam: prefix is reserved for the state kept by Inariam.
+type TagResourceRequest struct {
+	Tags map[string]string `json:"tags" validate:"required,min=1,max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
+}
+
+// Validate validates the TagResourceRequest structure using the go-playground/validator library.
+func (tagResourceRequest *TagResourceRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(tagResourceRequest)
+}
+
+// UntagResourceRequest represents a request to remove tags from an IAM resource, other than the reserved inariam:
+// tags.
+type UntagResourceRequest struct {
+	Keys []string `json:"keys" validate:"required,min=1,dive,required,startsnotwith=inariam:"`
+}
+
+// Validate validates the UntagResourceRequest structure using the go-playground/validator library.
+func (untagResourceRequest *UntagResourceRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(untagResourceRequest)
+}
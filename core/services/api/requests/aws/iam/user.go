@@ -6,6 +6,9 @@ import "github.com/go-playground/validator/v10"
 // CreateUserRequest represents a request to create an IAM user.
 type CreateUserRequest struct {
 	Username string `json:"username"`
+	// ConsoleAccess creates a login profile with a temporary password that must be changed at first sign-in.
+	ConsoleAccess bool              `json:"console_access"`
+	Tags          map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,startsnotwith=inariam:,endkeys,max=256"`
 }
 
 // Validate validates the CreateUserRequest structure using the go-playground/validator library.
@@ -0,0 +1,61 @@
+// Package organizations provides structures and functionality related to AWS Organizations service control policies.
+package organizations
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+const (
+	ErrEmptyScpUpdate = "at least one of name, description or content is required"
+)
+
+// CreateScpRequest represents a request to create a service control policy.
+type CreateScpRequest struct {
+	Name        string             `json:"name" validate:"required,max=128"`
+	Description string             `json:"description" validate:"max=512"`
+	Content     iam.PolicyDocument `json:"content" validate:"required"`
+	Tags        map[string]string  `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,endkeys,max=256"`
+}
+
+// Validate validates the CreateScpRequest structure using the go-playground/validator library.
+func (createScpRequest *CreateScpRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(createScpRequest); err != nil {
+		return err
+	}
+
+	return createScpRequest.Content.Validate()
+}
+
+// UpdateScpRequest represents a request to update a service control policy.
+// Omitted fields are left unchanged.
+type UpdateScpRequest struct {
+	PolicyID    string              `param:"id" validate:"required"`
+	Name        *string             `json:"name" validate:"omitempty,min=1,max=128"`
+	Description *string             `json:"description" validate:"omitempty,max=512"`
+	Content     *iam.PolicyDocument `json:"content"`
+}
+
+// Validate validates the UpdateScpRequest structure using the go-playground/validator library.
+func (updateScpRequest *UpdateScpRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(updateScpRequest); err != nil {
+		return err
+	}
+
+	if updateScpRequest.Name == nil && updateScpRequest.Description == nil && updateScpRequest.Content == nil {
+		return errors.New(ErrEmptyScpUpdate)
+	}
+
+	if updateScpRequest.Content != nil {
+		return updateScpRequest.Content.Validate()
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Package securityhub provides structures and functionality related to AWS Security Hub findings.
+package securityhub
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ErrInvalidTimeRange = "updated_after must be before updated_before"
+	ErrEmptyUpdate      = "at least one of workflow_status or note is required"
+)
+
+// ListFindingsRequest represents the AWS Security Finding Format criteria of a Security Hub findings list. Each
+// list matches any of its values, types are matched as prefixes and times are RFC 3339 timestamps.
+type ListFindingsRequest struct {
+	SeverityLabels     []string `query:"severity" validate:"dive,oneof=INFORMATIONAL LOW MEDIUM HIGH CRITICAL"`
+	WorkflowStatuses   []string `query:"workflow_status" validate:"dive,oneof=NEW NOTIFIED RESOLVED SUPPRESSED"`
+	ComplianceStatuses []string `query:"compliance_status" validate:"dive,oneof=PASSED WARNING FAILED NOT_AVAILABLE"`
+	ProductNames       []string `query:"product_name" validate:"max=20,dive,required"`
+	ResourceTypes      []string `query:"resource_type" validate:"max=20,dive,required"`
+	AccountIDs         []string `query:"account_id" validate:"max=20,dive,numeric,len=12"`
+	Types              []string `query:"type" validate:"max=20,dive,required"`
+	RecordState        string   `query:"record_state" validate:"omitempty,oneof=ACTIVE ARCHIVED"`
+	UpdatedAfter       string   `query:"updated_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	UpdatedBefore      string   `query:"updated_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// Validate validates the ListFindingsRequest structure using the go-playground/validator library.
+func (listFindingsRequest *ListFindingsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(listFindingsRequest); err != nil {
+		return err
+	}
+
+	after, before := listFindingsRequest.UpdatedAfterTime(), listFindingsRequest.UpdatedBeforeTime()
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return errors.New(ErrInvalidTimeRange)
+	}
+
+	return nil
+}
+
+// UpdatedAfterTime returns the parsed updated_after criterion, zero when omitted.
+func (listFindingsRequest *ListFindingsRequest) UpdatedAfterTime() time.Time {
+	after, _ := time.Parse(time.RFC3339, listFindingsRequest.UpdatedAfter)
+	return after
+}
+
+// UpdatedBeforeTime returns the parsed updated_before criterion, zero when omitted.
+func (listFindingsRequest *ListFindingsRequest) UpdatedBeforeTime() time.Time {
+	before, _ := time.Parse(time.RFC3339, listFindingsRequest.UpdatedBefore)
+	return before
+}
+
+// FindingIdentifier identifies a Security Hub finding, finding IDs being unique per product.
+type FindingIdentifier struct {
+	ID         string `json:"id" validate:"required,max=512"`
+	ProductArn string `json:"product_arn" validate:"required,startswith=arn:"`
+}
+
+// NoteRequest represents the note set on findings to track their investigation.
+type NoteRequest struct {
+	Text      string `json:"text" validate:"required,max=512"`
+	UpdatedBy string `json:"updated_by" validate:"required,max=512"`
+}
+
+// UpdateFindingsRequest represents a request to set the workflow status or the note of Security Hub findings.
+type UpdateFindingsRequest struct {
+	Findings       []FindingIdentifier `json:"findings" validate:"required,min=1,max=1000,dive"`
+	WorkflowStatus string              `json:"workflow_status" validate:"omitempty,oneof=NEW NOTIFIED RESOLVED SUPPRESSED"`
+	Note           *NoteRequest        `json:"note"`
+}
+
+// Validate validates the UpdateFindingsRequest structure using the go-playground/validator library.
+func (updateFindingsRequest *UpdateFindingsRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(updateFindingsRequest); err != nil {
+		return err
+	}
+
+	if updateFindingsRequest.WorkflowStatus == "" && updateFindingsRequest.Note == nil {
+		return errors.New(ErrEmptyUpdate)
+	}
+
+	return nil
+}
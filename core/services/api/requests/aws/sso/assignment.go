@@ -0,0 +1,21 @@
+package sso
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// AccountAssignmentRequest represents a request to assign a permission set to a user or a group of the identity
+// store in an account, or to remove this assignment.
+type AccountAssignmentRequest struct {
+	AccountID        string `json:"account_id" validate:"required,numeric,len=12"`
+	PermissionSetArn string `json:"permission_set_arn" validate:"required,startswith=arn:"`
+	PrincipalType    string `json:"principal_type" validate:"required,oneof=USER GROUP"`
+	PrincipalID      string `json:"principal_id" validate:"required,max=47"`
+}
+
+// Validate validates the AccountAssignmentRequest structure using the go-playground/validator library.
+func (accountAssignmentRequest *AccountAssignmentRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(accountAssignmentRequest)
+}
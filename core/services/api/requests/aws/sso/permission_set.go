@@ -0,0 +1,112 @@
+// Package sso provides structures and functionality related to AWS IAM Identity Center permission sets and
+// account assignments.
+package sso
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+const (
+	ErrEmptyPermissionSetUpdate = "at least one of description, session_duration or relay_state is required"
+)
+
+// CreatePermissionSetRequest represents a request to create a permission set.
+// The session duration is an ISO 8601 duration between one and twelve hours, such as PT8H.
+type CreatePermissionSetRequest struct {
+	Name            string            `json:"name" validate:"required,max=32"`
+	Description     string            `json:"description" validate:"max=700"`
+	SessionDuration string            `json:"session_duration" validate:"omitempty,startswith=PT,max=100"`
+	RelayState      string            `json:"relay_state" validate:"omitempty,url,max=240"`
+	Tags            map[string]string `json:"tags" validate:"max=50,dive,keys,min=1,max=128,startsnotwith=aws:,endkeys,max=256"`
+}
+
+// Validate validates the CreatePermissionSetRequest structure using the go-playground/validator library.
+func (createPermissionSetRequest *CreatePermissionSetRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(createPermissionSetRequest)
+}
+
+// UpdatePermissionSetRequest represents a request to update a permission set.
+// Omitted fields are left unchanged.
+type UpdatePermissionSetRequest struct {
+	Description     *string `json:"description" validate:"omitempty,max=700"`
+	SessionDuration *string `json:"session_duration" validate:"omitempty,startswith=PT,max=100"`
+	RelayState      *string `json:"relay_state" validate:"omitempty,url,max=240"`
+}
+
+// Validate validates the UpdatePermissionSetRequest structure using the go-playground/validator library.
+func (updatePermissionSetRequest *UpdatePermissionSetRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(updatePermissionSetRequest); err != nil {
+		return err
+	}
+
+	if updatePermissionSetRequest.Description == nil && updatePermissionSetRequest.SessionDuration == nil &&
+		updatePermissionSetRequest.RelayState == nil {
+		return errors.New(ErrEmptyPermissionSetUpdate)
+	}
+
+	return nil
+}
+
+// ManagedPolicyRequest represents a request to attach or detach an AWS managed policy to a permission set.
+type ManagedPolicyRequest struct {
+	PolicyArn string `json:"policy_arn" validate:"required,startswith=arn:"`
+}
+
+// Validate validates the ManagedPolicyRequest structure using the go-playground/validator library.
+func (managedPolicyRequest *ManagedPolicyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(managedPolicyRequest)
+}
+
+// CustomerManagedPolicyRequest represents a request to add or remove a customer managed policy reference of a
+// permission set. The policy must exist with this name and path in every account the permission set is
+// provisioned to.
+type CustomerManagedPolicyRequest struct {
+	Name string `json:"name" validate:"required,max=128"`
+	Path string `json:"path" validate:"omitempty,startswith=/,endswith=/,max=512"`
+}
+
+// Validate validates the CustomerManagedPolicyRequest structure using the go-playground/validator library.
+func (customerManagedPolicyRequest *CustomerManagedPolicyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(customerManagedPolicyRequest)
+}
+
+// InlinePolicyRequest represents a request to set the inline policy of a permission set.
+type InlinePolicyRequest struct {
+	Document iam.PolicyDocument `json:"document" validate:"required"`
+}
+
+// Validate validates the InlinePolicyRequest structure using the go-playground/validator library.
+func (inlinePolicyRequest *InlinePolicyRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	if err := validate.Struct(inlinePolicyRequest); err != nil {
+		return err
+	}
+
+	return inlinePolicyRequest.Document.Validate()
+}
+
+// ProvisionRequest represents a request to provision a permission set to an account, or to every account it is
+// already provisioned to when the account ID is omitted.
+type ProvisionRequest struct {
+	AccountID string `json:"account_id" validate:"omitempty,numeric,len=12"`
+}
+
+// Validate validates the ProvisionRequest structure using the go-playground/validator library.
+func (provisionRequest *ProvisionRequest) Validate() error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	return validate.Struct(provisionRequest)
+}
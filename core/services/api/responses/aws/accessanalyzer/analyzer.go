@@ -0,0 +1,51 @@
+// Package accessanalyzer provides structures and functionality related to AWS IAM Access Analyzer.
+package accessanalyzer
+
+import "time"
+
+// AnalyzerResponse represents a response detailing an analyzer. The type is its ACCOUNT or ORGANIZATION zone of
+// trust.
+type AnalyzerResponse struct {
+	Arn                    string     `json:"arn"`
+	Name                   string     `json:"name"`
+	Type                   string     `json:"type"`
+	Status                 string     `json:"status"`
+	StatusReason           string     `json:"status_reason,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	LastResourceAnalyzed   string     `json:"last_resource_analyzed,omitempty"`
+	LastResourceAnalyzedAt *time.Time `json:"last_resource_analyzed_at,omitempty"`
+}
+
+// FindingResponse represents a response detailing a resource shared outside of the zone of trust of an analyzer.
+type FindingResponse struct {
+	ID                   string            `json:"id"`
+	Resource             string            `json:"resource"`
+	ResourceType         string            `json:"resource_type"`
+	ResourceOwnerAccount string            `json:"resource_owner_account"`
+	Principal            map[string]string `json:"principal"`
+	Condition            map[string]string `json:"condition"`
+	Actions              []string          `json:"actions"`
+	IsPublic             bool              `json:"is_public"`
+	Status               string            `json:"status"`
+	Error                string            `json:"error,omitempty"`
+	Sources              []string          `json:"sources,omitempty"`
+	AnalyzedAt           time.Time         `json:"analyzed_at"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+}
+
+// CriterionResponse represents a criterion of an archive rule.
+type CriterionResponse struct {
+	Eq       []string `json:"eq,omitempty"`
+	Neq      []string `json:"neq,omitempty"`
+	Contains []string `json:"contains,omitempty"`
+	Exists   *bool    `json:"exists,omitempty"`
+}
+
+// ArchiveRuleResponse represents a response detailing an archive rule of an analyzer.
+type ArchiveRuleResponse struct {
+	Name      string                       `json:"name"`
+	Filter    map[string]CriterionResponse `json:"filter"`
+	CreatedAt time.Time                    `json:"created_at"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+// Package cloudtrail provides structures and functionality related to the IAM events recorded by AWS CloudTrail.
+package cloudtrail
+
+import "time"
+
+// IamEventResponse represents a response detailing an IAM API call recorded by CloudTrail. The error is set when
+// the call failed.
+type IamEventResponse struct {
+	ID           string                     `json:"id"`
+	EventName    string                     `json:"event_name"`
+	EventTime    time.Time                  `json:"event_time"`
+	Username     string                     `json:"username"`
+	PrincipalArn string                     `json:"principal_arn,omitempty"`
+	AccessKeyID  string                     `json:"access_key_id,omitempty"`
+	SourceIP     string                     `json:"source_ip,omitempty"`
+	UserAgent    string                     `json:"user_agent,omitempty"`
+	Region       string                     `json:"region,omitempty"`
+	ReadOnly     bool                       `json:"read_only"`
+	ErrorCode    string                     `json:"error_code,omitempty"`
+	ErrorMessage string                     `json:"error_message,omitempty"`
+	Resources    []IamEventResourceResponse `json:"resources"`
+}
+
+// IamEventResourceResponse represents a resource referenced by an IAM event, such as AWS::IAM::Role.
+type IamEventResourceResponse struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,24 @@
+// Package guardduty provides structures and functionality related to AWS GuardDuty.
+package guardduty
+
+import "time"
+
+// FindingResponse represents a response detailing a GuardDuty finding. The severity is a score between 1 and 10,
+// summarized by the LOW, MEDIUM, HIGH or CRITICAL severity label.
+type FindingResponse struct {
+	ID            string    `json:"id"`
+	DetectorID    string    `json:"detector_id"`
+	Arn           string    `json:"arn"`
+	AccountID     string    `json:"account_id"`
+	Region        string    `json:"region"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	Severity      float64   `json:"severity"`
+	SeverityLabel string    `json:"severity_label"`
+	ResourceType  string    `json:"resource_type,omitempty"`
+	Count         int64     `json:"count"`
+	Archived      bool      `json:"archived"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) access keys.
+package iam
+
+import "time"
+
+// AccessKeyResponse represents a response detailing an IAM access key without its secret.
+type AccessKeyResponse struct {
+	AccessKeyId     string     `json:"access_key_id"`
+	Status          string     `json:"status"`
+	CreateDate      time.Time  `json:"created_date"`
+	AgeDays         int        `json:"age_days"`
+	LastUsedDate    *time.Time `json:"last_used_date,omitempty"`
+	LastUsedService string     `json:"last_used_service,omitempty"`
+	LastUsedRegion  string     `json:"last_used_region,omitempty"`
+}
+
+// CreatedAccessKeyResponse represents a newly created IAM access key.
+// The secret access key is only ever returned in this response.
+type CreatedAccessKeyResponse struct {
+	Username        string    `json:"username"`
+	AccessKeyId     string    `json:"access_key_id"`
+	SecretAccessKey string    `json:"secret_access_key"`
+	Status          string    `json:"status"`
+	CreateDate      time.Time `json:"created_date"`
+}
+
+// AccessKeyRotationResponse represents the state of an IAM access key rotation after its confirmation.
+type AccessKeyRotationResponse struct {
+	OldAccessKeyId string    `json:"old_access_key_id"`
+	NewAccessKeyId string    `json:"new_access_key_id"`
+	DeleteAfter    time.Time `json:"delete_after"`
+}
@@ -0,0 +1,21 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) authorization snapshots.
+package iam
+
+import "time"
+
+// HTTP error messages related to IAM authorization snapshots.
+const (
+	HttpErrInvalidSnapshotID = "invalid authorization snapshot id"
+	HttpErrSnapshotsStore    = "authorization snapshots are unavailable, the database is not connected"
+)
+
+// AuthorizationSnapshotSummaryResponse represents a stored authorization snapshot, without its content.
+type AuthorizationSnapshotSummaryResponse struct {
+	SnapshotID  string    `json:"snapshot_id"`
+	AccountID   string    `json:"account_id"`
+	TakenAt     time.Time `json:"taken_at"`
+	UserCount   int       `json:"user_count"`
+	GroupCount  int       `json:"group_count"`
+	RoleCount   int       `json:"role_count"`
+	PolicyCount int       `json:"policy_count"`
+}
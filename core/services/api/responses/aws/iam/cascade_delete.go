@@ -0,0 +1,21 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) cascading deletes.
+package iam
+
+// DeleteStepResponse represents a detach or delete call of a cascading delete.
+// Status is empty in a dry run, then one of succeeded, failed or skipped.
+type DeleteStepResponse struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DeletePlanResponse represents the ordered steps deleting an IAM resource along with its dependencies.
+// Completed tells whether every step succeeded, the resource being deleted.
+type DeletePlanResponse struct {
+	ResourceType string               `json:"resource_type"`
+	Resource     string               `json:"resource"`
+	DryRun       bool                 `json:"dry_run"`
+	Completed    bool                 `json:"completed"`
+	Steps        []DeleteStepResponse `json:"steps"`
+}
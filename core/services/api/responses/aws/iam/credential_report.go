@@ -0,0 +1,60 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) credential reports.
+package iam
+
+import "time"
+
+// HTTP error messages related to IAM credential reports.
+const (
+	HttpErrInvalidReportFormat    = "format must be json or csv"
+	HttpErrInvalidReportID        = "invalid credential report id"
+	HttpErrCredentialReportsStore = "credential report history is unavailable, the database is not connected"
+)
+
+// CredentialReportAccessKeyResponse represents an access key slot of a credential report row.
+type CredentialReportAccessKeyResponse struct {
+	Active          bool       `json:"active"`
+	LastRotated     *time.Time `json:"last_rotated,omitempty"`
+	AgeDays         *int       `json:"age_days,omitempty"`
+	LastUsedDate    *time.Time `json:"last_used_date,omitempty"`
+	LastUsedRegion  string     `json:"last_used_region,omitempty"`
+	LastUsedService string     `json:"last_used_service,omitempty"`
+}
+
+// CredentialReportCertificateResponse represents a signing certificate slot of a credential report row.
+type CredentialReportCertificateResponse struct {
+	Active      bool       `json:"active"`
+	LastRotated *time.Time `json:"last_rotated,omitempty"`
+}
+
+// CredentialReportRowResponse represents a user of a credential report. Ages are computed at the report
+// generation time.
+type CredentialReportRowResponse struct {
+	User                 string                                `json:"user"`
+	Arn                  string                                `json:"arn"`
+	Root                 bool                                  `json:"root"`
+	UserCreationTime     time.Time                             `json:"user_creation_time"`
+	PasswordEnabled      bool                                  `json:"password_enabled"`
+	PasswordLastUsed     *time.Time                            `json:"password_last_used,omitempty"`
+	PasswordLastChanged  *time.Time                            `json:"password_last_changed,omitempty"`
+	PasswordAgeDays      *int                                  `json:"password_age_days,omitempty"`
+	PasswordNextRotation *time.Time                            `json:"password_next_rotation,omitempty"`
+	MFAActive            bool                                  `json:"mfa_active"`
+	AccessKeys           []CredentialReportAccessKeyResponse   `json:"access_keys"`
+	Certificates         []CredentialReportCertificateResponse `json:"certificates"`
+}
+
+// CredentialReportResponse represents an IAM credential report.
+// ReportID is the identifier of the copy stored for historical comparison, when the database is connected.
+type CredentialReportResponse struct {
+	ReportID      string                        `json:"report_id,omitempty"`
+	GeneratedTime time.Time                     `json:"generated_time"`
+	Rows          []CredentialReportRowResponse `json:"rows"`
+}
+
+// CredentialReportSummaryResponse represents a stored credential report, without its rows.
+type CredentialReportSummaryResponse struct {
+	ReportID      string    `json:"report_id"`
+	AccountID     string    `json:"account_id"`
+	GeneratedTime time.Time `json:"generated_time"`
+	RowCount      int       `json:"row_count"`
+}
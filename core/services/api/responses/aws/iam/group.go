@@ -17,6 +17,8 @@ type Group struct {
 	Id         string    `json:"id"`
 	Name       string    `json:"name"`
 	Path       string    `json:"path"`
+
+	Members []string `json:"members,omitempty"`
 }
 
 // GroupDetailResponse represents a response detailing an IAM group.
@@ -25,3 +27,10 @@ type GroupDetailResponse struct {
 	GroupID    string `json:"id"`
 	CreateDate string `json:"created_date"`
 }
+
+// GroupMembershipResponse represents the outcome of adding or removing a single user in an IAM group.
+type GroupMembershipResponse struct {
+	Username string `json:"username"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
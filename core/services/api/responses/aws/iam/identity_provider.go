@@ -0,0 +1,34 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) identity providers.
+package iam
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OIDCProviderResponse represents a response detailing an IAM OpenID Connect identity provider.
+type OIDCProviderResponse struct {
+	Arn         string            `json:"arn"`
+	URL         string            `json:"url"`
+	ClientIDs   []string          `json:"client_ids"`
+	Thumbprints []string          `json:"thumbprints"`
+	CreateDate  time.Time         `json:"created_date"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// SAMLProviderResponse represents a response detailing an IAM SAML 2.0 identity provider.
+// The metadata document is only returned for a single provider.
+type SAMLProviderResponse struct {
+	Arn              string            `json:"arn"`
+	Name             string            `json:"name"`
+	MetadataDocument string            `json:"metadata_document,omitempty"`
+	CreateDate       time.Time         `json:"created_date"`
+	ValidUntil       time.Time         `json:"valid_until"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+// GitHubActionsRoleResponse represents a role created for GitHub Actions along with its generated trust policy.
+type GitHubActionsRoleResponse struct {
+	Role        RoleDetailResponse `json:"role"`
+	TrustPolicy json.RawMessage    `json:"trust_policy"`
+}
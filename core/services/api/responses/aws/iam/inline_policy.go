@@ -0,0 +1,17 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) inline policies.
+package iam
+
+import "encoding/json"
+
+// HTTP error messages related to IAM inline policies.
+const (
+	HttpErrMissingInlinePolicyName = "inline policy name is required"
+	HttpErrInlinePolicyNotFound    = "inline policy not found"
+)
+
+// InlinePolicyResponse represents a response detailing an inline policy.
+type InlinePolicyResponse struct {
+	PrincipalName string          `json:"principal_name"`
+	PolicyName    string          `json:"policy_name"`
+	Document      json.RawMessage `json:"document"`
+}
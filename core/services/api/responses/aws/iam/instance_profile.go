@@ -0,0 +1,15 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) instance profiles.
+package iam
+
+import "time"
+
+// InstanceProfileResponse represents a response detailing an IAM instance profile.
+type InstanceProfileResponse struct {
+	Name       string            `json:"name"`
+	ID         string            `json:"id"`
+	Arn        string            `json:"arn"`
+	Path       string            `json:"path"`
+	CreateDate time.Time         `json:"created_date"`
+	Roles      []string          `json:"roles"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
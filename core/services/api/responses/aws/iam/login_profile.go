@@ -0,0 +1,31 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) console access.
+package iam
+
+import "time"
+
+// HTTP error messages related to IAM console access.
+const (
+	HttpErrPasswordPolicyNotFound = "no custom password policy is set for the account"
+)
+
+// LoginProfileResponse represents a response detailing the console access of an IAM user.
+type LoginProfileResponse struct {
+	Username              string    `json:"username"`
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	CreateDate            time.Time `json:"created_date"`
+	// TemporaryPassword is only returned when Inariam generated the password.
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+}
+
+// PasswordPolicyResponse represents a response detailing the account password policy.
+type PasswordPolicyResponse struct {
+	MinimumPasswordLength      int64 `json:"minimum_password_length"`
+	RequireSymbols             bool  `json:"require_symbols"`
+	RequireNumbers             bool  `json:"require_numbers"`
+	RequireUppercaseCharacters bool  `json:"require_uppercase_characters"`
+	RequireLowercaseCharacters bool  `json:"require_lowercase_characters"`
+	AllowUsersToChangePassword bool  `json:"allow_users_to_change_password"`
+	MaxPasswordAge             int64 `json:"max_password_age"`
+	PasswordReusePrevention    int64 `json:"password_reuse_prevention"`
+	HardExpiry                 bool  `json:"hard_expiry"`
+}
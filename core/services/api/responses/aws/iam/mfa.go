@@ -0,0 +1,19 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) MFA devices.
+package iam
+
+import "time"
+
+// MFADeviceResponse represents a response detailing an MFA device assigned to an IAM user.
+type MFADeviceResponse struct {
+	SerialNumber string    `json:"serial_number"`
+	EnableDate   time.Time `json:"enable_date"`
+	Virtual      bool      `json:"virtual"`
+}
+
+// VirtualMFADeviceResponse represents a newly created virtual MFA device.
+// The seed is only ever returned in this response.
+type VirtualMFADeviceResponse struct {
+	SerialNumber string `json:"serial_number"`
+	Seed         string `json:"seed"`
+	ImageQrCode  string `json:"qrcode_image"`
+}
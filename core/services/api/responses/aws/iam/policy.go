@@ -1,12 +1,22 @@
 // Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) policies.
 package iam
 
+import (
+	"encoding/json"
+	"time"
+
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
+
+	cloudTrailResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/cloudtrail"
+)
+
 // Error messages related to IAM policies.
 const (
 	ErrorMissingPolicyARN         = "Policy ARN is required"
 	ErrorMissingPolicyDocument    = "Policy document is required"
 	ErrorMarshalingPolicyDocument = "Error marshaling policy document"
 	ErrorParsingPolicyDocument    = "Error parsing policy document"
+	ErrorPolicyLintFailed         = "Policy document did not pass the lint checks"
 )
 
 // PolicyDetailResponse represents a response detailing an IAM policy.
@@ -14,3 +24,56 @@ type PolicyDetailResponse struct {
 	PolicyName string `json:"policy_name"`
 	PolicyID   string `json:"policy_id"`
 }
+
+// PolicyHistoryResponse represents a response detailing an IAM policy as returned by IAM, completed by its change
+// history when requested, the latest changes first.
+type PolicyHistoryResponse struct {
+	*awsIam.Policy
+	ChangeHistory []cloudTrailResp.IamEventResponse `json:"change_history,omitempty"`
+}
+
+// PolicyVersionResponse represents a response detailing a version of an IAM policy.
+type PolicyVersionResponse struct {
+	VersionId  string          `json:"version_id"`
+	IsDefault  bool            `json:"is_default"`
+	CreateDate time.Time       `json:"created_date"`
+	Document   json.RawMessage `json:"document,omitempty"`
+}
+
+// StatementChangeResponse represents a policy statement whose content changed between two versions.
+type StatementChangeResponse struct {
+	Sid    string          `json:"sid"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// PolicyDiffResponse represents the semantic difference between two versions of an IAM policy.
+type PolicyDiffResponse struct {
+	FromVersionId string                    `json:"from_version_id"`
+	ToVersionId   string                    `json:"to_version_id"`
+	Added         []json.RawMessage         `json:"added"`
+	Removed       []json.RawMessage         `json:"removed"`
+	Changed       []StatementChangeResponse `json:"changed"`
+	Unchanged     int                       `json:"unchanged"`
+}
+
+// LintFindingResponse represents an issue found in a policy document, located by a JSON pointer.
+type LintFindingResponse struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Pointer  string `json:"pointer"`
+}
+
+// PolicyLintResponse represents the result of the lint checks of a policy document.
+type PolicyLintResponse struct {
+	Blocked  bool                  `json:"blocked"`
+	Findings []LintFindingResponse `json:"findings"`
+}
+
+// PolicyLintErrorResponse represents a policy deployment rejected by the lint checks.
+type PolicyLintErrorResponse struct {
+	Code     int                   `json:"code"`
+	Error    string                `json:"error"`
+	Findings []LintFindingResponse `json:"findings"`
+}
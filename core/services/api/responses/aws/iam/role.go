@@ -1,6 +1,8 @@
 // Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) roles.
 package iam
 
+import cloudTrailResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/cloudtrail"
+
 // HTTP error messages related to IAM roles.
 const (
 	HttpErrMissingRoleName           = "role name is required"
@@ -14,4 +16,14 @@ type RoleDetailResponse struct {
 	RoleName string `json:"role_name"`
 	RoleID   string `json:"role_id"`
 	RoleArn  string `json:"role_arn"`
+
+	Path               string `json:"path,omitempty"`
+	Description        string `json:"description,omitempty"`
+	MaxSessionDuration int64  `json:"max_session_duration,omitempty"`
+
+	InlinePolicies         []string          `json:"inline_policies,omitempty"`
+	PermissionsBoundaryArn string            `json:"permissions_boundary_arn,omitempty"`
+	Tags                   map[string]string `json:"tags,omitempty"`
+	// ChangeHistory is only returned when requested, the latest changes first.
+	ChangeHistory []cloudTrailResp.IamEventResponse `json:"change_history,omitempty"`
 }
@@ -0,0 +1,60 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) service last accessed details.
+package iam
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// HTTP error messages related to IAM service last accessed details.
+const (
+	HttpErrInvalidUnusedDays = "days must be a positive number of days"
+)
+
+// LastAccessedJobResponse represents a started service last accessed details job.
+type LastAccessedJobResponse struct {
+	JobID string `json:"job_id"`
+}
+
+// ActionLastAccessedResponse represents when an action was last used.
+type ActionLastAccessedResponse struct {
+	ActionName     string     `json:"action_name"`
+	LastAccessed   *time.Time `json:"last_accessed,omitempty"`
+	LastAccessedBy string     `json:"last_accessed_by,omitempty"`
+	Region         string     `json:"region,omitempty"`
+}
+
+// ServiceLastAccessedResponse represents when a service was last used.
+type ServiceLastAccessedResponse struct {
+	ServiceName                string                       `json:"service_name"`
+	ServiceNamespace           string                       `json:"service_namespace"`
+	LastAuthenticated          *time.Time                   `json:"last_authenticated,omitempty"`
+	LastAuthenticatedBy        string                       `json:"last_authenticated_by,omitempty"`
+	LastAuthenticatedRegion    string                       `json:"last_authenticated_region,omitempty"`
+	TotalAuthenticatedEntities int64                        `json:"total_authenticated_entities"`
+	TrackedActions             []ActionLastAccessedResponse `json:"tracked_actions,omitempty"`
+}
+
+// ServiceLastAccessedJobResponse represents the status and result of a service last accessed details job.
+type ServiceLastAccessedJobResponse struct {
+	JobID          string                        `json:"job_id"`
+	Status         string                        `json:"status"`
+	Granularity    string                        `json:"granularity"`
+	CreationDate   time.Time                     `json:"creation_date"`
+	CompletionDate *time.Time                    `json:"completion_date,omitempty"`
+	Error          string                        `json:"error,omitempty"`
+	Services       []ServiceLastAccessedResponse `json:"services"`
+}
+
+// UnusedServicesResponse represents the services granted to a role but not used in the last days, with a
+// suggested policy combining the role policies without them.
+type UnusedServicesResponse struct {
+	RoleName       string                        `json:"role_name"`
+	RoleArn        string                        `json:"role_arn"`
+	Days           int                           `json:"days"`
+	Since          time.Time                     `json:"since"`
+	UnusedServices []ServiceLastAccessedResponse `json:"unused_services"`
+	// RemovedActions lists the actions removed from each policy, by policy name.
+	RemovedActions  map[string][]string `json:"removed_actions"`
+	SuggestedPolicy json.RawMessage     `json:"suggested_policy"`
+}
@@ -0,0 +1,18 @@
+// Package iam provides structures and functionality related to the offline evaluation of AWS IAM policies.
+package iam
+
+// MatchedStatementResponse identifies a policy statement that applies to a simulated request.
+type MatchedStatementResponse struct {
+	PolicyType     string `json:"policy_type"`
+	PolicyIndex    int    `json:"policy_index"`
+	StatementIndex int    `json:"statement_index"`
+	Sid            string `json:"sid,omitempty"`
+	Effect         string `json:"effect"`
+}
+
+// SimulateResponse represents the outcome of a simulated request.
+type SimulateResponse struct {
+	Decision          string                     `json:"decision"`
+	Reason            string                     `json:"reason,omitempty"`
+	MatchedStatements []MatchedStatementResponse `json:"matched_statements"`
+}
@@ -0,0 +1,7 @@
+// Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) tags.
+package iam
+
+// TagsResponse represents the tags of an IAM resource.
+type TagsResponse struct {
+	Tags map[string]string `json:"tags"`
+}
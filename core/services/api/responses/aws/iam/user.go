@@ -1,6 +1,8 @@
 // Package iam provides structures and functionality related to AWS Identity and Access Management (IAM) users.
 package iam
 
+import cloudTrailResp "gitea/pcp-inariam/inariam/core/services/api/responses/aws/cloudtrail"
+
 // HTTP error messages related to IAM users.
 const (
 	HttpErrMissingUserName    = "Username is required"
@@ -12,4 +14,12 @@ type UserDetailResponse struct {
 	Username string `json:"username"`
 	UserID   string `json:"id"`
 	UserArn  string `json:"arn"`
+
+	InlinePolicies         []string          `json:"inline_policies,omitempty"`
+	PermissionsBoundaryArn string            `json:"permissions_boundary_arn,omitempty"`
+	Tags                   map[string]string `json:"tags,omitempty"`
+	// TemporaryPassword is only returned when the user is created with console access.
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+	// ChangeHistory is only returned when requested, the latest changes first.
+	ChangeHistory []cloudTrailResp.IamEventResponse `json:"change_history,omitempty"`
 }
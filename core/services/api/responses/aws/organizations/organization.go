@@ -0,0 +1,70 @@
+// Package organizations provides structures and functionality related to AWS Organizations.
+package organizations
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OrganizationResponse represents a response detailing the organization of the account.
+type OrganizationResponse struct {
+	ID                    string `json:"id"`
+	Arn                   string `json:"arn"`
+	FeatureSet            string `json:"feature_set"`
+	ManagementAccountID   string `json:"management_account_id"`
+	ManagementAccountMail string `json:"management_account_email"`
+}
+
+// AccountResponse represents a response detailing an account of the organization.
+type AccountResponse struct {
+	ID           string    `json:"id"`
+	Arn          string    `json:"arn"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Status       string    `json:"status"`
+	JoinedMethod string    `json:"joined_method"`
+	JoinedDate   time.Time `json:"joined_date"`
+}
+
+// OrganizationalUnitResponse represents a node of the organization tree, the root or an organizational unit.
+type OrganizationalUnitResponse struct {
+	ID       string                       `json:"id"`
+	Arn      string                       `json:"arn"`
+	Name     string                       `json:"name"`
+	Type     string                       `json:"type"`
+	Accounts []AccountResponse            `json:"accounts"`
+	Children []OrganizationalUnitResponse `json:"children"`
+}
+
+// ScpResponse represents a response detailing a service control policy. The content is only returned for a
+// single policy.
+type ScpResponse struct {
+	ID          string          `json:"id"`
+	Arn         string          `json:"arn"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	AwsManaged  bool            `json:"aws_managed"`
+	Content     json.RawMessage `json:"content,omitempty"`
+}
+
+// PolicyTargetResponse represents a root, organizational unit or account a service control policy is attached to.
+type PolicyTargetResponse struct {
+	ID   string `json:"id"`
+	Arn  string `json:"arn,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// EffectiveScpLevelResponse represents the service control policies attached to one level of the path from the
+// root of the organization to an account.
+type EffectiveScpLevelResponse struct {
+	Target   PolicyTargetResponse `json:"target"`
+	Policies []ScpResponse        `json:"policies"`
+}
+
+// EffectiveScpsResponse represents the service control policies applying to an account, from the root down to
+// the account. An action is only allowed when every level allows it.
+type EffectiveScpsResponse struct {
+	AccountID string                      `json:"account_id"`
+	Levels    []EffectiveScpLevelResponse `json:"levels"`
+}
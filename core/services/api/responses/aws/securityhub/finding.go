@@ -0,0 +1,43 @@
+// Package securityhub provides structures and functionality related to AWS Security Hub.
+package securityhub
+
+import "time"
+
+// FindingResponse represents a response detailing a Security Hub finding, normalized from the AWS Security
+// Finding Format. The severity is the normalized score between 0 and 100.
+type FindingResponse struct {
+	ID               string        `json:"id"`
+	ProductArn       string        `json:"product_arn"`
+	ProductName      string        `json:"product_name"`
+	GeneratorID      string        `json:"generator_id"`
+	AccountID        string        `json:"account_id"`
+	Region           string        `json:"region"`
+	Types            []string      `json:"types"`
+	Title            string        `json:"title"`
+	Description      string        `json:"description"`
+	SeverityLabel    string        `json:"severity_label"`
+	Severity         int64         `json:"severity"`
+	ComplianceStatus string        `json:"compliance_status,omitempty"`
+	WorkflowStatus   string        `json:"workflow_status"`
+	RecordState      string        `json:"record_state"`
+	ResourceType     string        `json:"resource_type,omitempty"`
+	ResourceID       string        `json:"resource_id,omitempty"`
+	Note             *NoteResponse `json:"note,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// NoteResponse represents the note of a finding.
+type NoteResponse struct {
+	Text      string    `json:"text"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FindingUpdateResponse represents the outcome of the update of a single finding.
+type FindingUpdateResponse struct {
+	ID         string `json:"id"`
+	ProductArn string `json:"product_arn"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,66 @@
+// Package sso provides structures and functionality related to AWS IAM Identity Center.
+package sso
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// InstanceResponse represents a response detailing an IAM Identity Center instance.
+type InstanceResponse struct {
+	InstanceArn     string `json:"instance_arn"`
+	IdentityStoreID string `json:"identity_store_id"`
+}
+
+// PermissionSetResponse represents a response detailing a permission set. The policies are only returned for a
+// single permission set.
+type PermissionSetResponse struct {
+	Arn             string                         `json:"arn"`
+	Name            string                         `json:"name"`
+	Description     string                         `json:"description,omitempty"`
+	SessionDuration string                         `json:"session_duration,omitempty"`
+	RelayState      string                         `json:"relay_state,omitempty"`
+	CreatedDate     time.Time                      `json:"created_date"`
+	Policies        *PermissionSetPoliciesResponse `json:"policies,omitempty"`
+}
+
+// ManagedPolicyResponse represents an AWS managed policy attached to a permission set.
+type ManagedPolicyResponse struct {
+	Arn  string `json:"arn"`
+	Name string `json:"name"`
+}
+
+// CustomerManagedPolicyResponse represents a customer managed policy reference of a permission set.
+type CustomerManagedPolicyResponse struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+// PermissionSetPoliciesResponse represents the policies granting the permissions of a permission set.
+type PermissionSetPoliciesResponse struct {
+	ManagedPolicies         []ManagedPolicyResponse         `json:"managed_policies"`
+	CustomerManagedPolicies []CustomerManagedPolicyResponse `json:"customer_managed_policies"`
+	InlinePolicy            json.RawMessage                 `json:"inline_policy,omitempty"`
+}
+
+// AccountAssignmentResponse represents the assignment of a permission set to a user or a group in an account.
+type AccountAssignmentResponse struct {
+	AccountID        string `json:"account_id"`
+	PermissionSetArn string `json:"permission_set_arn"`
+	PrincipalType    string `json:"principal_type"`
+	PrincipalID      string `json:"principal_id"`
+}
+
+// OperationResponse represents the status of an asynchronous IAM Identity Center operation. The status is one
+// of IN_PROGRESS, SUCCEEDED or FAILED.
+type OperationResponse struct {
+	RequestID        string    `json:"request_id"`
+	Kind             string    `json:"kind"`
+	Status           string    `json:"status"`
+	FailureReason    string    `json:"failure_reason,omitempty"`
+	PermissionSetArn string    `json:"permission_set_arn"`
+	AccountID        string    `json:"account_id,omitempty"`
+	PrincipalType    string    `json:"principal_type,omitempty"`
+	PrincipalID      string    `json:"principal_id,omitempty"`
+	CreatedDate      time.Time `json:"created_date"`
+}
@@ -2,6 +2,8 @@
 package responses
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 )
 
@@ -38,3 +40,17 @@ func ErrorResponse(c echo.Context, statusCode int, message string) error {
 		Error: message,
 	})
 }
+
+// List represents the standard envelope of the list endpoints. NextCursor is empty on the last page.
+type List struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// ListResponse sends a page of a list with the cursor of the next page.
+func ListResponse(c echo.Context, items interface{}, nextCursor string) error {
+	return Response(c, http.StatusOK, List{
+		Items:      items,
+		NextCursor: nextCursor,
+	})
+}
@@ -59,6 +59,14 @@ func ConfigureRoutes(httpApi *api.API) {
 	awsIamGroup.POST("/", awsHandler.CreateGroup)
 	awsIamGroup.PUT("/:id", awsHandler.UpdateGroup)
 	awsIamGroup.DELETE("/:id", awsHandler.DeleteGroup)
+	awsIamGroup.GET("/:id/members", awsHandler.ListGroupMembers)
+	awsIamGroup.POST("/:id/members", awsHandler.AddGroupMembers)
+	awsIamGroup.DELETE("/:id/members", awsHandler.RemoveGroupMembers)
+	awsIamGroup.DELETE("/:id/members/:username", awsHandler.RemoveGroupMember)
+	awsIamGroup.GET("/:id/inline-policies", awsHandler.ListGroupInlinePolicies)
+	awsIamGroup.GET("/:id/inline-policies/:name", awsHandler.GetGroupInlinePolicy)
+	awsIamGroup.PUT("/:id/inline-policies/:name", awsHandler.PutGroupInlinePolicy)
+	awsIamGroup.DELETE("/:id/inline-policies/:name", awsHandler.DeleteGroupInlinePolicy)
 
 	awsIamUser := awsIam.Group("/users")
 	awsIamUser.GET("/", awsHandler.ListUsers)
@@ -66,20 +74,190 @@ func ConfigureRoutes(httpApi *api.API) {
 	awsIamUser.POST("/", awsHandler.CreateUser)
 	awsIamUser.PUT("/:id", awsHandler.UpdateUser)
 	awsIamUser.DELETE("/:id", awsHandler.DeleteUser)
+	awsIamUser.GET("/:id/groups", awsHandler.ListUserGroups)
+	awsIamUser.GET("/:id/login-profile", awsHandler.GetLoginProfile)
+	awsIamUser.POST("/:id/login-profile", awsHandler.CreateLoginProfile)
+	awsIamUser.PUT("/:id/login-profile", awsHandler.UpdateLoginProfile)
+	awsIamUser.DELETE("/:id/login-profile", awsHandler.DeleteLoginProfile)
+	awsIamUser.GET("/:id/mfa", awsHandler.ListMFADevices)
+	awsIamUser.POST("/:id/mfa", awsHandler.CreateVirtualMFADevice)
+	awsIamUser.POST("/:id/mfa/enable", awsHandler.EnableMFADevice)
+	awsIamUser.POST("/:id/mfa/deactivate", awsHandler.DeactivateMFADevice)
+	awsIamUser.DELETE("/:id/mfa", awsHandler.DeleteMFADevice)
+	awsIamUser.GET("/:id/access-keys", awsHandler.ListAccessKeys)
+	awsIamUser.POST("/:id/access-keys", awsHandler.CreateAccessKey)
+	awsIamUser.PUT("/:id/access-keys/:key", awsHandler.UpdateAccessKey)
+	awsIamUser.DELETE("/:id/access-keys/:key", awsHandler.DeleteAccessKey)
+	awsIamUser.POST("/:id/access-keys/:key/rotate", awsHandler.StartAccessKeyRotation)
+	awsIamUser.POST("/:id/access-keys/:key/rotate/confirm", awsHandler.ConfirmAccessKeyRotation)
+	awsIamUser.POST("/:id/access-keys/:key/rotate/complete", awsHandler.CompleteAccessKeyRotation)
+	awsIamUser.GET("/:id/inline-policies", awsHandler.ListUserInlinePolicies)
+	awsIamUser.GET("/:id/inline-policies/:name", awsHandler.GetUserInlinePolicy)
+	awsIamUser.PUT("/:id/inline-policies/:name", awsHandler.PutUserInlinePolicy)
+	awsIamUser.DELETE("/:id/inline-policies/:name", awsHandler.DeleteUserInlinePolicy)
+	awsIamUser.GET("/:id/tags", awsHandler.ListUserTags)
+	awsIamUser.POST("/:id/tags", awsHandler.TagUser)
+	awsIamUser.DELETE("/:id/tags", awsHandler.UntagUser)
+	awsIamUser.PUT("/:id/permissions-boundary", awsHandler.PutUserPermissionsBoundary)
+	awsIamUser.DELETE("/:id/permissions-boundary", awsHandler.DeleteUserPermissionsBoundary)
 
 	awsIamRole := awsIam.Group("/roles")
 	awsIamRole.GET("/", awsHandler.ListRoles)
 	awsIamRole.GET("/:id", awsHandler.GetRole)
 	awsIamRole.POST("/", awsHandler.CreateRole)
+	awsIamRole.POST("/github-actions", awsHandler.CreateGitHubActionsRole)
 	awsIamRole.PUT("/:id", awsHandler.UpdateRole)
 	awsIamRole.DELETE("/:id", awsHandler.DeleteRole)
+	awsIamRole.GET("/:id/inline-policies", awsHandler.ListRoleInlinePolicies)
+	awsIamRole.GET("/:id/inline-policies/:name", awsHandler.GetRoleInlinePolicy)
+	awsIamRole.PUT("/:id/inline-policies/:name", awsHandler.PutRoleInlinePolicy)
+	awsIamRole.DELETE("/:id/inline-policies/:name", awsHandler.DeleteRoleInlinePolicy)
+	awsIamRole.GET("/:id/tags", awsHandler.ListRoleTags)
+	awsIamRole.POST("/:id/tags", awsHandler.TagRole)
+	awsIamRole.DELETE("/:id/tags", awsHandler.UntagRole)
+	awsIamRole.PUT("/:id/permissions-boundary", awsHandler.PutRolePermissionsBoundary)
+	awsIamRole.DELETE("/:id/permissions-boundary", awsHandler.DeleteRolePermissionsBoundary)
+	awsIamRole.GET("/:id/instance-profiles", awsHandler.ListRoleInstanceProfiles)
+	awsIamRole.GET("/:id/unused-services", awsHandler.ListRoleUnusedServices)
+
+	awsIamInstanceProfile := awsIam.Group("/instance-profiles")
+	awsIamInstanceProfile.GET("/", awsHandler.ListInstanceProfiles)
+	awsIamInstanceProfile.GET("/:id", awsHandler.GetInstanceProfile)
+	awsIamInstanceProfile.POST("/", awsHandler.CreateInstanceProfile)
+	awsIamInstanceProfile.DELETE("/:id", awsHandler.DeleteInstanceProfile)
+	awsIamInstanceProfile.PUT("/:id/roles/:role", awsHandler.AddRoleToInstanceProfile)
+	awsIamInstanceProfile.DELETE("/:id/roles/:role", awsHandler.RemoveRoleFromInstanceProfile)
+	awsIamInstanceProfile.GET("/:id/tags", awsHandler.ListInstanceProfileTags)
+	awsIamInstanceProfile.POST("/:id/tags", awsHandler.TagInstanceProfile)
+	awsIamInstanceProfile.DELETE("/:id/tags", awsHandler.UntagInstanceProfile)
+
+	awsIamOIDCProvider := awsIam.Group("/oidc-providers")
+	awsIamOIDCProvider.GET("", awsHandler.ListOIDCProviders)
+	awsIamOIDCProvider.GET("/:arn", awsHandler.GetOIDCProvider)
+	awsIamOIDCProvider.POST("", awsHandler.CreateOIDCProvider)
+	awsIamOIDCProvider.PUT("/:arn", awsHandler.UpdateOIDCProvider)
+	awsIamOIDCProvider.DELETE("/:arn", awsHandler.DeleteOIDCProvider)
+	awsIamOIDCProvider.GET("/:arn/tags", awsHandler.ListOIDCProviderTags)
+	awsIamOIDCProvider.POST("/:arn/tags", awsHandler.TagOIDCProvider)
+	awsIamOIDCProvider.DELETE("/:arn/tags", awsHandler.UntagOIDCProvider)
+
+	awsIamSAMLProvider := awsIam.Group("/saml-providers")
+	awsIamSAMLProvider.GET("", awsHandler.ListSAMLProviders)
+	awsIamSAMLProvider.GET("/:arn", awsHandler.GetSAMLProvider)
+	awsIamSAMLProvider.POST("", awsHandler.CreateSAMLProvider)
+	awsIamSAMLProvider.PUT("/:arn", awsHandler.UpdateSAMLProvider)
+	awsIamSAMLProvider.DELETE("/:arn", awsHandler.DeleteSAMLProvider)
+
+	awsIamPasswordPolicy := awsIam.Group("/password-policy")
+	awsIamPasswordPolicy.GET("", awsHandler.GetPasswordPolicy)
+	awsIamPasswordPolicy.PUT("", awsHandler.UpdatePasswordPolicy)
+	awsIamPasswordPolicy.DELETE("", awsHandler.DeletePasswordPolicy)
 
 	awsIamPolicy := awsIam.Group("/policies")
 	awsIamPolicy.GET("/", awsHandler.ListPolicies)
 	awsIamPolicy.GET("/:arn", awsHandler.GetPolicy)
 	awsIamPolicy.POST("/", awsHandler.CreatePolicy)
+	awsIamPolicy.POST("/lint", awsHandler.LintPolicy)
 	awsIamPolicy.PUT("/:arn", awsHandler.UpdatePolicy)
 	awsIamPolicy.DELETE("/:arn", awsHandler.DeletePolicy)
+	awsIamPolicy.GET("/:arn/versions", awsHandler.ListPolicyVersions)
+	awsIamPolicy.GET("/:arn/versions/:version", awsHandler.GetPolicyVersion)
+	awsIamPolicy.PUT("/:arn/default-version", awsHandler.SetDefaultPolicyVersion)
+	awsIamPolicy.GET("/:arn/diff", awsHandler.DiffPolicyVersions)
+	awsIamPolicy.GET("/:arn/tags", awsHandler.ListPolicyTags)
+	awsIamPolicy.POST("/:arn/tags", awsHandler.TagPolicy)
+	awsIamPolicy.DELETE("/:arn/tags", awsHandler.UntagPolicy)
+
+	awsIam.POST("/simulate", awsHandler.SimulatePolicies)
+
+	awsIamLastAccessed := awsIam.Group("/last-accessed")
+	awsIamLastAccessed.POST("", awsHandler.GenerateLastAccessedDetails)
+	awsIamLastAccessed.GET("/:job", awsHandler.GetLastAccessedDetails)
+
+	awsIamSnapshot := awsIam.Group("/snapshots")
+	awsIamSnapshot.GET("", awsHandler.ListAuthorizationSnapshots)
+	awsIamSnapshot.POST("", awsHandler.TakeAuthorizationSnapshot)
+	awsIamSnapshot.GET("/:id", awsHandler.GetAuthorizationSnapshot)
+
+	awsIamCredentialReport := awsIam.Group("/credential-report")
+	awsIamCredentialReport.GET("", awsHandler.GetCredentialReport)
+	awsIamCredentialReport.GET("/history", awsHandler.ListCredentialReportHistory)
+	awsIamCredentialReport.GET("/history/:id", awsHandler.GetStoredCredentialReport)
+
+	awsOrganizations := httpApi.Echo.Group("/aws/organizations")
+	awsOrganizations.GET("", awsHandler.GetOrganization)
+	awsOrganizations.GET("/tree", awsHandler.GetOrganizationTree)
+
+	awsOrganizationsAccount := awsOrganizations.Group("/accounts")
+	awsOrganizationsAccount.GET("", awsHandler.ListOrganizationAccounts)
+	awsOrganizationsAccount.GET("/:id", awsHandler.GetOrganizationAccount)
+	awsOrganizationsAccount.GET("/:id/effective-scps", awsHandler.GetEffectiveScps)
+
+	awsOrganizationsScp := awsOrganizations.Group("/scps")
+	awsOrganizationsScp.GET("", awsHandler.ListScps)
+	awsOrganizationsScp.GET("/:id", awsHandler.GetScp)
+	awsOrganizationsScp.POST("", awsHandler.CreateScp)
+	awsOrganizationsScp.PUT("/:id", awsHandler.UpdateScp)
+	awsOrganizationsScp.DELETE("/:id", awsHandler.DeleteScp)
+	awsOrganizationsScp.GET("/:id/targets", awsHandler.ListScpTargets)
+	awsOrganizationsScp.PUT("/:id/targets/:target", awsHandler.AttachScp)
+	awsOrganizationsScp.DELETE("/:id/targets/:target", awsHandler.DetachScp)
+
+	awsSSO := httpApi.Echo.Group("/aws/sso")
+	awsSSO.GET("/instances", awsHandler.ListSSOInstances)
+	awsSSO.GET("/operations/:id", awsHandler.GetSSOOperation)
+
+	awsSSOPermissionSet := awsSSO.Group("/permission-sets")
+	awsSSOPermissionSet.GET("", awsHandler.ListPermissionSets)
+	awsSSOPermissionSet.GET("/:arn", awsHandler.GetPermissionSet)
+	awsSSOPermissionSet.POST("", awsHandler.CreatePermissionSet)
+	awsSSOPermissionSet.PUT("/:arn", awsHandler.UpdatePermissionSet)
+	awsSSOPermissionSet.DELETE("/:arn", awsHandler.DeletePermissionSet)
+	awsSSOPermissionSet.POST("/:arn/managed-policies", awsHandler.AttachPermissionSetManagedPolicy)
+	awsSSOPermissionSet.DELETE("/:arn/managed-policies", awsHandler.DetachPermissionSetManagedPolicy)
+	awsSSOPermissionSet.POST("/:arn/customer-managed-policies", awsHandler.AttachPermissionSetCustomerManagedPolicy)
+	awsSSOPermissionSet.DELETE("/:arn/customer-managed-policies", awsHandler.DetachPermissionSetCustomerManagedPolicy)
+	awsSSOPermissionSet.PUT("/:arn/inline-policy", awsHandler.PutPermissionSetInlinePolicy)
+	awsSSOPermissionSet.DELETE("/:arn/inline-policy", awsHandler.DeletePermissionSetInlinePolicy)
+	awsSSOPermissionSet.GET("/:arn/accounts", awsHandler.ListPermissionSetAccounts)
+	awsSSOPermissionSet.POST("/:arn/provision", awsHandler.ProvisionPermissionSet)
+
+	awsSSOAssignment := awsSSO.Group("/assignments")
+	awsSSOAssignment.GET("", awsHandler.ListAccountAssignments)
+	awsSSOAssignment.POST("", awsHandler.CreateAccountAssignment)
+	awsSSOAssignment.DELETE("", awsHandler.DeleteAccountAssignment)
+
+	awsGuardDutyFinding := httpApi.Echo.Group("/aws/guardduty/findings")
+	awsGuardDutyFinding.GET("", awsHandler.ListGuardDutyFindings)
+	awsGuardDutyFinding.POST("/archive", awsHandler.ArchiveGuardDutyFindings)
+	awsGuardDutyFinding.POST("/unarchive", awsHandler.UnarchiveGuardDutyFindings)
+
+	awsSecurityHubFinding := httpApi.Echo.Group("/aws/securityhub/findings")
+	awsSecurityHubFinding.GET("", awsHandler.ListSecurityHubFindings)
+	awsSecurityHubFinding.PUT("", awsHandler.UpdateSecurityHubFindings)
+
+	awsAccessAnalyzer := httpApi.Echo.Group("/aws/access-analyzer/analyzers")
+	awsAccessAnalyzer.GET("", awsHandler.ListAnalyzers)
+	awsAccessAnalyzer.GET("/:name", awsHandler.GetAnalyzer)
+	awsAccessAnalyzer.POST("", awsHandler.CreateAnalyzer)
+	awsAccessAnalyzer.DELETE("/:name", awsHandler.DeleteAnalyzer)
+
+	awsAccessAnalyzerFinding := awsAccessAnalyzer.Group("/:name/findings")
+	awsAccessAnalyzerFinding.GET("", awsHandler.ListAnalyzerFindings)
+	awsAccessAnalyzerFinding.GET("/:id", awsHandler.GetAnalyzerFinding)
+	awsAccessAnalyzerFinding.POST("/archive", awsHandler.ArchiveAnalyzerFindings)
+	awsAccessAnalyzerFinding.POST("/unarchive", awsHandler.UnarchiveAnalyzerFindings)
+
+	awsAccessAnalyzerArchiveRule := awsAccessAnalyzer.Group("/:name/archive-rules")
+	awsAccessAnalyzerArchiveRule.GET("", awsHandler.ListArchiveRules)
+	awsAccessAnalyzerArchiveRule.GET("/:rule", awsHandler.GetArchiveRule)
+	awsAccessAnalyzerArchiveRule.POST("", awsHandler.CreateArchiveRule)
+	awsAccessAnalyzerArchiveRule.PUT("/:rule", awsHandler.UpdateArchiveRule)
+	awsAccessAnalyzerArchiveRule.DELETE("/:rule", awsHandler.DeleteArchiveRule)
+	awsAccessAnalyzerArchiveRule.POST("/:rule/apply", awsHandler.ApplyArchiveRule)
+
+	awsCloudTrailIamEvent := httpApi.Echo.Group("/aws/cloudtrail/iam-events")
+	awsCloudTrailIamEvent.GET("", awsHandler.ListIamEvents)
 
 	gcpIam := httpApi.Echo.Group("/gcp/iam")
 
@@ -1,12 +1,32 @@
 package aws
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/accessanalyzer"
 
+	inaIam "gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 	"gitea/pcp-inariam/inariam/pkgs/log"
 )
 
+const (
+	ErrAccessAnalyzerNotExists      = "error Access Analyzer resource does not exist"
+	ErrAccessAnalyzerConflict       = "error Access Analyzer resource already exists"
+	ErrAccessAnalyzerInvalidType    = "error invalid analyzer type, expected ACCOUNT or ORGANIZATION"
+	ErrAccessAnalyzerEmptyCriterion = "error archive rule criterion requires eq, neq, contains or exists"
+)
+
+// Zones of trust of the analyzers. An account analyzer reports access from outside the account, an organization
+// analyzer access from outside the organization.
+const (
+	AccessAnalyzerTypeAccount      = accessanalyzer.TypeAccount
+	AccessAnalyzerTypeOrganization = accessanalyzer.TypeOrganization
+)
+
 type AccessAnalyzerSvc struct {
 	svc *accessanalyzer.AccessAnalyzer
 }
@@ -17,33 +37,522 @@ func (awsSess *Session) CreateAccessAnalyzerSvc() {
 	}
 }
 
-func (accessanalyzerSvc *AccessAnalyzerSvc) ListAccessAnalyzers() {
-	resp, err := accessanalyzerSvc.svc.ListAnalyzers(&accessanalyzer.ListAnalyzersInput{})
+// Analyzer is an Access Analyzer analyzer, reporting the resources shared outside of its zone of trust.
+type Analyzer struct {
+	Arn                    string
+	Name                   string
+	Type                   string
+	Status                 string
+	StatusReason           string
+	CreatedAt              time.Time
+	LastResourceAnalyzed   string
+	LastResourceAnalyzedAt time.Time
+}
+
+// AccessAnalyzerFinding is a resource shared outside of the zone of trust of an analyzer. Principal and
+// Condition describe who is granted the Actions on the resource and under which conditions.
+type AccessAnalyzerFinding struct {
+	ID                   string
+	Resource             string
+	ResourceType         string
+	ResourceOwnerAccount string
+	Principal            map[string]string
+	Condition            map[string]string
+	Actions              []string
+	IsPublic             bool
+	Status               string
+	Error                string
+	Sources              []string
+	AnalyzedAt           time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// AccessAnalyzerCriterion matches a finding property, such as resourceType or principal.AWS, against values.
+type AccessAnalyzerCriterion struct {
+	Eq       []string
+	Neq      []string
+	Contains []string
+	Exists   *bool
+}
+
+// ArchiveRule automatically archives the new findings of an analyzer matching every criterion of its filter.
+type ArchiveRule struct {
+	Name      string
+	Filter    map[string]AccessAnalyzerCriterion
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// AccessAnalyzerFindingFilter selects the Access Analyzer findings to list. Zero values do not filter, except
+// Statuses which defaults to the active findings.
+type AccessAnalyzerFindingFilter struct {
+	Statuses      []string
+	ResourceTypes []string
+	Resource      string
+	IsPublic      *bool
+}
+
+// Criteria returns the Access Analyzer criteria matching the filter.
+func (filter AccessAnalyzerFindingFilter) Criteria() map[string]AccessAnalyzerCriterion {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{accessanalyzer.FindingStatusActive}
+	}
+
+	criteria := map[string]AccessAnalyzerCriterion{
+		"status": {Eq: statuses},
+	}
+	if len(filter.ResourceTypes) > 0 {
+		criteria["resourceType"] = AccessAnalyzerCriterion{Eq: filter.ResourceTypes}
+	}
+	if filter.Resource != "" {
+		criteria["resource"] = AccessAnalyzerCriterion{Contains: []string{filter.Resource}}
+	}
+	if filter.IsPublic != nil {
+		criteria["isPublic"] = AccessAnalyzerCriterion{Eq: []string{strconv.FormatBool(*filter.IsPublic)}}
+	}
+
+	return criteria
+}
+
+// toAccessAnalyzerFilter converts criteria to their SDK representation, rejecting empty criteria.
+func toAccessAnalyzerFilter(criteria map[string]AccessAnalyzerCriterion) (map[string]*accessanalyzer.Criterion,
+	error,
+) {
+	filter := make(map[string]*accessanalyzer.Criterion, len(criteria))
+	for property, criterion := range criteria {
+		if len(criterion.Eq) == 0 && len(criterion.Neq) == 0 && len(criterion.Contains) == 0 &&
+			criterion.Exists == nil {
+			return nil, fmt.Errorf("%s: %w", property, errors.New(ErrAccessAnalyzerEmptyCriterion))
+		}
+
+		sdkCriterion := &accessanalyzer.Criterion{Exists: criterion.Exists}
+		if len(criterion.Eq) > 0 {
+			sdkCriterion.Eq = aws.StringSlice(criterion.Eq)
+		}
+		if len(criterion.Neq) > 0 {
+			sdkCriterion.Neq = aws.StringSlice(criterion.Neq)
+		}
+		if len(criterion.Contains) > 0 {
+			sdkCriterion.Contains = aws.StringSlice(criterion.Contains)
+		}
+		filter[property] = sdkCriterion
+	}
+
+	return filter, nil
+}
+
+func toAccessAnalyzerCriteria(filter map[string]*accessanalyzer.Criterion) map[string]AccessAnalyzerCriterion {
+	criteria := make(map[string]AccessAnalyzerCriterion, len(filter))
+	for property, criterion := range filter {
+		criteria[property] = AccessAnalyzerCriterion{
+			Eq:       aws.StringValueSlice(criterion.Eq),
+			Neq:      aws.StringValueSlice(criterion.Neq),
+			Contains: aws.StringValueSlice(criterion.Contains),
+			Exists:   criterion.Exists,
+		}
+	}
+
+	return criteria
+}
+
+func toAnalyzer(analyzer *accessanalyzer.AnalyzerSummary) Analyzer {
+	accessAnalyzer := Analyzer{
+		Arn:                    aws.StringValue(analyzer.Arn),
+		Name:                   aws.StringValue(analyzer.Name),
+		Type:                   aws.StringValue(analyzer.Type),
+		Status:                 aws.StringValue(analyzer.Status),
+		CreatedAt:              aws.TimeValue(analyzer.CreatedAt),
+		LastResourceAnalyzed:   aws.StringValue(analyzer.LastResourceAnalyzed),
+		LastResourceAnalyzedAt: aws.TimeValue(analyzer.LastResourceAnalyzedAt),
+	}
+	if analyzer.StatusReason != nil {
+		accessAnalyzer.StatusReason = aws.StringValue(analyzer.StatusReason.Code)
+	}
+
+	return accessAnalyzer
+}
 
+func toAccessAnalyzerFinding(finding *accessanalyzer.FindingSummary) AccessAnalyzerFinding {
+	accessAnalyzerFinding := AccessAnalyzerFinding{
+		ID:                   aws.StringValue(finding.Id),
+		Resource:             aws.StringValue(finding.Resource),
+		ResourceType:         aws.StringValue(finding.ResourceType),
+		ResourceOwnerAccount: aws.StringValue(finding.ResourceOwnerAccount),
+		Principal:            aws.StringValueMap(finding.Principal),
+		Condition:            aws.StringValueMap(finding.Condition),
+		Actions:              aws.StringValueSlice(finding.Action),
+		IsPublic:             aws.BoolValue(finding.IsPublic),
+		Status:               aws.StringValue(finding.Status),
+		Error:                aws.StringValue(finding.Error),
+		AnalyzedAt:           aws.TimeValue(finding.AnalyzedAt),
+		CreatedAt:            aws.TimeValue(finding.CreatedAt),
+		UpdatedAt:            aws.TimeValue(finding.UpdatedAt),
+	}
+	for _, source := range finding.Sources {
+		accessAnalyzerFinding.Sources = append(accessAnalyzerFinding.Sources, aws.StringValue(source.Type))
+	}
+
+	return accessAnalyzerFinding
+}
+
+func toArchiveRule(rule *accessanalyzer.ArchiveRuleSummary) ArchiveRule {
+	return ArchiveRule{
+		Name:      aws.StringValue(rule.RuleName),
+		Filter:    toAccessAnalyzerCriteria(rule.Filter),
+		CreatedAt: aws.TimeValue(rule.CreatedAt),
+		UpdatedAt: aws.TimeValue(rule.UpdatedAt),
+	}
+}
+
+// wrapAccessAnalyzerError adds the Inariam error matching the common Access Analyzer failures to err.
+func wrapAccessAnalyzerError(funcName string, err error) error {
+	switch {
+	case isAwsErrorCode(err, accessanalyzer.ErrCodeResourceNotFoundException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrAccessAnalyzerNotExists, err)
+	case isAwsErrorCode(err, accessanalyzer.ErrCodeConflictException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrAccessAnalyzerConflict, err)
+	}
+
+	return fmt.Errorf("%s: %w", funcName, err)
+}
+
+// ListAccessAnalyzers lists the analyzers of the region.
+func (accessanalyzerSvc *AccessAnalyzerSvc) ListAccessAnalyzers() ([]Analyzer, error) {
+	var analyzers []Analyzer
+
+	err := accessanalyzerSvc.svc.ListAnalyzersPages(&accessanalyzer.ListAnalyzersInput{},
+		func(page *accessanalyzer.ListAnalyzersOutput, lastPage bool) bool {
+			for _, analyzer := range page.Analyzers {
+				analyzers = append(analyzers, toAnalyzer(analyzer))
+			}
+			return !lastPage
+		})
+	if err != nil {
+		return nil, wrapAccessAnalyzerError("ListAccessAnalyzers", err)
+	}
+
+	return analyzers, nil
+}
+
+// GetAnalyzer returns an analyzer given its name.
+func (accessanalyzerSvc *AccessAnalyzerSvc) GetAnalyzer(analyzerName string) (*Analyzer, error) {
+	output, err := accessanalyzerSvc.svc.GetAnalyzer(&accessanalyzer.GetAnalyzerInput{
+		AnalyzerName: aws.String(analyzerName),
+	})
 	if err != nil {
-		log.Logger.Fatalf("Failed to list analyzers: %s", err)
+		return nil, wrapAccessAnalyzerError("GetAnalyzer", err)
 	}
 
-	for _, analyzer := range resp.Analyzers {
-		log.Logger.Infoln("Analyzer ARN:", *analyzer.Arn)
-		log.Logger.Infoln("Analyzer Name:", *analyzer.Name)
-		// ... add more fields as needed
+	analyzer := toAnalyzer(output.Analyzer)
+	return &analyzer, nil
+}
+
+// CreateAnalyzer creates an analyzer for an ACCOUNT or ORGANIZATION zone of trust along with its archive rules.
+// An organization analyzer must be created from the management account or a delegated administrator.
+func (accessanalyzerSvc *AccessAnalyzerSvc) CreateAnalyzer(analyzerName string, analyzerType string,
+	archiveRules []ArchiveRule, tags map[string]string,
+) (*Analyzer, error) {
+	if analyzerType != AccessAnalyzerTypeAccount && analyzerType != AccessAnalyzerTypeOrganization {
+		return nil, fmt.Errorf("CreateAnalyzer: %w", errors.New(ErrAccessAnalyzerInvalidType))
+	}
+
+	input := &accessanalyzer.CreateAnalyzerInput{
+		AnalyzerName: aws.String(analyzerName),
+		Type:         aws.String(analyzerType),
+	}
+	for _, rule := range archiveRules {
+		filter, err := toAccessAnalyzerFilter(rule.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("CreateAnalyzer: %w", err)
+		}
+		input.ArchiveRules = append(input.ArchiveRules, &accessanalyzer.InlineArchiveRule{
+			RuleName: aws.String(rule.Name),
+			Filter:   filter,
+		})
+	}
+	if len(tags) > 0 {
+		input.Tags = aws.StringMap(tags)
+	}
+
+	if _, err := accessanalyzerSvc.svc.CreateAnalyzer(input); err != nil {
+		return nil, wrapAccessAnalyzerError("CreateAnalyzer", err)
+	}
+
+	log.Logger.Infof("Analyzer '%s' created successfully\n", analyzerName)
+	return accessanalyzerSvc.GetAnalyzer(analyzerName)
+}
+
+// DeleteAnalyzer deletes an analyzer along with its findings and archive rules.
+func (accessanalyzerSvc *AccessAnalyzerSvc) DeleteAnalyzer(analyzerName string) error {
+	_, err := accessanalyzerSvc.svc.DeleteAnalyzer(&accessanalyzer.DeleteAnalyzerInput{
+		AnalyzerName: aws.String(analyzerName),
+	})
+	if err != nil {
+		return wrapAccessAnalyzerError("DeleteAnalyzer", err)
 	}
+
+	log.Logger.Infof("Analyzer '%s' deleted successfully\n", analyzerName)
+	return nil
 }
 
-func (accessanalyzerSvc *AccessAnalyzerSvc) ListFindings(analyzerArn string) {
+// ListFindings returns at most pageSize findings of an analyzer matching the filter, starting at marker, an
+// empty marker starting at the first finding. It returns the marker of the next page, empty on the last page.
+func (accessanalyzerSvc *AccessAnalyzerSvc) ListFindings(analyzerArn string, filter AccessAnalyzerFindingFilter,
+	marker string, pageSize int64,
+) ([]AccessAnalyzerFinding, string, error) {
+	criteria, err := toAccessAnalyzerFilter(filter.Criteria())
+	if err != nil {
+		return nil, "", fmt.Errorf("ListFindings: %w", err)
+	}
 
 	input := &accessanalyzer.ListFindingsInput{
 		AnalyzerArn: aws.String(analyzerArn),
+		Filter:      criteria,
+		MaxResults:  aws.Int64(pageSize),
+	}
+	if marker != "" {
+		input.NextToken = aws.String(marker)
+	}
+
+	output, err := accessanalyzerSvc.svc.ListFindings(input)
+	if err != nil {
+		return nil, "", wrapAccessAnalyzerError("ListFindings", err)
+	}
+
+	findings := make([]AccessAnalyzerFinding, 0, len(output.Findings))
+	for _, finding := range output.Findings {
+		findings = append(findings, toAccessAnalyzerFinding(finding))
+	}
+
+	return findings, aws.StringValue(output.NextToken), nil
+}
+
+// GetFinding returns a finding of an analyzer.
+func (accessanalyzerSvc *AccessAnalyzerSvc) GetFinding(analyzerArn string, findingID string,
+) (*AccessAnalyzerFinding, error) {
+	output, err := accessanalyzerSvc.svc.GetFinding(&accessanalyzer.GetFindingInput{
+		AnalyzerArn: aws.String(analyzerArn),
+		Id:          aws.String(findingID),
+	})
+	if err != nil {
+		return nil, wrapAccessAnalyzerError("GetFinding", err)
 	}
 
-	resp, err := accessanalyzerSvc.svc.ListFindings(input)
+	finding := toAccessAnalyzerFinding(&accessanalyzer.FindingSummary{
+		Action:               output.Finding.Action,
+		AnalyzedAt:           output.Finding.AnalyzedAt,
+		Condition:            output.Finding.Condition,
+		CreatedAt:            output.Finding.CreatedAt,
+		Error:                output.Finding.Error,
+		Id:                   output.Finding.Id,
+		IsPublic:             output.Finding.IsPublic,
+		Principal:            output.Finding.Principal,
+		Resource:             output.Finding.Resource,
+		ResourceOwnerAccount: output.Finding.ResourceOwnerAccount,
+		ResourceType:         output.Finding.ResourceType,
+		Sources:              output.Finding.Sources,
+		Status:               output.Finding.Status,
+		UpdatedAt:            output.Finding.UpdatedAt,
+	})
+	return &finding, nil
+}
+
+func (accessanalyzerSvc *AccessAnalyzerSvc) updateFindings(analyzerArn string, findingIds []string,
+	status string,
+) error {
+	_, err := accessanalyzerSvc.svc.UpdateFindings(&accessanalyzer.UpdateFindingsInput{
+		AnalyzerArn: aws.String(analyzerArn),
+		Ids:         aws.StringSlice(findingIds),
+		Status:      aws.String(status),
+	})
+
+	return err
+}
+
+// ArchiveFindings archives findings of an analyzer, marking the access they report as intended.
+func (accessanalyzerSvc *AccessAnalyzerSvc) ArchiveFindings(analyzerArn string, findingIds []string) error {
+	err := accessanalyzerSvc.updateFindings(analyzerArn, findingIds, accessanalyzer.FindingStatusUpdateArchived)
 	if err != nil {
-		log.Logger.Fatalf("Failed to list findings: %s", err)
+		return wrapAccessAnalyzerError("ArchiveFindings", err)
 	}
 
-	for _, findingId := range resp.Findings {
-		log.Logger.Infoln("Finding ID:", *findingId.Id)
-		// ... add more fields as needed
+	log.Logger.Infof("%d findings of analyzer '%s' archived successfully\n", len(findingIds), analyzerArn)
+	return nil
+}
+
+// UnarchiveFindings makes archived findings of an analyzer active again.
+func (accessanalyzerSvc *AccessAnalyzerSvc) UnarchiveFindings(analyzerArn string, findingIds []string) error {
+	err := accessanalyzerSvc.updateFindings(analyzerArn, findingIds, accessanalyzer.FindingStatusUpdateActive)
+	if err != nil {
+		return wrapAccessAnalyzerError("UnarchiveFindings", err)
 	}
+
+	log.Logger.Infof("%d findings of analyzer '%s' unarchived successfully\n", len(findingIds), analyzerArn)
+	return nil
+}
+
+// ListArchiveRules lists the archive rules of an analyzer.
+func (accessanalyzerSvc *AccessAnalyzerSvc) ListArchiveRules(analyzerName string) ([]ArchiveRule, error) {
+	var rules []ArchiveRule
+
+	err := accessanalyzerSvc.svc.ListArchiveRulesPages(&accessanalyzer.ListArchiveRulesInput{
+		AnalyzerName: aws.String(analyzerName),
+	}, func(page *accessanalyzer.ListArchiveRulesOutput, lastPage bool) bool {
+		for _, rule := range page.ArchiveRules {
+			rules = append(rules, toArchiveRule(rule))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapAccessAnalyzerError("ListArchiveRules", err)
+	}
+
+	return rules, nil
+}
+
+// GetArchiveRule returns an archive rule of an analyzer.
+func (accessanalyzerSvc *AccessAnalyzerSvc) GetArchiveRule(analyzerName string, ruleName string,
+) (*ArchiveRule, error) {
+	output, err := accessanalyzerSvc.svc.GetArchiveRule(&accessanalyzer.GetArchiveRuleInput{
+		AnalyzerName: aws.String(analyzerName),
+		RuleName:     aws.String(ruleName),
+	})
+	if err != nil {
+		return nil, wrapAccessAnalyzerError("GetArchiveRule", err)
+	}
+
+	rule := toArchiveRule(output.ArchiveRule)
+	return &rule, nil
+}
+
+// CreateArchiveRule creates an archive rule on an analyzer. It only applies to the new findings, see
+// ApplyArchiveRule for the existing ones.
+func (accessanalyzerSvc *AccessAnalyzerSvc) CreateArchiveRule(analyzerName string, rule ArchiveRule,
+) (*ArchiveRule, error) {
+	filter, err := toAccessAnalyzerFilter(rule.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("CreateArchiveRule: %w", err)
+	}
+
+	_, err = accessanalyzerSvc.svc.CreateArchiveRule(&accessanalyzer.CreateArchiveRuleInput{
+		AnalyzerName: aws.String(analyzerName),
+		RuleName:     aws.String(rule.Name),
+		Filter:       filter,
+	})
+	if err != nil {
+		return nil, wrapAccessAnalyzerError("CreateArchiveRule", err)
+	}
+
+	log.Logger.Infof("Archive rule '%s' of analyzer '%s' created successfully\n", rule.Name, analyzerName)
+	return accessanalyzerSvc.GetArchiveRule(analyzerName, rule.Name)
+}
+
+// UpdateArchiveRule replaces the filter of an archive rule.
+func (accessanalyzerSvc *AccessAnalyzerSvc) UpdateArchiveRule(analyzerName string, rule ArchiveRule,
+) (*ArchiveRule, error) {
+	filter, err := toAccessAnalyzerFilter(rule.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateArchiveRule: %w", err)
+	}
+
+	_, err = accessanalyzerSvc.svc.UpdateArchiveRule(&accessanalyzer.UpdateArchiveRuleInput{
+		AnalyzerName: aws.String(analyzerName),
+		RuleName:     aws.String(rule.Name),
+		Filter:       filter,
+	})
+	if err != nil {
+		return nil, wrapAccessAnalyzerError("UpdateArchiveRule", err)
+	}
+
+	log.Logger.Infof("Archive rule '%s' of analyzer '%s' updated successfully\n", rule.Name, analyzerName)
+	return accessanalyzerSvc.GetArchiveRule(analyzerName, rule.Name)
+}
+
+// DeleteArchiveRule deletes an archive rule, the findings it archived staying archived.
+func (accessanalyzerSvc *AccessAnalyzerSvc) DeleteArchiveRule(analyzerName string, ruleName string) error {
+	_, err := accessanalyzerSvc.svc.DeleteArchiveRule(&accessanalyzer.DeleteArchiveRuleInput{
+		AnalyzerName: aws.String(analyzerName),
+		RuleName:     aws.String(ruleName),
+	})
+	if err != nil {
+		return wrapAccessAnalyzerError("DeleteArchiveRule", err)
+	}
+
+	log.Logger.Infof("Archive rule '%s' of analyzer '%s' deleted successfully\n", ruleName, analyzerName)
+	return nil
+}
+
+// ApplyArchiveRule archives the existing findings of an analyzer matching an archive rule.
+func (accessanalyzerSvc *AccessAnalyzerSvc) ApplyArchiveRule(analyzerArn string, ruleName string) error {
+	_, err := accessanalyzerSvc.svc.ApplyArchiveRule(&accessanalyzer.ApplyArchiveRuleInput{
+		AnalyzerArn: aws.String(analyzerArn),
+		RuleName:    aws.String(ruleName),
+	})
+	if err != nil {
+		return wrapAccessAnalyzerError("ApplyArchiveRule", err)
+	}
+
+	log.Logger.Infof("Archive rule '%s' applied to analyzer '%s' successfully\n", ruleName, analyzerArn)
+	return nil
+}
+
+// locationPointer converts the path of an Access Analyzer finding location to a JSON pointer.
+func locationPointer(location *accessanalyzer.Location) string {
+	var tokens []string
+
+	for _, element := range location.Path {
+		switch {
+		case element.Index != nil:
+			tokens = append(tokens, strconv.FormatInt(*element.Index, 10))
+		case element.Key != nil:
+			tokens = append(tokens, *element.Key)
+		case element.Value != nil:
+			tokens = append(tokens, *element.Value)
+		}
+	}
+
+	return inaIam.JSONPointer(tokens...)
+}
+
+// ValidatePolicy runs the Access Analyzer policy checks on an identity-based policy document
+// and returns their results as lint findings.
+func (accessanalyzerSvc *AccessAnalyzerSvc) ValidatePolicy(policyDocument string) ([]inaIam.LintFinding, error) {
+	var findings []inaIam.LintFinding
+
+	input := &accessanalyzer.ValidatePolicyInput{
+		PolicyDocument: aws.String(policyDocument),
+		PolicyType:     aws.String(accessanalyzer.PolicyTypeIdentityPolicy),
+	}
+
+	err := accessanalyzerSvc.svc.ValidatePolicyPages(input, func(page *accessanalyzer.ValidatePolicyOutput, lastPage bool) bool {
+		for _, finding := range page.Findings {
+			severity := inaIam.LintSeverityWarning
+			switch aws.StringValue(finding.FindingType) {
+			case accessanalyzer.ValidatePolicyFindingTypeError:
+				severity = inaIam.LintSeverityError
+			case accessanalyzer.ValidatePolicyFindingTypeSuggestion:
+				severity = inaIam.LintSeveritySuggestion
+			}
+
+			pointer := ""
+			if len(finding.Locations) > 0 {
+				pointer = locationPointer(finding.Locations[0])
+			}
+
+			findings = append(findings, inaIam.LintFinding{
+				Severity: severity,
+				Code:     aws.StringValue(finding.IssueCode),
+				Message:  aws.StringValue(finding.FindingDetails),
+				Pointer:  pointer,
+			})
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ValidatePolicy: %w", err)
+	}
+
+	return findings, nil
 }
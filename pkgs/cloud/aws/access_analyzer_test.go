@@ -1,8 +1,11 @@
 package aws_test
 
 import (
-	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
 )
 
 func TestListAccessAnalyzers(t *testing.T) {
@@ -18,13 +21,46 @@ func TestListAccessAnalyzers(t *testing.T) {
 	awsSess.CreateAccessAnalyzerSvc()
 
 	t.Run("TestListFindings", func(t *testing.T) {
-		// Similar to above, start by creating a session.
+		analyzers, err := awsSess.AccessAnalyzerSvc.ListAccessAnalyzers()
+		assert.NoError(t, err)
 
-		// For this test, you need an analyzer ARN. Here, you might want to
-		// provide a placeholder or use ListAccessAnalyzers to get an ARN
-		// analyzerArn := "arn:aws:access-analyzer:us-west-2:123456789012:analyzer/example-analyzer"
-		awsSess.AccessAnalyzerSvc.ListAccessAnalyzers()
-		//awsSess.accessAnalyzerSvc.listFindings(analyzerArn)
+		for _, analyzer := range analyzers {
+			_, _, err = awsSess.AccessAnalyzerSvc.ListFindings(analyzer.Arn, awsCloud.AccessAnalyzerFindingFilter{}, "", 50)
+			assert.NoError(t, err)
+		}
 	})
+}
+
+func TestAccessAnalyzerFindingFilterCriteria(t *testing.T) {
+	criteria := awsCloud.AccessAnalyzerFindingFilter{}.Criteria()
+	assert.Equal(t, map[string]awsCloud.AccessAnalyzerCriterion{
+		"status": {Eq: []string{"ACTIVE"}},
+	}, criteria)
 
+	isPublic := true
+	criteria = awsCloud.AccessAnalyzerFindingFilter{
+		Statuses:      []string{"ARCHIVED", "RESOLVED"},
+		ResourceTypes: []string{"AWS::S3::Bucket"},
+		Resource:      "backup",
+		IsPublic:      &isPublic,
+	}.Criteria()
+	assert.Equal(t, map[string]awsCloud.AccessAnalyzerCriterion{
+		"status":       {Eq: []string{"ARCHIVED", "RESOLVED"}},
+		"resourceType": {Eq: []string{"AWS::S3::Bucket"}},
+		"resource":     {Contains: []string{"backup"}},
+		"isPublic":     {Eq: []string{"true"}},
+	}, criteria)
+}
+
+func TestAccessAnalyzerInvalidArguments(t *testing.T) {
+	accessAnalyzerSvc := &awsCloud.AccessAnalyzerSvc{}
+
+	_, err := accessAnalyzerSvc.CreateAnalyzer("analyzer", "REGION", nil, nil)
+	assert.ErrorContains(t, err, awsCloud.ErrAccessAnalyzerInvalidType)
+
+	_, err = accessAnalyzerSvc.CreateArchiveRule("analyzer", awsCloud.ArchiveRule{
+		Name:   "rule",
+		Filter: map[string]awsCloud.AccessAnalyzerCriterion{"resourceType": {}},
+	})
+	assert.ErrorContains(t, err, awsCloud.ErrAccessAnalyzerEmptyCriterion)
 }
@@ -1,10 +1,12 @@
 package aws
 
 import (
+	"errors"
 	"fmt"
 	"gitea/pcp-inariam/inariam/pkgs/log"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -41,3 +43,9 @@ func OpenSession(creds *Credentials) (*Session, error) {
 		ClientSession: sess,
 	}, nil
 }
+
+// isAwsErrorCode reports whether err is an AWS error with the given code.
+func isAwsErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == code
+}
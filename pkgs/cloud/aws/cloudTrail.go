@@ -1,21 +1,203 @@
 package aws
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"gitea/pcp-inariam/inariam/pkgs/log"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudtrail"
 )
 
+// CloudTrail types of the IAM resources referenced by IAM events.
+const (
+	IamResourceTypeUser   = "AWS::IAM::User"
+	IamResourceTypeGroup  = "AWS::IAM::Group"
+	IamResourceTypeRole   = "AWS::IAM::Role"
+	IamResourceTypePolicy = "AWS::IAM::Policy"
+)
+
+const (
+	ErrCloudTrailInvalidTimeRange = "error the end of the CloudTrail events time range is before its start"
+	ErrCloudTrailInvalidMarker    = "error invalid CloudTrail events marker"
+)
+
+const (
+	// cloudTrailMaxResults is the largest page size accepted by LookupEvents.
+	cloudTrailMaxResults = 50
+	// iamEventSource is the CloudTrail event source of the IAM API calls.
+	iamEventSource = "iam.amazonaws.com"
+	// iamChangeHistoryMaxPages bounds the LookupEvents calls made to build the change history of an entity.
+	iamChangeHistoryMaxPages = 10
+)
+
 type CloudTrailSvc struct {
 	svc *cloudtrail.CloudTrail
+	// iamSvc looks up the events of the region IAM, a global service, records its events in.
+	iamSvc *cloudtrail.CloudTrail
 }
 
 func (awsSess *Session) CreateCloudTrailSvc() {
 	if awsSess.CloudTrailSvc == nil {
-		awsSess.CloudTrailSvc = &CloudTrailSvc{cloudtrail.New(awsSess.ClientSession)}
+		region := IamEventsRegion(aws.StringValue(awsSess.ClientSession.Config.Region))
+		awsSess.CloudTrailSvc = &CloudTrailSvc{
+			svc:    cloudtrail.New(awsSess.ClientSession),
+			iamSvc: cloudtrail.New(awsSess.ClientSession, aws.NewConfig().WithRegion(region)),
+		}
+	}
+}
+
+// IamEventsRegion returns the region CloudTrail records the IAM events in for the partition of region: IAM being
+// a global service, its events are only recorded in the first region of the partition.
+func IamEventsRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "cn-north-1"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "us-gov-west-1"
+	}
+
+	return "us-east-1"
+}
+
+// IamEvent is a management event of an IAM API call recorded by CloudTrail.
+type IamEvent struct {
+	ID           string
+	Name         string
+	Time         time.Time
+	Username     string
+	PrincipalArn string
+	AccessKeyID  string
+	SourceIP     string
+	UserAgent    string
+	Region       string
+	ErrorCode    string
+	ErrorMessage string
+	ReadOnly     bool
+	Resources    []IamEventResource
+}
+
+// IamEventResource is a resource referenced by an IAM event, such as AWS::IAM::Role. IAM users, groups and roles
+// are named by their name, policies by their ARN.
+type IamEventResource struct {
+	Type string
+	Name string
+}
+
+// IamEventFilter selects the IAM events to list. Zero values do not filter, except IncludeReadOnly: read-only
+// events, such as GetUser, are only listed when set. Principal is the user name CloudTrail records for the caller,
+// the IAM user name or the session name of an assumed role, and Resource the name of a referenced resource of type
+// ResourceType, such as AWS::IAM::Role, since a user, a group and a role may share a name.
+type IamEventFilter struct {
+	Principal       string
+	Resource        string
+	ResourceType    string
+	EventName       string
+	StartTime       time.Time
+	EndTime         time.Time
+	IncludeReadOnly bool
+}
+
+// LookupAttribute returns the most selective attribute of the filter, LookupEvents accepting a single one. The
+// other criteria are checked by Matches.
+func (filter IamEventFilter) LookupAttribute() *cloudtrail.LookupAttribute {
+	key, value := cloudtrail.LookupAttributeKeyEventSource, iamEventSource
+	switch {
+	case filter.EventName != "":
+		key, value = cloudtrail.LookupAttributeKeyEventName, filter.EventName
+	case filter.Resource != "":
+		key, value = cloudtrail.LookupAttributeKeyResourceName, filter.Resource
+	case filter.Principal != "":
+		key, value = cloudtrail.LookupAttributeKeyUsername, filter.Principal
+	}
+
+	return &cloudtrail.LookupAttribute{AttributeKey: aws.String(key), AttributeValue: aws.String(value)}
+}
+
+// Matches reports whether a CloudTrail event is an IAM event matching the filter.
+func (filter IamEventFilter) Matches(event *cloudtrail.Event) bool {
+	if aws.StringValue(event.EventSource) != iamEventSource {
+		return false
+	}
+	if !filter.IncludeReadOnly && aws.StringValue(event.ReadOnly) == "true" {
+		return false
+	}
+	if filter.EventName != "" && aws.StringValue(event.EventName) != filter.EventName {
+		return false
+	}
+	if filter.Principal != "" && aws.StringValue(event.Username) != filter.Principal {
+		return false
+	}
+	if filter.Resource != "" || filter.ResourceType != "" {
+		for _, resource := range event.Resources {
+			if (filter.Resource == "" || aws.StringValue(resource.ResourceName) == filter.Resource) &&
+				(filter.ResourceType == "" || aws.StringValue(resource.ResourceType) == filter.ResourceType) {
+				return true
+			}
+		}
+		return false
 	}
 
+	return true
+}
+
+// cloudTrailRecord is the part of the CloudTrail record of an event not exposed by the LookupEvents fields.
+type cloudTrailRecord struct {
+	UserIdentity struct {
+		Arn string `json:"arn"`
+	} `json:"userIdentity"`
+	SourceIPAddress string `json:"sourceIPAddress"`
+	UserAgent       string `json:"userAgent"`
+	AwsRegion       string `json:"awsRegion"`
+	ErrorCode       string `json:"errorCode"`
+	ErrorMessage    string `json:"errorMessage"`
+}
+
+// ToIamEvent converts a CloudTrail event, completed by its CloudTrail record when it can be parsed.
+func ToIamEvent(event *cloudtrail.Event) IamEvent {
+	iamEvent := IamEvent{
+		ID:          aws.StringValue(event.EventId),
+		Name:        aws.StringValue(event.EventName),
+		Time:        aws.TimeValue(event.EventTime),
+		Username:    aws.StringValue(event.Username),
+		AccessKeyID: aws.StringValue(event.AccessKeyId),
+		ReadOnly:    aws.StringValue(event.ReadOnly) == "true",
+		Resources:   make([]IamEventResource, 0, len(event.Resources)),
+	}
+	for _, resource := range event.Resources {
+		iamEvent.Resources = append(iamEvent.Resources, IamEventResource{
+			Type: aws.StringValue(resource.ResourceType),
+			Name: aws.StringValue(resource.ResourceName),
+		})
+	}
+
+	record := cloudTrailRecord{}
+	if err := json.Unmarshal([]byte(aws.StringValue(event.CloudTrailEvent)), &record); err == nil {
+		iamEvent.PrincipalArn = record.UserIdentity.Arn
+		iamEvent.SourceIP = record.SourceIPAddress
+		iamEvent.UserAgent = record.UserAgent
+		iamEvent.Region = record.AwsRegion
+		iamEvent.ErrorCode = record.ErrorCode
+		iamEvent.ErrorMessage = record.ErrorMessage
+	}
+
+	return iamEvent
+}
+
+// wrapCloudTrailError adds the Inariam error matching the common CloudTrail failures to err.
+func wrapCloudTrailError(funcName string, err error) error {
+	switch {
+	case isAwsErrorCode(err, cloudtrail.ErrCodeInvalidTimeRangeException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrCloudTrailInvalidTimeRange, err)
+	case isAwsErrorCode(err, cloudtrail.ErrCodeInvalidNextTokenException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrCloudTrailInvalidMarker, err)
+	}
+
+	return fmt.Errorf("%s: %w", funcName, err)
 }
 
 func (CloudTrailSvc *CloudTrailSvc) DescribeTrails() {
@@ -34,25 +216,70 @@ func (CloudTrailSvc *CloudTrailSvc) DescribeTrails() {
 	}
 }
 
-func (CloudTrailSvc *CloudTrailSvc) LookupEvents() {
+// LookupIamEvents returns the IAM events matching the filter among at most pageSize events, starting at marker, an
+// empty marker starting at the latest event. Events are listed from the latest, CloudTrail keeping them 90 days.
+// It returns the marker of the next page, empty on the last page; a page may be empty while the marker is not.
+func (CloudTrailSvc *CloudTrailSvc) LookupIamEvents(filter IamEventFilter, marker string, pageSize int64,
+) ([]IamEvent, string, error) {
+	if !filter.StartTime.IsZero() && !filter.EndTime.IsZero() && filter.EndTime.Before(filter.StartTime) {
+		return nil, "", fmt.Errorf("LookupIamEvents: %w", errors.New(ErrCloudTrailInvalidTimeRange))
+	}
+	if pageSize > cloudTrailMaxResults {
+		pageSize = cloudTrailMaxResults
+	}
 
 	input := &cloudtrail.LookupEventsInput{
-		LookupAttributes: []*cloudtrail.LookupAttribute{
-			{
-				AttributeKey:   aws.String("EventName"),
-				AttributeValue: aws.String("DescribeInstances"),
-			},
-		},
+		LookupAttributes: []*cloudtrail.LookupAttribute{filter.LookupAttribute()},
+		MaxResults:       aws.Int64(pageSize),
+	}
+	if !filter.StartTime.IsZero() {
+		input.StartTime = aws.Time(filter.StartTime)
+	}
+	if !filter.EndTime.IsZero() {
+		input.EndTime = aws.Time(filter.EndTime)
+	}
+	if marker != "" {
+		input.NextToken = aws.String(marker)
 	}
 
-	resp, err := CloudTrailSvc.svc.LookupEvents(input)
+	output, err := CloudTrailSvc.iamSvc.LookupEvents(input)
 	if err != nil {
-		log.Logger.Fatalf("Failed to lookup events: %s", err)
+		return nil, "", wrapCloudTrailError("LookupIamEvents", err)
 	}
 
-	for _, event := range resp.Events {
-		log.Logger.Infoln("Event ID:", *event.EventId)
-		log.Logger.Infoln("Event Name:", *event.EventName)
-		// ... add more fields as needed
+	events := make([]IamEvent, 0, len(output.Events))
+	for _, event := range output.Events {
+		if filter.Matches(event) {
+			events = append(events, ToIamEvent(event))
+		}
 	}
+
+	return events, aws.StringValue(output.NextToken), nil
+}
+
+// IamChangeHistory returns the latest changes, at most limit, made to an IAM resource of a type such as
+// AWS::IAM::Role: the name of a user, group or role, or the ARN of a policy. Failed calls are kept, they show
+// attempted changes.
+func (CloudTrailSvc *CloudTrailSvc) IamChangeHistory(resourceType, resource string, limit int) ([]IamEvent, error) {
+	filter := IamEventFilter{Resource: resource, ResourceType: resourceType}
+	history := []IamEvent{}
+
+	marker := ""
+	for page := 0; page < iamChangeHistoryMaxPages && len(history) < limit; page++ {
+		events, nextMarker, err := CloudTrailSvc.LookupIamEvents(filter, marker, cloudTrailMaxResults)
+		if err != nil {
+			return nil, fmt.Errorf("IamChangeHistory: %w", err)
+		}
+
+		history = append(history, events...)
+		if nextMarker == "" {
+			break
+		}
+		marker = nextMarker
+	}
+	if len(history) > limit {
+		history = history[:limit]
+	}
+
+	return history, nil
 }
@@ -0,0 +1,155 @@
+package aws_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/stretchr/testify/assert"
+
+	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+func TestIamEventsRegion(t *testing.T) {
+	assert.Equal(t, "us-east-1", awsCloud.IamEventsRegion("eu-west-3"))
+	assert.Equal(t, "us-east-1", awsCloud.IamEventsRegion(""))
+	assert.Equal(t, "cn-north-1", awsCloud.IamEventsRegion("cn-northwest-1"))
+	assert.Equal(t, "us-gov-west-1", awsCloud.IamEventsRegion("us-gov-east-1"))
+}
+
+func TestIamEventFilterLookupAttribute(t *testing.T) {
+	tests := []struct {
+		filter awsCloud.IamEventFilter
+		key    string
+		value  string
+	}{
+		{filter: awsCloud.IamEventFilter{}, key: "EventSource", value: "iam.amazonaws.com"},
+		{filter: awsCloud.IamEventFilter{Principal: "alice"}, key: "Username", value: "alice"},
+		{filter: awsCloud.IamEventFilter{Principal: "alice", Resource: "admin"}, key: "ResourceName", value: "admin"},
+		{
+			filter: awsCloud.IamEventFilter{Principal: "alice", Resource: "admin", EventName: "CreateRole"},
+			key:    "EventName",
+			value:  "CreateRole",
+		},
+	}
+
+	for _, test := range tests {
+		attribute := test.filter.LookupAttribute()
+		assert.Equal(t, test.key, aws.StringValue(attribute.AttributeKey))
+		assert.Equal(t, test.value, aws.StringValue(attribute.AttributeValue))
+	}
+}
+
+func TestIamEventFilterMatches(t *testing.T) {
+	event := &cloudtrail.Event{
+		EventName:   aws.String("AttachRolePolicy"),
+		EventSource: aws.String("iam.amazonaws.com"),
+		ReadOnly:    aws.String("false"),
+		Username:    aws.String("alice"),
+		Resources: []*cloudtrail.Resource{
+			{ResourceType: aws.String("AWS::IAM::Role"), ResourceName: aws.String("admin")},
+			{
+				ResourceType: aws.String("AWS::IAM::Policy"),
+				ResourceName: aws.String("arn:aws:iam::aws:policy/AdministratorAccess"),
+			},
+		},
+	}
+	readOnlyEvent := &cloudtrail.Event{
+		EventName:   aws.String("GetRole"),
+		EventSource: aws.String("iam.amazonaws.com"),
+		ReadOnly:    aws.String("true"),
+	}
+	ec2Event := &cloudtrail.Event{
+		EventName:   aws.String("RunInstances"),
+		EventSource: aws.String("ec2.amazonaws.com"),
+		ReadOnly:    aws.String("false"),
+	}
+
+	tests := []struct {
+		filter  awsCloud.IamEventFilter
+		event   *cloudtrail.Event
+		matches bool
+	}{
+		{filter: awsCloud.IamEventFilter{}, event: event, matches: true},
+		{filter: awsCloud.IamEventFilter{}, event: ec2Event, matches: false},
+		{filter: awsCloud.IamEventFilter{}, event: readOnlyEvent, matches: false},
+		{filter: awsCloud.IamEventFilter{IncludeReadOnly: true}, event: readOnlyEvent, matches: true},
+		{filter: awsCloud.IamEventFilter{EventName: "AttachRolePolicy"}, event: event, matches: true},
+		{filter: awsCloud.IamEventFilter{EventName: "PutRolePolicy"}, event: event, matches: false},
+		{filter: awsCloud.IamEventFilter{Principal: "alice"}, event: event, matches: true},
+		{filter: awsCloud.IamEventFilter{Principal: "bob"}, event: event, matches: false},
+		{
+			filter:  awsCloud.IamEventFilter{Resource: "arn:aws:iam::aws:policy/AdministratorAccess"},
+			event:   event,
+			matches: true,
+		},
+		{filter: awsCloud.IamEventFilter{Resource: "developer"}, event: event, matches: false},
+		{
+			filter:  awsCloud.IamEventFilter{Resource: "admin", ResourceType: awsCloud.IamResourceTypeRole},
+			event:   event,
+			matches: true,
+		},
+		{
+			filter:  awsCloud.IamEventFilter{Resource: "admin", ResourceType: awsCloud.IamResourceTypeUser},
+			event:   event,
+			matches: false,
+		},
+		{filter: awsCloud.IamEventFilter{ResourceType: awsCloud.IamResourceTypePolicy}, event: event, matches: true},
+		{filter: awsCloud.IamEventFilter{ResourceType: awsCloud.IamResourceTypeGroup}, event: event, matches: false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.matches, test.filter.Matches(test.event), test.filter)
+	}
+}
+
+func TestToIamEvent(t *testing.T) {
+	eventTime := time.Date(2023, 8, 1, 12, 30, 0, 0, time.UTC)
+	event := awsCloud.ToIamEvent(&cloudtrail.Event{
+		EventId:     aws.String("4c2f0a1e"),
+		EventName:   aws.String("PutRolePolicy"),
+		EventTime:   aws.Time(eventTime),
+		EventSource: aws.String("iam.amazonaws.com"),
+		ReadOnly:    aws.String("false"),
+		Username:    aws.String("alice"),
+		AccessKeyId: aws.String("AKIAEXAMPLE"),
+		Resources: []*cloudtrail.Resource{
+			{ResourceType: aws.String("AWS::IAM::Role"), ResourceName: aws.String("admin")},
+		},
+		CloudTrailEvent: aws.String(`{"userIdentity":{"type":"IAMUser","arn":"arn:aws:iam::123456789012:user/alice"},
+			"sourceIPAddress":"203.0.113.10","userAgent":"aws-cli/2.13.0","awsRegion":"us-east-1",
+			"errorCode":"AccessDenied","errorMessage":"not authorized"}`),
+	})
+
+	assert.Equal(t, awsCloud.IamEvent{
+		ID:           "4c2f0a1e",
+		Name:         "PutRolePolicy",
+		Time:         eventTime,
+		Username:     "alice",
+		PrincipalArn: "arn:aws:iam::123456789012:user/alice",
+		AccessKeyID:  "AKIAEXAMPLE",
+		SourceIP:     "203.0.113.10",
+		UserAgent:    "aws-cli/2.13.0",
+		Region:       "us-east-1",
+		ErrorCode:    "AccessDenied",
+		ErrorMessage: "not authorized",
+		Resources:    []awsCloud.IamEventResource{{Type: "AWS::IAM::Role", Name: "admin"}},
+	}, event)
+
+	event = awsCloud.ToIamEvent(&cloudtrail.Event{EventName: aws.String("CreateUser")})
+	assert.Equal(t, "CreateUser", event.Name)
+	assert.Empty(t, event.PrincipalArn)
+	assert.Empty(t, event.Resources)
+}
+
+func TestLookupIamEventsInvalidTimeRange(t *testing.T) {
+	cloudTrailSvc := &awsCloud.CloudTrailSvc{}
+	start := time.Date(2023, 8, 2, 0, 0, 0, 0, time.UTC)
+
+	_, _, err := cloudTrailSvc.LookupIamEvents(awsCloud.IamEventFilter{
+		StartTime: start,
+		EndTime:   start.Add(-time.Hour),
+	}, "", 50)
+	assert.ErrorContains(t, err, awsCloud.ErrCloudTrailInvalidTimeRange)
+}
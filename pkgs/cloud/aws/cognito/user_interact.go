@@ -179,7 +179,7 @@ func (cognitoSvc *Svc) GenerateMFAActivationCode(session, email string) (*MFASet
 
 	qrCodeString := fmt.Sprintf("otpauth://totp/Inariam:%s?secret=%s", email, *output.SecretCode)
 
-	qrCode, err := generateQRCode(qrCodeString, 256)
+	qrCode, err := GenerateQRCode(qrCodeString, 256)
 
 	if err != nil {
 		return nil, fmt.Errorf("Cognito.GenerateMFAActivationCode: %w", err)
@@ -271,19 +271,19 @@ func (cognitoSvc *Svc) CompleteMFAAuthFlow(username, code, session string) (*MFA
 	}, nil
 }
 
-// generateQRCode generate QR Code base64 png to pass to an <img> tag.
-func generateQRCode(input string, size int) (string, error) {
+// GenerateQRCode generate QR Code base64 png to pass to an <img> tag.
+func GenerateQRCode(input string, size int) (string, error) {
 
 	qrCode, err := qrcode.New(input, qrcode.Medium)
 
 	if err != nil {
-		return "", fmt.Errorf("Cognito.generateQRCode: %s, %w", ErrGeneratingQrCode, err)
+		return "", fmt.Errorf("Cognito.GenerateQRCode: %s, %w", ErrGeneratingQrCode, err)
 	}
 
 	pngBytes, err := qrCode.PNG(size)
 
 	if err != nil {
-		return "", fmt.Errorf("Cognito.generateQRCode: %s, %w", ErrGeneratingQrCodePNG, err)
+		return "", fmt.Errorf("Cognito.GenerateQRCode: %s, %w", ErrGeneratingQrCodePNG, err)
 	}
 
 	encodedQr := base64.StdEncoding.EncodeToString(pngBytes)
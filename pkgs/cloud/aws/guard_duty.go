@@ -1,10 +1,36 @@
 package aws
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/guardduty"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrGuardDutyNoDetector      = "error no GuardDuty detector is enabled"
+	ErrGuardDutyInvalidMarker   = "error invalid GuardDuty findings marker"
+	ErrGuardDutyDetectorMissing = "error GuardDuty detector does not exist"
+)
+
+// Severity labels of the GuardDuty findings, from the severity score ranges documented by AWS.
+const (
+	GuardDutySeverityLow      = "LOW"
+	GuardDutySeverityMedium   = "MEDIUM"
+	GuardDutySeverityHigh     = "HIGH"
+	GuardDutySeverityCritical = "CRITICAL"
+)
+
+const (
+	// guardDutyMaxFindingIds is the largest number of finding IDs accepted by a single GuardDuty call.
+	guardDutyMaxFindingIds = 50
+	// guardDutyMarkerSeparator separates the detector ID from the GuardDuty page token in a findings marker.
+	guardDutyMarkerSeparator = ":"
 )
 
 type GuardDutySvc struct {
@@ -17,50 +43,286 @@ func (awsSess *Session) CreateGuarddutySvc() {
 	}
 }
 
-func (GuarddutySvc *GuardDutySvc) ListFindings() {
+// GuardDutyFinding is a GuardDuty finding. Severity is a score between 1 and 10, summarized by SeverityLabel.
+type GuardDutyFinding struct {
+	ID            string
+	DetectorID    string
+	Arn           string
+	AccountID     string
+	Region        string
+	Type          string
+	Title         string
+	Description   string
+	Severity      float64
+	SeverityLabel string
+	ResourceType  string
+	Count         int64
+	Archived      bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// GuardDutyFindingFilter selects the GuardDuty findings to list. Zero values do not filter, except Archived:
+// archived findings are only listed when set.
+type GuardDutyFindingFilter struct {
+	MinSeverity   int64
+	MaxSeverity   int64
+	Types         []string
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	Archived      bool
+}
+
+// Criteria returns the GuardDuty finding criteria matching the filter.
+func (filter GuardDutyFindingFilter) Criteria() *guardduty.FindingCriteria {
+	criterion := map[string]*guardduty.Condition{
+		"service.archived": {Equals: aws.StringSlice([]string{fmt.Sprint(filter.Archived)})},
+	}
+
+	if filter.MinSeverity > 0 || filter.MaxSeverity > 0 {
+		severity := &guardduty.Condition{}
+		if filter.MinSeverity > 0 {
+			severity.GreaterThanOrEqual = aws.Int64(filter.MinSeverity)
+		}
+		if filter.MaxSeverity > 0 {
+			severity.LessThan = aws.Int64(filter.MaxSeverity)
+		}
+		criterion["severity"] = severity
+	}
+
+	if len(filter.Types) > 0 {
+		criterion["type"] = &guardduty.Condition{Equals: aws.StringSlice(filter.Types)}
+	}
+
+	if !filter.UpdatedAfter.IsZero() || !filter.UpdatedBefore.IsZero() {
+		updatedAt := &guardduty.Condition{}
+		if !filter.UpdatedAfter.IsZero() {
+			updatedAt.GreaterThanOrEqual = aws.Int64(filter.UpdatedAfter.UnixMilli())
+		}
+		if !filter.UpdatedBefore.IsZero() {
+			updatedAt.LessThan = aws.Int64(filter.UpdatedBefore.UnixMilli())
+		}
+		criterion["updatedAt"] = updatedAt
+	}
+
+	return &guardduty.FindingCriteria{Criterion: criterion}
+}
+
+// GuardDutySeverityLabel returns the label of a GuardDuty severity score.
+func GuardDutySeverityLabel(severity float64) string {
+	switch {
+	case severity >= 9:
+		return GuardDutySeverityCritical
+	case severity >= 7:
+		return GuardDutySeverityHigh
+	case severity >= 4:
+		return GuardDutySeverityMedium
+	}
+
+	return GuardDutySeverityLow
+}
+
+// chunkFindingIds splits finding IDs in groups small enough for a single GuardDuty call.
+func chunkFindingIds(findingIds []string) [][]string {
+	var chunks [][]string
+	for len(findingIds) > guardDutyMaxFindingIds {
+		chunks = append(chunks, findingIds[:guardDutyMaxFindingIds])
+		findingIds = findingIds[guardDutyMaxFindingIds:]
+	}
+	if len(findingIds) > 0 {
+		chunks = append(chunks, findingIds)
+	}
+
+	return chunks
+}
+
+func parseGuardDutyTime(value *string) time.Time {
+	parsed, _ := time.Parse(time.RFC3339Nano, aws.StringValue(value))
+	return parsed
+}
+
+func toGuardDutyFinding(detectorID string, finding *guardduty.Finding) GuardDutyFinding {
+	guardDutyFinding := GuardDutyFinding{
+		ID:            aws.StringValue(finding.Id),
+		DetectorID:    detectorID,
+		Arn:           aws.StringValue(finding.Arn),
+		AccountID:     aws.StringValue(finding.AccountId),
+		Region:        aws.StringValue(finding.Region),
+		Type:          aws.StringValue(finding.Type),
+		Title:         aws.StringValue(finding.Title),
+		Description:   aws.StringValue(finding.Description),
+		Severity:      aws.Float64Value(finding.Severity),
+		SeverityLabel: GuardDutySeverityLabel(aws.Float64Value(finding.Severity)),
+		CreatedAt:     parseGuardDutyTime(finding.CreatedAt),
+		UpdatedAt:     parseGuardDutyTime(finding.UpdatedAt),
+	}
+	if finding.Resource != nil {
+		guardDutyFinding.ResourceType = aws.StringValue(finding.Resource.ResourceType)
+	}
+	if finding.Service != nil {
+		guardDutyFinding.Count = aws.Int64Value(finding.Service.Count)
+		guardDutyFinding.Archived = aws.BoolValue(finding.Service.Archived)
+	}
+
+	return guardDutyFinding
+}
+
+// wrapGuardDutyError adds the Inariam error matching the common GuardDuty failures to err.
+func wrapGuardDutyError(funcName string, err error) error {
+	if isAwsErrorCode(err, guardduty.ErrCodeBadRequestException) &&
+		strings.Contains(strings.ToLower(err.Error()), "detector") {
+		return fmt.Errorf("%s: %s %w", funcName, ErrGuardDutyDetectorMissing, err)
+	}
+
+	return fmt.Errorf("%s: %w", funcName, err)
+}
+
+// ListDetectors lists the IDs of the GuardDuty detectors of the region, a region has at most one.
+func (guardDutySvc *GuardDutySvc) ListDetectors() ([]string, error) {
+	var detectorIds []string
+
+	err := guardDutySvc.svc.ListDetectorsPages(&guardduty.ListDetectorsInput{},
+		func(page *guardduty.ListDetectorsOutput, lastPage bool) bool {
+			detectorIds = append(detectorIds, aws.StringValueSlice(page.DetectorIds)...)
+			return !lastPage
+		})
+	if err != nil {
+		return nil, wrapGuardDutyError("ListDetectors", err)
+	}
+
+	return detectorIds, nil
+}
+
+// OrderGuardDutyFindings returns the findings in the order of findingIds, GuardDuty not keeping the requested order.
+// Findings of IDs missing from findingIds are left out, and so are IDs without a finding.
+func OrderGuardDutyFindings(findingIds []string, findings []GuardDutyFinding) []GuardDutyFinding {
+	findingsByID := make(map[string]GuardDutyFinding, len(findings))
+	for _, finding := range findings {
+		findingsByID[finding.ID] = finding
+	}
+
+	ordered := make([]GuardDutyFinding, 0, len(findings))
+	for _, findingID := range findingIds {
+		if finding, ok := findingsByID[findingID]; ok {
+			ordered = append(ordered, finding)
+		}
+	}
+
+	return ordered
+}
+
+// GetFindings returns the details of findings of a detector in the order of findingIds, fetched in batches of at
+// most 50 findings. IDs GuardDuty does not know are left out.
+func (guardDutySvc *GuardDutySvc) GetFindings(detectorID string, findingIds []string) ([]GuardDutyFinding, error) {
+	findings := make([]GuardDutyFinding, 0, len(findingIds))
 
-	// Initialize a session using Amazon SDK with a region closest to you
+	for _, chunk := range chunkFindingIds(findingIds) {
+		output, err := guardDutySvc.svc.GetFindings(&guardduty.GetFindingsInput{
+			DetectorId: aws.String(detectorID),
+			FindingIds: aws.StringSlice(chunk),
+		})
+		if err != nil {
+			return nil, wrapGuardDutyError("GetFindings", err)
+		}
 
-	// Create a new instance of the GuardDuty service's client with a Session.
+		for _, finding := range output.Findings {
+			findings = append(findings, toGuardDutyFinding(detectorID, finding))
+		}
+	}
 
-	// Get the DetectorId (needed for other GuardDuty operations)
-	detectorResp, err := GuarddutySvc.svc.ListDetectors(&guardduty.ListDetectorsInput{})
+	return OrderGuardDutyFindings(findingIds, findings), nil
+}
+
+// ListFindings returns at most pageSize findings matching the filter, starting at marker, an empty marker
+// starting at the first finding of the first detector. The findings of every detector are listed one detector
+// after the other. It returns the marker of the next page, empty on the last page.
+func (guardDutySvc *GuardDutySvc) ListFindings(filter GuardDutyFindingFilter, marker string, pageSize int64,
+) ([]GuardDutyFinding, string, error) {
+	detectorIds, err := guardDutySvc.ListDetectors()
 	if err != nil {
-		log.Fatalf("Failed to list detectors: %s", err)
+		return nil, "", fmt.Errorf("ListFindings: %w", err)
 	}
-	if len(detectorResp.DetectorIds) == 0 {
-		log.Fatalf("No GuardDuty detectors found")
+	if len(detectorIds) == 0 {
+		return nil, "", fmt.Errorf("ListFindings: %w", errors.New(ErrGuardDutyNoDetector))
 	}
-	detectorID := detectorResp.DetectorIds[0] // Taking the first detector
 
-	fmt.Printf("Listing findings for detector %s", *detectorID)
+	detectorIndex, pageToken := 0, ""
+	if marker != "" {
+		detectorID, token, found := strings.Cut(marker, guardDutyMarkerSeparator)
+		detectorIndex = -1
+		for i := range detectorIds {
+			if detectorIds[i] == detectorID {
+				detectorIndex = i
+			}
+		}
+		if !found || detectorIndex < 0 {
+			return nil, "", fmt.Errorf("ListFindings: %w", errors.New(ErrGuardDutyInvalidMarker))
+		}
+		pageToken = token
+	}
+	if pageSize > guardDutyMaxFindingIds {
+		pageSize = guardDutyMaxFindingIds
+	}
 
-	// List findings
-	findingsResp, err := GuarddutySvc.svc.ListFindings(&guardduty.ListFindingsInput{
-		DetectorId: detectorID,
-	})
+	detectorID := detectorIds[detectorIndex]
+	input := &guardduty.ListFindingsInput{
+		DetectorId:      aws.String(detectorID),
+		FindingCriteria: filter.Criteria(),
+		MaxResults:      aws.Int64(pageSize),
+	}
+	if pageToken != "" {
+		input.NextToken = aws.String(pageToken)
+	}
+
+	output, err := guardDutySvc.svc.ListFindings(input)
 	if err != nil {
-		log.Fatalf("Failed to list findings: %s", err)
+		return nil, "", wrapGuardDutyError("ListFindings", err)
 	}
 
-	// Get details for each finding
-	for _, findingId := range findingsResp.FindingIds {
-		getFindingsResp, err := GuarddutySvc.svc.GetFindings(&guardduty.GetFindingsInput{
-			DetectorId: detectorID,
-			FindingIds: []*string{findingId},
+	findings, err := guardDutySvc.GetFindings(detectorID, aws.StringValueSlice(output.FindingIds))
+	if err != nil {
+		return nil, "", fmt.Errorf("ListFindings: %w", err)
+	}
+
+	nextMarker := ""
+	switch {
+	case aws.StringValue(output.NextToken) != "":
+		nextMarker = detectorID + guardDutyMarkerSeparator + aws.StringValue(output.NextToken)
+	case detectorIndex+1 < len(detectorIds):
+		nextMarker = detectorIds[detectorIndex+1] + guardDutyMarkerSeparator
+	}
+
+	return findings, nextMarker, nil
+}
+
+// ArchiveFindings archives findings of a detector, which are then hidden from the default findings list.
+func (guardDutySvc *GuardDutySvc) ArchiveFindings(detectorID string, findingIds []string) error {
+	for _, chunk := range chunkFindingIds(findingIds) {
+		_, err := guardDutySvc.svc.ArchiveFindings(&guardduty.ArchiveFindingsInput{
+			DetectorId: aws.String(detectorID),
+			FindingIds: aws.StringSlice(chunk),
 		})
 		if err != nil {
-			log.Printf("Failed to get finding details for %s: %s", *findingId, err)
-			continue
+			return wrapGuardDutyError("ArchiveFindings", err)
 		}
+	}
 
-		fmt.Printf("%v", getFindingsResp.Findings)
+	log.Logger.Infof("%d GuardDuty findings of detector '%s' archived successfully\n", len(findingIds), detectorID)
+	return nil
+}
 
-		for _, finding := range getFindingsResp.Findings {
-			fmt.Println("Title:", *finding.Title)
-			fmt.Println("Description:", *finding.Description)
-			// Add more fields as required
-			fmt.Println("---------------------")
+// UnarchiveFindings restores archived findings of a detector.
+func (guardDutySvc *GuardDutySvc) UnarchiveFindings(detectorID string, findingIds []string) error {
+	for _, chunk := range chunkFindingIds(findingIds) {
+		_, err := guardDutySvc.svc.UnarchiveFindings(&guardduty.UnarchiveFindingsInput{
+			DetectorId: aws.String(detectorID),
+			FindingIds: aws.StringSlice(chunk),
+		})
+		if err != nil {
+			return wrapGuardDutyError("UnarchiveFindings", err)
 		}
 	}
+
+	log.Logger.Infof("%d GuardDuty findings of detector '%s' unarchived successfully\n", len(findingIds), detectorID)
+	return nil
 }
@@ -1,16 +1,21 @@
 package aws_test
 
 import (
-	"gitea/pcp-inariam/inariam/pkgs/cloud/aws"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+
+	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
 )
 
 func TestGuardDuty(t *testing.T) {
 	// Initialize AWS session
 
-	credentials := aws.GetTestCredentials()
+	credentials := awsCloud.GetTestCredentials()
 
-	awsSess, err := aws.OpenSession(&credentials)
+	awsSess, err := awsCloud.OpenSession(&credentials)
 	if err != nil {
 		panic("guardduty_test failed, error opening session")
 	}
@@ -18,5 +23,78 @@ func TestGuardDuty(t *testing.T) {
 	awsSess.CreateGuarddutySvc()
 
 	// Ensure there's a detector before testing, or you can create one if none exists.
-	awsSess.GuarddutySvc.ListFindings()
+	findings, _, err := awsSess.GuarddutySvc.ListFindings(awsCloud.GuardDutyFindingFilter{}, "", 50)
+	assert.NoError(t, err)
+
+	for _, finding := range findings {
+		t.Log(finding.Title, finding.SeverityLabel)
+	}
+}
+
+func TestGuardDutySeverityLabel(t *testing.T) {
+	tests := []struct {
+		severity float64
+		label    string
+	}{
+		{severity: 1, label: awsCloud.GuardDutySeverityLow},
+		{severity: 3.9, label: awsCloud.GuardDutySeverityLow},
+		{severity: 4, label: awsCloud.GuardDutySeverityMedium},
+		{severity: 6.9, label: awsCloud.GuardDutySeverityMedium},
+		{severity: 7, label: awsCloud.GuardDutySeverityHigh},
+		{severity: 8.9, label: awsCloud.GuardDutySeverityHigh},
+		{severity: 9, label: awsCloud.GuardDutySeverityCritical},
+		{severity: 10, label: awsCloud.GuardDutySeverityCritical},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.label, awsCloud.GuardDutySeverityLabel(test.severity), test.severity)
+	}
+}
+
+func TestGuardDutyFindingFilterCriteria(t *testing.T) {
+	criterion := awsCloud.GuardDutyFindingFilter{}.Criteria().Criterion
+	assert.Len(t, criterion, 1)
+	assert.Equal(t, []string{"false"}, aws.StringValueSlice(criterion["service.archived"].Equals))
+
+	after := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	criterion = awsCloud.GuardDutyFindingFilter{
+		MinSeverity:  7,
+		Types:        []string{"Recon:EC2/PortProbeUnprotectedPort"},
+		UpdatedAfter: after,
+		Archived:     true,
+	}.Criteria().Criterion
+	assert.Len(t, criterion, 4)
+	assert.Equal(t, []string{"true"}, aws.StringValueSlice(criterion["service.archived"].Equals))
+	assert.Equal(t, int64(7), aws.Int64Value(criterion["severity"].GreaterThanOrEqual))
+	assert.Nil(t, criterion["severity"].LessThan)
+	assert.Equal(t, []string{"Recon:EC2/PortProbeUnprotectedPort"}, aws.StringValueSlice(criterion["type"].Equals))
+	assert.Equal(t, after.UnixMilli(), aws.Int64Value(criterion["updatedAt"].GreaterThanOrEqual))
+	assert.Nil(t, criterion["updatedAt"].LessThan)
+}
+
+func TestOrderGuardDutyFindings(t *testing.T) {
+	findings := []awsCloud.GuardDutyFinding{{ID: "c"}, {ID: "a"}, {ID: "b"}}
+
+	tests := []struct {
+		name       string
+		findingIds []string
+		want       []string
+	}{
+		{name: "requested order", findingIds: []string{"a", "b", "c"}, want: []string{"a", "b", "c"}},
+		{name: "reversed order", findingIds: []string{"c", "b", "a"}, want: []string{"c", "b", "a"}},
+		{name: "unknown IDs are left out", findingIds: []string{"b", "missing", "a"}, want: []string{"b", "a"}},
+		{name: "no ID", want: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ordered := awsCloud.OrderGuardDutyFindings(test.findingIds, findings)
+
+			ids := make([]string, 0, len(ordered))
+			for _, finding := range ordered {
+				ids = append(ids, finding.ID)
+			}
+			assert.Equal(t, test.want, ids)
+		})
+	}
 }
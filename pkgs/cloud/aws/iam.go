@@ -3,11 +3,17 @@ package aws
 import (
 	inaIam "gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/iam"
 )
 
 func (awsSess *Session) OpenIamService() {
 	if awsSess.IamSvc == nil {
-		awsSess.IamSvc = inaIam.New(iam.New(awsSess.ClientSession))
+		partition := awsSess.Identity.Partition
+		if partition == "" {
+			partition = inaIam.PartitionForRegion(aws.StringValue(awsSess.ClientSession.Config.Region))
+		}
+
+		awsSess.IamSvc = inaIam.New(iam.New(awsSess.ClientSession), awsSess.Identity.AccountID, partition)
 	}
 }
@@ -0,0 +1,302 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamAccessKeyLimit          = "error IAM user already has the maximum of 2 access keys, delete one before creating another"
+	ErrIamAccessKeyNotExists      = "error IAM access key does not exist"
+	ErrIamAccessKeyNotActive      = "error IAM access key is not active"
+	ErrIamAccessKeyStillActive    = "error IAM access key is still active, confirm the rotation first"
+	ErrIamAccessKeyRotationFailed = "error IAM access key rotation has not been confirmed"
+	ErrIamAccessKeyGracePeriod    = "error IAM access key grace period has not elapsed"
+	ErrIamAccessKeyInvalidStatus  = "error IAM access key status must be Active or Inactive"
+	ErrIamAccessKeySameKey        = "error IAM access key rotation requires a new key distinct from the old one"
+	ErrIamAccessKeyNotNewer       = "error IAM access key rotation requires a new key created after the old one"
+)
+
+const (
+	// MaxAccessKeysPerUser is the number of access keys AWS allows per IAM user.
+	MaxAccessKeysPerUser = 2
+	// DefaultAccessKeyGracePeriod is how long a deactivated key is kept before it may be deleted during a rotation.
+	DefaultAccessKeyGracePeriod = 7 * 24 * time.Hour
+
+	// accessKeyRotationTagPrefix prefixes the user tag that records when a rotated-out key may be deleted.
+	accessKeyRotationTagPrefix = ReservedTagPrefix + "rotation:"
+)
+
+// AccessKey represents the metadata of an IAM access key together with its last-used information.
+type AccessKey struct {
+	AccessKeyId     string
+	Status          string
+	CreateDate      time.Time
+	LastUsedDate    *time.Time
+	LastUsedService string
+	LastUsedRegion  string
+}
+
+// Age returns how long ago the access key was created.
+func (accessKey *AccessKey) Age() time.Duration {
+	return time.Since(accessKey.CreateDate)
+}
+
+// ListAccessKeys lists the access keys of an IAM user along with their last-used information.
+func (IamSvc *Svc) ListAccessKeys(username string) ([]AccessKey, error) {
+	var metadata []*iam.AccessKeyMetadata
+
+	err := IamSvc.svc.ListAccessKeysPages(&iam.ListAccessKeysInput{UserName: aws.String(username)},
+		func(page *iam.ListAccessKeysOutput, lastPage bool) bool {
+			metadata = append(metadata, page.AccessKeyMetadata...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListAccessKeys: %s %w", ErrIamRUserNotExists, err)
+		}
+		return nil, fmt.Errorf("ListAccessKeys: %w", err)
+	}
+
+	accessKeys := make([]AccessKey, 0, len(metadata))
+	for _, keyMetadata := range metadata {
+		accessKey := AccessKey{
+			AccessKeyId: aws.StringValue(keyMetadata.AccessKeyId),
+			Status:      aws.StringValue(keyMetadata.Status),
+			CreateDate:  aws.TimeValue(keyMetadata.CreateDate),
+		}
+
+		lastUsed, err := IamSvc.svc.GetAccessKeyLastUsed(&iam.GetAccessKeyLastUsedInput{
+			AccessKeyId: keyMetadata.AccessKeyId,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListAccessKeys: %w", err)
+		}
+
+		if lastUsed.AccessKeyLastUsed != nil {
+			accessKey.LastUsedDate = lastUsed.AccessKeyLastUsed.LastUsedDate
+			accessKey.LastUsedService = aws.StringValue(lastUsed.AccessKeyLastUsed.ServiceName)
+			accessKey.LastUsedRegion = aws.StringValue(lastUsed.AccessKeyLastUsed.Region)
+		}
+
+		accessKeys = append(accessKeys, accessKey)
+	}
+
+	return accessKeys, nil
+}
+
+// getAccessKey returns the access key of an IAM user with the given ID.
+func (IamSvc *Svc) getAccessKey(username string, accessKeyId string) (*AccessKey, error) {
+	accessKeys, err := IamSvc.ListAccessKeys(username)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, accessKey := range accessKeys {
+		if accessKey.AccessKeyId == accessKeyId {
+			return &accessKey, nil
+		}
+	}
+
+	return nil, errors.New(ErrIamAccessKeyNotExists)
+}
+
+// CreateAccessKey creates an access key for an IAM user.
+// The returned key holds the secret access key, which AWS only discloses at creation time.
+func (IamSvc *Svc) CreateAccessKey(username string) (*iam.AccessKey, error) {
+	accessKeys, err := IamSvc.ListAccessKeys(username)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAccessKey: %w", err)
+	}
+
+	if len(accessKeys) >= MaxAccessKeysPerUser {
+		return nil, fmt.Errorf("CreateAccessKey: %s", ErrIamAccessKeyLimit)
+	}
+
+	output, err := IamSvc.svc.CreateAccessKey(&iam.CreateAccessKeyInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CreateAccessKey: %w", err)
+	}
+
+	log.Logger.Infof("IAM access key '%s' created for user '%s' successfully\n", *output.AccessKey.AccessKeyId, username)
+	return output.AccessKey, nil
+}
+
+// UpdateAccessKeyStatus activates or deactivates an access key of an IAM user.
+func (IamSvc *Svc) UpdateAccessKeyStatus(username string, accessKeyId string, status string) error {
+	if status != iam.StatusTypeActive && status != iam.StatusTypeInactive {
+		return fmt.Errorf("UpdateAccessKeyStatus: %s", ErrIamAccessKeyInvalidStatus)
+	}
+
+	_, err := IamSvc.svc.UpdateAccessKey(&iam.UpdateAccessKeyInput{
+		UserName:    aws.String(username),
+		AccessKeyId: aws.String(accessKeyId),
+		Status:      aws.String(status),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("UpdateAccessKeyStatus: %s %w", ErrIamAccessKeyNotExists, err)
+		}
+		return fmt.Errorf("UpdateAccessKeyStatus: %w", err)
+	}
+
+	log.Logger.Infof("IAM access key '%s' of user '%s' set to %s\n", accessKeyId, username, status)
+	return nil
+}
+
+// DeleteAccessKey deletes an access key of an IAM user.
+func (IamSvc *Svc) DeleteAccessKey(username string, accessKeyId string) error {
+	_, err := IamSvc.svc.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+		UserName:    aws.String(username),
+		AccessKeyId: aws.String(accessKeyId),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteAccessKey: %s %w", ErrIamAccessKeyNotExists, err)
+		}
+		return fmt.Errorf("DeleteAccessKey: %w", err)
+	}
+
+	log.Logger.Infof("IAM access key '%s' of user '%s' deleted successfully\n", accessKeyId, username)
+	return nil
+}
+
+// StartAccessKeyRotation is the first step of a rotation: it creates the replacement for an active access key.
+// The caller must switch its workloads to the new key and then call ConfirmAccessKeyRotation.
+func (IamSvc *Svc) StartAccessKeyRotation(username string, oldAccessKeyId string) (*iam.AccessKey, error) {
+	oldAccessKey, err := IamSvc.getAccessKey(username, oldAccessKeyId)
+	if err != nil {
+		return nil, fmt.Errorf("StartAccessKeyRotation: %w", err)
+	}
+
+	if oldAccessKey.Status != iam.StatusTypeActive {
+		return nil, fmt.Errorf("StartAccessKeyRotation: %s", ErrIamAccessKeyNotActive)
+	}
+
+	newAccessKey, err := IamSvc.CreateAccessKey(username)
+	if err != nil {
+		return nil, fmt.Errorf("StartAccessKeyRotation: %w", err)
+	}
+
+	return newAccessKey, nil
+}
+
+// ConfirmAccessKeyRotation is the second step of a rotation: once the caller confirms the new key works,
+// the old key is deactivated and scheduled for deletion after the grace period.
+// It returns the time after which CompleteAccessKeyRotation may delete the old key.
+func (IamSvc *Svc) ConfirmAccessKeyRotation(username string, oldAccessKeyId string, newAccessKeyId string,
+	gracePeriod time.Duration) (time.Time, error) {
+	if newAccessKeyId == oldAccessKeyId {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %s", ErrIamAccessKeySameKey)
+	}
+
+	oldAccessKey, err := IamSvc.getAccessKey(username, oldAccessKeyId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %w", err)
+	}
+
+	newAccessKey, err := IamSvc.getAccessKey(username, newAccessKeyId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %w", err)
+	}
+
+	if newAccessKey.Status != iam.StatusTypeActive {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %s", ErrIamAccessKeyNotActive)
+	}
+
+	// The replacement is created by StartAccessKeyRotation, so a key older than the one rotated out
+	// is not a replacement and deactivating the old key could leave the user without a working key.
+	if !newAccessKey.CreateDate.After(oldAccessKey.CreateDate) {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %s", ErrIamAccessKeyNotNewer)
+	}
+
+	err = IamSvc.UpdateAccessKeyStatus(username, oldAccessKeyId, iam.StatusTypeInactive)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %w", err)
+	}
+
+	// The deletion deadline is kept as a tag on the user so that it survives server restarts.
+	deleteAfter := time.Now().UTC().Add(gracePeriod).Truncate(time.Second)
+	_, err = IamSvc.svc.TagUser(&iam.TagUserInput{
+		UserName: aws.String(username),
+		Tags: []*iam.Tag{
+			{
+				Key:   aws.String(accessKeyRotationTagPrefix + oldAccessKeyId),
+				Value: aws.String(deleteAfter.Format(time.RFC3339)),
+			},
+		},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ConfirmAccessKeyRotation: %w", err)
+	}
+
+	log.Logger.Infof("IAM access key '%s' of user '%s' deactivated, deletable after %s\n", oldAccessKeyId, username, deleteAfter)
+	return deleteAfter, nil
+}
+
+// CompleteAccessKeyRotation is the last step of a rotation: it deletes the old access key
+// once the grace period recorded by ConfirmAccessKeyRotation has elapsed.
+func (IamSvc *Svc) CompleteAccessKeyRotation(username string, oldAccessKeyId string) error {
+	oldAccessKey, err := IamSvc.getAccessKey(username, oldAccessKeyId)
+	if err != nil {
+		return fmt.Errorf("CompleteAccessKeyRotation: %w", err)
+	}
+
+	if oldAccessKey.Status == iam.StatusTypeActive {
+		return fmt.Errorf("CompleteAccessKeyRotation: %s", ErrIamAccessKeyStillActive)
+	}
+
+	tagKey := accessKeyRotationTagPrefix + oldAccessKeyId
+
+	var deleteAfter *time.Time
+	err = IamSvc.svc.ListUserTagsPages(&iam.ListUserTagsInput{UserName: aws.String(username)},
+		func(page *iam.ListUserTagsOutput, lastPage bool) bool {
+			for _, tag := range page.Tags {
+				if aws.StringValue(tag.Key) != tagKey {
+					continue
+				}
+				if parsed, err := time.Parse(time.RFC3339, aws.StringValue(tag.Value)); err == nil {
+					deleteAfter = &parsed
+				}
+				return false
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("CompleteAccessKeyRotation: %w", err)
+	}
+
+	if deleteAfter == nil {
+		return fmt.Errorf("CompleteAccessKeyRotation: %s", ErrIamAccessKeyRotationFailed)
+	}
+
+	if remaining := time.Until(*deleteAfter); remaining > 0 {
+		return fmt.Errorf("CompleteAccessKeyRotation: %s, %s remaining", ErrIamAccessKeyGracePeriod,
+			remaining.Truncate(time.Second))
+	}
+
+	err = IamSvc.DeleteAccessKey(username, oldAccessKeyId)
+	if err != nil {
+		return fmt.Errorf("CompleteAccessKeyRotation: %w", err)
+	}
+
+	_, err = IamSvc.svc.UntagUser(&iam.UntagUserInput{
+		UserName: aws.String(username),
+		TagKeys:  []*string{aws.String(tagKey)},
+	})
+	if err != nil {
+		return fmt.Errorf("CompleteAccessKeyRotation: %w", err)
+	}
+
+	return nil
+}
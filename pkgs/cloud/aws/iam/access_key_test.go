@@ -0,0 +1,16 @@
+package iam_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestConfirmAccessKeyRotationSameKey(t *testing.T) {
+	iamSvc := &iam.Svc{}
+
+	_, err := iamSvc.ConfirmAccessKeyRotation("alice", "AKIAEXAMPLE", "AKIAEXAMPLE", iam.DefaultAccessKeyGracePeriod)
+	assert.ErrorContains(t, err, iam.ErrIamAccessKeySameKey)
+}
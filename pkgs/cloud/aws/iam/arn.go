@@ -0,0 +1,84 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+)
+
+const (
+	ErrIamAccountUnknown = "error AWS account is unknown, ARNs cannot be built"
+	ErrIamInvalidName    = "error IAM resource name is empty"
+)
+
+// Partitions of AWS, each one with its own ARN prefix.
+const (
+	PartitionAws      = "aws"
+	PartitionAwsCn    = "aws-cn"
+	PartitionAwsUsGov = "aws-us-gov"
+)
+
+// PartitionForRegion returns the partition of an AWS region.
+func PartitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionAwsCn
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionAwsUsGov
+	default:
+		return PartitionAws
+	}
+}
+
+// AccountID returns the ID of the account the service works on.
+func (IamSvc *Svc) AccountID() string {
+	return IamSvc.accountID
+}
+
+// Partition returns the partition of the account the service works on.
+func (IamSvc *Svc) Partition() string {
+	return IamSvc.partition
+}
+
+// resourceArn builds the ARN of an IAM resource of the account, such as policy/name or role/path/name.
+func (IamSvc *Svc) resourceArn(resourceType string, name string) (string, error) {
+	if IamSvc.accountID == "" || IamSvc.partition == "" {
+		return "", errors.New(ErrIamAccountUnknown)
+	}
+
+	name = strings.Trim(name, "/")
+	if name == "" {
+		return "", errors.New(ErrIamInvalidName)
+	}
+
+	return arn.ARN{
+		Partition: IamSvc.partition,
+		Service:   "iam",
+		AccountID: IamSvc.accountID,
+		Resource:  resourceType + "/" + name,
+	}.String(), nil
+}
+
+// PolicyArn returns the ARN of a policy given either its full ARN, returned as is, or the name of a customer
+// managed policy of the account, optionally prefixed with its path such as team/ReadOnly.
+func (IamSvc *Svc) PolicyArn(policy string) (string, error) {
+	if arn.IsARN(policy) {
+		return policy, nil
+	}
+
+	policyArn, err := IamSvc.resourceArn("policy", policy)
+	if err != nil {
+		return "", fmt.Errorf("PolicyArn: %w", err)
+	}
+
+	return policyArn, nil
+}
+
+// isAwsManagedPolicyArn reports whether a policy ARN belongs to a policy managed by AWS, such as
+// arn:aws:iam::aws:policy/ReadOnlyAccess, rather than by an account.
+func isAwsManagedPolicyArn(policyArn string) bool {
+	parsedArn, err := arn.Parse(policyArn)
+	return err == nil && parsedArn.AccountID == "aws"
+}
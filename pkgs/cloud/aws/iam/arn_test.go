@@ -0,0 +1,46 @@
+package iam_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestPolicyArn(t *testing.T) {
+	tests := []struct {
+		name      string
+		partition string
+		policy    string
+		expected  string
+	}{
+		{"name", iam.PartitionAws, "ReadOnly", "arn:aws:iam::123456789012:policy/ReadOnly"},
+		{"name with path", iam.PartitionAws, "/team/ReadOnly", "arn:aws:iam::123456789012:policy/team/ReadOnly"},
+		{"china", iam.PartitionAwsCn, "ReadOnly", "arn:aws-cn:iam::123456789012:policy/ReadOnly"},
+		{"govcloud", iam.PartitionAwsUsGov, "ReadOnly", "arn:aws-us-gov:iam::123456789012:policy/ReadOnly"},
+		{"arn", iam.PartitionAwsCn, "arn:aws:iam::aws:policy/ReadOnlyAccess", "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policyArn, err := iam.New(nil, "123456789012", test.partition).PolicyArn(test.policy)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, policyArn)
+		})
+	}
+}
+
+func TestPolicyArnErrors(t *testing.T) {
+	_, err := iam.New(nil, "", "").PolicyArn("ReadOnly")
+	assert.ErrorContains(t, err, iam.ErrIamAccountUnknown)
+
+	_, err = iam.New(nil, "123456789012", iam.PartitionAws).PolicyArn("/")
+	assert.ErrorContains(t, err, iam.ErrIamInvalidName)
+}
+
+func TestPartitionForRegion(t *testing.T) {
+	assert.Equal(t, iam.PartitionAws, iam.PartitionForRegion("eu-west-1"))
+	assert.Equal(t, iam.PartitionAwsCn, iam.PartitionForRegion("cn-north-1"))
+	assert.Equal(t, iam.PartitionAwsUsGov, iam.PartitionForRegion("us-gov-west-1"))
+}
@@ -0,0 +1,444 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+const (
+	ErrCascadeDeleteIncomplete       = "error cascading delete did not complete, some steps failed"
+	ErrCascadeDeleteAwsManagedPolicy = "error AWS managed policies cannot be deleted"
+	ErrCascadeDeleteRequiredBoundary = "error the required role permissions boundary cannot be deleted"
+)
+
+// Types of the IAM resources a delete plan deletes.
+const (
+	ResourceTypeUser   = "user"
+	ResourceTypeGroup  = "group"
+	ResourceTypeRole   = "role"
+	ResourceTypePolicy = "policy"
+)
+
+// Statuses of the steps of an executed delete plan.
+const (
+	DeleteStepSucceeded = "succeeded"
+	DeleteStepFailed    = "failed"
+	DeleteStepSkipped   = "skipped"
+)
+
+// DeleteStep is one detach or delete call of a cascading delete. Action names the IAM operation and Target the
+// dependency it acts on. Status and Err are set once the plan is executed.
+type DeleteStep struct {
+	Action string
+	Target string
+	Status string
+	Err    error
+
+	run func() error
+}
+
+// DeletePlan lists, in execution order, the steps deleting an IAM resource along with everything depending on
+// it. The last step deletes the resource itself.
+type DeletePlan struct {
+	ResourceType string
+	Resource     string
+	Steps        []DeleteStep
+}
+
+// NewDeletePlan returns an empty delete plan for an IAM resource.
+func NewDeletePlan(resourceType string, resource string) *DeletePlan {
+	return &DeletePlan{ResourceType: resourceType, Resource: resource}
+}
+
+// AddStep appends a step to the plan.
+func (plan *DeletePlan) AddStep(action string, target string, run func() error) {
+	plan.Steps = append(plan.Steps, DeleteStep{Action: action, Target: target, run: run})
+}
+
+// Execute runs the steps of the plan in order. A failed dependency step does not stop the following ones, but
+// the resource itself is only deleted once every dependency is removed and is skipped otherwise. It returns an
+// error when a step failed, the status of each step telling which ones.
+func (plan *DeletePlan) Execute() error {
+	failed := false
+
+	last := len(plan.Steps) - 1
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		if i == last && failed {
+			step.Status = DeleteStepSkipped
+			continue
+		}
+
+		if err := step.run(); err != nil {
+			step.Status = DeleteStepFailed
+			step.Err = err
+			failed = true
+			continue
+		}
+		step.Status = DeleteStepSucceeded
+	}
+
+	if failed {
+		return fmt.Errorf("Execute: %w", errors.New(ErrCascadeDeleteIncomplete))
+	}
+
+	return nil
+}
+
+// addDetachPolicySteps appends the steps detaching managed policies.
+func (plan *DeletePlan) addDetachPolicySteps(action string, attachedPolicies []*iam.AttachedPolicy,
+	detach func(policyARN string) error,
+) {
+	for _, attachedPolicy := range attachedPolicies {
+		policyARN := aws.StringValue(attachedPolicy.PolicyArn)
+		plan.AddStep(action, policyARN, func() error {
+			return detach(policyARN)
+		})
+	}
+}
+
+// addDeleteInlinePolicySteps appends the steps deleting inline policies.
+func (plan *DeletePlan) addDeleteInlinePolicySteps(action string, policyNames []string,
+	remove func(policyName string) error,
+) {
+	for _, policyName := range policyNames {
+		policyName := policyName
+		plan.AddStep(action, policyName, func() error {
+			return remove(policyName)
+		})
+	}
+}
+
+// PlanUserDeletion computes the steps deleting an IAM user: its console access, access keys, signing
+// certificates, SSH public keys, service specific credentials, MFA devices, group memberships, managed and
+// inline policies, then the user itself.
+func (IamSvc *Svc) PlanUserDeletion(username string) (*DeletePlan, error) {
+	user, err := IamSvc.GetIamUser(username)
+	if err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", errors.New(ErrIamRUserNotExists))
+	}
+
+	plan := NewDeletePlan(ResourceTypeUser, username)
+
+	_, err = IamSvc.GetLoginProfile(username)
+	switch {
+	case err == nil:
+		plan.AddStep("DeleteLoginProfile", username, func() error {
+			return IamSvc.DeleteLoginProfile(username)
+		})
+	case !isNoSuchEntity(err):
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+
+	accessKeys, err := IamSvc.ListAccessKeys(username)
+	if err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+	for _, accessKey := range accessKeys {
+		accessKeyId := accessKey.AccessKeyId
+		plan.AddStep("DeleteAccessKey", accessKeyId, func() error {
+			return IamSvc.DeleteAccessKey(username, accessKeyId)
+		})
+	}
+
+	if err = IamSvc.addUserCredentialSteps(plan, username); err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+
+	devices, err := IamSvc.ListMFADevices(username)
+	if err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+	for _, device := range devices {
+		serialNumber := device.SerialNumber
+		plan.AddStep("DeactivateMFADevice", serialNumber, func() error {
+			return IamSvc.DeactivateMFADevice(username, serialNumber)
+		})
+		if device.Virtual {
+			plan.AddStep("DeleteVirtualMFADevice", serialNumber, func() error {
+				return IamSvc.DeleteVirtualMFADevice(serialNumber)
+			})
+		}
+	}
+
+	groups, err := IamSvc.ListGroupsForUser(username)
+	if err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+	for _, group := range groups {
+		groupName := aws.StringValue(group.GroupName)
+		plan.AddStep("RemoveUserFromGroup", groupName, func() error {
+			return IamSvc.RemoveUserFromGroup(groupName, username)
+		})
+	}
+
+	attachedPolicies, err := IamSvc.ListAttachedUserPolicies(username)
+	if err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+	plan.addDetachPolicySteps("DetachUserPolicy", attachedPolicies, func(policyARN string) error {
+		return IamSvc.DetachUserPolicy(username, policyARN)
+	})
+
+	inlinePolicyNames, err := IamSvc.ListUserInlinePolicies(username)
+	if err != nil {
+		return nil, fmt.Errorf("PlanUserDeletion: %w", err)
+	}
+	plan.addDeleteInlinePolicySteps("DeleteUserPolicy", inlinePolicyNames, func(policyName string) error {
+		return IamSvc.DeleteUserInlinePolicy(username, policyName)
+	})
+
+	plan.AddStep("DeleteUser", username, func() error {
+		return IamSvc.DeleteIamUser(username)
+	})
+
+	return plan, nil
+}
+
+// addUserCredentialSteps appends the steps deleting the signing certificates, SSH public keys and service
+// specific credentials of an IAM user.
+func (IamSvc *Svc) addUserCredentialSteps(plan *DeletePlan, username string) error {
+	var certificateIds []string
+	err := IamSvc.svc.ListSigningCertificatesPages(&iam.ListSigningCertificatesInput{UserName: aws.String(username)},
+		func(page *iam.ListSigningCertificatesOutput, lastPage bool) bool {
+			for _, certificate := range page.Certificates {
+				certificateIds = append(certificateIds, aws.StringValue(certificate.CertificateId))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("addUserCredentialSteps: %w", err)
+	}
+	for _, certificateId := range certificateIds {
+		certificateId := certificateId
+		plan.AddStep("DeleteSigningCertificate", certificateId, func() error {
+			_, err := IamSvc.svc.DeleteSigningCertificate(&iam.DeleteSigningCertificateInput{
+				UserName:      aws.String(username),
+				CertificateId: aws.String(certificateId),
+			})
+			return err
+		})
+	}
+
+	var sshPublicKeyIds []string
+	err = IamSvc.svc.ListSSHPublicKeysPages(&iam.ListSSHPublicKeysInput{UserName: aws.String(username)},
+		func(page *iam.ListSSHPublicKeysOutput, lastPage bool) bool {
+			for _, sshPublicKey := range page.SSHPublicKeys {
+				sshPublicKeyIds = append(sshPublicKeyIds, aws.StringValue(sshPublicKey.SSHPublicKeyId))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("addUserCredentialSteps: %w", err)
+	}
+	for _, sshPublicKeyId := range sshPublicKeyIds {
+		sshPublicKeyId := sshPublicKeyId
+		plan.AddStep("DeleteSSHPublicKey", sshPublicKeyId, func() error {
+			_, err := IamSvc.svc.DeleteSSHPublicKey(&iam.DeleteSSHPublicKeyInput{
+				UserName:       aws.String(username),
+				SSHPublicKeyId: aws.String(sshPublicKeyId),
+			})
+			return err
+		})
+	}
+
+	credentials, err := IamSvc.svc.ListServiceSpecificCredentials(&iam.ListServiceSpecificCredentialsInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return fmt.Errorf("addUserCredentialSteps: %w", err)
+	}
+	for _, credential := range credentials.ServiceSpecificCredentials {
+		credentialId := aws.StringValue(credential.ServiceSpecificCredentialId)
+		plan.AddStep("DeleteServiceSpecificCredential", credentialId, func() error {
+			_, err := IamSvc.svc.DeleteServiceSpecificCredential(&iam.DeleteServiceSpecificCredentialInput{
+				UserName:                    aws.String(username),
+				ServiceSpecificCredentialId: aws.String(credentialId),
+			})
+			return err
+		})
+	}
+
+	return nil
+}
+
+// PlanGroupDeletion computes the steps deleting an IAM group: its memberships, managed and inline policies,
+// then the group itself.
+func (IamSvc *Svc) PlanGroupDeletion(groupName string) (*DeletePlan, error) {
+	if _, err := IamSvc.CheckIfGroupExists(groupName); err != nil {
+		return nil, fmt.Errorf("PlanGroupDeletion: %w", err)
+	}
+
+	plan := NewDeletePlan(ResourceTypeGroup, groupName)
+
+	members, err := IamSvc.ListGroupMembers(groupName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanGroupDeletion: %w", err)
+	}
+	for _, member := range members {
+		username := aws.StringValue(member.UserName)
+		plan.AddStep("RemoveUserFromGroup", username, func() error {
+			return IamSvc.RemoveUserFromGroup(groupName, username)
+		})
+	}
+
+	attachedPolicies, err := IamSvc.ListAttachedGroupPolicies(groupName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanGroupDeletion: %w", err)
+	}
+	plan.addDetachPolicySteps("DetachGroupPolicy", attachedPolicies, func(policyARN string) error {
+		return IamSvc.DetachGroupPolicy(groupName, policyARN)
+	})
+
+	inlinePolicyNames, err := IamSvc.ListGroupInlinePolicies(groupName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanGroupDeletion: %w", err)
+	}
+	plan.addDeleteInlinePolicySteps("DeleteGroupPolicy", inlinePolicyNames, func(policyName string) error {
+		return IamSvc.DeleteGroupInlinePolicy(groupName, policyName)
+	})
+
+	plan.AddStep("DeleteGroup", groupName, func() error {
+		return IamSvc.DeleteIamGroup(groupName)
+	})
+
+	return plan, nil
+}
+
+// PlanRoleDeletion computes the steps deleting an IAM role: its instance profile memberships, managed and
+// inline policies, then the role itself.
+func (IamSvc *Svc) PlanRoleDeletion(roleName string) (*DeletePlan, error) {
+	role, err := IamSvc.GetIamRole(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanRoleDeletion: %w", err)
+	}
+	if role == nil {
+		return nil, fmt.Errorf("PlanRoleDeletion: %w", errors.New(ErrIamRoleNotExists))
+	}
+
+	plan := NewDeletePlan(ResourceTypeRole, roleName)
+
+	instanceProfiles, err := IamSvc.ListInstanceProfilesForRole(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanRoleDeletion: %w", err)
+	}
+	for _, instanceProfile := range instanceProfiles {
+		instanceProfileName := aws.StringValue(instanceProfile.InstanceProfileName)
+		plan.AddStep("RemoveRoleFromInstanceProfile", instanceProfileName, func() error {
+			return IamSvc.RemoveRoleFromInstanceProfile(instanceProfileName, roleName)
+		})
+	}
+
+	attachedPolicies, err := IamSvc.ListAttachedRolePolicies(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanRoleDeletion: %w", err)
+	}
+	plan.addDetachPolicySteps("DetachRolePolicy", attachedPolicies, func(policyARN string) error {
+		return IamSvc.DetachRolePolicy(roleName, policyARN)
+	})
+
+	inlinePolicyNames, err := IamSvc.ListRoleInlinePolicies(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("PlanRoleDeletion: %w", err)
+	}
+	plan.addDeleteInlinePolicySteps("DeleteRolePolicy", inlinePolicyNames, func(policyName string) error {
+		return IamSvc.DeleteRoleInlinePolicy(roleName, policyName)
+	})
+
+	plan.AddStep("DeleteRole", roleName, func() error {
+		return IamSvc.DeleteIAMRole(roleName)
+	})
+
+	return plan, nil
+}
+
+// PlanPolicyDeletion computes the steps deleting a customer managed IAM policy: detaching it from users, groups
+// and roles, removing it from the permissions boundaries using it, deleting its non-default versions, then the
+// policy itself. AWS managed policies are refused, and so is the required role permissions boundary, given as an
+// ARN and empty when none is configured, since removing it from roles would bypass the rule.
+func (IamSvc *Svc) PlanPolicyDeletion(policyARN string, requiredRoleBoundary string) (*DeletePlan, error) {
+	if isAwsManagedPolicyArn(policyARN) {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", errors.New(ErrCascadeDeleteAwsManagedPolicy))
+	}
+	if requiredRoleBoundary != "" && policyARN == requiredRoleBoundary {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", errors.New(ErrCascadeDeleteRequiredBoundary))
+	}
+
+	policy, err := IamSvc.GetIamPolicy(policyARN)
+	if err != nil {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", err)
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", errors.New(ErrIamPolicyNotExists))
+	}
+
+	plan := NewDeletePlan(ResourceTypePolicy, policyARN)
+
+	attached, err := IamSvc.ListPolicyEntities(policyARN, iam.PolicyUsageTypePermissionsPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", err)
+	}
+	for _, username := range attached.Users {
+		username := username
+		plan.AddStep("DetachUserPolicy", username, func() error {
+			return IamSvc.DetachUserPolicy(username, policyARN)
+		})
+	}
+	for _, groupName := range attached.Groups {
+		groupName := groupName
+		plan.AddStep("DetachGroupPolicy", groupName, func() error {
+			return IamSvc.DetachGroupPolicy(groupName, policyARN)
+		})
+	}
+	for _, roleName := range attached.Roles {
+		roleName := roleName
+		plan.AddStep("DetachRolePolicy", roleName, func() error {
+			return IamSvc.DetachRolePolicy(roleName, policyARN)
+		})
+	}
+
+	boundaries, err := IamSvc.ListPolicyEntities(policyARN, iam.PolicyUsageTypePermissionsBoundary)
+	if err != nil {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", err)
+	}
+	for _, username := range boundaries.Users {
+		username := username
+		plan.AddStep("DeleteUserPermissionsBoundary", username, func() error {
+			return IamSvc.DeleteUserPermissionsBoundary(username)
+		})
+	}
+	for _, roleName := range boundaries.Roles {
+		roleName := roleName
+		plan.AddStep("DeleteRolePermissionsBoundary", roleName, func() error {
+			return IamSvc.DeleteRolePermissionsBoundary(roleName)
+		})
+	}
+
+	versions, err := IamSvc.ListPolicyVersions(policyARN)
+	if err != nil {
+		return nil, fmt.Errorf("PlanPolicyDeletion: %w", err)
+	}
+	for _, version := range versions {
+		if version.IsDefault {
+			continue
+		}
+		versionId := version.VersionId
+		plan.AddStep("DeletePolicyVersion", versionId, func() error {
+			return IamSvc.DeletePolicyVersion(policyARN, versionId)
+		})
+	}
+
+	plan.AddStep("DeletePolicy", policyARN, func() error {
+		return IamSvc.DeleteIamPolicy(policyARN)
+	})
+
+	return plan, nil
+}
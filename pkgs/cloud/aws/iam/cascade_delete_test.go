@@ -0,0 +1,112 @@
+package iam_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestDeletePlanExecute(t *testing.T) {
+	var calls []string
+	step := func(name string, err error) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	tests := []struct {
+		name       string
+		failing    string
+		wantCalls  []string
+		wantStatus []string
+	}{
+		{
+			name:      "every step succeeds",
+			wantCalls: []string{"detach", "remove", "delete"},
+			wantStatus: []string{
+				iam.DeleteStepSucceeded, iam.DeleteStepSucceeded, iam.DeleteStepSucceeded,
+			},
+		},
+		{
+			name:      "a failed dependency skips the deletion",
+			failing:   "detach",
+			wantCalls: []string{"detach", "remove"},
+			wantStatus: []string{
+				iam.DeleteStepFailed, iam.DeleteStepSucceeded, iam.DeleteStepSkipped,
+			},
+		},
+		{
+			name:      "the deletion fails",
+			failing:   "delete",
+			wantCalls: []string{"detach", "remove", "delete"},
+			wantStatus: []string{
+				iam.DeleteStepSucceeded, iam.DeleteStepSucceeded, iam.DeleteStepFailed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			plan := iam.NewDeletePlan(iam.ResourceTypeRole, "app")
+			for _, name := range []string{"detach", "remove", "delete"} {
+				var err error
+				if name == tt.failing {
+					err = errors.New("denied")
+				}
+				plan.AddStep(name, "target", step(name, err))
+			}
+
+			err := plan.Execute()
+			if tt.failing == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, iam.ErrCascadeDeleteIncomplete)
+			}
+
+			assert.Equal(t, tt.wantCalls, calls)
+			for i, status := range tt.wantStatus {
+				assert.Equal(t, status, plan.Steps[i].Status)
+				assert.Equal(t, status == iam.DeleteStepFailed, plan.Steps[i].Err != nil)
+			}
+		})
+	}
+}
+
+func TestPlanPolicyDeletionRefused(t *testing.T) {
+	const requiredBoundary = "arn:aws:iam::123456789012:policy/RequiredBoundary"
+
+	tests := []struct {
+		name      string
+		policyARN string
+		wantErr   string
+	}{
+		{
+			name:      "AWS managed policy",
+			policyARN: "arn:aws:iam::aws:policy/ReadOnlyAccess",
+			wantErr:   iam.ErrCascadeDeleteAwsManagedPolicy,
+		},
+		{
+			name:      "AWS managed policy of another partition",
+			policyARN: "arn:aws-cn:iam::aws:policy/job-function/ViewOnlyAccess",
+			wantErr:   iam.ErrCascadeDeleteAwsManagedPolicy,
+		},
+		{
+			name:      "required role permissions boundary",
+			policyARN: requiredBoundary,
+			wantErr:   iam.ErrCascadeDeleteRequiredBoundary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := (&iam.Svc{}).PlanPolicyDeletion(tt.policyARN, requiredBoundary)
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Nil(t, plan)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package iam
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrCredentialReportTimeout      = "error IAM credential report generation did not complete in time"
+	ErrCredentialReportInvalid      = "error IAM credential report is not a valid CSV report"
+	ErrCredentialReportMissingField = "error IAM credential report is missing a column"
+)
+
+const (
+	// credentialReportPollInterval is the delay between two GenerateCredentialReport calls while the report is generated.
+	credentialReportPollInterval = 2 * time.Second
+	// credentialReportMaxPolls bounds how many times the generation state is polled.
+	credentialReportMaxPolls = 30
+
+	// credentialReportRootUser is the user name of the root account row.
+	credentialReportRootUser = "<root_account>"
+)
+
+// credentialReportColumns lists the columns every credential report row is parsed from.
+var credentialReportColumns = []string{
+	"user", "arn", "user_creation_time", "password_enabled", "password_last_used", "password_last_changed",
+	"password_next_rotation", "mfa_active",
+	"access_key_1_active", "access_key_1_last_rotated", "access_key_1_last_used_date",
+	"access_key_1_last_used_region", "access_key_1_last_used_service",
+	"access_key_2_active", "access_key_2_last_rotated", "access_key_2_last_used_date",
+	"access_key_2_last_used_region", "access_key_2_last_used_service",
+	"cert_1_active", "cert_1_last_rotated", "cert_2_active", "cert_2_last_rotated",
+}
+
+// CredentialReportAccessKey is the state of one of the two access key slots of a credential report row.
+type CredentialReportAccessKey struct {
+	Active          bool
+	LastRotated     *time.Time
+	LastUsedDate    *time.Time
+	LastUsedRegion  string
+	LastUsedService string
+}
+
+// CredentialReportCertificate is the state of one of the two signing certificate slots of a credential report row.
+type CredentialReportCertificate struct {
+	Active      bool
+	LastRotated *time.Time
+}
+
+// CredentialReportRow is a typed row of the IAM credential report. Dates AWS reports as N/A or
+// no_information are nil.
+type CredentialReportRow struct {
+	User                 string
+	Arn                  string
+	UserCreationTime     time.Time
+	PasswordEnabled      bool
+	PasswordLastUsed     *time.Time
+	PasswordLastChanged  *time.Time
+	PasswordNextRotation *time.Time
+	MFAActive            bool
+	AccessKeys           [2]CredentialReportAccessKey
+	Certificates         [2]CredentialReportCertificate
+}
+
+// IsRoot reports whether the row describes the root account.
+func (row *CredentialReportRow) IsRoot() bool {
+	return row.User == credentialReportRootUser
+}
+
+// PasswordAge returns how old the password was at the given time, or nil when it was never changed.
+func (row *CredentialReportRow) PasswordAge(at time.Time) *time.Duration {
+	return ageAt(row.PasswordLastChanged, at)
+}
+
+// AccessKeyAge returns how old an active access key was at the given time, or nil when the key slot is not active.
+func (row *CredentialReportRow) AccessKeyAge(index int, at time.Time) *time.Duration {
+	if !row.AccessKeys[index].Active {
+		return nil
+	}
+
+	return ageAt(row.AccessKeys[index].LastRotated, at)
+}
+
+// ageAt returns the duration between a date and the given time, or nil when the date is unknown.
+func ageAt(date *time.Time, at time.Time) *time.Duration {
+	if date == nil {
+		return nil
+	}
+
+	age := at.Sub(*date)
+	return &age
+}
+
+// CredentialReport is a generated IAM credential report, with its raw CSV content and parsed rows.
+type CredentialReport struct {
+	GeneratedTime time.Time
+	Content       []byte
+	Rows          []CredentialReportRow
+}
+
+// FetchCredentialReport triggers the generation of the IAM credential report, waits until it completes and
+// returns it parsed.
+func (IamSvc *Svc) FetchCredentialReport() (*CredentialReport, error) {
+	generated := false
+	for poll := 0; poll < credentialReportMaxPolls; poll++ {
+		output, err := IamSvc.svc.GenerateCredentialReport(&iam.GenerateCredentialReportInput{})
+		if err != nil {
+			return nil, fmt.Errorf("FetchCredentialReport: %w", err)
+		}
+
+		if aws.StringValue(output.State) == iam.ReportStateTypeComplete {
+			generated = true
+			break
+		}
+
+		time.Sleep(credentialReportPollInterval)
+	}
+	if !generated {
+		return nil, fmt.Errorf("FetchCredentialReport: %w", errors.New(ErrCredentialReportTimeout))
+	}
+
+	output, err := IamSvc.svc.GetCredentialReport(&iam.GetCredentialReportInput{})
+	if err != nil {
+		return nil, fmt.Errorf("FetchCredentialReport: %w", err)
+	}
+
+	rows, err := ParseCredentialReport(output.Content)
+	if err != nil {
+		return nil, fmt.Errorf("FetchCredentialReport: %w", err)
+	}
+
+	log.Logger.Infof("IAM credential report generated at %s with %d rows\n", aws.TimeValue(output.GeneratedTime),
+		len(rows))
+
+	return &CredentialReport{
+		GeneratedTime: aws.TimeValue(output.GeneratedTime),
+		Content:       output.Content,
+		Rows:          rows,
+	}, nil
+}
+
+// ParseCredentialReport parses the CSV content of an IAM credential report into typed rows.
+func ParseCredentialReport(content []byte) ([]CredentialReportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrCredentialReportInvalid, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		columns[name] = index
+	}
+	for _, name := range credentialReportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s: %s", ErrCredentialReportMissingField, name)
+		}
+	}
+
+	var rows []CredentialReportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrCredentialReportInvalid, err)
+		}
+
+		row, err := parseCredentialReportRow(record, columns)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseCredentialReportRow parses a CSV record of the credential report.
+func parseCredentialReportRow(record []string, columns map[string]int) (CredentialReportRow, error) {
+	field := func(name string) string {
+		return record[columns[name]]
+	}
+
+	var err error
+	// date parses a date column, keeping the first error.
+	date := func(name string) *time.Time {
+		value, dateErr := parseCredentialReportDate(field(name))
+		if dateErr != nil && err == nil {
+			err = fmt.Errorf("%s: %s %w", ErrCredentialReportInvalid, name, dateErr)
+		}
+		return value
+	}
+
+	row := CredentialReportRow{
+		User:                 field("user"),
+		Arn:                  field("arn"),
+		PasswordEnabled:      field("password_enabled") == "true",
+		PasswordLastUsed:     date("password_last_used"),
+		PasswordLastChanged:  date("password_last_changed"),
+		PasswordNextRotation: date("password_next_rotation"),
+		MFAActive:            field("mfa_active") == "true",
+	}
+	if creationTime := date("user_creation_time"); creationTime != nil {
+		row.UserCreationTime = *creationTime
+	}
+
+	for index := range row.AccessKeys {
+		prefix := fmt.Sprintf("access_key_%d_", index+1)
+		row.AccessKeys[index] = CredentialReportAccessKey{
+			Active:          field(prefix+"active") == "true",
+			LastRotated:     date(prefix + "last_rotated"),
+			LastUsedDate:    date(prefix + "last_used_date"),
+			LastUsedRegion:  credentialReportValue(field(prefix + "last_used_region")),
+			LastUsedService: credentialReportValue(field(prefix + "last_used_service")),
+		}
+	}
+
+	for index := range row.Certificates {
+		prefix := fmt.Sprintf("cert_%d_", index+1)
+		row.Certificates[index] = CredentialReportCertificate{
+			Active:      field(prefix+"active") == "true",
+			LastRotated: date(prefix + "last_rotated"),
+		}
+	}
+
+	return row, err
+}
+
+// credentialReportValue returns a credential report value, or an empty string for the placeholders AWS writes
+// when there is no value.
+func credentialReportValue(value string) string {
+	switch strings.ToLower(value) {
+	case "n/a", "no_information", "not_supported":
+		return ""
+	default:
+		return value
+	}
+}
+
+// parseCredentialReportDate parses an ISO 8601 date of the credential report, or returns nil for placeholders.
+func parseCredentialReportDate(value string) (*time.Time, error) {
+	if credentialReportValue(value) == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
@@ -0,0 +1,55 @@
+package iam_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+const credentialReportCSV = `user,arn,user_creation_time,password_enabled,password_last_used,password_last_changed,password_next_rotation,mfa_active,access_key_1_active,access_key_1_last_rotated,access_key_1_last_used_date,access_key_1_last_used_region,access_key_1_last_used_service,access_key_2_active,access_key_2_last_rotated,access_key_2_last_used_date,access_key_2_last_used_region,access_key_2_last_used_service,cert_1_active,cert_1_last_rotated,cert_2_active,cert_2_last_rotated
+<root_account>,arn:aws:iam::123456789012:root,2020-01-01T00:00:00+00:00,not_supported,2024-03-01T10:00:00+00:00,not_supported,not_supported,true,false,N/A,N/A,N/A,N/A,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A
+alice,arn:aws:iam::123456789012:user/alice,2023-01-01T00:00:00+00:00,true,no_information,2024-01-01T00:00:00+00:00,N/A,false,true,2023-06-01T00:00:00+00:00,2024-02-01T12:00:00+00:00,eu-west-1,s3,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A
+`
+
+func TestParseCredentialReport(t *testing.T) {
+	rows, err := iam.ParseCredentialReport([]byte(credentialReportCSV))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	root := rows[0]
+	assert.True(t, root.IsRoot())
+	assert.False(t, root.PasswordEnabled)
+	assert.True(t, root.MFAActive)
+	assert.Nil(t, root.PasswordLastChanged)
+	assert.Nil(t, root.AccessKeyAge(0, time.Now()))
+
+	alice := rows[1]
+	assert.False(t, alice.IsRoot())
+	assert.Equal(t, "arn:aws:iam::123456789012:user/alice", alice.Arn)
+	assert.True(t, alice.PasswordEnabled)
+	assert.Nil(t, alice.PasswordLastUsed)
+	assert.False(t, alice.MFAActive)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), alice.UserCreationTime.UTC())
+
+	at := time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 10*24*time.Hour, *alice.PasswordAge(at))
+
+	key := alice.AccessKeys[0]
+	assert.True(t, key.Active)
+	assert.Equal(t, "eu-west-1", key.LastUsedRegion)
+	assert.Equal(t, "s3", key.LastUsedService)
+	assert.NotNil(t, alice.AccessKeyAge(0, at))
+	assert.Nil(t, alice.AccessKeyAge(1, at))
+	assert.Empty(t, alice.AccessKeys[1].LastUsedService)
+}
+
+func TestParseCredentialReportErrors(t *testing.T) {
+	_, err := iam.ParseCredentialReport([]byte("user,arn\nalice,arn:aws:iam::123456789012:user/alice\n"))
+	assert.ErrorContains(t, err, iam.ErrCredentialReportMissingField)
+
+	_, err = iam.ParseCredentialReport([]byte(""))
+	assert.ErrorContains(t, err, iam.ErrCredentialReportInvalid)
+}
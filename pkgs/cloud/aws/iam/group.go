@@ -69,6 +69,22 @@ func (IamSvc *Svc) ListIamGroups() ([]*iam.Group, error) {
 	return groups, nil
 }
 
+// ListIamGroupsPage lists at most maxItems IAM groups starting at marker, an empty marker starting at the first
+// group. It returns the marker of the next page, empty on the last page.
+func (IamSvc *Svc) ListIamGroupsPage(marker string, maxItems int64) ([]*iam.Group, string, error) {
+	input := &iam.ListGroupsInput{MaxItems: aws.Int64(maxItems)}
+	if marker != "" {
+		input.Marker = aws.String(marker)
+	}
+
+	output, err := IamSvc.svc.ListGroups(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("ListIamGroupsPage: %w", err)
+	}
+
+	return output.Groups, nextMarker(output.IsTruncated, output.Marker), nil
+}
+
 // UpdateIamGroup updates the name of an existing IAM group and returns the updated group details
 func (IamSvc *Svc) UpdateIamGroup(oldGroupName string, newGroupName string) (*iam.Group, error) {
 	group, err := IamSvc.CheckIfGroupExists(oldGroupName)
@@ -156,3 +172,107 @@ func (IamSvc *Svc) DeleteIamGroup(groupName string) error {
 	log.Logger.Infof("IAM group '%s' deleted successfully. \n", groupName)
 	return nil
 }
+
+// GroupMembershipResult reports the outcome of adding or removing a single user in a bulk membership operation.
+type GroupMembershipResult struct {
+	Username string
+	Err      error
+}
+
+// ListGroupMembers lists all the IAM users that are members of an IAM group
+func (IamSvc *Svc) ListGroupMembers(groupName string) ([]*iam.User, error) {
+	var users []*iam.User
+
+	err := IamSvc.svc.GetGroupPages(&iam.GetGroupInput{GroupName: aws.String(groupName)},
+		func(page *iam.GetGroupOutput, lastPage bool) bool {
+			users = append(users, page.Users...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListGroupMembers: %s %w", ErrIamGroupNotExists, err)
+		}
+		return nil, fmt.Errorf("ListGroupMembers: %w", err)
+	}
+
+	return users, nil
+}
+
+// ListGroupsForUser lists all the IAM groups an IAM user belongs to
+func (IamSvc *Svc) ListGroupsForUser(username string) ([]*iam.Group, error) {
+	var groups []*iam.Group
+
+	err := IamSvc.svc.ListGroupsForUserPages(&iam.ListGroupsForUserInput{UserName: aws.String(username)},
+		func(page *iam.ListGroupsForUserOutput, lastPage bool) bool {
+			groups = append(groups, page.Groups...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListGroupsForUser: %s %w", ErrIamRUserNotExists, err)
+		}
+		return nil, fmt.Errorf("ListGroupsForUser: %w", err)
+	}
+
+	return groups, nil
+}
+
+// AddUserToGroup adds an IAM user to an IAM group
+func (IamSvc *Svc) AddUserToGroup(groupName string, username string) error {
+	_, err := IamSvc.svc.AddUserToGroup(&iam.AddUserToGroupInput{
+		GroupName: aws.String(groupName),
+		UserName:  aws.String(username),
+	})
+	if err != nil {
+		return fmt.Errorf("AddUserToGroup: %w", err)
+	}
+
+	log.Logger.Infof("IAM user '%s' added to group '%s' successfully\n", username, groupName)
+	return nil
+}
+
+// RemoveUserFromGroup removes an IAM user from an IAM group
+func (IamSvc *Svc) RemoveUserFromGroup(groupName string, username string) error {
+	_, err := IamSvc.svc.RemoveUserFromGroup(&iam.RemoveUserFromGroupInput{
+		GroupName: aws.String(groupName),
+		UserName:  aws.String(username),
+	})
+	if err != nil {
+		return fmt.Errorf("RemoveUserFromGroup: %w", err)
+	}
+
+	log.Logger.Infof("IAM user '%s' removed from group '%s' successfully\n", username, groupName)
+	return nil
+}
+
+// AddUsersToGroup adds several IAM users to an IAM group, reporting the outcome for each user.
+// A failure for one user does not prevent the remaining users from being added.
+func (IamSvc *Svc) AddUsersToGroup(groupName string, usernames []string) []GroupMembershipResult {
+	results := make([]GroupMembershipResult, 0, len(usernames))
+
+	for _, username := range usernames {
+		results = append(results, GroupMembershipResult{
+			Username: username,
+			Err:      IamSvc.AddUserToGroup(groupName, username),
+		})
+	}
+
+	return results
+}
+
+// RemoveUsersFromGroup removes several IAM users from an IAM group, reporting the outcome for each user.
+// A failure for one user does not prevent the remaining users from being removed.
+func (IamSvc *Svc) RemoveUsersFromGroup(groupName string, usernames []string) []GroupMembershipResult {
+	results := make([]GroupMembershipResult, 0, len(usernames))
+
+	for _, username := range usernames {
+		results = append(results, GroupMembershipResult{
+			Username: username,
+			Err:      IamSvc.RemoveUserFromGroup(groupName, username),
+		})
+	}
+
+	return results
+}
@@ -9,17 +9,19 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
 )
 
 const (
-	ROLE_NAME      = "testRole"
-	USERNAME       = "ironbyte"
-	NEW_USERNAME   = "ironbyte2"
-	GROUP_NAME     = "testGroup"
-	NEW_GROUP_NAME = "testGroup2"
-	ARN            = "arn:aws:iam::782390994097:policy/TestPolicy"
-	POLICY_NAME    = "TestPolicy"
-	TRUST_POLICY   = `{
+	ROLE_NAME          = "testRole"
+	USERNAME           = "ironbyte"
+	NEW_USERNAME       = "ironbyte2"
+	GROUP_NAME         = "testGroup"
+	NEW_GROUP_NAME     = "testGroup2"
+	ARN                = "arn:aws:iam::782390994097:policy/TestPolicy"
+	POLICY_NAME        = "TestPolicy"
+	INLINE_POLICY_NAME = "TestInlinePolicy"
+	TRUST_POLICY       = `{
 	  "Version": "2012-10-17",
 	  "Statement": [
 	    {
@@ -60,7 +62,7 @@ func TestAWSSession(t *testing.T) {
 	})
 
 	t.Run("CreateIAMRole", func(t *testing.T) {
-		newIamRole, err := awsSess.IamSvc.CreateIAMRole(ROLE_NAME, TRUST_POLICY)
+		newIamRole, err := awsSess.IamSvc.CreateIAMRole(ROLE_NAME, TRUST_POLICY, iam.RoleOptions{})
 		fmt.Println(newIamRole)
 		assert.NoError(t, err)
 	})
@@ -80,7 +82,7 @@ func TestAWSSession(t *testing.T) {
 	})
 
 	t.Run("CreateIamUser", func(t *testing.T) {
-		_, err := awsSess.IamSvc.CreateIamUser(USERNAME)
+		_, err := awsSess.IamSvc.CreateIamUser(USERNAME, nil)
 		assert.NoError(t, err)
 	})
 
@@ -89,6 +91,60 @@ func TestAWSSession(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("CreateAccessKey", func(t *testing.T) {
+		accessKey, err := awsSess.IamSvc.CreateAccessKey(NEW_USERNAME)
+		assert.NoError(t, err)
+
+		accessKeys, err := awsSess.IamSvc.ListAccessKeys(NEW_USERNAME)
+		assert.NoError(t, err)
+		assert.Len(t, accessKeys, 1)
+
+		err = awsSess.IamSvc.UpdateAccessKeyStatus(NEW_USERNAME, *accessKey.AccessKeyId, "Inactive")
+		assert.NoError(t, err)
+
+		err = awsSess.IamSvc.DeleteAccessKey(NEW_USERNAME, *accessKey.AccessKeyId)
+		assert.NoError(t, err)
+	})
+
+	t.Run("CreateVirtualMFADevice", func(t *testing.T) {
+		device, err := awsSess.IamSvc.CreateVirtualMFADevice(NEW_USERNAME, "")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, device.QrCode)
+
+		err = awsSess.IamSvc.RemoveVirtualMFADevice(NEW_USERNAME, device.SerialNumber)
+		assert.NoError(t, err)
+	})
+
+	t.Run("PutUserInlinePolicy", func(t *testing.T) {
+		err := awsSess.IamSvc.PutUserInlinePolicy(NEW_USERNAME, INLINE_POLICY_NAME, iam.PolicyDocument{
+			Version: "2012-10-17",
+			Statement: []iam.Statement{
+				{
+					Effect:   iam.EffectAllow,
+					Action:   iam.NewStringOrSlice("s3:ListBucket"),
+					Resource: iam.NewStringOrSlice("*"),
+				},
+			},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("ListUserInlinePolicies", func(t *testing.T) {
+		policyNames, err := awsSess.IamSvc.ListUserInlinePolicies(NEW_USERNAME)
+		assert.NoError(t, err)
+		assert.Contains(t, policyNames, INLINE_POLICY_NAME)
+	})
+
+	t.Run("GetUserInlinePolicy", func(t *testing.T) {
+		_, err := awsSess.IamSvc.GetUserInlinePolicy(NEW_USERNAME, INLINE_POLICY_NAME)
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteUserInlinePolicy", func(t *testing.T) {
+		err := awsSess.IamSvc.DeleteUserInlinePolicy(NEW_USERNAME, INLINE_POLICY_NAME)
+		assert.NoError(t, err)
+	})
+
 	t.Run("DeleteIamUser", func(t *testing.T) {
 		err := awsSess.IamSvc.DeleteIamUser(NEW_USERNAME)
 		assert.NoError(t, err)
@@ -109,6 +165,26 @@ func TestAWSSession(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("AddUserToGroup", func(t *testing.T) {
+		err := awsSess.IamSvc.AddUserToGroup(GROUP_NAME, USERNAME)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ListGroupMembers", func(t *testing.T) {
+		_, err := awsSess.IamSvc.ListGroupMembers(GROUP_NAME)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ListGroupsForUser", func(t *testing.T) {
+		_, err := awsSess.IamSvc.ListGroupsForUser(USERNAME)
+		assert.NoError(t, err)
+	})
+
+	t.Run("RemoveUserFromGroup", func(t *testing.T) {
+		err := awsSess.IamSvc.RemoveUserFromGroup(GROUP_NAME, USERNAME)
+		assert.NoError(t, err)
+	})
+
 	t.Run("DeleteIamGroup", func(t *testing.T) {
 		err := awsSess.IamSvc.DeleteIamGroup(GROUP_NAME)
 		assert.NoError(t, err)
@@ -0,0 +1,229 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+// IdentityPolicy is a managed or inline policy granting permissions to a user, group or role.
+// PolicyArn is empty for inline policies.
+type IdentityPolicy struct {
+	PolicyName string
+	PolicyArn  string
+	Inline     bool
+	Document   PolicyDocument
+}
+
+// ListAttachedRolePolicies lists the managed policies attached to an IAM role.
+func (IamSvc *Svc) ListAttachedRolePolicies(roleName string) ([]*iam.AttachedPolicy, error) {
+	var attachedPolicies []*iam.AttachedPolicy
+
+	err := IamSvc.svc.ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)},
+		func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
+			attachedPolicies = append(attachedPolicies, page.AttachedPolicies...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListAttachedRolePolicies: %s %w", ErrIamRoleNotExists, err)
+		}
+		return nil, fmt.Errorf("ListAttachedRolePolicies: %w", err)
+	}
+
+	return attachedPolicies, nil
+}
+
+// ListAttachedUserPolicies lists the managed policies attached to an IAM user.
+func (IamSvc *Svc) ListAttachedUserPolicies(username string) ([]*iam.AttachedPolicy, error) {
+	var attachedPolicies []*iam.AttachedPolicy
+
+	err := IamSvc.svc.ListAttachedUserPoliciesPages(&iam.ListAttachedUserPoliciesInput{UserName: aws.String(username)},
+		func(page *iam.ListAttachedUserPoliciesOutput, lastPage bool) bool {
+			attachedPolicies = append(attachedPolicies, page.AttachedPolicies...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListAttachedUserPolicies: %s %w", ErrIamRUserNotExists, err)
+		}
+		return nil, fmt.Errorf("ListAttachedUserPolicies: %w", err)
+	}
+
+	return attachedPolicies, nil
+}
+
+// ListAttachedGroupPolicies lists the managed policies attached to an IAM group.
+func (IamSvc *Svc) ListAttachedGroupPolicies(groupName string) ([]*iam.AttachedPolicy, error) {
+	var attachedPolicies []*iam.AttachedPolicy
+
+	err := IamSvc.svc.ListAttachedGroupPoliciesPages(&iam.ListAttachedGroupPoliciesInput{GroupName: aws.String(groupName)},
+		func(page *iam.ListAttachedGroupPoliciesOutput, lastPage bool) bool {
+			attachedPolicies = append(attachedPolicies, page.AttachedPolicies...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListAttachedGroupPolicies: %s %w", ErrIamGroupNotExists, err)
+		}
+		return nil, fmt.Errorf("ListAttachedGroupPolicies: %w", err)
+	}
+
+	return attachedPolicies, nil
+}
+
+// DetachUserPolicy detaches a managed policy from an IAM user.
+func (IamSvc *Svc) DetachUserPolicy(username string, policyARN string) error {
+	_, err := IamSvc.svc.DetachUserPolicy(&iam.DetachUserPolicyInput{
+		UserName:  aws.String(username),
+		PolicyArn: aws.String(policyARN),
+	})
+	if err != nil {
+		return fmt.Errorf("DetachUserPolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy '%s' detached from user '%s' successfully\n", policyARN, username)
+	return nil
+}
+
+// DetachGroupPolicy detaches a managed policy from an IAM group.
+func (IamSvc *Svc) DetachGroupPolicy(groupName string, policyARN string) error {
+	_, err := IamSvc.svc.DetachGroupPolicy(&iam.DetachGroupPolicyInput{
+		GroupName: aws.String(groupName),
+		PolicyArn: aws.String(policyARN),
+	})
+	if err != nil {
+		return fmt.Errorf("DetachGroupPolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy '%s' detached from group '%s' successfully\n", policyARN, groupName)
+	return nil
+}
+
+// DetachRolePolicy detaches a managed policy from an IAM role.
+func (IamSvc *Svc) DetachRolePolicy(roleName string, policyARN string) error {
+	_, err := IamSvc.svc.DetachRolePolicy(&iam.DetachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String(policyARN),
+	})
+	if err != nil {
+		return fmt.Errorf("DetachRolePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy '%s' detached from role '%s' successfully\n", policyARN, roleName)
+	return nil
+}
+
+// PolicyEntities are the users, groups and roles a managed policy is attached to, or is the permissions
+// boundary of, depending on the usage listed.
+type PolicyEntities struct {
+	Users  []string
+	Groups []string
+	Roles  []string
+}
+
+// ListPolicyEntities lists the entities using a managed policy. Usage is PermissionsPolicy for the entities the
+// policy is attached to, or PermissionsBoundary for those it is the permissions boundary of.
+func (IamSvc *Svc) ListPolicyEntities(policyARN string, usage string) (*PolicyEntities, error) {
+	entities := &PolicyEntities{}
+
+	err := IamSvc.svc.ListEntitiesForPolicyPages(&iam.ListEntitiesForPolicyInput{
+		PolicyArn:         aws.String(policyARN),
+		PolicyUsageFilter: aws.String(usage),
+	}, func(page *iam.ListEntitiesForPolicyOutput, lastPage bool) bool {
+		for _, user := range page.PolicyUsers {
+			entities.Users = append(entities.Users, aws.StringValue(user.UserName))
+		}
+		for _, group := range page.PolicyGroups {
+			entities.Groups = append(entities.Groups, aws.StringValue(group.GroupName))
+		}
+		for _, role := range page.PolicyRoles {
+			entities.Roles = append(entities.Roles, aws.StringValue(role.RoleName))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListPolicyEntities: %s %w", ErrIamPolicyNotExists, err)
+		}
+		return nil, fmt.Errorf("ListPolicyEntities: %w", err)
+	}
+
+	return entities, nil
+}
+
+// GetManagedPolicyDocument retrieves the document of the default version of a managed IAM policy.
+func (IamSvc *Svc) GetManagedPolicyDocument(policyARN string) (*PolicyDocument, error) {
+	policy, err := IamSvc.GetIamPolicy(policyARN)
+	if err != nil {
+		return nil, fmt.Errorf("GetManagedPolicyDocument: %w", err)
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("GetManagedPolicyDocument: %w", errors.New(ErrIamPolicyNotExists))
+	}
+
+	version, err := IamSvc.GetPolicyVersion(policyARN, aws.StringValue(policy.DefaultVersionId))
+	if err != nil {
+		return nil, fmt.Errorf("GetManagedPolicyDocument: %w", err)
+	}
+
+	document, err := ParsePolicyDocument(version.Document)
+	if err != nil {
+		return nil, fmt.Errorf("GetManagedPolicyDocument: %w", err)
+	}
+
+	return document, nil
+}
+
+// ListRoleIdentityPolicies retrieves the documents of the managed and inline policies granting permissions to an
+// IAM role.
+func (IamSvc *Svc) ListRoleIdentityPolicies(roleName string) ([]IdentityPolicy, error) {
+	var policies []IdentityPolicy
+
+	attachedPolicies, err := IamSvc.ListAttachedRolePolicies(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("ListRoleIdentityPolicies: %w", err)
+	}
+
+	for _, attachedPolicy := range attachedPolicies {
+		document, err := IamSvc.GetManagedPolicyDocument(aws.StringValue(attachedPolicy.PolicyArn))
+		if err != nil {
+			return nil, fmt.Errorf("ListRoleIdentityPolicies: %w", err)
+		}
+		policies = append(policies, IdentityPolicy{
+			PolicyName: aws.StringValue(attachedPolicy.PolicyName),
+			PolicyArn:  aws.StringValue(attachedPolicy.PolicyArn),
+			Document:   *document,
+		})
+	}
+
+	inlinePolicyNames, err := IamSvc.ListRoleInlinePolicies(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("ListRoleIdentityPolicies: %w", err)
+	}
+
+	for _, policyName := range inlinePolicyNames {
+		inlinePolicy, err := IamSvc.GetRoleInlinePolicy(roleName, policyName)
+		if err != nil {
+			return nil, fmt.Errorf("ListRoleIdentityPolicies: %w", err)
+		}
+		document, err := ParsePolicyDocument(inlinePolicy.Document)
+		if err != nil {
+			return nil, fmt.Errorf("ListRoleIdentityPolicies: %w", err)
+		}
+		policies = append(policies, IdentityPolicy{
+			PolicyName: policyName,
+			Inline:     true,
+			Document:   *document,
+		})
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,285 @@
+package iam
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamOIDCProviderExists    = "error IAM OIDC provider already exists"
+	ErrIamSAMLProviderExists    = "error IAM SAML provider already exists"
+	ErrIamSAMLProviderNotExists = "error IAM SAML provider does not exist"
+)
+
+// OIDCProvider is an IAM OpenID Connect identity provider. URL is the issuer of the tokens, without its https://
+// scheme as IAM returns it.
+type OIDCProvider struct {
+	Arn         string
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+	CreateDate  time.Time
+	Tags        map[string]string
+}
+
+// SAMLProvider is an IAM SAML 2.0 identity provider. ValidUntil is the expiration of the metadata document.
+type SAMLProvider struct {
+	Arn              string
+	Name             string
+	MetadataDocument string
+	CreateDate       time.Time
+	ValidUntil       time.Time
+	Tags             map[string]string
+}
+
+// CreateOpenIDConnectProvider creates an IAM OpenID Connect identity provider for the issuer at url, trusting
+// tokens issued to the given client IDs, the audiences. Thumbprints may be empty, IAM then retrieves the
+// thumbprint of the issuer certificate itself.
+func (IamSvc *Svc) CreateOpenIDConnectProvider(url string, clientIDs []string, thumbprints []string,
+	tags map[string]string,
+) (*OIDCProvider, error) {
+	output, err := IamSvc.svc.CreateOpenIDConnectProvider(&iam.CreateOpenIDConnectProviderInput{
+		Url:            aws.String(url),
+		ClientIDList:   aws.StringSlice(clientIDs),
+		ThumbprintList: aws.StringSlice(thumbprints),
+		Tags:           toIamTags(tags),
+	})
+	if err != nil {
+		if isAwsErrorCode(err, iam.ErrCodeEntityAlreadyExistsException) {
+			return nil, fmt.Errorf("CreateOpenIDConnectProvider: %s %w", ErrIamOIDCProviderExists, err)
+		}
+		return nil, fmt.Errorf("CreateOpenIDConnectProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM OIDC provider '%s' created successfully\n", url)
+	return IamSvc.GetOpenIDConnectProvider(aws.StringValue(output.OpenIDConnectProviderArn))
+}
+
+// GetOpenIDConnectProvider returns an IAM OpenID Connect identity provider.
+func (IamSvc *Svc) GetOpenIDConnectProvider(providerARN string) (*OIDCProvider, error) {
+	output, err := IamSvc.svc.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetOpenIDConnectProvider: %s %w", ErrIamOIDCProviderNotExists, err)
+		}
+		return nil, fmt.Errorf("GetOpenIDConnectProvider: %w", err)
+	}
+
+	return &OIDCProvider{
+		Arn:         providerARN,
+		URL:         aws.StringValue(output.Url),
+		ClientIDs:   aws.StringValueSlice(output.ClientIDList),
+		Thumbprints: aws.StringValueSlice(output.ThumbprintList),
+		CreateDate:  aws.TimeValue(output.CreateDate),
+		Tags:        FromIamTags(output.Tags),
+	}, nil
+}
+
+// ListOpenIDConnectProviders returns every IAM OpenID Connect identity provider of the account.
+func (IamSvc *Svc) ListOpenIDConnectProviders() ([]*OIDCProvider, error) {
+	output, err := IamSvc.svc.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("ListOpenIDConnectProviders: %w", err)
+	}
+
+	providers := make([]*OIDCProvider, 0, len(output.OpenIDConnectProviderList))
+	for _, entry := range output.OpenIDConnectProviderList {
+		provider, err := IamSvc.GetOpenIDConnectProvider(aws.StringValue(entry.Arn))
+		if err != nil {
+			return nil, fmt.Errorf("ListOpenIDConnectProviders: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// UpdateOpenIDConnectProvider replaces the client IDs and the thumbprints of an IAM OpenID Connect identity
+// provider. A nil slice leaves the corresponding list unchanged.
+func (IamSvc *Svc) UpdateOpenIDConnectProvider(providerARN string, clientIDs []string, thumbprints []string,
+) (*OIDCProvider, error) {
+	provider, err := IamSvc.GetOpenIDConnectProvider(providerARN)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateOpenIDConnectProvider: %w", err)
+	}
+
+	if thumbprints != nil {
+		_, err = IamSvc.svc.UpdateOpenIDConnectProviderThumbprint(&iam.UpdateOpenIDConnectProviderThumbprintInput{
+			OpenIDConnectProviderArn: aws.String(providerARN),
+			ThumbprintList:           aws.StringSlice(thumbprints),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("UpdateOpenIDConnectProvider: %w", err)
+		}
+	}
+
+	if clientIDs != nil {
+		for _, clientID := range missingValues(clientIDs, provider.ClientIDs) {
+			_, err = IamSvc.svc.AddClientIDToOpenIDConnectProvider(&iam.AddClientIDToOpenIDConnectProviderInput{
+				OpenIDConnectProviderArn: aws.String(providerARN),
+				ClientID:                 aws.String(clientID),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("UpdateOpenIDConnectProvider: %w", err)
+			}
+		}
+
+		for _, clientID := range missingValues(provider.ClientIDs, clientIDs) {
+			_, err = IamSvc.svc.RemoveClientIDFromOpenIDConnectProvider(
+				&iam.RemoveClientIDFromOpenIDConnectProviderInput{
+					OpenIDConnectProviderArn: aws.String(providerARN),
+					ClientID:                 aws.String(clientID),
+				})
+			if err != nil {
+				return nil, fmt.Errorf("UpdateOpenIDConnectProvider: %w", err)
+			}
+		}
+	}
+
+	log.Logger.Infof("IAM OIDC provider '%s' updated successfully\n", providerARN)
+	return IamSvc.GetOpenIDConnectProvider(providerARN)
+}
+
+// DeleteOpenIDConnectProvider deletes an IAM OpenID Connect identity provider. The roles trusting it are left
+// untouched but can no longer be assumed through it.
+func (IamSvc *Svc) DeleteOpenIDConnectProvider(providerARN string) error {
+	_, err := IamSvc.svc.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteOpenIDConnectProvider: %s %w", ErrIamOIDCProviderNotExists, err)
+		}
+		return fmt.Errorf("DeleteOpenIDConnectProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM OIDC provider '%s' deleted successfully\n", providerARN)
+	return nil
+}
+
+// CreateSAMLProvider creates an IAM SAML 2.0 identity provider from the metadata document of the IdP.
+func (IamSvc *Svc) CreateSAMLProvider(name string, metadataDocument string, tags map[string]string,
+) (*SAMLProvider, error) {
+	output, err := IamSvc.svc.CreateSAMLProvider(&iam.CreateSAMLProviderInput{
+		Name:                 aws.String(name),
+		SAMLMetadataDocument: aws.String(metadataDocument),
+		Tags:                 toIamTags(tags),
+	})
+	if err != nil {
+		if isAwsErrorCode(err, iam.ErrCodeEntityAlreadyExistsException) {
+			return nil, fmt.Errorf("CreateSAMLProvider: %s %w", ErrIamSAMLProviderExists, err)
+		}
+		return nil, fmt.Errorf("CreateSAMLProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM SAML provider '%s' created successfully\n", name)
+	return IamSvc.GetSAMLProvider(aws.StringValue(output.SAMLProviderArn))
+}
+
+// GetSAMLProvider returns an IAM SAML 2.0 identity provider along with its metadata document.
+func (IamSvc *Svc) GetSAMLProvider(providerARN string) (*SAMLProvider, error) {
+	output, err := IamSvc.svc.GetSAMLProvider(&iam.GetSAMLProviderInput{
+		SAMLProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetSAMLProvider: %s %w", ErrIamSAMLProviderNotExists, err)
+		}
+		return nil, fmt.Errorf("GetSAMLProvider: %w", err)
+	}
+
+	return &SAMLProvider{
+		Arn:              providerARN,
+		Name:             samlProviderName(providerARN),
+		MetadataDocument: aws.StringValue(output.SAMLMetadataDocument),
+		CreateDate:       aws.TimeValue(output.CreateDate),
+		ValidUntil:       aws.TimeValue(output.ValidUntil),
+		Tags:             FromIamTags(output.Tags),
+	}, nil
+}
+
+// ListSAMLProviders returns every IAM SAML 2.0 identity provider of the account, without their metadata
+// document and tags.
+func (IamSvc *Svc) ListSAMLProviders() ([]*SAMLProvider, error) {
+	output, err := IamSvc.svc.ListSAMLProviders(&iam.ListSAMLProvidersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("ListSAMLProviders: %w", err)
+	}
+
+	providers := make([]*SAMLProvider, 0, len(output.SAMLProviderList))
+	for _, entry := range output.SAMLProviderList {
+		providers = append(providers, &SAMLProvider{
+			Arn:        aws.StringValue(entry.Arn),
+			Name:       samlProviderName(aws.StringValue(entry.Arn)),
+			CreateDate: aws.TimeValue(entry.CreateDate),
+			ValidUntil: aws.TimeValue(entry.ValidUntil),
+		})
+	}
+
+	return providers, nil
+}
+
+// UpdateSAMLProvider replaces the metadata document of an IAM SAML 2.0 identity provider, typically when the
+// IdP rotates its signing certificate.
+func (IamSvc *Svc) UpdateSAMLProvider(providerARN string, metadataDocument string) (*SAMLProvider, error) {
+	_, err := IamSvc.svc.UpdateSAMLProvider(&iam.UpdateSAMLProviderInput{
+		SAMLProviderArn:      aws.String(providerARN),
+		SAMLMetadataDocument: aws.String(metadataDocument),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("UpdateSAMLProvider: %s %w", ErrIamSAMLProviderNotExists, err)
+		}
+		return nil, fmt.Errorf("UpdateSAMLProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM SAML provider '%s' updated successfully\n", providerARN)
+	return IamSvc.GetSAMLProvider(providerARN)
+}
+
+// DeleteSAMLProvider deletes an IAM SAML 2.0 identity provider.
+func (IamSvc *Svc) DeleteSAMLProvider(providerARN string) error {
+	_, err := IamSvc.svc.DeleteSAMLProvider(&iam.DeleteSAMLProviderInput{
+		SAMLProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteSAMLProvider: %s %w", ErrIamSAMLProviderNotExists, err)
+		}
+		return fmt.Errorf("DeleteSAMLProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM SAML provider '%s' deleted successfully\n", providerARN)
+	return nil
+}
+
+// samlProviderName returns the name of a SAML provider, the last part of its ARN.
+func samlProviderName(providerARN string) string {
+	return providerARN[strings.LastIndex(providerARN, "/")+1:]
+}
+
+// missingValues returns the values of wanted that are not in current.
+func missingValues(wanted []string, current []string) []string {
+	present := make(map[string]bool, len(current))
+	for _, value := range current {
+		present[value] = true
+	}
+
+	var missing []string
+	for _, value := range wanted {
+		if !present[value] {
+			missing = append(missing, value)
+			present[value] = true
+		}
+	}
+
+	return missing
+}
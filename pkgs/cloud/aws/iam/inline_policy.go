@@ -0,0 +1,261 @@
+package iam
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamInlinePolicyNotExists = "error IAM inline policy does not exist"
+	ErrDecodingPolicy           = "error decoding IAM policy document"
+)
+
+// InlinePolicy represents an inline policy embedded in a user, group or role.
+type InlinePolicy struct {
+	PrincipalName string
+	PolicyName    string
+	// Document is the URL-decoded JSON policy document as stored by AWS.
+	Document string
+}
+
+// PutUserInlinePolicy creates or replaces an inline policy embedded in an IAM user.
+func (IamSvc *Svc) PutUserInlinePolicy(username string, policyName string, policy PolicyDocument) error {
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("PutUserInlinePolicy: %s %w", ErrMarshallingPolicy, err)
+	}
+
+	_, err = IamSvc.svc.PutUserPolicy(&iam.PutUserPolicyInput{
+		UserName:       aws.String(username),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(string(document)),
+	})
+	if err != nil {
+		return fmt.Errorf("PutUserInlinePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM inline policy '%s' put on user '%s' successfully\n", policyName, username)
+	return nil
+}
+
+// GetUserInlinePolicy retrieves an inline policy embedded in an IAM user.
+func (IamSvc *Svc) GetUserInlinePolicy(username string, policyName string) (*InlinePolicy, error) {
+	output, err := IamSvc.svc.GetUserPolicy(&iam.GetUserPolicyInput{
+		UserName:   aws.String(username),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetUserInlinePolicy: %s %w", ErrIamInlinePolicyNotExists, err)
+		}
+		return nil, fmt.Errorf("GetUserInlinePolicy: %w", err)
+	}
+
+	document, err := decodePolicyDocument(*output.PolicyDocument)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserInlinePolicy: %w", err)
+	}
+
+	return &InlinePolicy{
+		PrincipalName: *output.UserName,
+		PolicyName:    *output.PolicyName,
+		Document:      document,
+	}, nil
+}
+
+// ListUserInlinePolicies lists the names of the inline policies embedded in an IAM user.
+func (IamSvc *Svc) ListUserInlinePolicies(username string) ([]string, error) {
+	var policyNames []string
+
+	err := IamSvc.svc.ListUserPoliciesPages(&iam.ListUserPoliciesInput{UserName: aws.String(username)},
+		func(page *iam.ListUserPoliciesOutput, lastPage bool) bool {
+			policyNames = append(policyNames, aws.StringValueSlice(page.PolicyNames)...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListUserInlinePolicies: %w", err)
+	}
+
+	return policyNames, nil
+}
+
+// DeleteUserInlinePolicy deletes an inline policy embedded in an IAM user.
+func (IamSvc *Svc) DeleteUserInlinePolicy(username string, policyName string) error {
+	_, err := IamSvc.svc.DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+		UserName:   aws.String(username),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteUserInlinePolicy: %s %w", ErrIamInlinePolicyNotExists, err)
+		}
+		return fmt.Errorf("DeleteUserInlinePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM inline policy '%s' deleted from user '%s' successfully\n", policyName, username)
+	return nil
+}
+
+// PutGroupInlinePolicy creates or replaces an inline policy embedded in an IAM group.
+func (IamSvc *Svc) PutGroupInlinePolicy(groupName string, policyName string, policy PolicyDocument) error {
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("PutGroupInlinePolicy: %s %w", ErrMarshallingPolicy, err)
+	}
+
+	_, err = IamSvc.svc.PutGroupPolicy(&iam.PutGroupPolicyInput{
+		GroupName:      aws.String(groupName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(string(document)),
+	})
+	if err != nil {
+		return fmt.Errorf("PutGroupInlinePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM inline policy '%s' put on group '%s' successfully\n", policyName, groupName)
+	return nil
+}
+
+// GetGroupInlinePolicy retrieves an inline policy embedded in an IAM group.
+func (IamSvc *Svc) GetGroupInlinePolicy(groupName string, policyName string) (*InlinePolicy, error) {
+	output, err := IamSvc.svc.GetGroupPolicy(&iam.GetGroupPolicyInput{
+		GroupName:  aws.String(groupName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetGroupInlinePolicy: %s %w", ErrIamInlinePolicyNotExists, err)
+		}
+		return nil, fmt.Errorf("GetGroupInlinePolicy: %w", err)
+	}
+
+	document, err := decodePolicyDocument(*output.PolicyDocument)
+	if err != nil {
+		return nil, fmt.Errorf("GetGroupInlinePolicy: %w", err)
+	}
+
+	return &InlinePolicy{
+		PrincipalName: *output.GroupName,
+		PolicyName:    *output.PolicyName,
+		Document:      document,
+	}, nil
+}
+
+// ListGroupInlinePolicies lists the names of the inline policies embedded in an IAM group.
+func (IamSvc *Svc) ListGroupInlinePolicies(groupName string) ([]string, error) {
+	var policyNames []string
+
+	err := IamSvc.svc.ListGroupPoliciesPages(&iam.ListGroupPoliciesInput{GroupName: aws.String(groupName)},
+		func(page *iam.ListGroupPoliciesOutput, lastPage bool) bool {
+			policyNames = append(policyNames, aws.StringValueSlice(page.PolicyNames)...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListGroupInlinePolicies: %w", err)
+	}
+
+	return policyNames, nil
+}
+
+// DeleteGroupInlinePolicy deletes an inline policy embedded in an IAM group.
+func (IamSvc *Svc) DeleteGroupInlinePolicy(groupName string, policyName string) error {
+	_, err := IamSvc.svc.DeleteGroupPolicy(&iam.DeleteGroupPolicyInput{
+		GroupName:  aws.String(groupName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteGroupInlinePolicy: %s %w", ErrIamInlinePolicyNotExists, err)
+		}
+		return fmt.Errorf("DeleteGroupInlinePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM inline policy '%s' deleted from group '%s' successfully\n", policyName, groupName)
+	return nil
+}
+
+// PutRoleInlinePolicy creates or replaces an inline policy embedded in an IAM role.
+func (IamSvc *Svc) PutRoleInlinePolicy(roleName string, policyName string, policy PolicyDocument) error {
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("PutRoleInlinePolicy: %s %w", ErrMarshallingPolicy, err)
+	}
+
+	_, err = IamSvc.svc.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(string(document)),
+	})
+	if err != nil {
+		return fmt.Errorf("PutRoleInlinePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM inline policy '%s' put on role '%s' successfully\n", policyName, roleName)
+	return nil
+}
+
+// GetRoleInlinePolicy retrieves an inline policy embedded in an IAM role.
+func (IamSvc *Svc) GetRoleInlinePolicy(roleName string, policyName string) (*InlinePolicy, error) {
+	output, err := IamSvc.svc.GetRolePolicy(&iam.GetRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetRoleInlinePolicy: %s %w", ErrIamInlinePolicyNotExists, err)
+		}
+		return nil, fmt.Errorf("GetRoleInlinePolicy: %w", err)
+	}
+
+	document, err := decodePolicyDocument(*output.PolicyDocument)
+	if err != nil {
+		return nil, fmt.Errorf("GetRoleInlinePolicy: %w", err)
+	}
+
+	return &InlinePolicy{
+		PrincipalName: *output.RoleName,
+		PolicyName:    *output.PolicyName,
+		Document:      document,
+	}, nil
+}
+
+// ListRoleInlinePolicies lists the names of the inline policies embedded in an IAM role.
+func (IamSvc *Svc) ListRoleInlinePolicies(roleName string) ([]string, error) {
+	var policyNames []string
+
+	err := IamSvc.svc.ListRolePoliciesPages(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)},
+		func(page *iam.ListRolePoliciesOutput, lastPage bool) bool {
+			policyNames = append(policyNames, aws.StringValueSlice(page.PolicyNames)...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListRoleInlinePolicies: %w", err)
+	}
+
+	return policyNames, nil
+}
+
+// DeleteRoleInlinePolicy deletes an inline policy embedded in an IAM role.
+func (IamSvc *Svc) DeleteRoleInlinePolicy(roleName string, policyName string) error {
+	_, err := IamSvc.svc.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteRoleInlinePolicy: %s %w", ErrIamInlinePolicyNotExists, err)
+		}
+		return fmt.Errorf("DeleteRoleInlinePolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM inline policy '%s' deleted from role '%s' successfully\n", policyName, roleName)
+	return nil
+}
@@ -0,0 +1,155 @@
+package iam
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamInstanceProfileExists    = "error IAM instance profile already exists"
+	ErrIamInstanceProfileNotExists = "error IAM instance profile does not exist"
+)
+
+// CreateInstanceProfile creates an IAM instance profile with the given path and tags.
+// An empty path keeps the AWS default "/".
+func (IamSvc *Svc) CreateInstanceProfile(name string, path string, tags map[string]string) (*iam.InstanceProfile, error) {
+	createInstanceProfileInput := &iam.CreateInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		Tags:                toIamTags(tags),
+	}
+	if path != "" {
+		createInstanceProfileInput.Path = aws.String(path)
+	}
+
+	output, err := IamSvc.svc.CreateInstanceProfile(createInstanceProfileInput)
+	if err != nil {
+		if isAwsErrorCode(err, iam.ErrCodeEntityAlreadyExistsException) {
+			return nil, fmt.Errorf("CreateInstanceProfile: %s %w", ErrIamInstanceProfileExists, err)
+		}
+		return nil, fmt.Errorf("CreateInstanceProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM instance profile '%s' created successfully\n", name)
+	return output.InstanceProfile, nil
+}
+
+// GetInstanceProfile returns an IAM instance profile and the roles it contains.
+func (IamSvc *Svc) GetInstanceProfile(name string) (*iam.InstanceProfile, error) {
+	output, err := IamSvc.svc.GetInstanceProfile(&iam.GetInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetInstanceProfile: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return nil, fmt.Errorf("GetInstanceProfile: %w", err)
+	}
+
+	return output.InstanceProfile, nil
+}
+
+// ListInstanceProfiles lists every IAM instance profile.
+func (IamSvc *Svc) ListInstanceProfiles() ([]*iam.InstanceProfile, error) {
+	var instanceProfiles []*iam.InstanceProfile
+
+	err := IamSvc.svc.ListInstanceProfilesPages(&iam.ListInstanceProfilesInput{},
+		func(output *iam.ListInstanceProfilesOutput, _ bool) bool {
+			instanceProfiles = append(instanceProfiles, output.InstanceProfiles...)
+			return true
+		})
+	if err != nil {
+		return nil, fmt.Errorf("ListInstanceProfiles: %w", err)
+	}
+
+	return instanceProfiles, nil
+}
+
+// ListInstanceProfilesPage lists at most maxItems IAM instance profiles starting at marker, an empty marker
+// starting at the first instance profile. It returns the marker of the next page, empty on the last page.
+func (IamSvc *Svc) ListInstanceProfilesPage(marker string, maxItems int64) ([]*iam.InstanceProfile, string, error) {
+	input := &iam.ListInstanceProfilesInput{MaxItems: aws.Int64(maxItems)}
+	if marker != "" {
+		input.Marker = aws.String(marker)
+	}
+
+	output, err := IamSvc.svc.ListInstanceProfiles(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("ListInstanceProfilesPage: %w", err)
+	}
+
+	return output.InstanceProfiles, nextMarker(output.IsTruncated, output.Marker), nil
+}
+
+// ListInstanceProfilesForRole lists the IAM instance profiles containing a role.
+func (IamSvc *Svc) ListInstanceProfilesForRole(roleName string) ([]*iam.InstanceProfile, error) {
+	var instanceProfiles []*iam.InstanceProfile
+
+	err := IamSvc.svc.ListInstanceProfilesForRolePages(&iam.ListInstanceProfilesForRoleInput{
+		RoleName: aws.String(roleName),
+	}, func(output *iam.ListInstanceProfilesForRoleOutput, _ bool) bool {
+		instanceProfiles = append(instanceProfiles, output.InstanceProfiles...)
+		return true
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListInstanceProfilesForRole: %s %w", ErrIamRoleNotExists, err)
+		}
+		return nil, fmt.Errorf("ListInstanceProfilesForRole: %w", err)
+	}
+
+	return instanceProfiles, nil
+}
+
+// AddRoleToInstanceProfile adds a role to an IAM instance profile. An instance profile holds at most one role.
+func (IamSvc *Svc) AddRoleToInstanceProfile(name string, roleName string) error {
+	_, err := IamSvc.svc.AddRoleToInstanceProfile(&iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		RoleName:            aws.String(roleName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("AddRoleToInstanceProfile: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return fmt.Errorf("AddRoleToInstanceProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' added to instance profile '%s'\n", roleName, name)
+	return nil
+}
+
+// RemoveRoleFromInstanceProfile removes a role from an IAM instance profile.
+func (IamSvc *Svc) RemoveRoleFromInstanceProfile(name string, roleName string) error {
+	_, err := IamSvc.svc.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		RoleName:            aws.String(roleName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("RemoveRoleFromInstanceProfile: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return fmt.Errorf("RemoveRoleFromInstanceProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' removed from instance profile '%s'\n", roleName, name)
+	return nil
+}
+
+// DeleteInstanceProfile deletes an IAM instance profile. Its role must be removed first.
+func (IamSvc *Svc) DeleteInstanceProfile(name string) error {
+	_, err := IamSvc.svc.DeleteInstanceProfile(&iam.DeleteInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteInstanceProfile: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return fmt.Errorf("DeleteInstanceProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM instance profile '%s' deleted successfully\n", name)
+	return nil
+}
@@ -0,0 +1,291 @@
+package iam
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamLoginProfileNotExists = "error IAM login profile does not exist"
+	ErrGeneratingPassword       = "error generating temporary password"
+)
+
+const (
+	// DefaultTemporaryPasswordLength is the length of generated passwords when the account policy does not require more.
+	DefaultTemporaryPasswordLength = 20
+
+	lowercaseCharacters = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	numberCharacters    = "0123456789"
+	// symbolCharacters is the subset of the symbols accepted by the IAM password policy.
+	symbolCharacters = "!@#$%^&*()_+-=[]{}|"
+)
+
+// LoginProfile represents the console access of an IAM user.
+type LoginProfile struct {
+	Username              string
+	PasswordResetRequired bool
+	CreateDate            time.Time
+	// Password is only set when Inariam generated the password, it cannot be read back from AWS.
+	Password string
+}
+
+// PasswordPolicy represents the account password policy applied to IAM users.
+type PasswordPolicy struct {
+	MinimumPasswordLength      int64
+	RequireSymbols             bool
+	RequireNumbers             bool
+	RequireUppercaseCharacters bool
+	RequireLowercaseCharacters bool
+	AllowUsersToChangePassword bool
+	// MaxPasswordAge is the number of days a password is valid, 0 means passwords never expire.
+	MaxPasswordAge int64
+	// PasswordReusePrevention is the number of previous passwords that cannot be reused, 0 disables the check.
+	PasswordReusePrevention int64
+	HardExpiry              bool
+}
+
+// randomCharacter picks a cryptographically random character of the given set.
+func randomCharacter(characters string) (byte, error) {
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(len(characters))))
+	if err != nil {
+		return 0, err
+	}
+
+	return characters[index.Int64()], nil
+}
+
+// GenerateTemporaryPassword generates a random password of at least length characters
+// that contains lowercase, uppercase, number and symbol characters, satisfying any IAM password policy.
+func GenerateTemporaryPassword(length int) (string, error) {
+	if length < DefaultTemporaryPasswordLength {
+		length = DefaultTemporaryPasswordLength
+	}
+
+	characterSets := []string{lowercaseCharacters, uppercaseCharacters, numberCharacters, symbolCharacters}
+	allCharacters := lowercaseCharacters + uppercaseCharacters + numberCharacters + symbolCharacters
+
+	password := make([]byte, 0, length)
+	for _, characterSet := range characterSets {
+		character, err := randomCharacter(characterSet)
+		if err != nil {
+			return "", fmt.Errorf("%s %w", ErrGeneratingPassword, err)
+		}
+		password = append(password, character)
+	}
+
+	for len(password) < length {
+		character, err := randomCharacter(allCharacters)
+		if err != nil {
+			return "", fmt.Errorf("%s %w", ErrGeneratingPassword, err)
+		}
+		password = append(password, character)
+	}
+
+	// Shuffle so that the guaranteed characters are not always at the start.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("%s %w", ErrGeneratingPassword, err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+// generateAccountPassword generates a temporary password compliant with the account password policy.
+func (IamSvc *Svc) generateAccountPassword() (string, error) {
+	policy, err := IamSvc.GetAccountPasswordPolicy()
+	if err != nil {
+		return "", err
+	}
+
+	length := DefaultTemporaryPasswordLength
+	if policy != nil && int(policy.MinimumPasswordLength) > length {
+		length = int(policy.MinimumPasswordLength)
+	}
+
+	return GenerateTemporaryPassword(length)
+}
+
+// GetLoginProfile retrieves the console login profile of an IAM user.
+func (IamSvc *Svc) GetLoginProfile(username string) (*LoginProfile, error) {
+	output, err := IamSvc.svc.GetLoginProfile(&iam.GetLoginProfileInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetLoginProfile: %s %w", ErrIamLoginProfileNotExists, err)
+		}
+		return nil, fmt.Errorf("GetLoginProfile: %w", err)
+	}
+
+	return &LoginProfile{
+		Username:              aws.StringValue(output.LoginProfile.UserName),
+		PasswordResetRequired: aws.BoolValue(output.LoginProfile.PasswordResetRequired),
+		CreateDate:            aws.TimeValue(output.LoginProfile.CreateDate),
+	}, nil
+}
+
+// CreateLoginProfile gives console access to an IAM user.
+// When password is empty a temporary password compliant with the account policy is generated and returned.
+func (IamSvc *Svc) CreateLoginProfile(username string, password string, passwordResetRequired bool) (*LoginProfile, error) {
+	generatedPassword := ""
+	if password == "" {
+		var err error
+		generatedPassword, err = IamSvc.generateAccountPassword()
+		if err != nil {
+			return nil, fmt.Errorf("CreateLoginProfile: %w", err)
+		}
+		password = generatedPassword
+	}
+
+	output, err := IamSvc.svc.CreateLoginProfile(&iam.CreateLoginProfileInput{
+		UserName:              aws.String(username),
+		Password:              aws.String(password),
+		PasswordResetRequired: aws.Bool(passwordResetRequired),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CreateLoginProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM login profile for user '%s' created successfully\n", username)
+	return &LoginProfile{
+		Username:              aws.StringValue(output.LoginProfile.UserName),
+		PasswordResetRequired: aws.BoolValue(output.LoginProfile.PasswordResetRequired),
+		CreateDate:            aws.TimeValue(output.LoginProfile.CreateDate),
+		Password:              generatedPassword,
+	}, nil
+}
+
+// UpdateLoginProfile resets the console password of an IAM user.
+// When password is empty a temporary password compliant with the account policy is generated and returned.
+func (IamSvc *Svc) UpdateLoginProfile(username string, password string, passwordResetRequired bool) (*LoginProfile, error) {
+	generatedPassword := ""
+	if password == "" {
+		var err error
+		generatedPassword, err = IamSvc.generateAccountPassword()
+		if err != nil {
+			return nil, fmt.Errorf("UpdateLoginProfile: %w", err)
+		}
+		password = generatedPassword
+	}
+
+	_, err := IamSvc.svc.UpdateLoginProfile(&iam.UpdateLoginProfileInput{
+		UserName:              aws.String(username),
+		Password:              aws.String(password),
+		PasswordResetRequired: aws.Bool(passwordResetRequired),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("UpdateLoginProfile: %s %w", ErrIamLoginProfileNotExists, err)
+		}
+		return nil, fmt.Errorf("UpdateLoginProfile: %w", err)
+	}
+
+	loginProfile, err := IamSvc.GetLoginProfile(username)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateLoginProfile: %w", err)
+	}
+	loginProfile.Password = generatedPassword
+
+	log.Logger.Infof("IAM login profile for user '%s' updated successfully\n", username)
+	return loginProfile, nil
+}
+
+// DeleteLoginProfile removes the console access of an IAM user.
+func (IamSvc *Svc) DeleteLoginProfile(username string) error {
+	_, err := IamSvc.svc.DeleteLoginProfile(&iam.DeleteLoginProfileInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteLoginProfile: %s %w", ErrIamLoginProfileNotExists, err)
+		}
+		return fmt.Errorf("DeleteLoginProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM login profile for user '%s' deleted successfully\n", username)
+	return nil
+}
+
+// GetAccountPasswordPolicy retrieves the account password policy.
+// Returns nil if the account has no custom password policy.
+func (IamSvc *Svc) GetAccountPasswordPolicy() (*PasswordPolicy, error) {
+	output, err := IamSvc.svc.GetAccountPasswordPolicy(&iam.GetAccountPasswordPolicyInput{})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetAccountPasswordPolicy: %w", err)
+	}
+
+	policy := output.PasswordPolicy
+	return &PasswordPolicy{
+		MinimumPasswordLength:      aws.Int64Value(policy.MinimumPasswordLength),
+		RequireSymbols:             aws.BoolValue(policy.RequireSymbols),
+		RequireNumbers:             aws.BoolValue(policy.RequireNumbers),
+		RequireUppercaseCharacters: aws.BoolValue(policy.RequireUppercaseCharacters),
+		RequireLowercaseCharacters: aws.BoolValue(policy.RequireLowercaseCharacters),
+		AllowUsersToChangePassword: aws.BoolValue(policy.AllowUsersToChangePassword),
+		MaxPasswordAge:             aws.Int64Value(policy.MaxPasswordAge),
+		PasswordReusePrevention:    aws.Int64Value(policy.PasswordReusePrevention),
+		HardExpiry:                 aws.BoolValue(policy.HardExpiry),
+	}, nil
+}
+
+// UpdateAccountPasswordPolicy replaces the account password policy and returns the applied policy.
+func (IamSvc *Svc) UpdateAccountPasswordPolicy(policy PasswordPolicy) (*PasswordPolicy, error) {
+	input := &iam.UpdateAccountPasswordPolicyInput{
+		MinimumPasswordLength:      aws.Int64(policy.MinimumPasswordLength),
+		RequireSymbols:             aws.Bool(policy.RequireSymbols),
+		RequireNumbers:             aws.Bool(policy.RequireNumbers),
+		RequireUppercaseCharacters: aws.Bool(policy.RequireUppercaseCharacters),
+		RequireLowercaseCharacters: aws.Bool(policy.RequireLowercaseCharacters),
+		AllowUsersToChangePassword: aws.Bool(policy.AllowUsersToChangePassword),
+		HardExpiry:                 aws.Bool(policy.HardExpiry),
+	}
+	// AWS rejects zero for these fields, leaving them unset disables them.
+	if policy.MaxPasswordAge > 0 {
+		input.MaxPasswordAge = aws.Int64(policy.MaxPasswordAge)
+	}
+	if policy.PasswordReusePrevention > 0 {
+		input.PasswordReusePrevention = aws.Int64(policy.PasswordReusePrevention)
+	}
+
+	_, err := IamSvc.svc.UpdateAccountPasswordPolicy(input)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateAccountPasswordPolicy: %w", err)
+	}
+
+	log.Logger.Infoln("IAM account password policy updated successfully")
+
+	appliedPolicy, err := IamSvc.GetAccountPasswordPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("UpdateAccountPasswordPolicy: %w", err)
+	}
+	if appliedPolicy == nil {
+		return &policy, nil
+	}
+
+	return appliedPolicy, nil
+}
+
+// DeleteAccountPasswordPolicy removes the account password policy, reverting to the AWS default.
+func (IamSvc *Svc) DeleteAccountPasswordPolicy() error {
+	_, err := IamSvc.svc.DeleteAccountPasswordPolicy(&iam.DeleteAccountPasswordPolicyInput{})
+	if err != nil {
+		return fmt.Errorf("DeleteAccountPasswordPolicy: %w", err)
+	}
+
+	log.Logger.Infoln("IAM account password policy deleted successfully")
+	return nil
+}
@@ -0,0 +1,28 @@
+package iam_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestGenerateTemporaryPassword(t *testing.T) {
+	for _, length := range []int{0, iam.DefaultTemporaryPasswordLength, 64} {
+		password, err := iam.GenerateTemporaryPassword(length)
+		assert.NoError(t, err)
+
+		expectedLength := length
+		if expectedLength < iam.DefaultTemporaryPasswordLength {
+			expectedLength = iam.DefaultTemporaryPasswordLength
+		}
+		assert.Len(t, password, expectedLength)
+
+		assert.True(t, strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz"), "missing lowercase in %q", password)
+		assert.True(t, strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"), "missing uppercase in %q", password)
+		assert.True(t, strings.ContainsAny(password, "0123456789"), "missing number in %q", password)
+		assert.True(t, strings.ContainsAny(password, "!@#$%^&*()_+-=[]{}|"), "missing symbol in %q", password)
+	}
+}
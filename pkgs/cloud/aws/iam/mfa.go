@@ -0,0 +1,170 @@
+package iam
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/cognito"
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamMFADeviceNotExists = "error IAM MFA device does not exist"
+	ErrIamMFAInvalidCodes    = "error IAM MFA authentication codes are invalid"
+)
+
+// MFADevice represents an MFA device assigned to an IAM user.
+type MFADevice struct {
+	SerialNumber string
+	EnableDate   time.Time
+	// Virtual reports whether the device is a virtual MFA device, whose serial number is an ARN.
+	Virtual bool
+}
+
+// VirtualMFADeviceSetup holds what a user needs to register a newly created virtual MFA device in an authenticator app.
+type VirtualMFADeviceSetup struct {
+	SerialNumber string
+	// Seed is the base32 secret of the device.
+	Seed string
+	// QrCode is a base64 PNG rendering of the otpauth URI of the device.
+	QrCode string
+}
+
+// ListMFADevices lists the MFA devices assigned to an IAM user.
+func (IamSvc *Svc) ListMFADevices(username string) ([]MFADevice, error) {
+	var devices []MFADevice
+
+	err := IamSvc.svc.ListMFADevicesPages(&iam.ListMFADevicesInput{UserName: aws.String(username)},
+		func(page *iam.ListMFADevicesOutput, lastPage bool) bool {
+			for _, device := range page.MFADevices {
+				serialNumber := aws.StringValue(device.SerialNumber)
+				devices = append(devices, MFADevice{
+					SerialNumber: serialNumber,
+					EnableDate:   aws.TimeValue(device.EnableDate),
+					Virtual:      strings.HasPrefix(serialNumber, "arn:"),
+				})
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListMFADevices: %s %w", ErrIamRUserNotExists, err)
+		}
+		return nil, fmt.Errorf("ListMFADevices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// CreateVirtualMFADevice creates a virtual MFA device for an IAM user and renders its seed as a QR code.
+// The device is not usable until it is enabled with EnableMFADevice.
+func (IamSvc *Svc) CreateVirtualMFADevice(username string, deviceName string) (*VirtualMFADeviceSetup, error) {
+	if deviceName == "" {
+		deviceName = username
+	}
+
+	output, err := IamSvc.svc.CreateVirtualMFADevice(&iam.CreateVirtualMFADeviceInput{
+		VirtualMFADeviceName: aws.String(deviceName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CreateVirtualMFADevice: %w", err)
+	}
+
+	seed := string(output.VirtualMFADevice.Base32StringSeed)
+	otpAuthURI := fmt.Sprintf("otpauth://totp/Inariam:%s?secret=%s&issuer=Inariam", url.PathEscape(username), seed)
+
+	qrCode, err := cognito.GenerateQRCode(otpAuthURI, 256)
+	if err != nil {
+		return nil, fmt.Errorf("CreateVirtualMFADevice: %w", err)
+	}
+
+	log.Logger.Infof("IAM virtual MFA device '%s' created for user '%s' successfully\n", deviceName, username)
+	return &VirtualMFADeviceSetup{
+		SerialNumber: aws.StringValue(output.VirtualMFADevice.SerialNumber),
+		Seed:         seed,
+		QrCode:       qrCode,
+	}, nil
+}
+
+// EnableMFADevice assigns an MFA device to an IAM user using two consecutive codes generated by the device.
+func (IamSvc *Svc) EnableMFADevice(username string, serialNumber string, authenticationCode1 string,
+	authenticationCode2 string) error {
+	_, err := IamSvc.svc.EnableMFADevice(&iam.EnableMFADeviceInput{
+		UserName:            aws.String(username),
+		SerialNumber:        aws.String(serialNumber),
+		AuthenticationCode1: aws.String(authenticationCode1),
+		AuthenticationCode2: aws.String(authenticationCode2),
+	})
+	if err != nil {
+		if isAwsErrorCode(err, iam.ErrCodeInvalidAuthenticationCodeException) {
+			return fmt.Errorf("EnableMFADevice: %s %w", ErrIamMFAInvalidCodes, err)
+		}
+		return fmt.Errorf("EnableMFADevice: %w", err)
+	}
+
+	log.Logger.Infof("IAM MFA device '%s' enabled for user '%s' successfully\n", serialNumber, username)
+	return nil
+}
+
+// DeactivateMFADevice unassigns an MFA device from an IAM user.
+func (IamSvc *Svc) DeactivateMFADevice(username string, serialNumber string) error {
+	_, err := IamSvc.svc.DeactivateMFADevice(&iam.DeactivateMFADeviceInput{
+		UserName:     aws.String(username),
+		SerialNumber: aws.String(serialNumber),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeactivateMFADevice: %s %w", ErrIamMFADeviceNotExists, err)
+		}
+		return fmt.Errorf("DeactivateMFADevice: %w", err)
+	}
+
+	log.Logger.Infof("IAM MFA device '%s' deactivated for user '%s' successfully\n", serialNumber, username)
+	return nil
+}
+
+// DeleteVirtualMFADevice deletes a virtual MFA device, it must be deactivated first.
+func (IamSvc *Svc) DeleteVirtualMFADevice(serialNumber string) error {
+	_, err := IamSvc.svc.DeleteVirtualMFADevice(&iam.DeleteVirtualMFADeviceInput{
+		SerialNumber: aws.String(serialNumber),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteVirtualMFADevice: %s %w", ErrIamMFADeviceNotExists, err)
+		}
+		return fmt.Errorf("DeleteVirtualMFADevice: %w", err)
+	}
+
+	log.Logger.Infof("IAM virtual MFA device '%s' deleted successfully\n", serialNumber)
+	return nil
+}
+
+// RemoveVirtualMFADevice deactivates a virtual MFA device if it is assigned to the IAM user and then deletes it.
+func (IamSvc *Svc) RemoveVirtualMFADevice(username string, serialNumber string) error {
+	devices, err := IamSvc.ListMFADevices(username)
+	if err != nil {
+		return fmt.Errorf("RemoveVirtualMFADevice: %w", err)
+	}
+
+	for _, device := range devices {
+		if device.SerialNumber != serialNumber {
+			continue
+		}
+		if err = IamSvc.DeactivateMFADevice(username, serialNumber); err != nil {
+			return fmt.Errorf("RemoveVirtualMFADevice: %w", err)
+		}
+		break
+	}
+
+	if err = IamSvc.DeleteVirtualMFADevice(serialNumber); err != nil {
+		return fmt.Errorf("RemoveVirtualMFADevice: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package iam
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrPermissionsBoundaryRequired = "error a permissions boundary is required"
+	ErrPermissionsBoundaryMismatch = "error the permissions boundary does not match the required boundary"
+)
+
+// PermissionsBoundaryArn returns the ARN of an attached permissions boundary, or an empty string when none is set.
+func PermissionsBoundaryArn(boundary *iam.AttachedPermissionsBoundary) string {
+	if boundary == nil {
+		return ""
+	}
+
+	return aws.StringValue(boundary.PermissionsBoundaryArn)
+}
+
+// CheckRequiredPermissionsBoundary verifies that a principal carries the required permissions boundary.
+// An empty required boundary disables the check.
+func CheckRequiredPermissionsBoundary(required string, boundary string) error {
+	if required == "" {
+		return nil
+	}
+
+	if boundary == "" {
+		return fmt.Errorf("%s: %s", ErrPermissionsBoundaryRequired, required)
+	}
+
+	if boundary != required {
+		return fmt.Errorf("%s: %s", ErrPermissionsBoundaryMismatch, required)
+	}
+
+	return nil
+}
+
+// PutUserPermissionsBoundary sets or replaces the permissions boundary of an IAM user.
+func (IamSvc *Svc) PutUserPermissionsBoundary(username string, boundaryArn string) error {
+	_, err := IamSvc.svc.PutUserPermissionsBoundary(&iam.PutUserPermissionsBoundaryInput{
+		UserName:            aws.String(username),
+		PermissionsBoundary: aws.String(boundaryArn),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("PutUserPermissionsBoundary: %s %w", ErrIamRUserNotExists, err)
+		}
+		return fmt.Errorf("PutUserPermissionsBoundary: %w", err)
+	}
+
+	log.Logger.Infof("IAM user '%s' permissions boundary set to '%s'\n", username, boundaryArn)
+	return nil
+}
+
+// DeleteUserPermissionsBoundary removes the permissions boundary of an IAM user.
+func (IamSvc *Svc) DeleteUserPermissionsBoundary(username string) error {
+	_, err := IamSvc.svc.DeleteUserPermissionsBoundary(&iam.DeleteUserPermissionsBoundaryInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteUserPermissionsBoundary: %s %w", ErrIamRUserNotExists, err)
+		}
+		return fmt.Errorf("DeleteUserPermissionsBoundary: %w", err)
+	}
+
+	log.Logger.Infof("IAM user '%s' permissions boundary deleted\n", username)
+	return nil
+}
+
+// PutRolePermissionsBoundary sets or replaces the permissions boundary of an IAM role.
+func (IamSvc *Svc) PutRolePermissionsBoundary(roleName string, boundaryArn string) error {
+	_, err := IamSvc.svc.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+		RoleName:            aws.String(roleName),
+		PermissionsBoundary: aws.String(boundaryArn),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("PutRolePermissionsBoundary: %s %w", ErrIamRoleNotExists, err)
+		}
+		return fmt.Errorf("PutRolePermissionsBoundary: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' permissions boundary set to '%s'\n", roleName, boundaryArn)
+	return nil
+}
+
+// DeleteRolePermissionsBoundary removes the permissions boundary of an IAM role.
+func (IamSvc *Svc) DeleteRolePermissionsBoundary(roleName string) error {
+	_, err := IamSvc.svc.DeleteRolePermissionsBoundary(&iam.DeleteRolePermissionsBoundaryInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeleteRolePermissionsBoundary: %s %w", ErrIamRoleNotExists, err)
+		}
+		return fmt.Errorf("DeleteRolePermissionsBoundary: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' permissions boundary deleted\n", roleName)
+	return nil
+}
@@ -0,0 +1,42 @@
+package iam_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestCheckRequiredPermissionsBoundary(t *testing.T) {
+	const boundary = "arn:aws:iam::123456789012:policy/DelegatedAdminBoundary"
+
+	tests := []struct {
+		name     string
+		required string
+		boundary string
+		wantErr  string
+	}{
+		{name: "no rule and no boundary"},
+		{name: "no rule", boundary: "arn:aws:iam::123456789012:policy/Other"},
+		{name: "required boundary set", required: boundary, boundary: boundary},
+		{name: "missing boundary", required: boundary, wantErr: iam.ErrPermissionsBoundaryRequired},
+		{
+			name:     "other boundary",
+			required: boundary,
+			boundary: "arn:aws:iam::123456789012:policy/Other",
+			wantErr:  iam.ErrPermissionsBoundaryMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := iam.CheckRequiredPermissionsBoundary(tt.required, tt.boundary)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
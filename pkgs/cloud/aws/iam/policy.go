@@ -23,22 +23,8 @@ const (
 	ErrMarshallingPolicy = "error marshalling IAM policy failed"
 )
 
-// PolicyDocument represents the structure of an IAM policy document.
-type PolicyDocument struct {
-	Version   string
-	Statement []StatementEntry
-}
-
-// StatementEntry represents an entry in an IAM policy statement.
-type StatementEntry struct {
-	Effect   string
-	Action   string
-	Resource string
-}
-
-// GetIamPolicy retrieves an IAM policy using its ARN.
+// GetIamPolicy retrieves an IAM policy using its ARN, see PolicyArn to get the ARN of a policy from its name.
 // Returns the IAM policy or nil if it doesn't exist.
-// TODO build the complete policyARN path using the account ID
 func (IamSvc *Svc) GetIamPolicy(policyARN string) (*iam.Policy, error) {
 	getPolicyInput := &iam.GetPolicyInput{
 		PolicyArn: aws.String(policyARN),
@@ -58,12 +44,17 @@ func (IamSvc *Svc) GetIamPolicy(policyARN string) (*iam.Policy, error) {
 	return policyOutput.Policy, nil
 }
 
-// CreateIamPolicy creates a new IAM policy with the given name, description, and policy document.
+// CreateIamPolicy creates a new IAM policy with the given name, description, policy document and tags.
 // Returns the created IAM policy or an error if creation fails.
-func (IamSvc *Svc) CreateIamPolicy(policyName string, description string, policy PolicyDocument) (*iam.Policy, error) {
-	// We check with the ARB of the policy (Change the AccountID).
-	policyDetails, err := IamSvc.GetIamPolicy("arn:aws:iam::" + "782390994097:policy/" + policyName)
+func (IamSvc *Svc) CreateIamPolicy(policyName string, description string, policy PolicyDocument,
+	tags map[string]string,
+) (*iam.Policy, error) {
+	policyARN, err := IamSvc.PolicyArn(policyName)
+	if err != nil {
+		return nil, fmt.Errorf("CreateIamPolicy: %w", err)
+	}
 
+	policyDetails, err := IamSvc.GetIamPolicy(policyARN)
 	if policyDetails != nil {
 		return nil, fmt.Errorf("CreateIamPolicy: %s %w", ErrIamPolicyExists, err)
 	}
@@ -79,6 +70,7 @@ func (IamSvc *Svc) CreateIamPolicy(policyName string, description string, policy
 		PolicyDocument: aws.String(string(result)),
 		PolicyName:     aws.String(policyName),
 		Description:    aws.String(description),
+		Tags:           toIamTags(tags),
 	}
 
 	outputPolicy, err := IamSvc.svc.CreatePolicy(createPolicyInput)
@@ -138,18 +130,42 @@ func (IamSvc *Svc) DeleteIamPolicy(policyARN string) error {
 	return nil
 }
 
-// ListIamPolicies returns a list of IAM policies.
+// ListIamPolicies returns every IAM policy, AWS managed and customer managed.
 func (IamSvc *Svc) ListIamPolicies() ([]*iam.Policy, error) {
-	listPoliciesInput := &iam.ListPoliciesInput{
-		MaxItems: aws.Int64(10),
+	var policies []*iam.Policy
+
+	err := IamSvc.svc.ListPoliciesPages(&iam.ListPoliciesInput{},
+		func(page *iam.ListPoliciesOutput, lastPage bool) bool {
+			policies = append(policies, page.Policies...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ListIamPolicies: %w", err)
 	}
 
-	listPoliciesOutput, err := IamSvc.svc.ListPolicies(listPoliciesInput)
+	return policies, nil
+}
+
+// ListIamPoliciesPage lists at most maxItems IAM policies of a scope starting at marker, an empty marker starting at
+// the first policy. The scope is one of the iam.PolicyScopeType values, Local selecting the customer managed
+// policies, and every policy is listed when it is empty. It returns the marker of the next page, empty on the last
+// page.
+func (IamSvc *Svc) ListIamPoliciesPage(scope string, marker string, maxItems int64) ([]*iam.Policy, string, error) {
+	input := &iam.ListPoliciesInput{MaxItems: aws.Int64(maxItems)}
+	if scope != "" {
+		input.Scope = aws.String(scope)
+	}
+	if marker != "" {
+		input.Marker = aws.String(marker)
+	}
+
+	output, err := IamSvc.svc.ListPolicies(input)
 	if err != nil {
-		return nil, fmt.Errorf("ListIamPolicies: %w", err)
+		return nil, "", fmt.Errorf("ListIamPoliciesPage: %w", err)
 	}
 
-	return listPoliciesOutput.Policies, nil
+	return output.Policies, nextMarker(output.IsTruncated, output.Marker), nil
 }
 
 // UpdateIamPolicy updates the content of an existing IAM policy.
@@ -176,6 +192,14 @@ func (IamSvc *Svc) UpdateIamPolicy(policyARN string, newPolicy PolicyDocument) (
 	}
 
 	_, err = IamSvc.svc.CreatePolicyVersion(createPolicyVersionInput)
+	if isAwsErrorCode(err, iam.ErrCodeLimitExceededException) {
+		// AWS keeps at most 5 versions, make room for the new one and retry once. Pruning only after a refused
+		// creation keeps the history intact when the new version cannot be created anyway.
+		if err = IamSvc.pruneOldestPolicyVersion(policyARN); err != nil {
+			return nil, fmt.Errorf("UpdateIamPolicy: %w", err)
+		}
+		_, err = IamSvc.svc.CreatePolicyVersion(createPolicyVersionInput)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("UpdateIamPolicy: %w", err)
 	}
@@ -0,0 +1,207 @@
+package iam
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ErrUnsupportedConditionOperator = "error unsupported condition operator"
+)
+
+// conditionComparator compares a value of the request context with a value of the policy.
+type conditionComparator func(contextValue string, policyValue string) bool
+
+// conditionOperator describes a condition operator: how values are compared and whether the result is negated.
+type conditionOperator struct {
+	compare conditionComparator
+	negated bool
+}
+
+var conditionOperators = map[string]conditionOperator{
+	"stringequals":              {compare: compareStringEquals},
+	"stringnotequals":           {compare: compareStringEquals, negated: true},
+	"stringequalsignorecase":    {compare: compareStringEqualsIgnoreCase},
+	"stringnotequalsignorecase": {compare: compareStringEqualsIgnoreCase, negated: true},
+	"stringlike":                {compare: compareStringLike},
+	"stringnotlike":             {compare: compareStringLike, negated: true},
+	"numericequals":             {compare: compareNumeric(func(c, p float64) bool { return c == p })},
+	"numericnotequals":          {compare: compareNumeric(func(c, p float64) bool { return c == p }), negated: true},
+	"numericlessthan":           {compare: compareNumeric(func(c, p float64) bool { return c < p })},
+	"numericlessthanequals":     {compare: compareNumeric(func(c, p float64) bool { return c <= p })},
+	"numericgreaterthan":        {compare: compareNumeric(func(c, p float64) bool { return c > p })},
+	"numericgreaterthanequals":  {compare: compareNumeric(func(c, p float64) bool { return c >= p })},
+	"dateequals":                {compare: compareDate(func(c, p time.Time) bool { return c.Equal(p) })},
+	"datenotequals":             {compare: compareDate(func(c, p time.Time) bool { return c.Equal(p) }), negated: true},
+	"datelessthan":              {compare: compareDate(func(c, p time.Time) bool { return c.Before(p) })},
+	"datelessthanequals":        {compare: compareDate(func(c, p time.Time) bool { return !c.After(p) })},
+	"dategreaterthan":           {compare: compareDate(func(c, p time.Time) bool { return c.After(p) })},
+	"dategreaterthanequals":     {compare: compareDate(func(c, p time.Time) bool { return !c.Before(p) })},
+	"bool":                      {compare: compareStringEqualsIgnoreCase},
+	"binaryequals":              {compare: compareStringEquals},
+	"ipaddress":                 {compare: compareIPAddress},
+	"notipaddress":              {compare: compareIPAddress, negated: true},
+	"arnequals":                 {compare: compareStringLike},
+	"arnlike":                   {compare: compareStringLike},
+	"arnnotequals":              {compare: compareStringLike, negated: true},
+	"arnnotlike":                {compare: compareStringLike, negated: true},
+}
+
+func compareStringEquals(contextValue string, policyValue string) bool {
+	return contextValue == policyValue
+}
+
+func compareStringEqualsIgnoreCase(contextValue string, policyValue string) bool {
+	return strings.EqualFold(contextValue, policyValue)
+}
+
+func compareStringLike(contextValue string, policyValue string) bool {
+	return matchWildcard(policyValue, contextValue, false)
+}
+
+func compareNumeric(compare func(contextValue float64, policyValue float64) bool) conditionComparator {
+	return func(contextValue string, policyValue string) bool {
+		c, err := strconv.ParseFloat(contextValue, 64)
+		if err != nil {
+			return false
+		}
+		p, err := strconv.ParseFloat(policyValue, 64)
+		if err != nil {
+			return false
+		}
+		return compare(c, p)
+	}
+}
+
+// parseConditionDate parses a date condition value, written in ISO 8601 format or as an epoch timestamp.
+func parseConditionDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(epoch, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+func compareDate(compare func(contextValue time.Time, policyValue time.Time) bool) conditionComparator {
+	return func(contextValue string, policyValue string) bool {
+		c, ok := parseConditionDate(contextValue)
+		if !ok {
+			return false
+		}
+		p, ok := parseConditionDate(policyValue)
+		if !ok {
+			return false
+		}
+		return compare(c, p)
+	}
+}
+
+func compareIPAddress(contextValue string, policyValue string) bool {
+	ip := net.ParseIP(contextValue)
+	if ip == nil {
+		return false
+	}
+
+	if !strings.Contains(policyValue, "/") {
+		return ip.Equal(net.ParseIP(policyValue))
+	}
+
+	_, network, err := net.ParseCIDR(policyValue)
+	if err != nil {
+		return false
+	}
+
+	return network.Contains(ip)
+}
+
+// matches reports whether every condition of a statement is satisfied by the request.
+func (condition Condition) matches(ctx *evaluationContext) (bool, error) {
+	for operatorName, keys := range condition {
+		for key, policyValues := range keys {
+			matches, err := matchCondition(ctx, operatorName, key, policyValues.Values)
+			if err != nil {
+				return false, err
+			}
+			if !matches {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// matchCondition evaluates a single condition key of a condition operator, such as ForAnyValue:StringLikeIfExists.
+func matchCondition(ctx *evaluationContext, operatorName string, key string, policyValues []string) (bool, error) {
+	name := strings.ToLower(operatorName)
+
+	forAnyValue := strings.HasPrefix(name, "foranyvalue:")
+	forAllValues := strings.HasPrefix(name, "forallvalues:")
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "foranyvalue:"), "forallvalues:")
+
+	ifExists := strings.HasSuffix(name, "ifexists") && name != "ifexists"
+	name = strings.TrimSuffix(name, "ifexists")
+
+	contextValues, present := ctx.value(key)
+
+	if name == "null" {
+		for _, policyValue := range policyValues {
+			if strings.EqualFold(policyValue, "true") == present {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	operator, ok := conditionOperators[name]
+	if !ok {
+		return false, fmt.Errorf("%s %q", ErrUnsupportedConditionOperator, operatorName)
+	}
+
+	if !present || len(contextValues) == 0 {
+		switch {
+		case forAllValues:
+			return true, nil
+		case forAnyValue:
+			return false, nil
+		default:
+			return ifExists || operator.negated, nil
+		}
+	}
+
+	// contextValueMatches reports whether a single context value satisfies the operator for the policy values.
+	contextValueMatches := func(contextValue string) bool {
+		for _, policyValue := range policyValues {
+			if operator.compare(contextValue, policyValue) {
+				return !operator.negated
+			}
+		}
+		return operator.negated
+	}
+
+	if forAllValues {
+		for _, contextValue := range contextValues {
+			if !contextValueMatches(contextValue) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	for _, contextValue := range contextValues {
+		if contextValueMatches(contextValue) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,192 @@
+package iam
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	ErrParsingPolicy = "error parsing IAM policy document"
+)
+
+// StatementChange represents a statement, identified by its Sid, whose content differs between two documents.
+type StatementChange struct {
+	Sid    string
+	Before Statement
+	After  Statement
+}
+
+// PolicyDiff represents the semantic difference between two policy documents.
+// Statements are compared after normalisation, so reordering statements or the values
+// of a multi-value element, or writing a single value as a one-element array, is not a change.
+type PolicyDiff struct {
+	Added     []Statement
+	Removed   []Statement
+	Changed   []StatementChange
+	Unchanged int
+}
+
+// normalizedStatement is a statement in canonical form along with its JSON encoding, used as comparison key.
+type normalizedStatement struct {
+	statement Statement
+	canonical string
+}
+
+// normalizeValues returns a multi-value element as a sorted, deduplicated array.
+// Elements absent from the statement are kept absent.
+func normalizeValues(value StringOrSlice) StringOrSlice {
+	if value.Values == nil {
+		return value
+	}
+
+	values := append([]string{}, value.Values...)
+	sort.Strings(values)
+
+	deduplicated := values[:0]
+	for i, item := range values {
+		if i == 0 || item != values[i-1] {
+			deduplicated = append(deduplicated, item)
+		}
+	}
+
+	return StringOrSlice{Values: deduplicated, Array: true}
+}
+
+// normalizePrincipal rewrites the identifiers of every principal type as sorted arrays.
+func normalizePrincipal(principal *Principal) *Principal {
+	if principal == nil || principal.Wildcard {
+		return principal
+	}
+
+	normalized := &Principal{Values: make(map[string]StringOrSlice, len(principal.Values))}
+	for principalType, identifiers := range principal.Values {
+		normalized.Values[principalType] = normalizeValues(identifiers)
+	}
+
+	return normalized
+}
+
+// normalizeStatement rewrites every multi-value element of a statement as a sorted array.
+func normalizeStatement(statement Statement) Statement {
+	normalized := Statement{
+		Sid:          statement.Sid,
+		Effect:       statement.Effect,
+		Principal:    normalizePrincipal(statement.Principal),
+		NotPrincipal: normalizePrincipal(statement.NotPrincipal),
+		Action:       normalizeValues(statement.Action),
+		NotAction:    normalizeValues(statement.NotAction),
+		Resource:     normalizeValues(statement.Resource),
+		NotResource:  normalizeValues(statement.NotResource),
+	}
+
+	if statement.Condition != nil {
+		normalized.Condition = make(Condition, len(statement.Condition))
+		for operator, conditionKeys := range statement.Condition {
+			normalizedConditions := make(map[string]StringOrSlice, len(conditionKeys))
+			for conditionKey, values := range conditionKeys {
+				normalizedConditions[conditionKey] = normalizeValues(values)
+			}
+			normalized.Condition[operator] = normalizedConditions
+		}
+	}
+
+	return normalized
+}
+
+// parseStatements parses the statements of a policy document in normalised form.
+func parseStatements(document string) ([]normalizedStatement, error) {
+	policy, err := ParsePolicyDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	normalizedStatements := make([]normalizedStatement, 0, len(policy.Statement))
+	for _, statement := range policy.Statement {
+		normalized := normalizeStatement(statement)
+
+		// The JSON encoding sorts the condition maps, which makes it a canonical form.
+		canonical, err := json.Marshal(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", ErrParsingPolicy, err)
+		}
+
+		normalizedStatements = append(normalizedStatements, normalizedStatement{
+			statement: normalized,
+			canonical: string(canonical),
+		})
+	}
+
+	return normalizedStatements, nil
+}
+
+// DiffPolicyDocuments computes the semantic difference between two JSON policy documents.
+// Statements sharing a Sid are compared with each other and reported as changed when they differ,
+// the remaining statements are matched by content and reported as added or removed.
+func DiffPolicyDocuments(fromDocument string, toDocument string) (*PolicyDiff, error) {
+	fromStatements, err := parseStatements(fromDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	toStatements, err := parseStatements(toDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &PolicyDiff{}
+	fromMatched := make([]bool, len(fromStatements))
+	toMatched := make([]bool, len(toStatements))
+
+	// Statements with the same Sid are the same statement, possibly modified.
+	for i, fromStatement := range fromStatements {
+		if fromStatement.statement.Sid == "" {
+			continue
+		}
+		for j, toStatement := range toStatements {
+			if toMatched[j] || toStatement.statement.Sid != fromStatement.statement.Sid {
+				continue
+			}
+			fromMatched[i], toMatched[j] = true, true
+			if fromStatement.canonical == toStatement.canonical {
+				diff.Unchanged++
+			} else {
+				diff.Changed = append(diff.Changed, StatementChange{
+					Sid:    fromStatement.statement.Sid,
+					Before: fromStatement.statement,
+					After:  toStatement.statement,
+				})
+			}
+			break
+		}
+	}
+
+	// Anonymous statements can only be matched by content.
+	for i, fromStatement := range fromStatements {
+		if fromMatched[i] {
+			continue
+		}
+		for j, toStatement := range toStatements {
+			if toMatched[j] || toStatement.canonical != fromStatement.canonical {
+				continue
+			}
+			fromMatched[i], toMatched[j] = true, true
+			diff.Unchanged++
+			break
+		}
+	}
+
+	for i, fromStatement := range fromStatements {
+		if !fromMatched[i] {
+			diff.Removed = append(diff.Removed, fromStatement.statement)
+		}
+	}
+
+	for j, toStatement := range toStatements {
+		if !toMatched[j] {
+			diff.Added = append(diff.Added, toStatement.statement)
+		}
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,95 @@
+package iam_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestDiffPolicyDocuments(t *testing.T) {
+	tests := []struct {
+		name          string
+		from          string
+		to            string
+		wantAdded     int
+		wantRemoved   int
+		wantChanged   []string
+		wantUnchanged int
+	}{
+		{
+			name:          "identical documents",
+			from:          `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			to:            `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			wantUnchanged: 1,
+		},
+		{
+			name:          "single statement object equals one element array",
+			from:          `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}}`,
+			to:            `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}]}`,
+			wantUnchanged: 1,
+		},
+		{
+			name: "reordered statements and values",
+			from: `{"Statement":[
+				{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"},
+				{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`,
+			to: `{"Statement":[
+				{"Effect":"Deny","Action":"iam:*","Resource":"*"},
+				{"Effect":"Allow","Action":["s3:PutObject","s3:GetObject"],"Resource":"*"}]}`,
+			wantUnchanged: 2,
+		},
+		{
+			name:          "statement added",
+			from:          `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			to:            `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Effect":"Allow","Action":"ec2:*","Resource":"*"}]}`,
+			wantAdded:     1,
+			wantUnchanged: 1,
+		},
+		{
+			name:        "anonymous statement modified is removed and added",
+			from:        `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			to:          `{"Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"*"}]}`,
+			wantAdded:   1,
+			wantRemoved: 1,
+		},
+		{
+			name: "statement with sid modified",
+			from: `{"Statement":[{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			to: `{"Statement":[{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"*",
+				"Condition":{"Bool":{"aws:SecureTransport":"true"}}}]}`,
+			wantChanged: []string{"Read"},
+		},
+		{
+			name: "condition values reordered",
+			from: `{"Statement":[{"Sid":"Net","Effect":"Deny","Action":"*","Resource":"*",
+				"Condition":{"NotIpAddress":{"aws:SourceIp":["10.0.0.0/8","192.168.0.0/16"]}}}]}`,
+			to: `{"Statement":[{"Sid":"Net","Effect":"Deny","Action":"*","Resource":"*",
+				"Condition":{"NotIpAddress":{"aws:SourceIp":["192.168.0.0/16","10.0.0.0/8"]}}}]}`,
+			wantUnchanged: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := iam.DiffPolicyDocuments(tt.from, tt.to)
+			assert.NoError(t, err)
+
+			assert.Len(t, diff.Added, tt.wantAdded)
+			assert.Len(t, diff.Removed, tt.wantRemoved)
+			assert.Equal(t, tt.wantUnchanged, diff.Unchanged)
+
+			var changedSids []string
+			for _, change := range diff.Changed {
+				changedSids = append(changedSids, change.Sid)
+			}
+			assert.Equal(t, tt.wantChanged, changedSids)
+		})
+	}
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := iam.DiffPolicyDocuments(`{`, `{}`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,377 @@
+package iam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	ErrInvalidPolicy = "error invalid IAM policy document"
+)
+
+// Policy effects.
+const (
+	EffectAllow = "Allow"
+	EffectDeny  = "Deny"
+)
+
+// DefaultPolicyVersion is the current version of the IAM policy language.
+const DefaultPolicyVersion = "2012-10-17"
+
+// StringOrSlice is a policy value that the IAM grammar accepts either as a single value or as an array of values.
+// It remembers how it was written so that documents are re-encoded the way they were read.
+type StringOrSlice struct {
+	Values []string
+	// Array reports whether the value is encoded as a JSON array even when it holds a single element.
+	Array bool
+	// literals records which values were written as bare JSON booleans or numbers, as condition values may be.
+	literals []bool
+}
+
+// NewStringOrSlice returns a StringOrSlice holding the given values.
+func NewStringOrSlice(values ...string) StringOrSlice {
+	return StringOrSlice{Values: values, Array: len(values) != 1}
+}
+
+// IsEmpty reports whether the value holds no element.
+func (value StringOrSlice) IsEmpty() bool {
+	return len(value.Values) == 0
+}
+
+// UnmarshalJSON decodes a string, a boolean, a number or an array of those.
+func (value *StringOrSlice) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	*value = StringOrSlice{}
+
+	var items []json.RawMessage
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		value.Array = true
+		value.Values = []string{}
+	} else {
+		items = []json.RawMessage{data}
+	}
+
+	for _, item := range items {
+		var str string
+		if err := json.Unmarshal(item, &str); err == nil {
+			value.Values = append(value.Values, str)
+			value.literals = append(value.literals, false)
+			continue
+		}
+
+		var literal interface{}
+		if err := json.Unmarshal(item, &literal); err != nil {
+			return err
+		}
+		switch literal.(type) {
+		case bool, float64:
+			value.Values = append(value.Values, string(bytes.TrimSpace(item)))
+			value.literals = append(value.literals, true)
+		default:
+			return fmt.Errorf("%s: expected a string or an array of strings, got %s", ErrInvalidPolicy, item)
+		}
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the value as a single value or as an array, depending on how it was read.
+func (value StringOrSlice) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(value.Values))
+	for i, item := range value.Values {
+		if i < len(value.literals) && value.literals[i] && json.Valid([]byte(item)) {
+			items = append(items, json.RawMessage(item))
+			continue
+		}
+		encoded, err := marshalJSON(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, encoded)
+	}
+
+	if len(items) == 1 && !value.Array {
+		return items[0], nil
+	}
+
+	return marshalJSON(items)
+}
+
+// Principal is the Principal or NotPrincipal element of a statement, either the "*" wildcard
+// or a map from principal type (AWS, Service, Federated, CanonicalUser) to identifiers.
+type Principal struct {
+	Wildcard bool
+	Values   map[string]StringOrSlice
+}
+
+// UnmarshalJSON decodes "*" or an object of principal types.
+func (principal *Principal) UnmarshalJSON(data []byte) error {
+	*principal = Principal{}
+
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		if wildcard != "*" {
+			return fmt.Errorf("%s: principal must be \"*\" or an object, got %q", ErrInvalidPolicy, wildcard)
+		}
+		principal.Wildcard = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &principal.Values)
+}
+
+// MarshalJSON encodes the principal as "*" or as an object of principal types.
+func (principal Principal) MarshalJSON() ([]byte, error) {
+	if principal.Wildcard {
+		return marshalJSON("*")
+	}
+
+	return marshalJSON(principal.Values)
+}
+
+// Condition maps condition operators, such as StringEquals, to condition keys and their values.
+type Condition map[string]map[string]StringOrSlice
+
+// Statement is a statement of an IAM policy document.
+type Statement struct {
+	Sid          string
+	Effect       string
+	Principal    *Principal
+	NotPrincipal *Principal
+	Action       StringOrSlice
+	NotAction    StringOrSlice
+	Resource     StringOrSlice
+	NotResource  StringOrSlice
+	Condition    Condition
+}
+
+// statementJSON is the wire representation of a Statement, the field order matches the AWS documentation.
+type statementJSON struct {
+	Sid          string         `json:"Sid,omitempty"`
+	Effect       string         `json:"Effect"`
+	Principal    *Principal     `json:"Principal,omitempty"`
+	NotPrincipal *Principal     `json:"NotPrincipal,omitempty"`
+	Action       *StringOrSlice `json:"Action,omitempty"`
+	NotAction    *StringOrSlice `json:"NotAction,omitempty"`
+	Resource     *StringOrSlice `json:"Resource,omitempty"`
+	NotResource  *StringOrSlice `json:"NotResource,omitempty"`
+	Condition    Condition      `json:"Condition,omitempty"`
+}
+
+// optionalStringOrSlice returns nil for values that must be omitted from the encoded statement.
+func optionalStringOrSlice(value StringOrSlice) *StringOrSlice {
+	if value.Values == nil {
+		return nil
+	}
+
+	return &value
+}
+
+// UnmarshalJSON decodes a statement.
+func (statement *Statement) UnmarshalJSON(data []byte) error {
+	var wire statementJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*statement = Statement{
+		Sid:          wire.Sid,
+		Effect:       wire.Effect,
+		Principal:    wire.Principal,
+		NotPrincipal: wire.NotPrincipal,
+		Condition:    wire.Condition,
+	}
+	if wire.Action != nil {
+		statement.Action = *wire.Action
+	}
+	if wire.NotAction != nil {
+		statement.NotAction = *wire.NotAction
+	}
+	if wire.Resource != nil {
+		statement.Resource = *wire.Resource
+	}
+	if wire.NotResource != nil {
+		statement.NotResource = *wire.NotResource
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes a statement, omitting the elements it does not use.
+func (statement Statement) MarshalJSON() ([]byte, error) {
+	return marshalJSON(statementJSON{
+		Sid:          statement.Sid,
+		Effect:       statement.Effect,
+		Principal:    statement.Principal,
+		NotPrincipal: statement.NotPrincipal,
+		Action:       optionalStringOrSlice(statement.Action),
+		NotAction:    optionalStringOrSlice(statement.NotAction),
+		Resource:     optionalStringOrSlice(statement.Resource),
+		NotResource:  optionalStringOrSlice(statement.NotResource),
+		Condition:    statement.Condition,
+	})
+}
+
+// Validate checks the statement against the IAM policy grammar.
+func (statement *Statement) Validate() error {
+	if statement.Effect != EffectAllow && statement.Effect != EffectDeny {
+		return fmt.Errorf("%s: Effect must be %q or %q, got %q", ErrInvalidPolicy, EffectAllow, EffectDeny, statement.Effect)
+	}
+
+	if statement.Action.IsEmpty() == statement.NotAction.IsEmpty() {
+		return fmt.Errorf("%s: exactly one of Action or NotAction is required", ErrInvalidPolicy)
+	}
+
+	if !statement.Resource.IsEmpty() && !statement.NotResource.IsEmpty() {
+		return fmt.Errorf("%s: Resource and NotResource cannot be used together", ErrInvalidPolicy)
+	}
+
+	if statement.Principal != nil && statement.NotPrincipal != nil {
+		return fmt.Errorf("%s: Principal and NotPrincipal cannot be used together", ErrInvalidPolicy)
+	}
+
+	return nil
+}
+
+// PolicyDocument is an IAM policy document, identity-based, resource-based or trust policy.
+type PolicyDocument struct {
+	Version   string
+	Id        string
+	Statement []Statement
+	// SingleStatement reports whether Statement is encoded as a single object rather than an array.
+	SingleStatement bool
+}
+
+// policyDocumentJSON is the wire representation of a PolicyDocument.
+type policyDocumentJSON struct {
+	Version   string          `json:"Version,omitempty"`
+	Id        string          `json:"Id,omitempty"`
+	Statement json.RawMessage `json:"Statement"`
+}
+
+// UnmarshalJSON decodes a policy document whose Statement is a single object or an array of objects.
+func (policy *PolicyDocument) UnmarshalJSON(data []byte) error {
+	var wire policyDocumentJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*policy = PolicyDocument{
+		Version: wire.Version,
+		Id:      wire.Id,
+	}
+
+	statement := bytes.TrimSpace(wire.Statement)
+	switch {
+	case len(statement) == 0 || bytes.Equal(statement, []byte("null")):
+		return nil
+	case statement[0] == '{':
+		var single Statement
+		if err := json.Unmarshal(statement, &single); err != nil {
+			return err
+		}
+		policy.Statement = []Statement{single}
+		policy.SingleStatement = true
+		return nil
+	default:
+		return json.Unmarshal(statement, &policy.Statement)
+	}
+}
+
+// MarshalJSON encodes a policy document.
+func (policy PolicyDocument) MarshalJSON() ([]byte, error) {
+	var statement interface{} = policy.Statement
+	if policy.SingleStatement && len(policy.Statement) == 1 {
+		statement = policy.Statement[0]
+	} else if policy.Statement == nil {
+		statement = []Statement{}
+	}
+
+	encodedStatement, err := marshalJSON(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(policyDocumentJSON{
+		Version:   policy.Version,
+		Id:        policy.Id,
+		Statement: encodedStatement,
+	})
+}
+
+// Validate checks the policy document against the IAM policy grammar.
+func (policy *PolicyDocument) Validate() error {
+	if len(policy.Statement) == 0 {
+		return errors.New(ErrInvalidPolicy + ": at least one statement is required")
+	}
+
+	for i := range policy.Statement {
+		if err := policy.Statement[i].Validate(); err != nil {
+			return fmt.Errorf("Statement[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// String returns the JSON encoding of the policy document, its length being the size IAM counts against its quotas.
+func (policy PolicyDocument) String() string {
+	encoded, err := marshalJSON(policy)
+	if err != nil {
+		return ""
+	}
+
+	return string(encoded)
+}
+
+// marshalJSON encodes v like json.Marshal but without escaping <, > and & as \u003c, \u003e and \u0026, which
+// would make a policy look longer than IAM counts it.
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// decodePolicyDocument URL-decodes a policy document returned by the IAM API.
+// Documents that are already plain JSON are returned unchanged.
+func decodePolicyDocument(document string) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(document), "{") {
+		return document, nil
+	}
+
+	// PathUnescape keeps '+' as is, the IAM API encodes spaces as %20.
+	decoded, err := url.PathUnescape(document)
+	if err != nil {
+		return "", fmt.Errorf("%s %w", ErrDecodingPolicy, err)
+	}
+
+	return decoded, nil
+}
+
+// ParsePolicyDocument parses a JSON policy document, URL-decoding it first if it comes URL-encoded from the IAM API.
+func ParsePolicyDocument(document string) (*PolicyDocument, error) {
+	decoded, err := decodePolicyDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy PolicyDocument
+	if err = json.Unmarshal([]byte(decoded), &policy); err != nil {
+		return nil, fmt.Errorf("%s %w", ErrParsingPolicy, err)
+	}
+
+	return &policy, nil
+}
@@ -0,0 +1,154 @@
+package iam_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestPolicyDocumentRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{
+			name:     "single statement object with string values",
+			document: `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}}`,
+		},
+		{
+			name: "statement array with single element arrays",
+			document: `{"Version":"2012-10-17","Id":"S3Policy","Statement":[{"Sid":"List","Effect":"Allow",
+				"Action":["s3:ListBucket"],"Resource":["arn:aws:s3:::bucket"]}]}`,
+		},
+		{
+			name: "not action and not resource",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","NotAction":["iam:*","sts:*"],
+				"NotResource":"arn:aws:s3:::bucket/*"}]}`,
+		},
+		{
+			name: "trust policy with principals",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com",
+				"AWS":["arn:aws:iam::123456789012:root","arn:aws:iam::210987654321:role/ci"]},"Action":"sts:AssumeRole"}]}`,
+		},
+		{
+			name: "wildcard principal and not principal",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject",
+				"Resource":"arn:aws:s3:::public/*"},{"Effect":"Deny","NotPrincipal":{"AWS":"arn:aws:iam::123456789012:root"},
+				"Action":"s3:*","Resource":"*"}]}`,
+		},
+		{
+			name: "conditions with string, boolean and number values",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*",
+				"Condition":{"Bool":{"aws:SecureTransport":false},"NumericLessThan":{"s3:max-keys":[10,"20"]},
+				"StringEquals":{"aws:PrincipalTag/team":["a","b"],"aws:RequestedRegion":"eu-west-1"},
+				"ForAnyValue:StringLike":{"aws:TagKeys":["owner*"]},"Null":{"aws:TokenIssueTime":"true"}}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := iam.ParsePolicyDocument(tt.document)
+			assert.NoError(t, err)
+			assert.NoError(t, policy.Validate())
+
+			encoded, err := json.Marshal(policy)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.document, string(encoded))
+		})
+	}
+}
+
+func TestParsePolicyDocumentURLEncoded(t *testing.T) {
+	document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject",` +
+		`"Resource":"arn:aws:s3:::my bucket/a+b/*"}]}`
+
+	policy, err := iam.ParsePolicyDocument(strings.ReplaceAll(url.QueryEscape(document), "+", "%20"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"arn:aws:s3:::my bucket/a+b/*"}, policy.Statement[0].Resource.Values)
+	assert.JSONEq(t, document, policy.String())
+
+	_, err = iam.ParsePolicyDocument(`%7B%ZZ`)
+	assert.ErrorContains(t, err, iam.ErrDecodingPolicy)
+
+	_, err = iam.ParsePolicyDocument(`{"Statement":[{"Effect":"Allow","Action":{"s3":"*"}}]}`)
+	assert.ErrorContains(t, err, iam.ErrParsingPolicy)
+}
+
+func TestPolicyDocumentBuiltInCode(t *testing.T) {
+	policy := iam.PolicyDocument{
+		Version: iam.DefaultPolicyVersion,
+		Statement: []iam.Statement{
+			{
+				Effect:   iam.EffectAllow,
+				Action:   iam.NewStringOrSlice("s3:GetObject", "s3:PutObject"),
+				Resource: iam.NewStringOrSlice("*"),
+			},
+		},
+	}
+
+	assert.JSONEq(t,
+		`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"}]}`,
+		policy.String())
+}
+
+func TestPolicyDocumentValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{
+			name:     "valid identity policy",
+			document: `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+		},
+		{
+			name:     "no statement",
+			document: `{"Version":"2012-10-17"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid effect",
+			document: `{"Statement":[{"Effect":"allow","Action":"s3:*","Resource":"*"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "missing action",
+			document: `{"Statement":[{"Effect":"Allow","Resource":"*"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "action and not action",
+			document: `{"Statement":[{"Effect":"Allow","Action":"s3:*","NotAction":"iam:*","Resource":"*"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "resource and not resource",
+			document: `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*","NotResource":"arn:aws:s3:::b"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "principal and not principal",
+			document: `{"Statement":[{"Effect":"Allow","Principal":"*","NotPrincipal":"*","Action":"sts:AssumeRole"}]}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := iam.ParsePolicyDocument(tt.document)
+			assert.NoError(t, err)
+
+			err = policy.Validate()
+			if tt.wantErr {
+				assert.ErrorContains(t, err, iam.ErrInvalidPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	ErrMissingEvaluationAction = "error an action is required to evaluate policies"
+)
+
+// EvaluationDecision is the outcome of a policy evaluation, the values match the ones returned by the IAM policy simulator.
+type EvaluationDecision string
+
+const (
+	DecisionAllowed      EvaluationDecision = "allowed"
+	DecisionExplicitDeny EvaluationDecision = "explicitDeny"
+	DecisionImplicitDeny EvaluationDecision = "implicitDeny"
+)
+
+// PolicyType identifies where a policy evaluated for a request comes from.
+type PolicyType string
+
+const (
+	PolicyTypeIdentity            PolicyType = "identity"
+	PolicyTypeResource            PolicyType = "resource"
+	PolicyTypePermissionsBoundary PolicyType = "permissionsBoundary"
+	PolicyTypeServiceControl      PolicyType = "serviceControl"
+)
+
+// EvaluationRequest describes a request made by a principal: the action, the resource and the request context.
+type EvaluationRequest struct {
+	// Principal is the ARN of the calling principal, it is matched against the Principal element of resource policies.
+	Principal string
+	Action    string
+	// Resource is the ARN of the resource, it may be empty for actions that do not support resource-level permissions.
+	Resource string
+	// Context holds the condition keys of the request, such as aws:SourceIp or aws:username, and their values.
+	Context map[string][]string
+}
+
+// PolicySet holds every policy that applies to a request.
+type PolicySet struct {
+	IdentityPolicies    []PolicyDocument
+	ResourcePolicies    []PolicyDocument
+	PermissionsBoundary *PolicyDocument
+	// ServiceControlPolicies holds the SCPs attached at each level of the organization, from the root to the account.
+	// The SCPs of a level are combined, and every level must allow the request.
+	ServiceControlPolicies [][]PolicyDocument
+}
+
+// MatchedStatement identifies a statement that applies to the evaluated request.
+type MatchedStatement struct {
+	PolicyType     PolicyType
+	PolicyIndex    int
+	StatementIndex int
+	Sid            string
+	Effect         string
+}
+
+// EvaluationResult is the outcome of the evaluation of a request against a policy set.
+type EvaluationResult struct {
+	Decision EvaluationDecision
+	// Reason explains an implicit deny, it is empty when the request is allowed or explicitly denied.
+	Reason            string
+	MatchedStatements []MatchedStatement
+}
+
+// principalMatch tells how the Principal element of a resource policy statement designates the calling principal.
+type principalMatch int
+
+const (
+	principalNotMatched principalMatch = iota
+	// principalMatched means the statement names the principal, or every principal with "*".
+	principalMatched
+	// principalAccountMatched means the statement names the account of the principal. This delegates the access to
+	// the account, whose identity policies must still allow the request.
+	principalAccountMatched
+)
+
+// evaluationContext is an EvaluationRequest prepared for matching, condition keys are case-insensitive.
+type evaluationContext struct {
+	request EvaluationRequest
+	keys    map[string][]string
+}
+
+func newEvaluationContext(request EvaluationRequest) *evaluationContext {
+	keys := make(map[string][]string, len(request.Context)+1)
+	for key, values := range request.Context {
+		keys[strings.ToLower(key)] = values
+	}
+
+	if _, ok := keys["aws:principalarn"]; !ok && request.Principal != "" {
+		keys["aws:principalarn"] = []string{request.Principal}
+	}
+
+	return &evaluationContext{request: request, keys: keys}
+}
+
+// value returns the values of a condition key and whether the key is present in the request.
+func (ctx *evaluationContext) value(key string) ([]string, bool) {
+	values, ok := ctx.keys[strings.ToLower(key)]
+	return values, ok
+}
+
+// EvaluatePolicies evaluates a request against a policy set following the AWS policy evaluation logic:
+// an explicit deny in any policy wins, then the request must be allowed by every level of SCPs and by either
+// an identity policy or a resource policy. An identity policy allow is capped by the permissions boundary,
+// whereas a resource policy naming the principal grants access on its own. A resource policy naming the account of
+// the principal only delegates the access to the account, an identity policy must allow the request as well.
+func EvaluatePolicies(request EvaluationRequest, policies PolicySet) (*EvaluationResult, error) {
+	if request.Action == "" {
+		return nil, errors.New(ErrMissingEvaluationAction)
+	}
+
+	ctx := newEvaluationContext(request)
+	result := &EvaluationResult{Decision: DecisionImplicitDeny}
+
+	identityAllowed, _, identityDenied, err := evaluatePolicyList(ctx, PolicyTypeIdentity, policies.IdentityPolicies,
+		false, result)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceAllowed, resourceDelegated, resourceDenied, err := evaluatePolicyList(ctx, PolicyTypeResource,
+		policies.ResourcePolicies, true, result)
+	if err != nil {
+		return nil, err
+	}
+
+	boundaryAllowed, boundaryDenied := true, false
+	if policies.PermissionsBoundary != nil {
+		boundaryAllowed, _, boundaryDenied, err = evaluatePolicyList(ctx, PolicyTypePermissionsBoundary,
+			[]PolicyDocument{*policies.PermissionsBoundary}, false, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scpAllowed, scpDenied := true, false
+	for level, levelPolicies := range policies.ServiceControlPolicies {
+		allowed, _, denied, err := evaluatePolicyList(ctx, PolicyTypeServiceControl, levelPolicies, false, result)
+		if err != nil {
+			return nil, fmt.Errorf("service control policies level %d: %w", level, err)
+		}
+		scpAllowed = scpAllowed && allowed
+		scpDenied = scpDenied || denied
+	}
+
+	switch {
+	case identityDenied || resourceDenied || boundaryDenied || scpDenied:
+		result.Decision = DecisionExplicitDeny
+	case !scpAllowed:
+		result.Reason = "the request is not allowed by the service control policies"
+	case !identityAllowed && !resourceAllowed && resourceDelegated:
+		result.Reason = "the resource policy only grants access to the account, no identity policy allows the request"
+	case !identityAllowed && !resourceAllowed:
+		result.Reason = "no identity or resource policy allows the request"
+	case !boundaryAllowed && !resourceAllowed:
+		result.Reason = "the request is not allowed by the permissions boundary"
+	default:
+		result.Decision = DecisionAllowed
+	}
+
+	return result, nil
+}
+
+// evaluatePolicyList evaluates a request against a list of policies of the same type, it reports whether a statement
+// allows the request, whether a statement only allows it by naming the account of the principal, and whether a
+// statement denies it, and records the matching statements in the result.
+func evaluatePolicyList(ctx *evaluationContext, policyType PolicyType, policies []PolicyDocument,
+	matchPrincipal bool, result *EvaluationResult,
+) (allowed bool, delegated bool, denied bool, err error) {
+	for policyIndex := range policies {
+		for statementIndex, statement := range policies[policyIndex].Statement {
+			principal := principalMatched
+			if matchPrincipal {
+				principal = statement.principalMatch(ctx.request.Principal)
+				if principal == principalNotMatched {
+					continue
+				}
+			}
+
+			matches, err := statement.matches(ctx)
+			if err != nil {
+				return false, false, false, fmt.Errorf("%s policy %d statement %d: %w", policyType, policyIndex,
+					statementIndex, err)
+			}
+			if !matches {
+				continue
+			}
+
+			result.MatchedStatements = append(result.MatchedStatements, MatchedStatement{
+				PolicyType:     policyType,
+				PolicyIndex:    policyIndex,
+				StatementIndex: statementIndex,
+				Sid:            statement.Sid,
+				Effect:         statement.Effect,
+			})
+
+			switch {
+			case statement.Effect == EffectDeny:
+				// A deny naming the account applies to every principal of the account.
+				denied = true
+			case principal == principalAccountMatched:
+				delegated = true
+			default:
+				allowed = true
+			}
+		}
+	}
+
+	return allowed, delegated, denied, nil
+}
+
+// principalMatch tells how the Principal or NotPrincipal element of a resource policy statement designates the
+// principal. A NotPrincipal element designates every principal it does not exclude.
+func (statement *Statement) principalMatch(principalARN string) principalMatch {
+	switch {
+	case statement.Principal != nil:
+		return statement.Principal.match(principalARN)
+	case statement.NotPrincipal != nil:
+		if statement.NotPrincipal.match(principalARN) != principalNotMatched {
+			return principalNotMatched
+		}
+		return principalMatched
+	}
+
+	return principalNotMatched
+}
+
+// matches reports whether the action, resource and conditions of the statement apply to the request.
+func (statement *Statement) matches(ctx *evaluationContext) (bool, error) {
+	switch {
+	case !statement.Action.IsEmpty():
+		if !matchesAnyAction(statement.Action.Values, ctx.request.Action) {
+			return false, nil
+		}
+	case !statement.NotAction.IsEmpty():
+		if matchesAnyAction(statement.NotAction.Values, ctx.request.Action) {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+
+	switch {
+	case !statement.Resource.IsEmpty():
+		if !matchesAnyResource(ctx, statement.Resource.Values, ctx.request.Resource) {
+			return false, nil
+		}
+	case !statement.NotResource.IsEmpty():
+		if matchesAnyResource(ctx, statement.NotResource.Values, ctx.request.Resource) {
+			return false, nil
+		}
+	}
+
+	return statement.Condition.matches(ctx)
+}
+
+// match tells how the principal element designates the given principal ARN. Principal ARNs are compared as is,
+// "*" being the only wildcard AWS accepts in a principal. An account ID or an account root ARN designates the
+// account of the principal.
+func (principal *Principal) match(principalARN string) principalMatch {
+	if principal.Wildcard {
+		return principalMatched
+	}
+
+	match := principalNotMatched
+	for _, values := range principal.Values {
+		for _, value := range values.Values {
+			if value == "*" || value == principalARN {
+				return principalMatched
+			}
+
+			account := value
+			if strings.HasSuffix(value, ":root") {
+				account = accountOfARN(value)
+			}
+			if account != "" && accountOfARN(principalARN) == account {
+				match = principalAccountMatched
+			}
+		}
+	}
+
+	return match
+}
+
+// accountOfARN returns the account ID segment of an ARN.
+func accountOfARN(arn string) string {
+	segments := strings.SplitN(arn, ":", 6)
+	if len(segments) < 6 {
+		return ""
+	}
+
+	return segments[4]
+}
+
+// matchesAnyAction reports whether an action matches one of the action patterns, actions are case-insensitive.
+func matchesAnyAction(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if matchWildcard(pattern, action, true) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAnyResource reports whether a resource ARN matches one of the resource patterns, after substituting
+// policy variables such as ${aws:username}. A pattern referencing a variable absent from the request never matches.
+func matchesAnyResource(ctx *evaluationContext, patterns []string, resource string) bool {
+	for _, pattern := range patterns {
+		substituted, ok := substitutePolicyVariables(ctx, pattern)
+		if ok && matchWildcard(substituted, resource, false) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// substitutePolicyVariables replaces the ${key} policy variables of a value with the values of the request context.
+// The ${*}, ${?} and ${$} variables stand for the literal characters.
+func substitutePolicyVariables(ctx *evaluationContext, value string) (string, bool) {
+	var builder strings.Builder
+
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			builder.WriteString(value)
+			return builder.String(), true
+		}
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			builder.WriteString(value)
+			return builder.String(), true
+		}
+		end += start
+
+		builder.WriteString(value[:start])
+		variable := value[start+2 : end]
+		switch variable {
+		case "*", "?", "$":
+			// Escaped characters are matched literally, the wildcard matcher has no escaping so keep them as is.
+			builder.WriteString(variable)
+		default:
+			// A default value may be given as ${key, 'default'}.
+			key, defaultValue, hasDefault := strings.Cut(variable, ",")
+			values, ok := ctx.value(strings.TrimSpace(key))
+			switch {
+			case ok && len(values) == 1:
+				builder.WriteString(values[0])
+			case hasDefault:
+				builder.WriteString(strings.Trim(strings.TrimSpace(defaultValue), "'"))
+			default:
+				return "", false
+			}
+		}
+		value = value[end+1:]
+	}
+}
+
+// matchWildcard reports whether a value matches a pattern where '*' matches any sequence of characters
+// and '?' matches any single character.
+func matchWildcard(pattern string, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		pattern, value = strings.ToLower(pattern), strings.ToLower(value)
+	}
+
+	p, v := 0, 0
+	starPattern, starValue := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			starPattern, starValue = p, v
+			p++
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case starPattern >= 0:
+			starValue++
+			p, v = starPattern+1, starValue
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
@@ -0,0 +1,408 @@
+package iam_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func mustParsePolicy(t *testing.T, document string) iam.PolicyDocument {
+	t.Helper()
+
+	policy, err := iam.ParsePolicyDocument(document)
+	if err != nil {
+		t.Fatalf("parsing policy: %v", err)
+	}
+
+	return *policy
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	const (
+		userARN   = "arn:aws:iam::123456789012:user/alice"
+		bucketARN = "arn:aws:s3:::reports/2023/q1.csv"
+	)
+
+	allowS3Read := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:Get*","s3:List*"],"Resource":"arn:aws:s3:::reports/*"}]}`
+	allowAll := `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"*","Resource":"*"}}`
+
+	tests := []struct {
+		name         string
+		request      iam.EvaluationRequest
+		policies     func(t *testing.T) iam.PolicySet
+		wantDecision iam.EvaluationDecision
+	}{
+		{
+			name:    "no policy is an implicit deny",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "wildcard action and resource allow",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "S3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowS3Read)}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "action outside of the allowed ones",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:PutObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowS3Read)}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "resource outside of the allowed ones",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: "arn:aws:s3:::secrets/key"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowS3Read)}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "explicit deny overrides allow",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, allowAll),
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::reports/2023/*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionExplicitDeny,
+		},
+		{
+			name:    "not action allow",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "ec2:RunInstances", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "not action excludes the action",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "iam:CreateUser", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "not resource deny spares the listed resource",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, allowAll),
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Deny","Action":"s3:*","NotResource":"arn:aws:s3:::reports/*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name: "policy variable in resource",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:PutObject", Resource: "arn:aws:s3:::home/alice/notes",
+				Context: map[string][]string{"aws:username": {"alice"}}},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::home/${aws:username}/*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name: "condition satisfied",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN,
+				Context: map[string][]string{"aws:SourceIp": {"10.1.2.3"}, "aws:SecureTransport": {"true"}}},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*",
+						"Condition":{"IpAddress":{"aws:SourceIp":["10.0.0.0/8"]},"Bool":{"aws:SecureTransport":true}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name: "condition not satisfied",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN,
+				Context: map[string][]string{"aws:SourceIp": {"192.168.1.1"}}},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*",
+						"Condition":{"IpAddress":{"aws:SourceIp":"10.0.0.0/8"}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "negated condition matches when the key is absent",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, allowAll),
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Deny","Action":"*","Resource":"*",
+						"Condition":{"StringNotEquals":{"aws:RequestedRegion":"eu-west-1"}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionExplicitDeny,
+		},
+		{
+			name:    "if exists condition matches when the key is absent",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "ec2:RunInstances", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"ec2:*","Resource":"*",
+						"Condition":{"StringLikeIfExists":{"ec2:InstanceType":"t3.*"}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name: "for all values condition",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "ec2:CreateTags", Resource: "*",
+				Context: map[string][]string{"aws:TagKeys": {"owner", "secret"}}},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"ec2:CreateTags","Resource":"*",
+						"Condition":{"ForAllValues:StringEquals":{"aws:TagKeys":["owner","team"]}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name: "numeric and date conditions",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:ListBucket", Resource: "arn:aws:s3:::reports",
+				Context: map[string][]string{"s3:max-keys": {"5"}, "aws:CurrentTime": {"2023-06-01T12:00:00Z"}}},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::reports",
+						"Condition":{"NumericLessThanEquals":{"s3:max-keys":10},"DateLessThan":{"aws:CurrentTime":"2024-01-01T00:00:00Z"}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "null condition",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*",
+						"Condition":{"Null":{"aws:MultiFactorAuthAge":"false"}}}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "permissions boundary caps identity policies",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "iam:CreateUser", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				boundary := mustParsePolicy(t, allowS3Read)
+				return iam.PolicySet{
+					IdentityPolicies:    []iam.PolicyDocument{mustParsePolicy(t, allowAll)},
+					PermissionsBoundary: &boundary,
+				}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "permissions boundary and identity policy both allow",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				boundary := mustParsePolicy(t, allowS3Read)
+				return iam.PolicySet{
+					IdentityPolicies:    []iam.PolicyDocument{mustParsePolicy(t, allowAll)},
+					PermissionsBoundary: &boundary,
+				}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "permissions boundary alone grants nothing",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				boundary := mustParsePolicy(t, allowAll)
+				return iam.PolicySet{PermissionsBoundary: &boundary}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "every SCP level must allow",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "ec2:RunInstances", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{
+					IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowAll)},
+					ServiceControlPolicies: [][]iam.PolicyDocument{
+						{mustParsePolicy(t, allowAll)},
+						{mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`)},
+					},
+				}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "SCPs of a level are combined",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "ec2:RunInstances", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{
+					IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowAll)},
+					ServiceControlPolicies: [][]iam.PolicyDocument{
+						{mustParsePolicy(t, allowAll)},
+						{
+							mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`),
+							mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"ec2:*","Resource":"*"}]}`),
+						},
+					},
+				}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "SCP explicit deny",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "organizations:LeaveOrganization", Resource: "*"},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{
+					IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowAll)},
+					ServiceControlPolicies: [][]iam.PolicyDocument{{
+						mustParsePolicy(t, allowAll),
+						mustParsePolicy(t, `{"Statement":[{"Effect":"Deny","Action":"organizations:LeaveOrganization","Resource":"*"}]}`),
+					}},
+				}
+			},
+			wantDecision: iam.DecisionExplicitDeny,
+		},
+		{
+			name:    "resource policy delegating to the account needs an identity policy",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{ResourcePolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},
+						"Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "resource policy delegating to the account ID with an identity policy",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{
+					IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowS3Read)},
+					ResourcePolicies: []iam.PolicyDocument{
+						mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"123456789012"},
+							"Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"}]}`),
+					},
+				}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "resource policy delegating to the account does not bypass the permissions boundary",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				boundary := mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"ec2:*","Resource":"*"}]}`)
+				return iam.PolicySet{
+					IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowS3Read)},
+					ResourcePolicies: []iam.PolicyDocument{
+						mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},
+							"Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"}]}`),
+					},
+					PermissionsBoundary: &boundary,
+				}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "resource policy denying the account",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{
+					IdentityPolicies: []iam.PolicyDocument{mustParsePolicy(t, allowS3Read)},
+					ResourcePolicies: []iam.PolicyDocument{
+						mustParsePolicy(t, `{"Statement":[{"Effect":"Deny","Principal":{"AWS":"arn:aws:iam::123456789012:root"},
+							"Action":"s3:GetObject","Resource":"*"}]}`),
+					},
+				}
+			},
+			wantDecision: iam.DecisionExplicitDeny,
+		},
+		{
+			name:    "resource policy naming the principal grants access on its own",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{ResourcePolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:user/alice"},
+						"Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionAllowed,
+		},
+		{
+			name:    "wildcards in a principal ARN are literal",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{ResourcePolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:user/*"},
+						"Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+		{
+			name:    "resource policy for another principal",
+			request: iam.EvaluationRequest{Principal: userARN, Action: "s3:GetObject", Resource: bucketARN},
+			policies: func(t *testing.T) iam.PolicySet {
+				return iam.PolicySet{ResourcePolicies: []iam.PolicyDocument{
+					mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::210987654321:user/bob"]},
+						"Action":"s3:GetObject","Resource":"*"}]}`),
+				}}
+			},
+			wantDecision: iam.DecisionImplicitDeny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := iam.EvaluatePolicies(tt.request, tt.policies(t))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDecision, result.Decision)
+		})
+	}
+}
+
+func TestEvaluatePoliciesMatchedStatements(t *testing.T) {
+	result, err := iam.EvaluatePolicies(
+		iam.EvaluationRequest{Action: "s3:DeleteBucket", Resource: "arn:aws:s3:::reports"},
+		iam.PolicySet{IdentityPolicies: []iam.PolicyDocument{
+			mustParsePolicy(t, `{"Statement":[{"Sid":"ReadOnly","Effect":"Allow","Action":"s3:Get*","Resource":"*"},
+				{"Sid":"All","Effect":"Allow","Action":"s3:*","Resource":"*"},
+				{"Sid":"NoDelete","Effect":"Deny","Action":"s3:Delete*","Resource":"*"}]}`),
+		}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, iam.DecisionExplicitDeny, result.Decision)
+	assert.Equal(t, []iam.MatchedStatement{
+		{PolicyType: iam.PolicyTypeIdentity, PolicyIndex: 0, StatementIndex: 1, Sid: "All", Effect: iam.EffectAllow},
+		{PolicyType: iam.PolicyTypeIdentity, PolicyIndex: 0, StatementIndex: 2, Sid: "NoDelete", Effect: iam.EffectDeny},
+	}, result.MatchedStatements)
+}
+
+func TestEvaluatePoliciesErrors(t *testing.T) {
+	_, err := iam.EvaluatePolicies(iam.EvaluationRequest{}, iam.PolicySet{})
+	assert.ErrorContains(t, err, iam.ErrMissingEvaluationAction)
+
+	_, err = iam.EvaluatePolicies(iam.EvaluationRequest{Action: "s3:GetObject"}, iam.PolicySet{
+		IdentityPolicies: []iam.PolicyDocument{
+			mustParsePolicy(t, `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*","Condition":{"StringSounds":{"a":"b"}}}]}`),
+		},
+	})
+	assert.ErrorContains(t, err, iam.ErrUnsupportedConditionOperator)
+}
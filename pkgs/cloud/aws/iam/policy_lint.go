@@ -0,0 +1,326 @@
+package iam
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxManagedPolicySize is the maximum number of characters of a managed policy document, whitespace excluded.
+const MaxManagedPolicySize = 6144
+
+// LintSeverity is the severity of a lint finding.
+type LintSeverity string
+
+const (
+	// LintSeverityError marks a document that AWS rejects or that must never be deployed.
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityWarning marks a risky document, it only blocks in strict mode.
+	LintSeverityWarning LintSeverity = "warning"
+	// LintSeveritySuggestion marks a possible improvement, it never blocks.
+	LintSeveritySuggestion LintSeverity = "suggestion"
+)
+
+// Lint finding codes.
+const (
+	LintCodeInvalidGrammar       = "INVALID_GRAMMAR"
+	LintCodeUnsupportedVersion   = "UNSUPPORTED_VERSION"
+	LintCodeFullAdmin            = "FULL_ADMIN_ACCESS"
+	LintCodePassRoleWildcard     = "PASS_ROLE_WITH_STAR_RESOURCE"
+	LintCodeAllowWithNotAction   = "ALLOW_WITH_NOT_ACTION"
+	LintCodeUnknownService       = "UNKNOWN_SERVICE"
+	LintCodeRedundantStatement   = "REDUNDANT_STATEMENT"
+	LintCodePolicySizeExceeded   = "POLICY_SIZE_EXCEEDED"
+	LintCodeInvalidActionPattern = "INVALID_ACTION"
+)
+
+// LintFinding is an issue found in a policy document. Pointer is a JSON pointer (RFC 6901) to the offending element.
+type LintFinding struct {
+	Severity LintSeverity
+	Code     string
+	Message  string
+	Pointer  string
+}
+
+// knownServicePrefixes are the AWS service prefixes used in IAM actions.
+var knownServicePrefixes = map[string]bool{}
+
+func init() {
+	for _, prefix := range strings.Fields(`
+		a4b access-analyzer account acm acm-pca activate airflow amplify amplifybackend amplifyuibuilder aoss apigateway
+		app-integrations appconfig appflow application-autoscaling application-cost-profiler applicationinsights
+		appmesh apprunner appstream appsync aps arc-zonal-shift artifact athena auditmanager autoscaling
+		autoscaling-plans aws-marketplace aws-portal backup backup-gateway backup-storage batch bedrock billing
+		billingconductor braket budgets bugbust cassandra ce chatbot chime cleanrooms cloud9 clouddirectory
+		cloudformation cloudfront cloudhsm cloudsearch cloudshell cloudtrail cloudwatch codeartifact codebuild
+		codecatalyst codecommit codedeploy codeguru codeguru-profiler codeguru-reviewer codepipeline codestar
+		codestar-connections codestar-notifications codewhisperer cognito-identity cognito-idp cognito-sync
+		comprehend comprehendmedical compute-optimizer config connect consolidatedbilling controltower cur
+		customer-verification-id databrew dataexchange datapipeline datasync dax deepcomposer deeplens deepracer
+		detective devicefarm devops-guru directconnect discovery dlm dms docdb-elastic drs ds dynamodb ebs ec2
+		ec2-instance-connect ec2messages ecr ecr-public ecs eks elasticache elasticbeanstalk elasticfilesystem
+		elasticloadbalancing elasticmapreduce elastictranscoder emr-containers emr-serverless entityresolution es
+		events evidently execute-api firehose fis fms forecast frauddetector freertos fsx gamelift geo glacier
+		globalaccelerator glue grafana greengrass groundstation guardduty health healthlake honeycode iam
+		identity-sync identitystore imagebuilder importexport inspector inspector2 invoicing iot iot1click
+		iotanalytics iotdeviceadvisor iotevents iotfleethub iotfleetwise iotsitewise iottwinmaker iotwireless iq
+		ivs ivschat kafka kafka-cluster kafkaconnect kendra kinesis kinesisanalytics kinesisvideo kms lakeformation
+		lambda launchwizard lex license-manager lightsail logs lookoutequipment lookoutmetrics lookoutvision m2
+		machinelearning macie2 managedblockchain mediaconnect mediaconvert medialive mediapackage mediapackage-vod
+		mediastore mediatailor memorydb mgh mgn migrationhub-orchestrator migrationhub-strategy mobileanalytics
+		mobiletargeting monitron mq neptune-db network-firewall networkmanager nimble oam omics opsworks
+		opsworks-cm organizations osis outposts panorama payments personalize pi pipes polly pricing
+		private-networks profile proton purchase-orders qldb quicksight ram rbin rds rds-data rds-db redshift
+		redshift-data redshift-serverless refactor-spaces rekognition resiliencehub resource-explorer-2
+		resource-groups robomaker rolesanywhere route53 route53-recovery-cluster route53-recovery-control-config
+		route53-recovery-readiness route53domains route53resolver rum s3 s3-object-lambda s3-outposts sagemaker
+		savingsplans scheduler schemas sdb secretsmanager securityhub securitylake serverlessrepo servicecatalog
+		servicediscovery servicequotas ses shield signer simspaceweaver sms sms-voice snowball sns sqs ssm
+		ssm-contacts ssm-guiconnect ssm-incidents ssmmessages sso sso-directory sso-oauth states storagegateway sts
+		support supportapp sustainability swf synthetics tag tax textract timestream tiros tnb transcribe transfer
+		translate trustedadvisor voiceid vpc-lattice waf waf-regional wafv2 wellarchitected wisdom workdocs worklink
+		workmail workmailmessageflow workspaces workspaces-web xray`) {
+		knownServicePrefixes[prefix] = true
+	}
+}
+
+// JSONPointer builds a JSON pointer (RFC 6901) from its reference tokens.
+func JSONPointer(tokens ...string) string {
+	var pointer strings.Builder
+	for _, token := range tokens {
+		pointer.WriteString("/")
+		pointer.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+
+	return pointer.String()
+}
+
+// statementPointer returns the JSON pointer of the i-th statement of a policy.
+func (policy *PolicyDocument) statementPointer(i int) string {
+	if policy.SingleStatement && len(policy.Statement) == 1 {
+		return "/Statement"
+	}
+
+	return "/Statement/" + strconv.Itoa(i)
+}
+
+// valuePointer returns the JSON pointer of the i-th value of a string-or-array element.
+func valuePointer(elementPointer string, value StringOrSlice, i int) string {
+	if !value.Array && len(value.Values) == 1 {
+		return elementPointer
+	}
+
+	return elementPointer + "/" + strconv.Itoa(i)
+}
+
+// LintPolicyDocument checks an identity-based policy document for grammar errors, risky permissions,
+// unknown services, redundant statements and size-limit overflow.
+func LintPolicyDocument(policy PolicyDocument) []LintFinding {
+	findings := []LintFinding{}
+
+	if policy.Version != DefaultPolicyVersion {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityWarning,
+			Code:     LintCodeUnsupportedVersion,
+			Message:  fmt.Sprintf("Version should be %q, policy variables are not supported by older versions", DefaultPolicyVersion),
+			Pointer:  "/Version",
+		})
+	}
+
+	if len(policy.Statement) == 0 {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityError,
+			Code:     LintCodeInvalidGrammar,
+			Message:  "the policy must contain at least one statement",
+			Pointer:  "/Statement",
+		})
+	}
+
+	for i := range policy.Statement {
+		findings = append(findings, lintStatement(&policy.Statement[i], policy.statementPointer(i))...)
+	}
+
+	findings = append(findings, lintRedundantStatements(&policy)...)
+
+	if size := len(policy.String()); size > MaxManagedPolicySize {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityError,
+			Code:     LintCodePolicySizeExceeded,
+			Message:  fmt.Sprintf("the policy is %d characters long, the limit is %d", size, MaxManagedPolicySize),
+		})
+	}
+
+	return findings
+}
+
+// lintStatement checks a single statement of a policy.
+func lintStatement(statement *Statement, pointer string) []LintFinding {
+	var findings []LintFinding
+
+	if err := statement.Validate(); err != nil {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityError,
+			Code:     LintCodeInvalidGrammar,
+			Message:  strings.TrimPrefix(err.Error(), ErrInvalidPolicy+": "),
+			Pointer:  pointer,
+		})
+	}
+
+	if statement.Principal != nil || statement.NotPrincipal != nil {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityError,
+			Code:     LintCodeInvalidGrammar,
+			Message:  "identity-based policies cannot specify a principal",
+			Pointer:  pointer,
+		})
+	}
+
+	actionElements := []struct {
+		field   string
+		actions StringOrSlice
+	}{{"Action", statement.Action}, {"NotAction", statement.NotAction}}
+
+	for _, element := range actionElements {
+		field, actions := element.field, element.actions
+		for i, action := range actions.Values {
+			if action == "*" {
+				continue
+			}
+			actionPointer := valuePointer(pointer+"/"+field, actions, i)
+			service, _, found := strings.Cut(action, ":")
+			switch {
+			case !found || service == "":
+				findings = append(findings, LintFinding{
+					Severity: LintSeverityError,
+					Code:     LintCodeInvalidActionPattern,
+					Message:  fmt.Sprintf("action %q must be written as service:action", action),
+					Pointer:  actionPointer,
+				})
+			case !strings.ContainsAny(service, "*?") && !knownServicePrefixes[strings.ToLower(service)]:
+				findings = append(findings, LintFinding{
+					Severity: LintSeverityWarning,
+					Code:     LintCodeUnknownService,
+					Message:  fmt.Sprintf("unknown service prefix %q", service),
+					Pointer:  actionPointer,
+				})
+			}
+		}
+	}
+
+	if statement.Effect != EffectAllow {
+		return findings
+	}
+
+	if !statement.NotAction.IsEmpty() {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityWarning,
+			Code:     LintCodeAllowWithNotAction,
+			Message:  "Allow with NotAction grants every action not listed, including those of services added in the future",
+			Pointer:  pointer + "/NotAction",
+		})
+	}
+
+	allResources := false
+	for _, resource := range statement.Resource.Values {
+		if resource == "*" {
+			allResources = true
+		}
+	}
+
+	if allResources && matchesAnyAction(statement.Action.Values, "*:*") {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityWarning,
+			Code:     LintCodeFullAdmin,
+			Message:  "the statement allows every action on every resource",
+			Pointer:  pointer,
+		})
+	} else if allResources && matchesAnyAction(statement.Action.Values, "iam:PassRole") {
+		findings = append(findings, LintFinding{
+			Severity: LintSeverityWarning,
+			Code:     LintCodePassRoleWildcard,
+			Message:  "iam:PassRole on every resource lets the principal pass any role, and its permissions, to a service",
+			Pointer:  pointer + "/Resource",
+		})
+	}
+
+	return findings
+}
+
+// coversAll reports whether each value is matched by one of the patterns.
+func coversAll(patterns []string, values []string, ignoreCase bool) bool {
+	for _, value := range values {
+		covered := false
+		for _, pattern := range patterns {
+			if matchWildcard(pattern, value, ignoreCase) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// statementCovers reports whether a statement grants or denies at least everything another statement does.
+// Only statements using Action and Resource with the same effect and conditions are compared.
+func statementCovers(covering *Statement, covered *Statement) bool {
+	if covering.Effect != covered.Effect ||
+		covering.Action.IsEmpty() || covered.Action.IsEmpty() ||
+		covering.Resource.IsEmpty() || covered.Resource.IsEmpty() ||
+		!covering.NotAction.IsEmpty() || !covered.NotAction.IsEmpty() ||
+		!covering.NotResource.IsEmpty() || !covered.NotResource.IsEmpty() {
+		return false
+	}
+
+	coveringCondition, _ := json.Marshal(covering.Condition)
+	coveredCondition, _ := json.Marshal(covered.Condition)
+	if string(coveringCondition) != string(coveredCondition) {
+		return false
+	}
+
+	return coversAll(covering.Action.Values, covered.Action.Values, true) &&
+		coversAll(covering.Resource.Values, covered.Resource.Values, false)
+}
+
+// lintRedundantStatements reports the statements whose permissions are included in another statement.
+// When two statements are equivalent, only the second one is reported.
+func lintRedundantStatements(policy *PolicyDocument) []LintFinding {
+	var findings []LintFinding
+
+	for i := range policy.Statement {
+		for j := range policy.Statement {
+			if i == j || !statementCovers(&policy.Statement[j], &policy.Statement[i]) {
+				continue
+			}
+			if j > i && statementCovers(&policy.Statement[i], &policy.Statement[j]) {
+				// Equivalent statements, the later one is reported.
+				continue
+			}
+			findings = append(findings, LintFinding{
+				Severity: LintSeveritySuggestion,
+				Code:     LintCodeRedundantStatement,
+				Message:  fmt.Sprintf("the statement is already covered by %s", policy.statementPointer(j)),
+				Pointer:  policy.statementPointer(i),
+			})
+			break
+		}
+	}
+
+	return findings
+}
+
+// LintBlocks reports whether lint findings must prevent a policy from being deployed:
+// errors always do, warnings only do in strict mode.
+func LintBlocks(findings []LintFinding, strict bool) bool {
+	for _, finding := range findings {
+		if finding.Severity == LintSeverityError || (strict && finding.Severity == LintSeverityWarning) {
+			return true
+		}
+	}
+
+	return false
+}
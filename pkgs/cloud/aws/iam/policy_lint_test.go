@@ -0,0 +1,154 @@
+package iam_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestLintPolicyDocument(t *testing.T) {
+	tests := []struct {
+		name         string
+		document     string
+		wantFindings []iam.LintFinding
+	}{
+		{
+			name:         "clean policy",
+			document:     `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"arn:aws:s3:::b/*"}]}`,
+			wantFindings: []iam.LintFinding{},
+		},
+		{
+			name:     "full admin access",
+			document: `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"*","Resource":"*"}}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeverityWarning, Code: iam.LintCodeFullAdmin, Pointer: "/Statement"},
+			},
+		},
+		{
+			name:     "pass role on every resource",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["ec2:RunInstances","iam:Pass*"],"Resource":"*"}]}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeverityWarning, Code: iam.LintCodePassRoleWildcard, Pointer: "/Statement/0/Resource"},
+			},
+		},
+		{
+			name:     "allow with not action",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotAction":"iam:*","Resource":"arn:aws:s3:::b"}]}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeverityWarning, Code: iam.LintCodeAllowWithNotAction, Pointer: "/Statement/0/NotAction"},
+			},
+		},
+		{
+			name:     "unknown service and malformed action",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["s3:GetObject","s4:GetObject","GetObject"],"Resource":"*"}]}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeverityWarning, Code: iam.LintCodeUnknownService, Pointer: "/Statement/0/Action/1"},
+				{Severity: iam.LintSeverityError, Code: iam.LintCodeInvalidActionPattern, Pointer: "/Statement/0/Action/2"},
+			},
+		},
+		{
+			name: "redundant statement",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/key"},
+				{"Effect":"Allow","Action":"s3:Get*","Resource":"arn:aws:s3:::b/*"}]}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeveritySuggestion, Code: iam.LintCodeRedundantStatement, Pointer: "/Statement/0"},
+			},
+		},
+		{
+			name: "duplicated statement is reported once",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},
+				{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeveritySuggestion, Code: iam.LintCodeRedundantStatement, Pointer: "/Statement/1"},
+			},
+		},
+		{
+			name: "statements with different conditions are not redundant",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*",
+				"Condition":{"Bool":{"aws:SecureTransport":"true"}}},{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+			wantFindings: []iam.LintFinding{},
+		},
+		{
+			name:     "grammar error and old version",
+			document: `{"Version":"2008-10-17","Statement":[{"Effect":"Permit","Action":"s3:GetObject","Resource":"*"}]}`,
+			wantFindings: []iam.LintFinding{
+				{Severity: iam.LintSeverityWarning, Code: iam.LintCodeUnsupportedVersion, Pointer: "/Version"},
+				{Severity: iam.LintSeverityError, Code: iam.LintCodeInvalidGrammar, Pointer: "/Statement/0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := iam.ParsePolicyDocument(tt.document)
+			assert.NoError(t, err)
+
+			findings := iam.LintPolicyDocument(*policy)
+			for i := range findings {
+				assert.NotEmpty(t, findings[i].Message)
+				findings[i].Message = ""
+			}
+			assert.Equal(t, tt.wantFindings, findings)
+		})
+	}
+}
+
+func TestLintPolicyDocumentSize(t *testing.T) {
+	resources := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		resources = append(resources, fmt.Sprintf("arn:aws:s3:::bucket-%03d/*", i))
+	}
+
+	policy := iam.PolicyDocument{
+		Version: iam.DefaultPolicyVersion,
+		Statement: []iam.Statement{{
+			Effect:   iam.EffectAllow,
+			Action:   iam.NewStringOrSlice("s3:GetObject"),
+			Resource: iam.NewStringOrSlice(resources...),
+		}},
+	}
+
+	findings := iam.LintPolicyDocument(policy)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, iam.LintCodePolicySizeExceeded, findings[0].Code)
+	assert.True(t, strings.Contains(findings[0].Message, "6144"))
+	assert.True(t, iam.LintBlocks(findings, false))
+}
+
+func TestLintPolicyDocumentSizeUnescaped(t *testing.T) {
+	// Each resource holds an ampersand that json.Marshal would escape to \u0026, adding 5 characters.
+	resources := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		resources = append(resources, fmt.Sprintf("arn:aws:s3:::bucket-%03d/a&b", i))
+	}
+
+	policy := iam.PolicyDocument{
+		Version: iam.DefaultPolicyVersion,
+		Statement: []iam.Statement{{
+			Effect:   iam.EffectAllow,
+			Action:   iam.NewStringOrSlice("s3:GetObject"),
+			Resource: iam.NewStringOrSlice(resources...),
+		}},
+	}
+
+	assert.Contains(t, policy.String(), "bucket-000/a&b")
+	assert.Less(t, len(policy.String()), iam.MaxManagedPolicySize)
+	assert.Empty(t, iam.LintPolicyDocument(policy))
+}
+
+func TestLintBlocks(t *testing.T) {
+	warning := []iam.LintFinding{{Severity: iam.LintSeverityWarning}}
+	suggestion := []iam.LintFinding{{Severity: iam.LintSeveritySuggestion}}
+
+	assert.False(t, iam.LintBlocks(nil, true))
+	assert.False(t, iam.LintBlocks(warning, false))
+	assert.True(t, iam.LintBlocks(warning, true))
+	assert.False(t, iam.LintBlocks(suggestion, true))
+	assert.True(t, iam.LintBlocks([]iam.LintFinding{{Severity: iam.LintSeverityError}}, false))
+	assert.Equal(t, "/Statement/0/Condition/StringEquals/aws:PrincipalTag~1team",
+		iam.JSONPointer("Statement", "0", "Condition", "StringEquals", "aws:PrincipalTag/team"))
+}
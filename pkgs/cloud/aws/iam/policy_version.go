@@ -0,0 +1,168 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrIamPolicyVersionNotExists = "error IAM policy version does not exist"
+	ErrIamPolicyVersionIsDefault = "error IAM policy version is already the default version"
+	ErrIamPolicyVersionLimit     = "error IAM policy has reached the version limit and no version can be pruned"
+)
+
+// MaxPolicyVersions is the number of versions AWS keeps for a managed policy.
+const MaxPolicyVersions = 5
+
+// PolicyVersion represents a version of a managed IAM policy.
+type PolicyVersion struct {
+	VersionId  string
+	IsDefault  bool
+	CreateDate time.Time
+	// Document is the URL-decoded JSON policy document, it is only set when a single version is retrieved.
+	Document string
+}
+
+// ListPolicyVersions lists the versions of a managed IAM policy, newest first.
+func (IamSvc *Svc) ListPolicyVersions(policyARN string) ([]PolicyVersion, error) {
+	var versions []PolicyVersion
+
+	err := IamSvc.svc.ListPolicyVersionsPages(&iam.ListPolicyVersionsInput{PolicyArn: aws.String(policyARN)},
+		func(page *iam.ListPolicyVersionsOutput, lastPage bool) bool {
+			for _, version := range page.Versions {
+				versions = append(versions, PolicyVersion{
+					VersionId:  aws.StringValue(version.VersionId),
+					IsDefault:  aws.BoolValue(version.IsDefaultVersion),
+					CreateDate: aws.TimeValue(version.CreateDate),
+				})
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListPolicyVersions: %s %w", ErrIamPolicyNotExists, err)
+		}
+		return nil, fmt.Errorf("ListPolicyVersions: %w", err)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CreateDate.After(versions[j].CreateDate)
+	})
+
+	return versions, nil
+}
+
+// GetPolicyVersion retrieves a version of a managed IAM policy along with its document.
+func (IamSvc *Svc) GetPolicyVersion(policyARN string, versionId string) (*PolicyVersion, error) {
+	output, err := IamSvc.svc.GetPolicyVersion(&iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: aws.String(versionId),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("GetPolicyVersion: %s %w", ErrIamPolicyVersionNotExists, err)
+		}
+		return nil, fmt.Errorf("GetPolicyVersion: %w", err)
+	}
+
+	document, err := decodePolicyDocument(aws.StringValue(output.PolicyVersion.Document))
+	if err != nil {
+		return nil, fmt.Errorf("GetPolicyVersion: %w", err)
+	}
+
+	return &PolicyVersion{
+		VersionId:  aws.StringValue(output.PolicyVersion.VersionId),
+		IsDefault:  aws.BoolValue(output.PolicyVersion.IsDefaultVersion),
+		CreateDate: aws.TimeValue(output.PolicyVersion.CreateDate),
+		Document:   document,
+	}, nil
+}
+
+// SetDefaultPolicyVersion makes an existing version the default version of a managed IAM policy,
+// which rolls the policy back (or forward) to that version.
+func (IamSvc *Svc) SetDefaultPolicyVersion(policyARN string, versionId string) error {
+	_, err := IamSvc.svc.SetDefaultPolicyVersion(&iam.SetDefaultPolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: aws.String(versionId),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("SetDefaultPolicyVersion: %s %w", ErrIamPolicyVersionNotExists, err)
+		}
+		return fmt.Errorf("SetDefaultPolicyVersion: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy '%s' default version set to '%s'\n", policyARN, versionId)
+	return nil
+}
+
+// DeletePolicyVersion deletes a non-default version of a managed IAM policy.
+func (IamSvc *Svc) DeletePolicyVersion(policyARN string, versionId string) error {
+	_, err := IamSvc.svc.DeletePolicyVersion(&iam.DeletePolicyVersionInput{
+		PolicyArn: aws.String(policyARN),
+		VersionId: aws.String(versionId),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("DeletePolicyVersion: %s %w", ErrIamPolicyVersionNotExists, err)
+		}
+		if isAwsErrorCode(err, iam.ErrCodeDeleteConflictException) {
+			return fmt.Errorf("DeletePolicyVersion: %s %w", ErrIamPolicyVersionIsDefault, err)
+		}
+		return fmt.Errorf("DeletePolicyVersion: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy version '%s' of '%s' deleted successfully\n", versionId, policyARN)
+	return nil
+}
+
+// pruneOldestPolicyVersion deletes the oldest non-default version of a managed IAM policy
+// when it holds the maximum number of versions, so that a new version can be created.
+func (IamSvc *Svc) pruneOldestPolicyVersion(policyARN string) error {
+	versions, err := IamSvc.ListPolicyVersions(policyARN)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) < MaxPolicyVersions {
+		return nil
+	}
+
+	// Versions are sorted newest first.
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].IsDefault {
+			continue
+		}
+		return IamSvc.DeletePolicyVersion(policyARN, versions[i].VersionId)
+	}
+
+	return errors.New(ErrIamPolicyVersionLimit)
+}
+
+// DiffPolicyVersions computes the semantic difference between two versions of a managed IAM policy.
+func (IamSvc *Svc) DiffPolicyVersions(policyARN string, fromVersionId string, toVersionId string) (*PolicyDiff, error) {
+	fromVersion, err := IamSvc.GetPolicyVersion(policyARN, fromVersionId)
+	if err != nil {
+		return nil, fmt.Errorf("DiffPolicyVersions: %w", err)
+	}
+
+	toVersion, err := IamSvc.GetPolicyVersion(policyARN, toVersionId)
+	if err != nil {
+		return nil, fmt.Errorf("DiffPolicyVersions: %w", err)
+	}
+
+	diff, err := DiffPolicyDocuments(fromVersion.Document, toVersion.Document)
+	if err != nil {
+		return nil, fmt.Errorf("DiffPolicyVersions: %w", err)
+	}
+
+	return diff, nil
+}
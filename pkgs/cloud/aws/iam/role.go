@@ -37,8 +37,17 @@ func (IamSvc *Svc) GetIamRole(roleName string) (*iam.Role, error) {
 	return role.Role, nil
 }
 
-// CreateIAMRole creates an IAM role and returns role ARN.
-func (IamSvc *Svc) CreateIAMRole(roleName string, trustPolicy string) (*iam.Role, error) {
+// RoleOptions holds the optional settings of a new IAM role. Zero values keep the AWS defaults.
+type RoleOptions struct {
+	Path                string
+	Description         string
+	MaxSessionDuration  int64
+	PermissionsBoundary string
+	Tags                map[string]string
+}
+
+// CreateIAMRole creates an IAM role with the given options and returns role details.
+func (IamSvc *Svc) CreateIAMRole(roleName string, trustPolicy string, options RoleOptions) (*iam.Role, error) {
 	roleDetails, _ := IamSvc.GetIamRole(roleName)
 	if roleDetails != nil {
 		return nil, errors.New(ErrIamRoleExists)
@@ -47,7 +56,21 @@ func (IamSvc *Svc) CreateIAMRole(roleName string, trustPolicy string) (*iam.Role
 	createRoleInput := &iam.CreateRoleInput{
 		RoleName:                 aws.String(roleName),
 		AssumeRolePolicyDocument: aws.String(trustPolicy),
+		Tags:                     toIamTags(options.Tags),
+	}
+	if options.Path != "" {
+		createRoleInput.Path = aws.String(options.Path)
+	}
+	if options.Description != "" {
+		createRoleInput.Description = aws.String(options.Description)
+	}
+	if options.MaxSessionDuration != 0 {
+		createRoleInput.MaxSessionDuration = aws.Int64(options.MaxSessionDuration)
+	}
+	if options.PermissionsBoundary != "" {
+		createRoleInput.PermissionsBoundary = aws.String(options.PermissionsBoundary)
 	}
+
 	createRoleOutput, err := IamSvc.svc.CreateRole(createRoleInput)
 	if err != nil {
 		return nil, fmt.Errorf("CreateIAMRole: %w", err)
@@ -58,6 +81,26 @@ func (IamSvc *Svc) CreateIAMRole(roleName string, trustPolicy string) (*iam.Role
 	return createRoleOutput.Role, nil
 }
 
+// UpdateIAMRole updates the description and the maximum session duration of an IAM role.
+// Nil values are left unchanged. It returns the updated role details.
+func (IamSvc *Svc) UpdateIAMRole(roleName string, description *string, maxSessionDuration *int64) (*iam.Role, error) {
+	_, err := IamSvc.svc.UpdateRole(&iam.UpdateRoleInput{
+		RoleName:           aws.String(roleName),
+		Description:        description,
+		MaxSessionDuration: maxSessionDuration,
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("UpdateIAMRole: %s %w", ErrIamRoleNotExists, err)
+		}
+		return nil, fmt.Errorf("UpdateIAMRole: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' updated successfully\n", roleName)
+
+	return IamSvc.GetIamRole(roleName)
+}
+
 // ModifyIAMRoleTrustPolicy modifies an IAM role trust policy and returns role details.
 func (IamSvc *Svc) ModifyIAMRoleTrustPolicy(roleName string, newTrustPolicy string) (*iam.Role, error) {
 	roleDetails, _ := IamSvc.GetIamRole(roleName)
@@ -101,12 +144,33 @@ func (IamSvc *Svc) DeleteIAMRole(roleName string) error {
 
 // ListIamRoles lists all IAM roles.
 func (IamSvc *Svc) ListIamRoles() ([]*iam.Role, error) {
-	listRolesInput := &iam.ListRolesInput{}
-
-	result, err := IamSvc.svc.ListRoles(listRolesInput)
+	var roles []*iam.Role
+
+	err := IamSvc.svc.ListRolesPages(&iam.ListRolesInput{},
+		func(page *iam.ListRolesOutput, lastPage bool) bool {
+			roles = append(roles, page.Roles...)
+			return !lastPage
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("ListRoles: %w", err)
 	}
 
-	return result.Roles, nil
+	return roles, nil
+}
+
+// ListIamRolesPage lists at most maxItems IAM roles starting at marker, an empty marker starting at the first
+// role. It returns the marker of the next page, empty on the last page.
+func (IamSvc *Svc) ListIamRolesPage(marker string, maxItems int64) ([]*iam.Role, string, error) {
+	input := &iam.ListRolesInput{MaxItems: aws.Int64(maxItems)}
+	if marker != "" {
+		input.Marker = aws.String(marker)
+	}
+
+	output, err := IamSvc.svc.ListRoles(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("ListIamRolesPage: %w", err)
+	}
+
+	return output.Roles, nextMarker(output.IsTruncated, output.Marker), nil
 }
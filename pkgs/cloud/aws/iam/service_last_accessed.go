@@ -0,0 +1,247 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+const (
+	ErrIamEntityNotExists     = "error IAM entity does not exist"
+	ErrLastAccessedJobFailed  = "error IAM service last accessed details job failed"
+	ErrLastAccessedJobTimeout = "error IAM service last accessed details job did not complete in time"
+)
+
+const (
+	// lastAccessedPollInterval is the delay between two GetServiceLastAccessedDetails calls while the job runs.
+	lastAccessedPollInterval = 2 * time.Second
+	// lastAccessedMaxPolls bounds how many times the job status is polled.
+	lastAccessedMaxPolls = 30
+)
+
+// ActionLastAccessed is when an action of a service was last used, for action-level last accessed details.
+type ActionLastAccessed struct {
+	ActionName     string
+	LastAccessed   *time.Time
+	LastAccessedBy string
+	Region         string
+}
+
+// ServiceLastAccessed is when a service granted to an IAM entity was last used.
+// LastAuthenticated is nil when the service was never used during the tracking period.
+type ServiceLastAccessed struct {
+	ServiceName                string
+	ServiceNamespace           string
+	LastAuthenticated          *time.Time
+	LastAuthenticatedBy        string
+	LastAuthenticatedRegion    string
+	TotalAuthenticatedEntities int64
+	TrackedActions             []ActionLastAccessed
+}
+
+// ServiceLastAccessedJob is the status and, once completed, the result of a service last accessed details job.
+type ServiceLastAccessedJob struct {
+	JobId          string
+	Status         string
+	Granularity    string
+	CreationDate   time.Time
+	CompletionDate *time.Time
+	Error          string
+	Services       []ServiceLastAccessed
+}
+
+// GenerateServiceLastAccessedDetails starts a job reporting when the services granted to a user, group, role or
+// policy were last used. Granularity is SERVICE_LEVEL or ACTION_LEVEL; action-level details are only tracked for
+// some services.
+func (IamSvc *Svc) GenerateServiceLastAccessedDetails(arn string, granularity string) (string, error) {
+	output, err := IamSvc.svc.GenerateServiceLastAccessedDetails(&iam.GenerateServiceLastAccessedDetailsInput{
+		Arn:         aws.String(arn),
+		Granularity: aws.String(granularity),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return "", fmt.Errorf("GenerateServiceLastAccessedDetails: %s %w", ErrIamEntityNotExists, err)
+		}
+		return "", fmt.Errorf("GenerateServiceLastAccessedDetails: %w", err)
+	}
+
+	return aws.StringValue(output.JobId), nil
+}
+
+// GetServiceLastAccessedDetails retrieves the status of a service last accessed details job, with every
+// service once the job is completed.
+func (IamSvc *Svc) GetServiceLastAccessedDetails(jobId string) (*ServiceLastAccessedJob, error) {
+	var job *ServiceLastAccessedJob
+
+	input := &iam.GetServiceLastAccessedDetailsInput{JobId: aws.String(jobId)}
+	for {
+		output, err := IamSvc.svc.GetServiceLastAccessedDetails(input)
+		if err != nil {
+			if isNoSuchEntity(err) {
+				return nil, fmt.Errorf("GetServiceLastAccessedDetails: %s %w", ErrIamEntityNotExists, err)
+			}
+			return nil, fmt.Errorf("GetServiceLastAccessedDetails: %w", err)
+		}
+
+		if job == nil {
+			job = &ServiceLastAccessedJob{
+				JobId:          jobId,
+				Status:         aws.StringValue(output.JobStatus),
+				Granularity:    aws.StringValue(output.JobType),
+				CreationDate:   aws.TimeValue(output.JobCreationDate),
+				CompletionDate: output.JobCompletionDate,
+			}
+			if output.Error != nil {
+				job.Error = aws.StringValue(output.Error.Message)
+			}
+		}
+
+		for _, service := range output.ServicesLastAccessed {
+			job.Services = append(job.Services, serviceLastAccessed(service))
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	return job, nil
+}
+
+// FetchServiceLastAccessedDetails starts a service last accessed details job and waits until it completes.
+func (IamSvc *Svc) FetchServiceLastAccessedDetails(arn string, granularity string) (*ServiceLastAccessedJob, error) {
+	jobId, err := IamSvc.GenerateServiceLastAccessedDetails(arn, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("FetchServiceLastAccessedDetails: %w", err)
+	}
+
+	for poll := 0; poll < lastAccessedMaxPolls; poll++ {
+		job, err := IamSvc.GetServiceLastAccessedDetails(jobId)
+		if err != nil {
+			return nil, fmt.Errorf("FetchServiceLastAccessedDetails: %w", err)
+		}
+
+		switch job.Status {
+		case iam.JobStatusTypeCompleted:
+			return job, nil
+		case iam.JobStatusTypeFailed:
+			return nil, fmt.Errorf("FetchServiceLastAccessedDetails: %s: %s", ErrLastAccessedJobFailed, job.Error)
+		}
+
+		time.Sleep(lastAccessedPollInterval)
+	}
+
+	return nil, fmt.Errorf("FetchServiceLastAccessedDetails: %w", errors.New(ErrLastAccessedJobTimeout))
+}
+
+// serviceLastAccessed converts the last accessed details of a service returned by the IAM API.
+func serviceLastAccessed(service *iam.ServiceLastAccessed) ServiceLastAccessed {
+	details := ServiceLastAccessed{
+		ServiceName:                aws.StringValue(service.ServiceName),
+		ServiceNamespace:           aws.StringValue(service.ServiceNamespace),
+		LastAuthenticated:          service.LastAuthenticated,
+		LastAuthenticatedBy:        aws.StringValue(service.LastAuthenticatedEntity),
+		LastAuthenticatedRegion:    aws.StringValue(service.LastAuthenticatedRegion),
+		TotalAuthenticatedEntities: aws.Int64Value(service.TotalAuthenticatedEntities),
+	}
+
+	for _, action := range service.TrackedActionsLastAccessed {
+		details.TrackedActions = append(details.TrackedActions, ActionLastAccessed{
+			ActionName:     aws.StringValue(action.ActionName),
+			LastAccessed:   action.LastAccessedTime,
+			LastAccessedBy: aws.StringValue(action.LastAccessedEntity),
+			Region:         aws.StringValue(action.LastAccessedRegion),
+		})
+	}
+
+	return details
+}
+
+// UnusedServices returns the services not used since the given time, including those never used.
+func UnusedServices(services []ServiceLastAccessed, since time.Time) []ServiceLastAccessed {
+	var unused []ServiceLastAccessed
+	for _, service := range services {
+		if service.LastAuthenticated == nil || service.LastAuthenticated.Before(since) {
+			unused = append(unused, service)
+		}
+	}
+
+	return unused
+}
+
+// actionServiceNamespace returns the service prefix of an action, such as s3 for s3:GetObject, or an empty
+// string for the * action.
+func actionServiceNamespace(action string) string {
+	namespace, _, found := strings.Cut(action, ":")
+	if !found {
+		return ""
+	}
+
+	return strings.ToLower(namespace)
+}
+
+// TrimPolicyDocument removes from the Allow statements of a policy the actions of the given service namespaces,
+// dropping the statements left without any action. It returns the trimmed policy and the removed actions.
+// Deny statements, NotAction statements and the * action are kept since they cannot be trimmed by service.
+func TrimPolicyDocument(policy PolicyDocument, namespaces []string) (PolicyDocument, []string) {
+	trimmedNamespaces := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		trimmedNamespaces[strings.ToLower(namespace)] = true
+	}
+
+	trimmed := policy
+	trimmed.Statement = nil
+	trimmed.SingleStatement = false
+
+	var removed []string
+	for _, statement := range policy.Statement {
+		if statement.Effect != EffectAllow || statement.Action.IsEmpty() {
+			trimmed.Statement = append(trimmed.Statement, statement)
+			continue
+		}
+
+		var actions []string
+		for _, action := range statement.Action.Values {
+			if trimmedNamespaces[actionServiceNamespace(action)] {
+				removed = append(removed, action)
+				continue
+			}
+			actions = append(actions, action)
+		}
+
+		if len(actions) == 0 {
+			continue
+		}
+		if len(actions) < len(statement.Action.Values) {
+			statement.Action = NewStringOrSlice(actions...)
+		}
+		trimmed.Statement = append(trimmed.Statement, statement)
+	}
+
+	return trimmed, removed
+}
+
+// MergePolicyDocuments combines the statements of several policies into a single policy. A statement Sid
+// already used by a previous statement is cleared, since Sids must be unique within a policy.
+func MergePolicyDocuments(policies ...PolicyDocument) PolicyDocument {
+	merged := PolicyDocument{Version: DefaultPolicyVersion}
+
+	sids := make(map[string]bool)
+	for _, policy := range policies {
+		for _, statement := range policy.Statement {
+			if sids[statement.Sid] {
+				statement.Sid = ""
+			} else if statement.Sid != "" {
+				sids[statement.Sid] = true
+			}
+			merged.Statement = append(merged.Statement, statement)
+		}
+	}
+
+	return merged
+}
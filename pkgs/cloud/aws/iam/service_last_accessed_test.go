@@ -0,0 +1,74 @@
+package iam_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestUnusedServices(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -10)
+	old := now.AddDate(0, 0, -200)
+
+	services := []iam.ServiceLastAccessed{
+		{ServiceNamespace: "s3", LastAuthenticated: &recent},
+		{ServiceNamespace: "ec2", LastAuthenticated: &old},
+		{ServiceNamespace: "sqs"},
+	}
+
+	unused := iam.UnusedServices(services, now.AddDate(0, 0, -90))
+
+	var namespaces []string
+	for _, service := range unused {
+		namespaces = append(namespaces, service.ServiceNamespace)
+	}
+	assert.Equal(t, []string{"ec2", "sqs"}, namespaces)
+}
+
+func TestTrimPolicyDocument(t *testing.T) {
+	policy := mustParsePolicy(t, `{
+		"Version": "2012-10-17",
+		"Statement": [
+			{"Sid": "Mixed", "Effect": "Allow", "Action": ["s3:GetObject", "EC2:Describe*"], "Resource": "*"},
+			{"Sid": "OnlyUnused", "Effect": "Allow", "Action": "sqs:SendMessage", "Resource": "*"},
+			{"Sid": "Everything", "Effect": "Allow", "Action": "*", "Resource": "*"},
+			{"Sid": "Deny", "Effect": "Deny", "Action": "ec2:TerminateInstances", "Resource": "*"},
+			{"Sid": "NotAction", "Effect": "Allow", "NotAction": "ec2:*", "Resource": "*"}
+		]
+	}`)
+
+	trimmed, removed := iam.TrimPolicyDocument(policy, []string{"ec2", "sqs"})
+
+	assert.ElementsMatch(t, []string{"EC2:Describe*", "sqs:SendMessage"}, removed)
+
+	var sids []string
+	for _, statement := range trimmed.Statement {
+		sids = append(sids, statement.Sid)
+	}
+	assert.Equal(t, []string{"Mixed", "Everything", "Deny", "NotAction"}, sids)
+	assert.Equal(t, []string{"s3:GetObject"}, trimmed.Statement[0].Action.Values)
+
+	// The original policy is left untouched.
+	assert.Len(t, policy.Statement, 5)
+	assert.Len(t, policy.Statement[0].Action.Values, 2)
+}
+
+func TestMergePolicyDocuments(t *testing.T) {
+	first := mustParsePolicy(t, `{"Version":"2012-10-17","Statement":{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"*"}}`)
+	second := mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Sid":"Read","Effect":"Allow","Action":"sqs:ReceiveMessage","Resource":"*"},{"Effect":"Allow","Action":"sns:Publish","Resource":"*"}]}`)
+
+	merged := iam.MergePolicyDocuments(first, second)
+
+	assert.NoError(t, merged.Validate())
+	assert.Len(t, merged.Statement, 3)
+	assert.Equal(t, "Read", merged.Statement[0].Sid)
+	assert.Empty(t, merged.Statement[1].Sid)
+
+	_, err := json.Marshal(merged)
+	assert.NoError(t, err)
+}
@@ -1,13 +1,48 @@
 package iam
 
 import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 )
 
 type Svc struct {
 	svc *iam.IAM
+
+	// accountID and partition locate the account the service works on, they are used to build ARNs.
+	accountID string
+	partition string
+}
+
+// New returns an IAM service working on the account with the given ID, in the given partition such as aws,
+// aws-cn or aws-us-gov.
+func New(svc *iam.IAM, accountID string, partition string) *Svc {
+	return &Svc{svc: svc, accountID: accountID, partition: partition}
+}
+
+// isAwsErrorCode reports whether err is an AWS error with the given code.
+func isAwsErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code() == code
+	}
+
+	return false
 }
 
-func New(svc *iam.IAM) *Svc {
-	return &Svc{svc: svc}
+// isNoSuchEntity reports whether err is an IAM NoSuchEntity error.
+func isNoSuchEntity(err error) bool {
+	return isAwsErrorCode(err, iam.ErrCodeNoSuchEntityException)
+}
+
+// nextMarker returns the marker of the page following a truncated list output, or an empty string on the last
+// page.
+func nextMarker(isTruncated *bool, marker *string) string {
+	if !aws.BoolValue(isTruncated) {
+		return ""
+	}
+
+	return aws.StringValue(marker)
 }
@@ -0,0 +1,347 @@
+package iam
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+// ReservedTagPrefix prefixes the tags Inariam keeps its own state in, such as access key rotation deadlines. They
+// are hidden from the tags returned to clients, which may not set or remove them.
+const ReservedTagPrefix = "inariam:"
+
+const (
+	ErrInvalidTagFilter         = "error invalid tag filter, expected key, key=value or key:value"
+	ErrIamOIDCProviderNotExists = "error IAM OIDC provider does not exist"
+)
+
+// toIamTags converts tags to the IAM API representation, sorted by key.
+func toIamTags(tags map[string]string) []*iam.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	iamTags := make([]*iam.Tag, 0, len(tags))
+	for _, key := range keys {
+		iamTags = append(iamTags, &iam.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	return iamTags
+}
+
+// FromIamTags converts tags returned by the IAM API to a map of values by key, leaving out the reserved tags.
+func FromIamTags(iamTags []*iam.Tag) map[string]string {
+	tags := make(map[string]string, len(iamTags))
+	for _, tag := range iamTags {
+		if strings.HasPrefix(aws.StringValue(tag.Key), ReservedTagPrefix) {
+			continue
+		}
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	return tags
+}
+
+// TagFilter selects the resources holding a tag, with any value when Value is empty and AnyValue is set.
+type TagFilter struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+// ParseTagFilter parses a tag filter written as key=value or key:value, or as key alone to match any value.
+// The key:value form is split at the first colon, keys holding colons such as aws:cloudformation:stack-name
+// are filtered with the key=value form.
+func ParseTagFilter(filter string) (TagFilter, error) {
+	separator := ":"
+	if strings.Contains(filter, "=") {
+		separator = "="
+	}
+
+	key, value, hasValue := strings.Cut(filter, separator)
+	if key == "" {
+		return TagFilter{}, fmt.Errorf("%s: %q", ErrInvalidTagFilter, filter)
+	}
+
+	return TagFilter{Key: key, Value: value, AnyValue: !hasValue}, nil
+}
+
+// Matches reports whether the tags satisfy the filter.
+func (filter TagFilter) Matches(tags map[string]string) bool {
+	value, ok := tags[filter.Key]
+	return ok && (filter.AnyValue || value == filter.Value)
+}
+
+// MatchTagFilters reports whether the tags satisfy every filter.
+func MatchTagFilters(filters []TagFilter, tags map[string]string) bool {
+	for _, filter := range filters {
+		if !filter.Matches(tags) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TagUser adds or overwrites tags on an IAM user.
+func (IamSvc *Svc) TagUser(username string, tags map[string]string) error {
+	_, err := IamSvc.svc.TagUser(&iam.TagUserInput{UserName: aws.String(username), Tags: toIamTags(tags)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("TagUser: %s %w", ErrIamRUserNotExists, err)
+		}
+		return fmt.Errorf("TagUser: %w", err)
+	}
+
+	log.Logger.Infof("IAM user '%s' tagged successfully\n", username)
+	return nil
+}
+
+// UntagUser removes tags from an IAM user.
+func (IamSvc *Svc) UntagUser(username string, keys []string) error {
+	_, err := IamSvc.svc.UntagUser(&iam.UntagUserInput{UserName: aws.String(username), TagKeys: aws.StringSlice(keys)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("UntagUser: %s %w", ErrIamRUserNotExists, err)
+		}
+		return fmt.Errorf("UntagUser: %w", err)
+	}
+
+	log.Logger.Infof("IAM user '%s' untagged successfully\n", username)
+	return nil
+}
+
+// ListUserTags lists the tags of an IAM user.
+func (IamSvc *Svc) ListUserTags(username string) (map[string]string, error) {
+	var tags []*iam.Tag
+
+	err := IamSvc.svc.ListUserTagsPages(&iam.ListUserTagsInput{UserName: aws.String(username)},
+		func(page *iam.ListUserTagsOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListUserTags: %s %w", ErrIamRUserNotExists, err)
+		}
+		return nil, fmt.Errorf("ListUserTags: %w", err)
+	}
+
+	return FromIamTags(tags), nil
+}
+
+// TagRole adds or overwrites tags on an IAM role.
+func (IamSvc *Svc) TagRole(roleName string, tags map[string]string) error {
+	_, err := IamSvc.svc.TagRole(&iam.TagRoleInput{RoleName: aws.String(roleName), Tags: toIamTags(tags)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("TagRole: %s %w", ErrIamRoleNotExists, err)
+		}
+		return fmt.Errorf("TagRole: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' tagged successfully\n", roleName)
+	return nil
+}
+
+// UntagRole removes tags from an IAM role.
+func (IamSvc *Svc) UntagRole(roleName string, keys []string) error {
+	_, err := IamSvc.svc.UntagRole(&iam.UntagRoleInput{RoleName: aws.String(roleName), TagKeys: aws.StringSlice(keys)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("UntagRole: %s %w", ErrIamRoleNotExists, err)
+		}
+		return fmt.Errorf("UntagRole: %w", err)
+	}
+
+	log.Logger.Infof("IAM role '%s' untagged successfully\n", roleName)
+	return nil
+}
+
+// ListRoleTags lists the tags of an IAM role.
+func (IamSvc *Svc) ListRoleTags(roleName string) (map[string]string, error) {
+	var tags []*iam.Tag
+
+	err := IamSvc.svc.ListRoleTagsPages(&iam.ListRoleTagsInput{RoleName: aws.String(roleName)},
+		func(page *iam.ListRoleTagsOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListRoleTags: %s %w", ErrIamRoleNotExists, err)
+		}
+		return nil, fmt.Errorf("ListRoleTags: %w", err)
+	}
+
+	return FromIamTags(tags), nil
+}
+
+// TagPolicy adds or overwrites tags on a managed IAM policy.
+func (IamSvc *Svc) TagPolicy(policyARN string, tags map[string]string) error {
+	_, err := IamSvc.svc.TagPolicy(&iam.TagPolicyInput{PolicyArn: aws.String(policyARN), Tags: toIamTags(tags)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("TagPolicy: %s %w", ErrIamPolicyNotExists, err)
+		}
+		return fmt.Errorf("TagPolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy '%s' tagged successfully\n", policyARN)
+	return nil
+}
+
+// UntagPolicy removes tags from a managed IAM policy.
+func (IamSvc *Svc) UntagPolicy(policyARN string, keys []string) error {
+	_, err := IamSvc.svc.UntagPolicy(&iam.UntagPolicyInput{PolicyArn: aws.String(policyARN), TagKeys: aws.StringSlice(keys)})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("UntagPolicy: %s %w", ErrIamPolicyNotExists, err)
+		}
+		return fmt.Errorf("UntagPolicy: %w", err)
+	}
+
+	log.Logger.Infof("IAM policy '%s' untagged successfully\n", policyARN)
+	return nil
+}
+
+// ListPolicyTags lists the tags of a managed IAM policy.
+func (IamSvc *Svc) ListPolicyTags(policyARN string) (map[string]string, error) {
+	var tags []*iam.Tag
+
+	err := IamSvc.svc.ListPolicyTagsPages(&iam.ListPolicyTagsInput{PolicyArn: aws.String(policyARN)},
+		func(page *iam.ListPolicyTagsOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListPolicyTags: %s %w", ErrIamPolicyNotExists, err)
+		}
+		return nil, fmt.Errorf("ListPolicyTags: %w", err)
+	}
+
+	return FromIamTags(tags), nil
+}
+
+// TagInstanceProfile adds or overwrites tags on an IAM instance profile.
+func (IamSvc *Svc) TagInstanceProfile(instanceProfileName string, tags map[string]string) error {
+	_, err := IamSvc.svc.TagInstanceProfile(&iam.TagInstanceProfileInput{
+		InstanceProfileName: aws.String(instanceProfileName),
+		Tags:                toIamTags(tags),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("TagInstanceProfile: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return fmt.Errorf("TagInstanceProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM instance profile '%s' tagged successfully\n", instanceProfileName)
+	return nil
+}
+
+// UntagInstanceProfile removes tags from an IAM instance profile.
+func (IamSvc *Svc) UntagInstanceProfile(instanceProfileName string, keys []string) error {
+	_, err := IamSvc.svc.UntagInstanceProfile(&iam.UntagInstanceProfileInput{
+		InstanceProfileName: aws.String(instanceProfileName),
+		TagKeys:             aws.StringSlice(keys),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("UntagInstanceProfile: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return fmt.Errorf("UntagInstanceProfile: %w", err)
+	}
+
+	log.Logger.Infof("IAM instance profile '%s' untagged successfully\n", instanceProfileName)
+	return nil
+}
+
+// ListInstanceProfileTags lists the tags of an IAM instance profile.
+func (IamSvc *Svc) ListInstanceProfileTags(instanceProfileName string) (map[string]string, error) {
+	var tags []*iam.Tag
+
+	err := IamSvc.svc.ListInstanceProfileTagsPages(&iam.ListInstanceProfileTagsInput{InstanceProfileName: aws.String(instanceProfileName)},
+		func(page *iam.ListInstanceProfileTagsOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListInstanceProfileTags: %s %w", ErrIamInstanceProfileNotExists, err)
+		}
+		return nil, fmt.Errorf("ListInstanceProfileTags: %w", err)
+	}
+
+	return FromIamTags(tags), nil
+}
+
+// TagOpenIDConnectProvider adds or overwrites tags on an IAM OpenID Connect identity provider.
+func (IamSvc *Svc) TagOpenIDConnectProvider(providerARN string, tags map[string]string) error {
+	_, err := IamSvc.svc.TagOpenIDConnectProvider(&iam.TagOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+		Tags:                     toIamTags(tags),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("TagOpenIDConnectProvider: %s %w", ErrIamOIDCProviderNotExists, err)
+		}
+		return fmt.Errorf("TagOpenIDConnectProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM OIDC provider '%s' tagged successfully\n", providerARN)
+	return nil
+}
+
+// UntagOpenIDConnectProvider removes tags from an IAM OpenID Connect identity provider.
+func (IamSvc *Svc) UntagOpenIDConnectProvider(providerARN string, keys []string) error {
+	_, err := IamSvc.svc.UntagOpenIDConnectProvider(&iam.UntagOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+		TagKeys:                  aws.StringSlice(keys),
+	})
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return fmt.Errorf("UntagOpenIDConnectProvider: %s %w", ErrIamOIDCProviderNotExists, err)
+		}
+		return fmt.Errorf("UntagOpenIDConnectProvider: %w", err)
+	}
+
+	log.Logger.Infof("IAM OIDC provider '%s' untagged successfully\n", providerARN)
+	return nil
+}
+
+// ListOpenIDConnectProviderTags lists the tags of an IAM OpenID Connect identity provider.
+func (IamSvc *Svc) ListOpenIDConnectProviderTags(providerARN string) (map[string]string, error) {
+	var tags []*iam.Tag
+
+	err := IamSvc.svc.ListOpenIDConnectProviderTagsPages(&iam.ListOpenIDConnectProviderTagsInput{OpenIDConnectProviderArn: aws.String(providerARN)},
+		func(page *iam.ListOpenIDConnectProviderTagsOutput, lastPage bool) bool {
+			tags = append(tags, page.Tags...)
+			return !lastPage
+		},
+	)
+	if err != nil {
+		if isNoSuchEntity(err) {
+			return nil, fmt.Errorf("ListOpenIDConnectProviderTags: %s %w", ErrIamOIDCProviderNotExists, err)
+		}
+		return nil, fmt.Errorf("ListOpenIDConnectProviderTags: %w", err)
+	}
+
+	return FromIamTags(tags), nil
+}
@@ -0,0 +1,85 @@
+package iam_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsIam "github.com/aws/aws-sdk-go/service/iam"
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestParseTagFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		want    iam.TagFilter
+		wantErr bool
+	}{
+		{name: "key and value", filter: "team:security", want: iam.TagFilter{Key: "team", Value: "security"}},
+		{name: "key only", filter: "team", want: iam.TagFilter{Key: "team", AnyValue: true}},
+		{name: "empty value", filter: "team:", want: iam.TagFilter{Key: "team"}},
+		{name: "value with colon", filter: "owner:arn:aws", want: iam.TagFilter{Key: "owner", Value: "arn:aws"}},
+		{
+			name:   "key with colons",
+			filter: "aws:cloudformation:stack-name=network",
+			want:   iam.TagFilter{Key: "aws:cloudformation:stack-name", Value: "network"},
+		},
+		{name: "value with equal sign", filter: "query=a=b", want: iam.TagFilter{Key: "query", Value: "a=b"}},
+		{name: "empty value with equal sign", filter: "team=", want: iam.TagFilter{Key: "team"}},
+		{name: "missing key", filter: ":security", wantErr: true},
+		{name: "missing key with equal sign", filter: "=security", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := iam.ParseTagFilter(tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, filter)
+		})
+	}
+}
+
+func TestMatchTagFilters(t *testing.T) {
+	tags := map[string]string{"team": "security", "env": ""}
+
+	tests := []struct {
+		name    string
+		filters []string
+		want    bool
+	}{
+		{name: "no filter", want: true},
+		{name: "matching value", filters: []string{"team:security"}, want: true},
+		{name: "other value", filters: []string{"team:platform"}},
+		{name: "any value", filters: []string{"env"}, want: true},
+		{name: "empty value", filters: []string{"env:"}, want: true},
+		{name: "missing key", filters: []string{"owner"}},
+		{name: "every filter must match", filters: []string{"team:security", "owner"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filters []iam.TagFilter
+			for _, value := range tt.filters {
+				filter, err := iam.ParseTagFilter(value)
+				assert.NoError(t, err)
+				filters = append(filters, filter)
+			}
+			assert.Equal(t, tt.want, iam.MatchTagFilters(filters, tags))
+		})
+	}
+}
+
+func TestFromIamTagsHidesReservedTags(t *testing.T) {
+	tags := iam.FromIamTags([]*awsIam.Tag{
+		{Key: aws.String("team"), Value: aws.String("security")},
+		{Key: aws.String(iam.ReservedTagPrefix + "rotation:AKIAEXAMPLE"), Value: aws.String("2023-08-01T12:00:00Z")},
+	})
+
+	assert.Equal(t, map[string]string{"team": "security"}, tags)
+}
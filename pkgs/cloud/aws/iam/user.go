@@ -37,8 +37,8 @@ func (IamSvc *Svc) GetIamUser(user string) (*iam.User, error) {
 	return userDetails.User, nil
 }
 
-// CreateIamUser creates an IAM user and returns user details.
-func (IamSvc *Svc) CreateIamUser(username string) (*iam.User, error) {
+// CreateIamUser creates an IAM user with the given tags and returns user details.
+func (IamSvc *Svc) CreateIamUser(username string, tags map[string]string) (*iam.User, error) {
 	user, err := IamSvc.GetIamUser(username)
 	if user != nil {
 		return nil, fmt.Errorf("CreateIamUser: %s %w", ErrIamUserExists, err)
@@ -46,6 +46,7 @@ func (IamSvc *Svc) CreateIamUser(username string) (*iam.User, error) {
 
 	createUserInput := &iam.CreateUserInput{
 		UserName: aws.String(username),
+		Tags:     toIamTags(tags),
 	}
 
 	createdUser, err := IamSvc.svc.CreateUser(createUserInput)
@@ -110,29 +111,35 @@ func (IamSvc *Svc) DeleteIamUser(username string) error {
 	return nil
 }
 
-// ListIamUsers lists IAM users and returns details.
+// ListIamUsers lists every IAM user.
 func (IamSvc *Svc) ListIamUsers() ([]*iam.User, error) {
-	listUsersInput := &iam.ListUsersInput{
-		MaxItems: aws.Int64(10),
-	}
+	var users []*iam.User
 
-	listUsersOutput, err := IamSvc.svc.ListUsers(listUsersInput)
+	err := IamSvc.svc.ListUsersPages(&iam.ListUsersInput{},
+		func(page *iam.ListUsersOutput, lastPage bool) bool {
+			users = append(users, page.Users...)
+			return !lastPage
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("ListIamUsers: %s %w", ErrIamUserEmptyList, err)
 	}
 
-	var users []*iam.User
+	return users, nil
+}
 
-	for _, user := range listUsersOutput.Users {
-		// Log or process the user details as needed
-		log.Logger.Infof("User Name: %s\n", *user.UserName)
-		log.Logger.Infof("User ID: %s\n", *user.UserId)
-		log.Logger.Infof("User ARN: %s\n", *user.Arn)
-		log.Logger.Infof("Create Date: %s\n", user.CreateDate)
+// ListIamUsersPage lists at most maxItems IAM users starting at marker, an empty marker starting at the first
+// user. It returns the marker of the next page, empty on the last page.
+func (IamSvc *Svc) ListIamUsersPage(marker string, maxItems int64) ([]*iam.User, string, error) {
+	input := &iam.ListUsersInput{MaxItems: aws.Int64(maxItems)}
+	if marker != "" {
+		input.Marker = aws.String(marker)
+	}
 
-		// Assuming you want to collect the user details
-		users = append(users, user)
+	output, err := IamSvc.svc.ListUsers(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("ListIamUsersPage: %w", err)
 	}
 
-	return users, nil
+	return output.Users, nextMarker(output.IsTruncated, output.Marker), nil
 }
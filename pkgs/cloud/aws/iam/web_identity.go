@@ -0,0 +1,112 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+)
+
+const (
+	ErrInvalidOIDCProviderArn  = "error invalid OIDC provider ARN"
+	ErrInvalidGitHubRepository = "error invalid GitHub repository, expected owner/name"
+	ErrInvalidGitHubBranch     = "error invalid GitHub branch"
+)
+
+const (
+	// GitHubActionsIssuer is the issuer of the OIDC tokens of GitHub Actions workflows.
+	GitHubActionsIssuer = "token.actions.githubusercontent.com"
+	// GitHubActionsAudience is the audience requested by the aws-actions/configure-aws-credentials action.
+	GitHubActionsAudience = "sts.amazonaws.com"
+)
+
+// ActionAssumeRoleWithWebIdentity is the STS action federating an OIDC identity into a role.
+const ActionAssumeRoleWithWebIdentity = "sts:AssumeRoleWithWebIdentity"
+
+var gitHubRepositoryPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// GitHubActionsProviderArn returns the ARN of the GitHub Actions OIDC provider of the account.
+func (IamSvc *Svc) GitHubActionsProviderArn() (string, error) {
+	providerArn, err := IamSvc.resourceArn("oidc-provider", GitHubActionsIssuer)
+	if err != nil {
+		return "", fmt.Errorf("GitHubActionsProviderArn: %w", err)
+	}
+
+	return providerArn, nil
+}
+
+// OIDCProviderIssuer returns the issuer of an OIDC provider, its host and path, from the provider ARN. It
+// prefixes the condition keys of the tokens, such as token.actions.githubusercontent.com:sub.
+func OIDCProviderIssuer(providerARN string) (string, error) {
+	parsed, err := arn.Parse(providerARN)
+	if err != nil || parsed.Service != "iam" {
+		return "", errors.New(ErrInvalidOIDCProviderArn)
+	}
+
+	issuer, found := strings.CutPrefix(parsed.Resource, "oidc-provider/")
+	if !found || issuer == "" {
+		return "", errors.New(ErrInvalidOIDCProviderArn)
+	}
+
+	return issuer, nil
+}
+
+// WebIdentityTrustPolicy returns the trust policy letting the identities of an OIDC provider assume a role
+// through AssumeRoleWithWebIdentity, when their token was issued for audience and its subject matches one of
+// subjects. Subjects holding * or ? wildcards are matched with StringLike.
+func WebIdentityTrustPolicy(providerARN string, audience string, subjects ...string) (PolicyDocument, error) {
+	issuer, err := OIDCProviderIssuer(providerARN)
+	if err != nil {
+		return PolicyDocument{}, fmt.Errorf("WebIdentityTrustPolicy: %w", err)
+	}
+
+	condition := Condition{
+		"StringEquals": {issuer + ":aud": NewStringOrSlice(audience)},
+	}
+	if len(subjects) > 0 {
+		operator := "StringEquals"
+		for _, subject := range subjects {
+			if strings.ContainsAny(subject, "*?") {
+				operator = "StringLike"
+			}
+		}
+		if condition[operator] == nil {
+			condition[operator] = map[string]StringOrSlice{}
+		}
+		condition[operator][issuer+":sub"] = NewStringOrSlice(subjects...)
+	}
+
+	return PolicyDocument{
+		Version: DefaultPolicyVersion,
+		Statement: []Statement{{
+			Effect: EffectAllow,
+			Principal: &Principal{Values: map[string]StringOrSlice{
+				"Federated": NewStringOrSlice(providerARN),
+			}},
+			Action:    NewStringOrSlice(ActionAssumeRoleWithWebIdentity),
+			Condition: condition,
+		}},
+	}, nil
+}
+
+// GitHubActionsTrustPolicy returns the trust policy letting the GitHub Actions workflows of a repository,
+// given as owner/name, assume a role when they run on branch. The branch may hold wildcards, such as
+// release/*, and providerARN is the GitHub Actions OIDC provider of the account.
+func GitHubActionsTrustPolicy(providerARN string, repository string, branch string) (PolicyDocument, error) {
+	if !gitHubRepositoryPattern.MatchString(repository) {
+		return PolicyDocument{}, fmt.Errorf("GitHubActionsTrustPolicy: %w", errors.New(ErrInvalidGitHubRepository))
+	}
+	if branch == "" || strings.ContainsAny(branch, " :\t\n") {
+		return PolicyDocument{}, fmt.Errorf("GitHubActionsTrustPolicy: %w", errors.New(ErrInvalidGitHubBranch))
+	}
+
+	subject := fmt.Sprintf("repo:%s:ref:refs/heads/%s", repository, strings.TrimPrefix(branch, "refs/heads/"))
+	trustPolicy, err := WebIdentityTrustPolicy(providerARN, GitHubActionsAudience, subject)
+	if err != nil {
+		return PolicyDocument{}, fmt.Errorf("GitHubActionsTrustPolicy: %w", err)
+	}
+
+	return trustPolicy, nil
+}
@@ -0,0 +1,85 @@
+package iam_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+const gitHubProviderArn = "arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"
+
+func TestOIDCProviderIssuer(t *testing.T) {
+	issuer, err := iam.OIDCProviderIssuer("arn:aws:iam::123456789012:oidc-provider/example.com/tenant")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/tenant", issuer)
+
+	for _, providerArn := range []string{
+		"token.actions.githubusercontent.com",
+		"arn:aws:iam::123456789012:saml-provider/Okta",
+		"arn:aws:iam::123456789012:oidc-provider/",
+		"arn:aws:s3:::oidc-provider/bucket",
+	} {
+		_, err = iam.OIDCProviderIssuer(providerArn)
+		assert.EqualError(t, err, iam.ErrInvalidOIDCProviderArn, providerArn)
+	}
+}
+
+func TestGitHubActionsTrustPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		branch     string
+		expected   string
+	}{
+		{
+			name:       "branch",
+			repository: "octo-org/app",
+			branch:     "main",
+			expected: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+				`"Principal":{"Federated":"` + gitHubProviderArn + `"},` +
+				`"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{` +
+				`"token.actions.githubusercontent.com:aud":"sts.amazonaws.com",` +
+				`"token.actions.githubusercontent.com:sub":"repo:octo-org/app:ref:refs/heads/main"}}}]}`,
+		},
+		{
+			name:       "branch wildcard",
+			repository: "octo-org/app",
+			branch:     "refs/heads/release/*",
+			expected: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow",` +
+				`"Principal":{"Federated":"` + gitHubProviderArn + `"},` +
+				`"Action":"sts:AssumeRoleWithWebIdentity","Condition":{` +
+				`"StringEquals":{"token.actions.githubusercontent.com:aud":"sts.amazonaws.com"},` +
+				`"StringLike":{"token.actions.githubusercontent.com:sub":"repo:octo-org/app:ref:refs/heads/release/*"}}}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			trustPolicy, err := iam.GitHubActionsTrustPolicy(gitHubProviderArn, test.repository, test.branch)
+			assert.NoError(t, err)
+			assert.NoError(t, trustPolicy.Validate())
+
+			encoded, err := json.Marshal(trustPolicy)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(encoded))
+		})
+	}
+
+	_, err := iam.GitHubActionsTrustPolicy(gitHubProviderArn, "app", "main")
+	assert.ErrorContains(t, err, iam.ErrInvalidGitHubRepository)
+
+	_, err = iam.GitHubActionsTrustPolicy(gitHubProviderArn, "octo-org/app", "")
+	assert.ErrorContains(t, err, iam.ErrInvalidGitHubBranch)
+
+	_, err = iam.GitHubActionsTrustPolicy("arn:aws:iam::123456789012:saml-provider/Okta", "octo-org/app", "main")
+	assert.ErrorContains(t, err, iam.ErrInvalidOIDCProviderArn)
+}
+
+func TestGitHubActionsProviderArn(t *testing.T) {
+	providerArn, err := iam.New(nil, "123456789012", iam.PartitionAws).GitHubActionsProviderArn()
+	assert.NoError(t, err)
+	assert.Equal(t, gitHubProviderArn, providerArn)
+}
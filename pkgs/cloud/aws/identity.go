@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	inaIam "gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+// CallerIdentity is the AWS account and principal the credentials of a session belong to.
+type CallerIdentity struct {
+	AccountID string
+	Arn       string
+	UserID    string
+	// Partition is the partition of the account, such as aws, aws-cn or aws-us-gov.
+	Partition string
+}
+
+// ResolveCallerIdentity retrieves the account and principal of the session credentials with STS
+// GetCallerIdentity and caches them on the session.
+func (awsSess *Session) ResolveCallerIdentity() (CallerIdentity, error) {
+	if awsSess.Identity.AccountID != "" {
+		return awsSess.Identity, nil
+	}
+
+	output, err := sts.New(awsSess.ClientSession).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return CallerIdentity{}, fmt.Errorf("ResolveCallerIdentity: %w", err)
+	}
+
+	identity := CallerIdentity{
+		AccountID: aws.StringValue(output.Account),
+		Arn:       aws.StringValue(output.Arn),
+		UserID:    aws.StringValue(output.UserId),
+	}
+
+	if callerArn, err := arn.Parse(identity.Arn); err == nil {
+		identity.Partition = callerArn.Partition
+	} else {
+		identity.Partition = inaIam.PartitionForRegion(aws.StringValue(awsSess.ClientSession.Config.Region))
+	}
+
+	awsSess.Identity = identity
+	return identity, nil
+}
@@ -14,6 +14,12 @@ type Session struct {
 	IamSvc            *inaIam.Svc
 	SecurityHubSvc    *SecurityHubSvc
 	CognitoSvc        *cognito.Svc
+	OrganizationsSvc  *OrganizationsSvc
+	SSOAdminSvc       *SSOAdminSvc
+
+	// Identity is the account and principal of the session credentials, resolved by ResolveCallerIdentity or set by
+	// the caller before the services are opened.
+	Identity CallerIdentity
 }
 
 type Credentials struct {
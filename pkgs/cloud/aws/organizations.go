@@ -0,0 +1,529 @@
+package aws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+
+	inaIam "gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrOrganizationNotInUse         = "error the account is not a member of an organization"
+	ErrOrganizationAccountNotExists = "error organization account does not exist"
+	ErrOrganizationTargetNotExists  = "error organization root, organizational unit or account does not exist"
+	ErrScpNotExists                 = "error service control policy does not exist"
+	ErrScpExists                    = "error service control policy already exists"
+	ErrScpTooLarge                  = "error service control policy exceeds 5120 characters"
+	ErrScpPrincipal                 = "error service control policies do not support Principal and NotPrincipal"
+)
+
+// MaxScpLength is the maximum length of the content of a service control policy, whitespace included.
+const MaxScpLength = 5120
+
+// Types of the targets service control policies attach to.
+const (
+	OrganizationTargetRoot               = organizations.TargetTypeRoot
+	OrganizationTargetOrganizationalUnit = organizations.TargetTypeOrganizationalUnit
+	OrganizationTargetAccount            = organizations.TargetTypeAccount
+)
+
+type OrganizationsSvc struct {
+	svc *organizations.Organizations
+}
+
+func (awsSess *Session) CreateOrganizationsSvc() {
+	if awsSess.OrganizationsSvc == nil {
+		awsSess.OrganizationsSvc = &OrganizationsSvc{organizations.New(awsSess.ClientSession)}
+	}
+}
+
+// Organization describes the organization of the session account.
+type Organization struct {
+	ID                 string
+	Arn                string
+	FeatureSet         string
+	MasterAccountID    string
+	MasterAccountEmail string
+}
+
+// OrganizationAccount is a member account of an organization.
+type OrganizationAccount struct {
+	ID              string
+	Arn             string
+	Name            string
+	Email           string
+	Status          string
+	JoinedMethod    string
+	JoinedTimestamp time.Time
+}
+
+// OrganizationalUnit is a node of the organization tree, the root or an organizational unit, with the accounts
+// it directly contains and its child organizational units.
+type OrganizationalUnit struct {
+	ID       string
+	Arn      string
+	Name     string
+	Type     string
+	Accounts []OrganizationAccount
+	Children []*OrganizationalUnit
+}
+
+// ServiceControlPolicy is a service control policy of an organization. Content is only set when the policy is
+// retrieved on its own.
+type ServiceControlPolicy struct {
+	ID          string
+	Arn         string
+	Name        string
+	Description string
+	AwsManaged  bool
+	Content     *inaIam.PolicyDocument
+}
+
+// PolicyTarget is a root, organizational unit or account a service control policy is attached to.
+type PolicyTarget struct {
+	ID   string
+	Arn  string
+	Name string
+	Type string
+}
+
+// EffectiveScpLevel lists the service control policies attached to a level of the path from the root of the
+// organization to an account. An action is only allowed to the account when every level allows it.
+type EffectiveScpLevel struct {
+	Target   PolicyTarget
+	Policies []ServiceControlPolicy
+}
+
+// wrapOrganizationsError adds the Inariam error matching the common Organizations failures to err.
+func wrapOrganizationsError(funcName string, err error) error {
+	switch {
+	case isAwsErrorCode(err, organizations.ErrCodeAWSOrganizationsNotInUseException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrOrganizationNotInUse, err)
+	case isAwsErrorCode(err, organizations.ErrCodeAccountNotFoundException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrOrganizationAccountNotExists, err)
+	case isAwsErrorCode(err, organizations.ErrCodeTargetNotFoundException),
+		isAwsErrorCode(err, organizations.ErrCodeOrganizationalUnitNotFoundException),
+		isAwsErrorCode(err, organizations.ErrCodeRootNotFoundException),
+		isAwsErrorCode(err, organizations.ErrCodeParentNotFoundException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrOrganizationTargetNotExists, err)
+	case isAwsErrorCode(err, organizations.ErrCodePolicyNotFoundException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrScpNotExists, err)
+	case isAwsErrorCode(err, organizations.ErrCodeDuplicatePolicyException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrScpExists, err)
+	}
+
+	return fmt.Errorf("%s: %w", funcName, err)
+}
+
+func toOrganizationAccount(account *organizations.Account) OrganizationAccount {
+	return OrganizationAccount{
+		ID:              aws.StringValue(account.Id),
+		Arn:             aws.StringValue(account.Arn),
+		Name:            aws.StringValue(account.Name),
+		Email:           aws.StringValue(account.Email),
+		Status:          aws.StringValue(account.Status),
+		JoinedMethod:    aws.StringValue(account.JoinedMethod),
+		JoinedTimestamp: aws.TimeValue(account.JoinedTimestamp),
+	}
+}
+
+func toServiceControlPolicy(summary *organizations.PolicySummary) ServiceControlPolicy {
+	return ServiceControlPolicy{
+		ID:          aws.StringValue(summary.Id),
+		Arn:         aws.StringValue(summary.Arn),
+		Name:        aws.StringValue(summary.Name),
+		Description: aws.StringValue(summary.Description),
+		AwsManaged:  aws.BoolValue(summary.AwsManaged),
+	}
+}
+
+// DescribeOrganization returns the organization the session account belongs to.
+func (organizationsSvc *OrganizationsSvc) DescribeOrganization() (*Organization, error) {
+	output, err := organizationsSvc.svc.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+	if err != nil {
+		return nil, wrapOrganizationsError("DescribeOrganization", err)
+	}
+
+	return &Organization{
+		ID:                 aws.StringValue(output.Organization.Id),
+		Arn:                aws.StringValue(output.Organization.Arn),
+		FeatureSet:         aws.StringValue(output.Organization.FeatureSet),
+		MasterAccountID:    aws.StringValue(output.Organization.MasterAccountId),
+		MasterAccountEmail: aws.StringValue(output.Organization.MasterAccountEmail),
+	}, nil
+}
+
+// ListAccounts lists every account of the organization.
+func (organizationsSvc *OrganizationsSvc) ListAccounts() ([]OrganizationAccount, error) {
+	var accounts []OrganizationAccount
+
+	err := organizationsSvc.svc.ListAccountsPages(&organizations.ListAccountsInput{},
+		func(page *organizations.ListAccountsOutput, lastPage bool) bool {
+			for _, account := range page.Accounts {
+				accounts = append(accounts, toOrganizationAccount(account))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapOrganizationsError("ListAccounts", err)
+	}
+
+	return accounts, nil
+}
+
+// GetAccount returns an account of the organization.
+func (organizationsSvc *OrganizationsSvc) GetAccount(accountID string) (*OrganizationAccount, error) {
+	output, err := organizationsSvc.svc.DescribeAccount(&organizations.DescribeAccountInput{
+		AccountId: aws.String(accountID),
+	})
+	if err != nil {
+		return nil, wrapOrganizationsError("GetAccount", err)
+	}
+
+	account := toOrganizationAccount(output.Account)
+	return &account, nil
+}
+
+// GetOrganizationTree returns the roots of the organization with their organizational units and accounts.
+// Organizations are limited to a single root, but the API returns a list.
+func (organizationsSvc *OrganizationsSvc) GetOrganizationTree() ([]*OrganizationalUnit, error) {
+	var roots []*OrganizationalUnit
+
+	err := organizationsSvc.svc.ListRootsPages(&organizations.ListRootsInput{},
+		func(page *organizations.ListRootsOutput, lastPage bool) bool {
+			for _, root := range page.Roots {
+				roots = append(roots, &OrganizationalUnit{
+					ID:   aws.StringValue(root.Id),
+					Arn:  aws.StringValue(root.Arn),
+					Name: aws.StringValue(root.Name),
+					Type: OrganizationTargetRoot,
+				})
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapOrganizationsError("GetOrganizationTree", err)
+	}
+
+	for _, root := range roots {
+		if err = organizationsSvc.fillOrganizationalUnit(root); err != nil {
+			return nil, fmt.Errorf("GetOrganizationTree: %w", err)
+		}
+	}
+
+	return roots, nil
+}
+
+// fillOrganizationalUnit retrieves the accounts and, recursively, the child organizational units of a node of
+// the organization tree.
+func (organizationsSvc *OrganizationsSvc) fillOrganizationalUnit(unit *OrganizationalUnit) error {
+	err := organizationsSvc.svc.ListAccountsForParentPages(&organizations.ListAccountsForParentInput{
+		ParentId: aws.String(unit.ID),
+	}, func(page *organizations.ListAccountsForParentOutput, lastPage bool) bool {
+		for _, account := range page.Accounts {
+			unit.Accounts = append(unit.Accounts, toOrganizationAccount(account))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return wrapOrganizationsError("fillOrganizationalUnit", err)
+	}
+
+	err = organizationsSvc.svc.ListOrganizationalUnitsForParentPages(&organizations.ListOrganizationalUnitsForParentInput{
+		ParentId: aws.String(unit.ID),
+	}, func(page *organizations.ListOrganizationalUnitsForParentOutput, lastPage bool) bool {
+		for _, child := range page.OrganizationalUnits {
+			unit.Children = append(unit.Children, &OrganizationalUnit{
+				ID:   aws.StringValue(child.Id),
+				Arn:  aws.StringValue(child.Arn),
+				Name: aws.StringValue(child.Name),
+				Type: OrganizationTargetOrganizationalUnit,
+			})
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return wrapOrganizationsError("fillOrganizationalUnit", err)
+	}
+
+	for _, child := range unit.Children {
+		if err = organizationsSvc.fillOrganizationalUnit(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateServiceControlPolicy checks a policy document against the restrictions of service control policies
+// on top of the IAM policy grammar.
+func ValidateServiceControlPolicy(policy inaIam.PolicyDocument) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("ValidateServiceControlPolicy: %w", err)
+	}
+
+	for i, statement := range policy.Statement {
+		if statement.Principal != nil || statement.NotPrincipal != nil {
+			return fmt.Errorf("ValidateServiceControlPolicy: Statement[%d]: %w", i, errors.New(ErrScpPrincipal))
+		}
+	}
+
+	if len(policy.String()) > MaxScpLength {
+		return fmt.Errorf("ValidateServiceControlPolicy: %w", errors.New(ErrScpTooLarge))
+	}
+
+	return nil
+}
+
+// ListServiceControlPolicies lists the service control policies of the organization, without their content.
+func (organizationsSvc *OrganizationsSvc) ListServiceControlPolicies() ([]ServiceControlPolicy, error) {
+	var policies []ServiceControlPolicy
+
+	err := organizationsSvc.svc.ListPoliciesPages(&organizations.ListPoliciesInput{
+		Filter: aws.String(organizations.PolicyTypeServiceControlPolicy),
+	}, func(page *organizations.ListPoliciesOutput, lastPage bool) bool {
+		for _, summary := range page.Policies {
+			policies = append(policies, toServiceControlPolicy(summary))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapOrganizationsError("ListServiceControlPolicies", err)
+	}
+
+	return policies, nil
+}
+
+// GetServiceControlPolicy returns a service control policy along with its content.
+func (organizationsSvc *OrganizationsSvc) GetServiceControlPolicy(policyID string) (*ServiceControlPolicy, error) {
+	output, err := organizationsSvc.svc.DescribePolicy(&organizations.DescribePolicyInput{
+		PolicyId: aws.String(policyID),
+	})
+	if err != nil {
+		return nil, wrapOrganizationsError("GetServiceControlPolicy", err)
+	}
+
+	policy := toServiceControlPolicy(output.Policy.PolicySummary)
+	policy.Content, err = inaIam.ParsePolicyDocument(aws.StringValue(output.Policy.Content))
+	if err != nil {
+		return nil, fmt.Errorf("GetServiceControlPolicy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// CreateServiceControlPolicy creates a service control policy. It is not attached to any target.
+func (organizationsSvc *OrganizationsSvc) CreateServiceControlPolicy(name string, description string,
+	content inaIam.PolicyDocument, tags map[string]string,
+) (*ServiceControlPolicy, error) {
+	if err := ValidateServiceControlPolicy(content); err != nil {
+		return nil, fmt.Errorf("CreateServiceControlPolicy: %w", err)
+	}
+
+	encodedContent, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("CreateServiceControlPolicy: %s %w", inaIam.ErrMarshallingPolicy, err)
+	}
+
+	var organizationsTags []*organizations.Tag
+	for key, value := range tags {
+		organizationsTags = append(organizationsTags, &organizations.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	output, err := organizationsSvc.svc.CreatePolicy(&organizations.CreatePolicyInput{
+		Name:        aws.String(name),
+		Description: aws.String(description),
+		Content:     aws.String(string(encodedContent)),
+		Type:        aws.String(organizations.PolicyTypeServiceControlPolicy),
+		Tags:        organizationsTags,
+	})
+	if err != nil {
+		return nil, wrapOrganizationsError("CreateServiceControlPolicy", err)
+	}
+
+	log.Logger.Infof("Service control policy '%s' created successfully\n", name)
+
+	policy := toServiceControlPolicy(output.Policy.PolicySummary)
+	policy.Content = &content
+	return &policy, nil
+}
+
+// UpdateServiceControlPolicy updates the name, description or content of a service control policy. Nil values
+// are left unchanged.
+func (organizationsSvc *OrganizationsSvc) UpdateServiceControlPolicy(policyID string, name *string,
+	description *string, content *inaIam.PolicyDocument,
+) (*ServiceControlPolicy, error) {
+	input := &organizations.UpdatePolicyInput{
+		PolicyId:    aws.String(policyID),
+		Name:        name,
+		Description: description,
+	}
+
+	if content != nil {
+		if err := ValidateServiceControlPolicy(*content); err != nil {
+			return nil, fmt.Errorf("UpdateServiceControlPolicy: %w", err)
+		}
+
+		encodedContent, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("UpdateServiceControlPolicy: %s %w", inaIam.ErrMarshallingPolicy, err)
+		}
+		input.Content = aws.String(string(encodedContent))
+	}
+
+	_, err := organizationsSvc.svc.UpdatePolicy(input)
+	if err != nil {
+		return nil, wrapOrganizationsError("UpdateServiceControlPolicy", err)
+	}
+
+	log.Logger.Infof("Service control policy '%s' updated successfully\n", policyID)
+	return organizationsSvc.GetServiceControlPolicy(policyID)
+}
+
+// DeleteServiceControlPolicy deletes a service control policy, which must be detached from every target first.
+func (organizationsSvc *OrganizationsSvc) DeleteServiceControlPolicy(policyID string) error {
+	_, err := organizationsSvc.svc.DeletePolicy(&organizations.DeletePolicyInput{
+		PolicyId: aws.String(policyID),
+	})
+	if err != nil {
+		return wrapOrganizationsError("DeleteServiceControlPolicy", err)
+	}
+
+	log.Logger.Infof("Service control policy '%s' deleted successfully\n", policyID)
+	return nil
+}
+
+// AttachServiceControlPolicy attaches a service control policy to a root, an organizational unit or an account.
+func (organizationsSvc *OrganizationsSvc) AttachServiceControlPolicy(policyID string, targetID string) error {
+	_, err := organizationsSvc.svc.AttachPolicy(&organizations.AttachPolicyInput{
+		PolicyId: aws.String(policyID),
+		TargetId: aws.String(targetID),
+	})
+	if err != nil {
+		return wrapOrganizationsError("AttachServiceControlPolicy", err)
+	}
+
+	log.Logger.Infof("Service control policy '%s' attached to '%s' successfully\n", policyID, targetID)
+	return nil
+}
+
+// DetachServiceControlPolicy detaches a service control policy from a root, an organizational unit or an account.
+func (organizationsSvc *OrganizationsSvc) DetachServiceControlPolicy(policyID string, targetID string) error {
+	_, err := organizationsSvc.svc.DetachPolicy(&organizations.DetachPolicyInput{
+		PolicyId: aws.String(policyID),
+		TargetId: aws.String(targetID),
+	})
+	if err != nil {
+		return wrapOrganizationsError("DetachServiceControlPolicy", err)
+	}
+
+	log.Logger.Infof("Service control policy '%s' detached from '%s' successfully\n", policyID, targetID)
+	return nil
+}
+
+// ListServiceControlPolicyTargets lists the roots, organizational units and accounts a service control policy
+// is attached to.
+func (organizationsSvc *OrganizationsSvc) ListServiceControlPolicyTargets(policyID string) ([]PolicyTarget, error) {
+	var targets []PolicyTarget
+
+	err := organizationsSvc.svc.ListTargetsForPolicyPages(&organizations.ListTargetsForPolicyInput{
+		PolicyId: aws.String(policyID),
+	}, func(page *organizations.ListTargetsForPolicyOutput, lastPage bool) bool {
+		for _, target := range page.Targets {
+			targets = append(targets, PolicyTarget{
+				ID:   aws.StringValue(target.TargetId),
+				Arn:  aws.StringValue(target.Arn),
+				Name: aws.StringValue(target.Name),
+				Type: aws.StringValue(target.Type),
+			})
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapOrganizationsError("ListServiceControlPolicyTargets", err)
+	}
+
+	return targets, nil
+}
+
+// listServiceControlPoliciesForTarget lists the service control policies directly attached to a target.
+func (organizationsSvc *OrganizationsSvc) listServiceControlPoliciesForTarget(targetID string,
+) ([]ServiceControlPolicy, error) {
+	var policies []ServiceControlPolicy
+
+	err := organizationsSvc.svc.ListPoliciesForTargetPages(&organizations.ListPoliciesForTargetInput{
+		TargetId: aws.String(targetID),
+		Filter:   aws.String(organizations.PolicyTypeServiceControlPolicy),
+	}, func(page *organizations.ListPoliciesForTargetOutput, lastPage bool) bool {
+		for _, summary := range page.Policies {
+			policies = append(policies, toServiceControlPolicy(summary))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapOrganizationsError("listServiceControlPoliciesForTarget", err)
+	}
+
+	return policies, nil
+}
+
+// GetEffectiveServiceControlPolicies returns the service control policies applying to an account, level by
+// level from the root of the organization down to the account itself.
+func (organizationsSvc *OrganizationsSvc) GetEffectiveServiceControlPolicies(accountID string,
+) ([]EffectiveScpLevel, error) {
+	account, err := organizationsSvc.GetAccount(accountID)
+	if err != nil {
+		return nil, fmt.Errorf("GetEffectiveServiceControlPolicies: %w", err)
+	}
+
+	path := []PolicyTarget{{ID: account.ID, Arn: account.Arn, Name: account.Name, Type: OrganizationTargetAccount}}
+	for childID := account.ID; ; {
+		parents, err := organizationsSvc.svc.ListParents(&organizations.ListParentsInput{ChildId: aws.String(childID)})
+		if err != nil {
+			return nil, wrapOrganizationsError("GetEffectiveServiceControlPolicies", err)
+		}
+		if len(parents.Parents) == 0 {
+			break
+		}
+
+		parent := PolicyTarget{
+			ID:   aws.StringValue(parents.Parents[0].Id),
+			Type: aws.StringValue(parents.Parents[0].Type),
+		}
+		if parent.Type == OrganizationTargetOrganizationalUnit {
+			unit, err := organizationsSvc.svc.DescribeOrganizationalUnit(&organizations.DescribeOrganizationalUnitInput{
+				OrganizationalUnitId: aws.String(parent.ID),
+			})
+			if err != nil {
+				return nil, wrapOrganizationsError("GetEffectiveServiceControlPolicies", err)
+			}
+			parent.Arn = aws.StringValue(unit.OrganizationalUnit.Arn)
+			parent.Name = aws.StringValue(unit.OrganizationalUnit.Name)
+		}
+		path = append([]PolicyTarget{parent}, path...)
+
+		if parent.Type == OrganizationTargetRoot {
+			break
+		}
+		childID = parent.ID
+	}
+
+	levels := make([]EffectiveScpLevel, 0, len(path))
+	for _, target := range path {
+		policies, err := organizationsSvc.listServiceControlPoliciesForTarget(target.ID)
+		if err != nil {
+			return nil, fmt.Errorf("GetEffectiveServiceControlPolicies: %w", err)
+		}
+		levels = append(levels, EffectiveScpLevel{Target: target, Policies: policies})
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,55 @@
+package aws_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+	inaIam "gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+)
+
+func TestValidateServiceControlPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{
+			name: "deny region",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"*","Resource":"*",` +
+				`"Condition":{"StringNotEquals":{"aws:RequestedRegion":["eu-west-1"]}}}]}`,
+		},
+		{
+			name:     "principal",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":"*","Action":"*","Resource":"*"}]}`,
+			err:      awsCloud.ErrScpPrincipal,
+		},
+		{
+			name: "too large",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":["` +
+				strings.Repeat("s3:GetObject\",\"", 400) + `s3:GetObject"],"Resource":"*"}]}`,
+			err: awsCloud.ErrScpTooLarge,
+		},
+		{
+			name:     "invalid",
+			document: `{"Version":"2012-10-17","Statement":[]}`,
+			err:      inaIam.ErrInvalidPolicy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := inaIam.ParsePolicyDocument(test.document)
+			assert.NoError(t, err)
+
+			err = awsCloud.ValidateServiceControlPolicy(*policy)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
@@ -2,10 +2,23 @@ package aws
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/securityhub"
 
 	"gitea/pcp-inariam/inariam/pkgs/log"
+)
 
-	"github.com/aws/aws-sdk-go/service/securityhub"
+const (
+	ErrSecurityHubNotEnabled = "error Security Hub is not enabled"
+)
+
+const (
+	// securityHubMaxResults is the largest page size accepted by GetFindings.
+	securityHubMaxResults = 100
+	// securityHubMaxFindingIdentifiers is the largest number of findings accepted by a single BatchUpdateFindings.
+	securityHubMaxFindingIdentifiers = 100
 )
 
 type SecurityHubSvc struct {
@@ -13,19 +26,194 @@ type SecurityHubSvc struct {
 }
 
 func (awsSess *Session) CreateSecurityHubSvc() {
-
 	if awsSess.SecurityHubSvc == nil {
-		return
+		awsSess.SecurityHubSvc = &SecurityHubSvc{securityhub.New(awsSess.ClientSession)}
 	}
+}
+
+// SecurityHubFinding is a finding in the AWS Security Finding Format, reduced to the fields used by Inariam.
+// The resource is the first resource of the finding.
+type SecurityHubFinding struct {
+	ID               string
+	ProductArn       string
+	ProductName      string
+	GeneratorID      string
+	AccountID        string
+	Region           string
+	Types            []string
+	Title            string
+	Description      string
+	SeverityLabel    string
+	Severity         int64
+	ComplianceStatus string
+	WorkflowStatus   string
+	RecordState      string
+	ResourceType     string
+	ResourceID       string
+	Note             *SecurityHubNote
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// SecurityHubNote is the note of a finding, set by a user to track its investigation.
+type SecurityHubNote struct {
+	Text      string
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// SecurityHubFindingIdentifier identifies a finding, finding IDs being unique per product.
+type SecurityHubFindingIdentifier struct {
+	ID         string
+	ProductArn string
+}
+
+// SecurityHubFindingFilter selects the Security Hub findings to list. Each list matches any of its values and
+// zero values do not filter, except RecordState which defaults to the active findings. Types are matched as
+// prefixes, such as "Software and Configuration Checks/AWS Security Best Practices".
+type SecurityHubFindingFilter struct {
+	SeverityLabels     []string
+	WorkflowStatuses   []string
+	ComplianceStatuses []string
+	ProductNames       []string
+	ResourceTypes      []string
+	AccountIDs         []string
+	Types              []string
+	RecordState        string
+	UpdatedAfter       time.Time
+	UpdatedBefore      time.Time
+}
+
+// SecurityHubFindingUpdate is the workflow change applied to findings. Empty fields are left unchanged, the note
+// requiring both its text and author.
+type SecurityHubFindingUpdate struct {
+	WorkflowStatus string
+	NoteText       string
+	NoteUpdatedBy  string
+}
 
-	awsSess.SecurityHubSvc = &SecurityHubSvc{
-		securityhub.New(awsSess.ClientSession),
+// SecurityHubUpdateResult is the outcome of the update of a single finding.
+type SecurityHubUpdateResult struct {
+	Finding SecurityHubFindingIdentifier
+	Err     error
+}
+
+func securityHubStringFilters(comparison string, values []string) []*securityhub.StringFilter {
+	filters := make([]*securityhub.StringFilter, 0, len(values))
+	for _, value := range values {
+		filters = append(filters, &securityhub.StringFilter{
+			Comparison: aws.String(comparison),
+			Value:      aws.String(value),
+		})
 	}
 
+	return filters
 }
 
-func (securityHubSvc *SecurityHubSvc) CheckIfEnabled() (bool, error) {
+// Filters returns the AWS Security Finding Format filters matching the filter.
+func (filter SecurityHubFindingFilter) Filters() *securityhub.AwsSecurityFindingFilters {
+	recordState := filter.RecordState
+	if recordState == "" {
+		recordState = securityhub.RecordStateActive
+	}
+
+	filters := &securityhub.AwsSecurityFindingFilters{
+		RecordState: securityHubStringFilters(securityhub.StringFilterComparisonEquals, []string{recordState}),
+	}
+	if len(filter.SeverityLabels) > 0 {
+		filters.SeverityLabel = securityHubStringFilters(securityhub.StringFilterComparisonEquals, filter.SeverityLabels)
+	}
+	if len(filter.WorkflowStatuses) > 0 {
+		filters.WorkflowStatus = securityHubStringFilters(securityhub.StringFilterComparisonEquals,
+			filter.WorkflowStatuses)
+	}
+	if len(filter.ComplianceStatuses) > 0 {
+		filters.ComplianceStatus = securityHubStringFilters(securityhub.StringFilterComparisonEquals,
+			filter.ComplianceStatuses)
+	}
+	if len(filter.ProductNames) > 0 {
+		filters.ProductName = securityHubStringFilters(securityhub.StringFilterComparisonEquals, filter.ProductNames)
+	}
+	if len(filter.ResourceTypes) > 0 {
+		filters.ResourceType = securityHubStringFilters(securityhub.StringFilterComparisonEquals, filter.ResourceTypes)
+	}
+	if len(filter.AccountIDs) > 0 {
+		filters.AwsAccountId = securityHubStringFilters(securityhub.StringFilterComparisonEquals, filter.AccountIDs)
+	}
+	if len(filter.Types) > 0 {
+		filters.Type = securityHubStringFilters(securityhub.StringFilterComparisonPrefix, filter.Types)
+	}
 
+	if !filter.UpdatedAfter.IsZero() || !filter.UpdatedBefore.IsZero() {
+		updatedAt := &securityhub.DateFilter{}
+		if !filter.UpdatedAfter.IsZero() {
+			updatedAt.Start = aws.String(filter.UpdatedAfter.UTC().Format(time.RFC3339))
+		}
+		if !filter.UpdatedBefore.IsZero() {
+			updatedAt.End = aws.String(filter.UpdatedBefore.UTC().Format(time.RFC3339))
+		}
+		filters.UpdatedAt = []*securityhub.DateFilter{updatedAt}
+	}
+
+	return filters
+}
+
+func parseSecurityHubTime(value *string) time.Time {
+	parsed, _ := time.Parse(time.RFC3339Nano, aws.StringValue(value))
+	return parsed
+}
+
+// toSecurityHubFinding normalizes a finding in the AWS Security Finding Format.
+func toSecurityHubFinding(finding *securityhub.AwsSecurityFinding) SecurityHubFinding {
+	securityHubFinding := SecurityHubFinding{
+		ID:          aws.StringValue(finding.Id),
+		ProductArn:  aws.StringValue(finding.ProductArn),
+		ProductName: aws.StringValue(finding.ProductName),
+		GeneratorID: aws.StringValue(finding.GeneratorId),
+		AccountID:   aws.StringValue(finding.AwsAccountId),
+		Region:      aws.StringValue(finding.Region),
+		Types:       aws.StringValueSlice(finding.Types),
+		Title:       aws.StringValue(finding.Title),
+		Description: aws.StringValue(finding.Description),
+		RecordState: aws.StringValue(finding.RecordState),
+		CreatedAt:   parseSecurityHubTime(finding.CreatedAt),
+		UpdatedAt:   parseSecurityHubTime(finding.UpdatedAt),
+	}
+	if finding.Severity != nil {
+		securityHubFinding.SeverityLabel = aws.StringValue(finding.Severity.Label)
+		securityHubFinding.Severity = aws.Int64Value(finding.Severity.Normalized)
+	}
+	if finding.Compliance != nil {
+		securityHubFinding.ComplianceStatus = aws.StringValue(finding.Compliance.Status)
+	}
+	if finding.Workflow != nil {
+		securityHubFinding.WorkflowStatus = aws.StringValue(finding.Workflow.Status)
+	}
+	if len(finding.Resources) > 0 {
+		securityHubFinding.ResourceType = aws.StringValue(finding.Resources[0].Type)
+		securityHubFinding.ResourceID = aws.StringValue(finding.Resources[0].Id)
+	}
+	if finding.Note != nil {
+		securityHubFinding.Note = &SecurityHubNote{
+			Text:      aws.StringValue(finding.Note.Text),
+			UpdatedBy: aws.StringValue(finding.Note.UpdatedBy),
+			UpdatedAt: parseSecurityHubTime(finding.Note.UpdatedAt),
+		}
+	}
+
+	return securityHubFinding
+}
+
+// wrapSecurityHubError adds the Inariam error matching the common Security Hub failures to err.
+func wrapSecurityHubError(funcName string, err error) error {
+	if isAwsErrorCode(err, securityhub.ErrCodeInvalidAccessException) {
+		return fmt.Errorf("%s: %s %w", funcName, ErrSecurityHubNotEnabled, err)
+	}
+
+	return fmt.Errorf("%s: %w", funcName, err)
+}
+
+func (securityHubSvc *SecurityHubSvc) CheckIfEnabled() (bool, error) {
 	resp, err := securityHubSvc.svc.DescribeHub(&securityhub.DescribeHubInput{})
 	if err != nil {
 		log.Logger.Infoln(err)
@@ -36,6 +224,92 @@ func (securityHubSvc *SecurityHubSvc) CheckIfEnabled() (bool, error) {
 		return false, err
 	}
 
-	fmt.Printf("ARN: %s,  Activation Date: %s, ", *resp.HubArn, *resp.SubscribedAt)
+	log.Logger.Infof("Security Hub '%s' enabled since %s\n", *resp.HubArn, aws.StringValue(resp.SubscribedAt))
 	return true, nil
 }
+
+// GetFindings returns at most pageSize findings matching the filter, starting at marker, an empty marker
+// starting at the first finding. It returns the marker of the next page, empty on the last page.
+func (securityHubSvc *SecurityHubSvc) GetFindings(filter SecurityHubFindingFilter, marker string, pageSize int64,
+) ([]SecurityHubFinding, string, error) {
+	if pageSize > securityHubMaxResults {
+		pageSize = securityHubMaxResults
+	}
+
+	input := &securityhub.GetFindingsInput{
+		Filters:    filter.Filters(),
+		MaxResults: aws.Int64(pageSize),
+	}
+	if marker != "" {
+		input.NextToken = aws.String(marker)
+	}
+
+	output, err := securityHubSvc.svc.GetFindings(input)
+	if err != nil {
+		return nil, "", wrapSecurityHubError("GetFindings", err)
+	}
+
+	findings := make([]SecurityHubFinding, 0, len(output.Findings))
+	for _, finding := range output.Findings {
+		findings = append(findings, toSecurityHubFinding(finding))
+	}
+
+	return findings, aws.StringValue(output.NextToken), nil
+}
+
+// BatchUpdateFindings sets the workflow status or the note of findings, in batches of at most 100 findings.
+// It returns the outcome of the update of each finding, Security Hub rejecting findings individually.
+func (securityHubSvc *SecurityHubSvc) BatchUpdateFindings(findings []SecurityHubFindingIdentifier,
+	update SecurityHubFindingUpdate,
+) ([]SecurityHubUpdateResult, error) {
+	results := make([]SecurityHubUpdateResult, 0, len(findings))
+
+	for start := 0; start < len(findings); start += securityHubMaxFindingIdentifiers {
+		end := start + securityHubMaxFindingIdentifiers
+		if end > len(findings) {
+			end = len(findings)
+		}
+
+		input := &securityhub.BatchUpdateFindingsInput{}
+		for _, finding := range findings[start:end] {
+			input.FindingIdentifiers = append(input.FindingIdentifiers, &securityhub.AwsSecurityFindingIdentifier{
+				Id:         aws.String(finding.ID),
+				ProductArn: aws.String(finding.ProductArn),
+			})
+		}
+		if update.WorkflowStatus != "" {
+			input.Workflow = &securityhub.WorkflowUpdate{Status: aws.String(update.WorkflowStatus)}
+		}
+		if update.NoteText != "" {
+			input.Note = &securityhub.NoteUpdate{
+				Text:      aws.String(update.NoteText),
+				UpdatedBy: aws.String(update.NoteUpdatedBy),
+			}
+		}
+
+		output, err := securityHubSvc.svc.BatchUpdateFindings(input)
+		if err != nil {
+			return results, wrapSecurityHubError("BatchUpdateFindings", err)
+		}
+
+		for _, processed := range output.ProcessedFindings {
+			results = append(results, SecurityHubUpdateResult{Finding: SecurityHubFindingIdentifier{
+				ID:         aws.StringValue(processed.Id),
+				ProductArn: aws.StringValue(processed.ProductArn),
+			}})
+		}
+		for _, unprocessed := range output.UnprocessedFindings {
+			results = append(results, SecurityHubUpdateResult{
+				Finding: SecurityHubFindingIdentifier{
+					ID:         aws.StringValue(unprocessed.FindingIdentifier.Id),
+					ProductArn: aws.StringValue(unprocessed.FindingIdentifier.ProductArn),
+				},
+				Err: fmt.Errorf("BatchUpdateFindings: %s: %s", aws.StringValue(unprocessed.ErrorCode),
+					aws.StringValue(unprocessed.ErrorMessage)),
+			})
+		}
+	}
+
+	log.Logger.Infof("Update of %d Security Hub findings requested\n", len(findings))
+	return results, nil
+}
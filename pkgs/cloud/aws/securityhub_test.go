@@ -0,0 +1,45 @@
+package aws_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/securityhub"
+	"github.com/stretchr/testify/assert"
+
+	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+func TestCreateSecurityHubSvc(t *testing.T) {
+	clientSession, err := session.NewSession(&aws.Config{Region: aws.String("eu-west-1")})
+	assert.NoError(t, err)
+
+	awsSess := &awsCloud.Session{ClientSession: clientSession}
+	awsSess.CreateSecurityHubSvc()
+	assert.NotNil(t, awsSess.SecurityHubSvc)
+}
+
+func TestSecurityHubFindingFilterFilters(t *testing.T) {
+	filters := awsCloud.SecurityHubFindingFilter{}.Filters()
+	assert.Len(t, filters.RecordState, 1)
+	assert.Equal(t, securityhub.RecordStateActive, aws.StringValue(filters.RecordState[0].Value))
+	assert.Nil(t, filters.SeverityLabel)
+	assert.Nil(t, filters.UpdatedAt)
+
+	after := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	filters = awsCloud.SecurityHubFindingFilter{
+		SeverityLabels: []string{"HIGH", "CRITICAL"},
+		Types:          []string{"Software and Configuration Checks"},
+		RecordState:    securityhub.RecordStateArchived,
+		UpdatedAfter:   after,
+	}.Filters()
+	assert.Equal(t, securityhub.RecordStateArchived, aws.StringValue(filters.RecordState[0].Value))
+	assert.Len(t, filters.SeverityLabel, 2)
+	assert.Equal(t, securityhub.StringFilterComparisonEquals, aws.StringValue(filters.SeverityLabel[1].Comparison))
+	assert.Equal(t, "CRITICAL", aws.StringValue(filters.SeverityLabel[1].Value))
+	assert.Equal(t, securityhub.StringFilterComparisonPrefix, aws.StringValue(filters.Type[0].Comparison))
+	assert.Equal(t, "2023-08-01T00:00:00Z", aws.StringValue(filters.UpdatedAt[0].Start))
+	assert.Nil(t, filters.UpdatedAt[0].End)
+}
@@ -0,0 +1,472 @@
+package aws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssoadmin"
+
+	inaIam "gitea/pcp-inariam/inariam/pkgs/cloud/aws/iam"
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+const (
+	ErrSSONoInstance           = "error no IAM Identity Center instance is enabled"
+	ErrSSOResourceNotExists    = "error IAM Identity Center resource does not exist"
+	ErrSSOConflict             = "error IAM Identity Center resource is being modified or already exists"
+	ErrSSOInvalidOperationKind = "error invalid IAM Identity Center operation kind"
+	ErrSSOOperationFailed      = "error IAM Identity Center operation failed"
+	ErrSSOOperationTimeout     = "error IAM Identity Center operation did not complete in time"
+	ErrSSOInvalidPrincipalType = "error invalid principal type, expected USER or GROUP"
+)
+
+// Kinds of the asynchronous IAM Identity Center operations.
+const (
+	SSOOperationAssignmentCreation = "assignment-creation"
+	SSOOperationAssignmentDeletion = "assignment-deletion"
+	SSOOperationProvisioning       = "provisioning"
+)
+
+// Statuses of the asynchronous IAM Identity Center operations.
+const (
+	SSOOperationInProgress = ssoadmin.StatusValuesInProgress
+	SSOOperationSucceeded  = ssoadmin.StatusValuesSucceeded
+	SSOOperationFailed     = ssoadmin.StatusValuesFailed
+)
+
+// Principal types of account assignments.
+const (
+	SSOPrincipalUser  = ssoadmin.PrincipalTypeUser
+	SSOPrincipalGroup = ssoadmin.PrincipalTypeGroup
+)
+
+const (
+	// ssoOperationPollInterval is the delay between two status calls while an operation is in progress.
+	ssoOperationPollInterval = 2 * time.Second
+	// ssoOperationMaxPolls bounds how many times the status of an operation is polled.
+	ssoOperationMaxPolls = 30
+)
+
+type SSOAdminSvc struct {
+	svc *ssoadmin.SSOAdmin
+}
+
+func (awsSess *Session) CreateSSOAdminSvc() {
+	if awsSess.SSOAdminSvc == nil {
+		awsSess.SSOAdminSvc = &SSOAdminSvc{ssoadmin.New(awsSess.ClientSession)}
+	}
+}
+
+// SSOInstance is an IAM Identity Center instance and the identity store holding its users and groups.
+type SSOInstance struct {
+	InstanceArn     string
+	IdentityStoreID string
+}
+
+// PermissionSet is an IAM Identity Center permission set. SessionDuration is an ISO 8601 duration such as PT8H.
+type PermissionSet struct {
+	Arn             string
+	Name            string
+	Description     string
+	SessionDuration string
+	RelayState      string
+	CreatedDate     time.Time
+}
+
+// ManagedPolicyReference is an AWS managed policy attached to a permission set.
+type ManagedPolicyReference struct {
+	Arn  string
+	Name string
+}
+
+// CustomerManagedPolicyReference references, by name and path, a customer managed policy that must exist in
+// every account the permission set is provisioned to.
+type CustomerManagedPolicyReference struct {
+	Name string
+	Path string
+}
+
+// PermissionSetPolicies are the policies granting the permissions of a permission set.
+type PermissionSetPolicies struct {
+	ManagedPolicies         []ManagedPolicyReference
+	CustomerManagedPolicies []CustomerManagedPolicyReference
+	InlinePolicy            *inaIam.PolicyDocument
+}
+
+// AccountAssignment grants a user or a group of the identity store access to an account through a permission set.
+type AccountAssignment struct {
+	AccountID        string
+	PermissionSetArn string
+	PrincipalType    string
+	PrincipalID      string
+}
+
+// SSOOperation is the status of an asynchronous IAM Identity Center operation, an account assignment creation
+// or deletion, or the provisioning of a permission set to an account.
+type SSOOperation struct {
+	RequestID        string
+	Kind             string
+	Status           string
+	FailureReason    string
+	PermissionSetArn string
+	AccountID        string
+	PrincipalType    string
+	PrincipalID      string
+	CreatedDate      time.Time
+}
+
+// Done reports whether the operation is over, successfully or not.
+func (operation *SSOOperation) Done() bool {
+	return operation.Status != SSOOperationInProgress
+}
+
+// wrapSSOAdminError adds the Inariam error matching the common IAM Identity Center failures to err.
+func wrapSSOAdminError(funcName string, err error) error {
+	switch {
+	case isAwsErrorCode(err, ssoadmin.ErrCodeResourceNotFoundException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrSSOResourceNotExists, err)
+	case isAwsErrorCode(err, ssoadmin.ErrCodeConflictException):
+		return fmt.Errorf("%s: %s %w", funcName, ErrSSOConflict, err)
+	}
+
+	return fmt.Errorf("%s: %w", funcName, err)
+}
+
+// ListInstances lists the IAM Identity Center instances, an organization has at most one.
+func (ssoAdminSvc *SSOAdminSvc) ListInstances() ([]SSOInstance, error) {
+	var instances []SSOInstance
+
+	err := ssoAdminSvc.svc.ListInstancesPages(&ssoadmin.ListInstancesInput{},
+		func(page *ssoadmin.ListInstancesOutput, lastPage bool) bool {
+			for _, instance := range page.Instances {
+				instances = append(instances, SSOInstance{
+					InstanceArn:     aws.StringValue(instance.InstanceArn),
+					IdentityStoreID: aws.StringValue(instance.IdentityStoreId),
+				})
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, wrapSSOAdminError("ListInstances", err)
+	}
+
+	return instances, nil
+}
+
+// DefaultInstanceArn returns the ARN of the IAM Identity Center instance of the organization.
+func (ssoAdminSvc *SSOAdminSvc) DefaultInstanceArn() (string, error) {
+	instances, err := ssoAdminSvc.ListInstances()
+	if err != nil {
+		return "", fmt.Errorf("DefaultInstanceArn: %w", err)
+	}
+	if len(instances) == 0 {
+		return "", fmt.Errorf("DefaultInstanceArn: %w", errors.New(ErrSSONoInstance))
+	}
+
+	return instances[0].InstanceArn, nil
+}
+
+// ListPermissionSets lists the permission sets of an instance along with their details.
+func (ssoAdminSvc *SSOAdminSvc) ListPermissionSets(instanceArn string) ([]*PermissionSet, error) {
+	var permissionSetArns []string
+
+	err := ssoAdminSvc.svc.ListPermissionSetsPages(&ssoadmin.ListPermissionSetsInput{
+		InstanceArn: aws.String(instanceArn),
+	}, func(page *ssoadmin.ListPermissionSetsOutput, lastPage bool) bool {
+		permissionSetArns = append(permissionSetArns, aws.StringValueSlice(page.PermissionSets)...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("ListPermissionSets", err)
+	}
+
+	permissionSets := make([]*PermissionSet, 0, len(permissionSetArns))
+	for _, permissionSetArn := range permissionSetArns {
+		permissionSet, err := ssoAdminSvc.GetPermissionSet(instanceArn, permissionSetArn)
+		if err != nil {
+			return nil, fmt.Errorf("ListPermissionSets: %w", err)
+		}
+		permissionSets = append(permissionSets, permissionSet)
+	}
+
+	return permissionSets, nil
+}
+
+func toPermissionSet(permissionSet *ssoadmin.PermissionSet) *PermissionSet {
+	return &PermissionSet{
+		Arn:             aws.StringValue(permissionSet.PermissionSetArn),
+		Name:            aws.StringValue(permissionSet.Name),
+		Description:     aws.StringValue(permissionSet.Description),
+		SessionDuration: aws.StringValue(permissionSet.SessionDuration),
+		RelayState:      aws.StringValue(permissionSet.RelayState),
+		CreatedDate:     aws.TimeValue(permissionSet.CreatedDate),
+	}
+}
+
+// GetPermissionSet returns a permission set of an instance.
+func (ssoAdminSvc *SSOAdminSvc) GetPermissionSet(instanceArn string, permissionSetArn string) (*PermissionSet, error) {
+	output, err := ssoAdminSvc.svc.DescribePermissionSet(&ssoadmin.DescribePermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("GetPermissionSet", err)
+	}
+
+	return toPermissionSet(output.PermissionSet), nil
+}
+
+// CreatePermissionSet creates a permission set without any policy. Empty optional values keep the AWS defaults,
+// a one hour session notably.
+func (ssoAdminSvc *SSOAdminSvc) CreatePermissionSet(instanceArn string, name string, description string,
+	sessionDuration string, relayState string, tags map[string]string,
+) (*PermissionSet, error) {
+	input := &ssoadmin.CreatePermissionSetInput{
+		InstanceArn: aws.String(instanceArn),
+		Name:        aws.String(name),
+	}
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+	if sessionDuration != "" {
+		input.SessionDuration = aws.String(sessionDuration)
+	}
+	if relayState != "" {
+		input.RelayState = aws.String(relayState)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		input.Tags = append(input.Tags, &ssoadmin.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	output, err := ssoAdminSvc.svc.CreatePermissionSet(input)
+	if err != nil {
+		return nil, wrapSSOAdminError("CreatePermissionSet", err)
+	}
+
+	log.Logger.Infof("Permission set '%s' created successfully\n", name)
+	return toPermissionSet(output.PermissionSet), nil
+}
+
+// UpdatePermissionSet updates the description, session duration or relay state of a permission set. Nil values
+// are left unchanged. The accounts the permission set is provisioned to must be provisioned again.
+func (ssoAdminSvc *SSOAdminSvc) UpdatePermissionSet(instanceArn string, permissionSetArn string,
+	description *string, sessionDuration *string, relayState *string,
+) (*PermissionSet, error) {
+	_, err := ssoAdminSvc.svc.UpdatePermissionSet(&ssoadmin.UpdatePermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+		Description:      description,
+		SessionDuration:  sessionDuration,
+		RelayState:       relayState,
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("UpdatePermissionSet", err)
+	}
+
+	log.Logger.Infof("Permission set '%s' updated successfully\n", permissionSetArn)
+	return ssoAdminSvc.GetPermissionSet(instanceArn, permissionSetArn)
+}
+
+// DeletePermissionSet deletes a permission set, which must not be assigned to any account.
+func (ssoAdminSvc *SSOAdminSvc) DeletePermissionSet(instanceArn string, permissionSetArn string) error {
+	_, err := ssoAdminSvc.svc.DeletePermissionSet(&ssoadmin.DeletePermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+	})
+	if err != nil {
+		return wrapSSOAdminError("DeletePermissionSet", err)
+	}
+
+	log.Logger.Infof("Permission set '%s' deleted successfully\n", permissionSetArn)
+	return nil
+}
+
+// GetPermissionSetPolicies returns the managed policies, customer managed policy references and inline policy
+// of a permission set.
+func (ssoAdminSvc *SSOAdminSvc) GetPermissionSetPolicies(instanceArn string, permissionSetArn string,
+) (*PermissionSetPolicies, error) {
+	policies := &PermissionSetPolicies{
+		ManagedPolicies:         []ManagedPolicyReference{},
+		CustomerManagedPolicies: []CustomerManagedPolicyReference{},
+	}
+
+	err := ssoAdminSvc.svc.ListManagedPoliciesInPermissionSetPages(&ssoadmin.ListManagedPoliciesInPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+	}, func(page *ssoadmin.ListManagedPoliciesInPermissionSetOutput, lastPage bool) bool {
+		for _, policy := range page.AttachedManagedPolicies {
+			policies.ManagedPolicies = append(policies.ManagedPolicies, ManagedPolicyReference{
+				Arn:  aws.StringValue(policy.Arn),
+				Name: aws.StringValue(policy.Name),
+			})
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("GetPermissionSetPolicies", err)
+	}
+
+	err = ssoAdminSvc.svc.ListCustomerManagedPolicyReferencesInPermissionSetPages(
+		&ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetInput{
+			InstanceArn:      aws.String(instanceArn),
+			PermissionSetArn: aws.String(permissionSetArn),
+		}, func(page *ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetOutput, lastPage bool) bool {
+			for _, reference := range page.CustomerManagedPolicyReferences {
+				policies.CustomerManagedPolicies = append(policies.CustomerManagedPolicies,
+					CustomerManagedPolicyReference{
+						Name: aws.StringValue(reference.Name),
+						Path: aws.StringValue(reference.Path),
+					})
+			}
+			return !lastPage
+		})
+	if err != nil {
+		return nil, wrapSSOAdminError("GetPermissionSetPolicies", err)
+	}
+
+	inline, err := ssoAdminSvc.svc.GetInlinePolicyForPermissionSet(&ssoadmin.GetInlinePolicyForPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("GetPermissionSetPolicies", err)
+	}
+	if document := aws.StringValue(inline.InlinePolicy); document != "" {
+		policies.InlinePolicy, err = inaIam.ParsePolicyDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("GetPermissionSetPolicies: %w", err)
+		}
+	}
+
+	return policies, nil
+}
+
+// AttachManagedPolicyToPermissionSet attaches an AWS managed policy to a permission set.
+func (ssoAdminSvc *SSOAdminSvc) AttachManagedPolicyToPermissionSet(instanceArn string, permissionSetArn string,
+	policyArn string,
+) error {
+	_, err := ssoAdminSvc.svc.AttachManagedPolicyToPermissionSet(&ssoadmin.AttachManagedPolicyToPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+		ManagedPolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return wrapSSOAdminError("AttachManagedPolicyToPermissionSet", err)
+	}
+
+	log.Logger.Infof("Managed policy '%s' attached to permission set '%s' successfully\n", policyArn, permissionSetArn)
+	return nil
+}
+
+// DetachManagedPolicyFromPermissionSet detaches an AWS managed policy from a permission set.
+func (ssoAdminSvc *SSOAdminSvc) DetachManagedPolicyFromPermissionSet(instanceArn string, permissionSetArn string,
+	policyArn string,
+) error {
+	_, err := ssoAdminSvc.svc.DetachManagedPolicyFromPermissionSet(&ssoadmin.DetachManagedPolicyFromPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+		ManagedPolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return wrapSSOAdminError("DetachManagedPolicyFromPermissionSet", err)
+	}
+
+	log.Logger.Infof("Managed policy '%s' detached from permission set '%s' successfully\n", policyArn,
+		permissionSetArn)
+	return nil
+}
+
+func toSSOCustomerManagedPolicyReference(reference CustomerManagedPolicyReference,
+) *ssoadmin.CustomerManagedPolicyReference {
+	ssoReference := &ssoadmin.CustomerManagedPolicyReference{Name: aws.String(reference.Name)}
+	if reference.Path != "" {
+		ssoReference.Path = aws.String(reference.Path)
+	}
+
+	return ssoReference
+}
+
+// AttachCustomerManagedPolicyToPermissionSet adds a customer managed policy reference to a permission set.
+func (ssoAdminSvc *SSOAdminSvc) AttachCustomerManagedPolicyToPermissionSet(instanceArn string,
+	permissionSetArn string, reference CustomerManagedPolicyReference,
+) error {
+	_, err := ssoAdminSvc.svc.AttachCustomerManagedPolicyReferenceToPermissionSet(
+		&ssoadmin.AttachCustomerManagedPolicyReferenceToPermissionSetInput{
+			InstanceArn:                    aws.String(instanceArn),
+			PermissionSetArn:               aws.String(permissionSetArn),
+			CustomerManagedPolicyReference: toSSOCustomerManagedPolicyReference(reference),
+		})
+	if err != nil {
+		return wrapSSOAdminError("AttachCustomerManagedPolicyToPermissionSet", err)
+	}
+
+	log.Logger.Infof("Customer managed policy '%s' attached to permission set '%s' successfully\n", reference.Name,
+		permissionSetArn)
+	return nil
+}
+
+// DetachCustomerManagedPolicyFromPermissionSet removes a customer managed policy reference from a permission set.
+func (ssoAdminSvc *SSOAdminSvc) DetachCustomerManagedPolicyFromPermissionSet(instanceArn string,
+	permissionSetArn string, reference CustomerManagedPolicyReference,
+) error {
+	_, err := ssoAdminSvc.svc.DetachCustomerManagedPolicyReferenceFromPermissionSet(
+		&ssoadmin.DetachCustomerManagedPolicyReferenceFromPermissionSetInput{
+			InstanceArn:                    aws.String(instanceArn),
+			PermissionSetArn:               aws.String(permissionSetArn),
+			CustomerManagedPolicyReference: toSSOCustomerManagedPolicyReference(reference),
+		})
+	if err != nil {
+		return wrapSSOAdminError("DetachCustomerManagedPolicyFromPermissionSet", err)
+	}
+
+	log.Logger.Infof("Customer managed policy '%s' detached from permission set '%s' successfully\n",
+		reference.Name, permissionSetArn)
+	return nil
+}
+
+// PutPermissionSetInlinePolicy sets or replaces the inline policy of a permission set.
+func (ssoAdminSvc *SSOAdminSvc) PutPermissionSetInlinePolicy(instanceArn string, permissionSetArn string,
+	policy inaIam.PolicyDocument,
+) error {
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("PutPermissionSetInlinePolicy: %s %w", inaIam.ErrMarshallingPolicy, err)
+	}
+
+	_, err = ssoAdminSvc.svc.PutInlinePolicyToPermissionSet(&ssoadmin.PutInlinePolicyToPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+		InlinePolicy:     aws.String(string(document)),
+	})
+	if err != nil {
+		return wrapSSOAdminError("PutPermissionSetInlinePolicy", err)
+	}
+
+	log.Logger.Infof("Inline policy of permission set '%s' set successfully\n", permissionSetArn)
+	return nil
+}
+
+// DeletePermissionSetInlinePolicy removes the inline policy of a permission set.
+func (ssoAdminSvc *SSOAdminSvc) DeletePermissionSetInlinePolicy(instanceArn string, permissionSetArn string) error {
+	_, err := ssoAdminSvc.svc.DeleteInlinePolicyFromPermissionSet(&ssoadmin.DeleteInlinePolicyFromPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+	})
+	if err != nil {
+		return wrapSSOAdminError("DeletePermissionSetInlinePolicy", err)
+	}
+
+	log.Logger.Infof("Inline policy of permission set '%s' deleted successfully\n", permissionSetArn)
+	return nil
+}
@@ -0,0 +1,40 @@
+package aws_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awsCloud "gitea/pcp-inariam/inariam/pkgs/cloud/aws"
+)
+
+func TestSSOOperationDone(t *testing.T) {
+	tests := []struct {
+		status string
+		done   bool
+	}{
+		{status: awsCloud.SSOOperationInProgress, done: false},
+		{status: awsCloud.SSOOperationSucceeded, done: true},
+		{status: awsCloud.SSOOperationFailed, done: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.status, func(t *testing.T) {
+			operation := awsCloud.SSOOperation{Status: test.status}
+			assert.Equal(t, test.done, operation.Done())
+		})
+	}
+}
+
+func TestSSOInvalidArguments(t *testing.T) {
+	ssoAdminSvc := &awsCloud.SSOAdminSvc{}
+
+	_, err := ssoAdminSvc.CreateAccountAssignment("instance", awsCloud.AccountAssignment{PrincipalType: "ROLE"})
+	assert.ErrorContains(t, err, awsCloud.ErrSSOInvalidPrincipalType)
+
+	_, err = ssoAdminSvc.DeleteAccountAssignment("instance", awsCloud.AccountAssignment{PrincipalType: "ROLE"})
+	assert.ErrorContains(t, err, awsCloud.ErrSSOInvalidPrincipalType)
+
+	_, err = ssoAdminSvc.GetOperation("instance", "unknown", "request")
+	assert.ErrorContains(t, err, awsCloud.ErrSSOInvalidOperationKind)
+}
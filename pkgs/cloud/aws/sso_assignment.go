@@ -0,0 +1,226 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssoadmin"
+
+	"gitea/pcp-inariam/inariam/pkgs/log"
+)
+
+func toSSOAssignmentOperation(kind string, status *ssoadmin.AccountAssignmentOperationStatus) *SSOOperation {
+	return &SSOOperation{
+		RequestID:        aws.StringValue(status.RequestId),
+		Kind:             kind,
+		Status:           aws.StringValue(status.Status),
+		FailureReason:    aws.StringValue(status.FailureReason),
+		PermissionSetArn: aws.StringValue(status.PermissionSetArn),
+		AccountID:        aws.StringValue(status.TargetId),
+		PrincipalType:    aws.StringValue(status.PrincipalType),
+		PrincipalID:      aws.StringValue(status.PrincipalId),
+		CreatedDate:      aws.TimeValue(status.CreatedDate),
+	}
+}
+
+func toSSOProvisioningOperation(status *ssoadmin.PermissionSetProvisioningStatus) *SSOOperation {
+	return &SSOOperation{
+		RequestID:        aws.StringValue(status.RequestId),
+		Kind:             SSOOperationProvisioning,
+		Status:           aws.StringValue(status.Status),
+		FailureReason:    aws.StringValue(status.FailureReason),
+		PermissionSetArn: aws.StringValue(status.PermissionSetArn),
+		AccountID:        aws.StringValue(status.AccountId),
+		CreatedDate:      aws.TimeValue(status.CreatedDate),
+	}
+}
+
+// ListAccountAssignments lists the users and groups a permission set is assigned to in an account.
+func (ssoAdminSvc *SSOAdminSvc) ListAccountAssignments(instanceArn string, accountID string,
+	permissionSetArn string,
+) ([]AccountAssignment, error) {
+	var assignments []AccountAssignment
+
+	err := ssoAdminSvc.svc.ListAccountAssignmentsPages(&ssoadmin.ListAccountAssignmentsInput{
+		InstanceArn:      aws.String(instanceArn),
+		AccountId:        aws.String(accountID),
+		PermissionSetArn: aws.String(permissionSetArn),
+	}, func(page *ssoadmin.ListAccountAssignmentsOutput, lastPage bool) bool {
+		for _, assignment := range page.AccountAssignments {
+			assignments = append(assignments, AccountAssignment{
+				AccountID:        aws.StringValue(assignment.AccountId),
+				PermissionSetArn: aws.StringValue(assignment.PermissionSetArn),
+				PrincipalType:    aws.StringValue(assignment.PrincipalType),
+				PrincipalID:      aws.StringValue(assignment.PrincipalId),
+			})
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("ListAccountAssignments", err)
+	}
+
+	return assignments, nil
+}
+
+// ListPermissionSetAccounts lists the accounts a permission set is provisioned to.
+func (ssoAdminSvc *SSOAdminSvc) ListPermissionSetAccounts(instanceArn string, permissionSetArn string,
+) ([]string, error) {
+	var accountIDs []string
+
+	err := ssoAdminSvc.svc.ListAccountsForProvisionedPermissionSetPages(
+		&ssoadmin.ListAccountsForProvisionedPermissionSetInput{
+			InstanceArn:      aws.String(instanceArn),
+			PermissionSetArn: aws.String(permissionSetArn),
+		}, func(page *ssoadmin.ListAccountsForProvisionedPermissionSetOutput, lastPage bool) bool {
+			accountIDs = append(accountIDs, aws.StringValueSlice(page.AccountIds)...)
+			return !lastPage
+		})
+	if err != nil {
+		return nil, wrapSSOAdminError("ListPermissionSetAccounts", err)
+	}
+
+	return accountIDs, nil
+}
+
+// CreateAccountAssignment starts assigning a permission set to a user or a group in an account, provisioning the
+// permission set to the account when needed. The returned operation is usually still in progress, see
+// WaitForOperation.
+func (ssoAdminSvc *SSOAdminSvc) CreateAccountAssignment(instanceArn string, assignment AccountAssignment,
+) (*SSOOperation, error) {
+	if assignment.PrincipalType != SSOPrincipalUser && assignment.PrincipalType != SSOPrincipalGroup {
+		return nil, fmt.Errorf("CreateAccountAssignment: %w", errors.New(ErrSSOInvalidPrincipalType))
+	}
+
+	output, err := ssoAdminSvc.svc.CreateAccountAssignment(&ssoadmin.CreateAccountAssignmentInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(assignment.PermissionSetArn),
+		PrincipalType:    aws.String(assignment.PrincipalType),
+		PrincipalId:      aws.String(assignment.PrincipalID),
+		TargetId:         aws.String(assignment.AccountID),
+		TargetType:       aws.String(ssoadmin.TargetTypeAwsAccount),
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("CreateAccountAssignment", err)
+	}
+
+	log.Logger.Infof("Assignment of permission set '%s' to %s '%s' in account '%s' requested\n",
+		assignment.PermissionSetArn, assignment.PrincipalType, assignment.PrincipalID, assignment.AccountID)
+	return toSSOAssignmentOperation(SSOOperationAssignmentCreation, output.AccountAssignmentCreationStatus), nil
+}
+
+// DeleteAccountAssignment starts removing the assignment of a permission set to a user or a group in an
+// account. The returned operation is usually still in progress, see WaitForOperation.
+func (ssoAdminSvc *SSOAdminSvc) DeleteAccountAssignment(instanceArn string, assignment AccountAssignment,
+) (*SSOOperation, error) {
+	if assignment.PrincipalType != SSOPrincipalUser && assignment.PrincipalType != SSOPrincipalGroup {
+		return nil, fmt.Errorf("DeleteAccountAssignment: %w", errors.New(ErrSSOInvalidPrincipalType))
+	}
+
+	output, err := ssoAdminSvc.svc.DeleteAccountAssignment(&ssoadmin.DeleteAccountAssignmentInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(assignment.PermissionSetArn),
+		PrincipalType:    aws.String(assignment.PrincipalType),
+		PrincipalId:      aws.String(assignment.PrincipalID),
+		TargetId:         aws.String(assignment.AccountID),
+		TargetType:       aws.String(ssoadmin.TargetTypeAwsAccount),
+	})
+	if err != nil {
+		return nil, wrapSSOAdminError("DeleteAccountAssignment", err)
+	}
+
+	log.Logger.Infof("Removal of permission set '%s' from %s '%s' in account '%s' requested\n",
+		assignment.PermissionSetArn, assignment.PrincipalType, assignment.PrincipalID, assignment.AccountID)
+	return toSSOAssignmentOperation(SSOOperationAssignmentDeletion, output.AccountAssignmentDeletionStatus), nil
+}
+
+// ProvisionPermissionSet starts provisioning the current version of a permission set to an account, or to every
+// account it is already provisioned to when accountID is empty. Changes to a permission set only reach the
+// accounts once provisioned.
+func (ssoAdminSvc *SSOAdminSvc) ProvisionPermissionSet(instanceArn string, permissionSetArn string,
+	accountID string,
+) (*SSOOperation, error) {
+	input := &ssoadmin.ProvisionPermissionSetInput{
+		InstanceArn:      aws.String(instanceArn),
+		PermissionSetArn: aws.String(permissionSetArn),
+		TargetType:       aws.String(ssoadmin.ProvisionTargetTypeAllProvisionedAccounts),
+	}
+	if accountID != "" {
+		input.TargetType = aws.String(ssoadmin.ProvisionTargetTypeAwsAccount)
+		input.TargetId = aws.String(accountID)
+	}
+
+	output, err := ssoAdminSvc.svc.ProvisionPermissionSet(input)
+	if err != nil {
+		return nil, wrapSSOAdminError("ProvisionPermissionSet", err)
+	}
+
+	log.Logger.Infof("Provisioning of permission set '%s' requested\n", permissionSetArn)
+	return toSSOProvisioningOperation(output.PermissionSetProvisioningStatus), nil
+}
+
+// GetOperation returns the current status of an asynchronous operation given its kind and request ID.
+func (ssoAdminSvc *SSOAdminSvc) GetOperation(instanceArn string, kind string, requestID string,
+) (*SSOOperation, error) {
+	switch kind {
+	case SSOOperationAssignmentCreation:
+		output, err := ssoAdminSvc.svc.DescribeAccountAssignmentCreationStatus(
+			&ssoadmin.DescribeAccountAssignmentCreationStatusInput{
+				InstanceArn:                        aws.String(instanceArn),
+				AccountAssignmentCreationRequestId: aws.String(requestID),
+			})
+		if err != nil {
+			return nil, wrapSSOAdminError("GetOperation", err)
+		}
+		return toSSOAssignmentOperation(kind, output.AccountAssignmentCreationStatus), nil
+	case SSOOperationAssignmentDeletion:
+		output, err := ssoAdminSvc.svc.DescribeAccountAssignmentDeletionStatus(
+			&ssoadmin.DescribeAccountAssignmentDeletionStatusInput{
+				InstanceArn:                        aws.String(instanceArn),
+				AccountAssignmentDeletionRequestId: aws.String(requestID),
+			})
+		if err != nil {
+			return nil, wrapSSOAdminError("GetOperation", err)
+		}
+		return toSSOAssignmentOperation(kind, output.AccountAssignmentDeletionStatus), nil
+	case SSOOperationProvisioning:
+		output, err := ssoAdminSvc.svc.DescribePermissionSetProvisioningStatus(
+			&ssoadmin.DescribePermissionSetProvisioningStatusInput{
+				InstanceArn:                     aws.String(instanceArn),
+				ProvisionPermissionSetRequestId: aws.String(requestID),
+			})
+		if err != nil {
+			return nil, wrapSSOAdminError("GetOperation", err)
+		}
+		return toSSOProvisioningOperation(output.PermissionSetProvisioningStatus), nil
+	}
+
+	return nil, fmt.Errorf("GetOperation: %w", errors.New(ErrSSOInvalidOperationKind))
+}
+
+// WaitForOperation polls the status of an asynchronous operation until it is over. It returns the last known
+// status of the operation along with an error when the operation failed or did not complete in time.
+func (ssoAdminSvc *SSOAdminSvc) WaitForOperation(instanceArn string, operation *SSOOperation,
+) (*SSOOperation, error) {
+	for poll := 0; ; poll++ {
+		switch operation.Status {
+		case SSOOperationSucceeded:
+			return operation, nil
+		case SSOOperationFailed:
+			return operation, fmt.Errorf("WaitForOperation: %s: %s", ErrSSOOperationFailed, operation.FailureReason)
+		}
+
+		if poll == ssoOperationMaxPolls {
+			return operation, fmt.Errorf("WaitForOperation: %w", errors.New(ErrSSOOperationTimeout))
+		}
+		time.Sleep(ssoOperationPollInterval)
+
+		current, err := ssoAdminSvc.GetOperation(instanceArn, operation.Kind, operation.RequestID)
+		if err != nil {
+			return operation, fmt.Errorf("WaitForOperation: %w", err)
+		}
+		operation = current
+	}
+}
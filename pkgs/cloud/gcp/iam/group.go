@@ -29,19 +29,41 @@ func (adminSvc *AdminSvc) CreateGroup(groupName, description string) (*admin.Gro
 	return resp, nil
 }
 
-// ListGroups retrieves a list of all groups.
+// ListGroups retrieves a list of all groups, following every page of the directory.
 func (adminSvc *AdminSvc) ListGroups() ([]*admin.Group, error) {
-	groups, err := adminSvc.svc.Groups.List().Do()
+	var groups []*admin.Group
+
+	pageToken := ""
+	for {
+		page, nextPageToken, err := adminSvc.ListGroupsPage(pageToken, 0)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, page...)
+
+		if nextPageToken == "" {
+			return groups, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// ListGroupsPage retrieves at most maxResults groups starting at pageToken, an empty token starting at the
+// first group, and a zero maxResults using the directory default. It returns the token of the next page, empty
+// on the last page.
+func (adminSvc *AdminSvc) ListGroupsPage(pageToken string, maxResults int64) ([]*admin.Group, string, error) {
+	call := adminSvc.svc.Groups.List().PageToken(pageToken)
+	if maxResults > 0 {
+		call = call.MaxResults(maxResults)
+	}
+
+	groups, err := call.Do()
 	if err != nil {
 		log.Logger.Errorf("ListGroups: failed to list groups: %v", err)
-		return nil, err
+		return nil, "", err
 	}
 
-	for _, group := range groups.Groups {
-		fmt.Printf("Name: %s, Id: %s, Description: %s\n",
-			group.Name, group.Id, group.Description)
-	}
-	return groups.Groups, nil
+	return groups.Groups, groups.NextPageToken, nil
 }
 
 // GetGroup retrieves information about a specific group.
@@ -146,12 +146,36 @@ func (iamService *IamSvc) DeleteIamRole(roleName string) error {
 
 // ListIamRoles retrieves a list of all IAM roles in the project.
 func (iamService *IamSvc) ListIamRoles() ([]*iam.Role, error) {
-	response, err := iamService.svc.Projects.Roles.List("projects/" + iamService.projectId).Do()
-	if err != nil {
-		return nil, fmt.Errorf("%s : %v", ErrorFailedToGetRoleInfo, err)
+	var roles []*iam.Role
+
+	pageToken := ""
+	for {
+		page, nextPageToken, err := iamService.ListIamRolesPage(pageToken, 0)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, page...)
+
+		if nextPageToken == "" {
+			return roles, nil
+		}
+		pageToken = nextPageToken
 	}
-	for _, role := range response.Roles {
-		log.Logger.Infof("Listing role: %v\n", role.Name)
+}
+
+// ListIamRolesPage retrieves at most pageSize IAM roles of the project starting at pageToken, an empty token
+// starting at the first role, and a zero pageSize using the API default. It returns the token of the next page,
+// empty on the last page.
+func (iamService *IamSvc) ListIamRolesPage(pageToken string, pageSize int64) ([]*iam.Role, string, error) {
+	call := iamService.svc.Projects.Roles.List("projects/" + iamService.projectId).PageToken(pageToken)
+	if pageSize > 0 {
+		call = call.PageSize(pageSize)
 	}
-	return response.Roles, nil
+
+	response, err := call.Do()
+	if err != nil {
+		return nil, "", fmt.Errorf("%s : %v", ErrorFailedToGetRoleInfo, err)
+	}
+
+	return response.Roles, response.NextPageToken, nil
 }
@@ -63,17 +63,38 @@ func (iamService *IamSvc) DeleteIamServiceAccount(email string) error {
 
 // ListIamServiceAccounts retrieves a list of all IAM service accounts in the project.
 func (iamService *IamSvc) ListIamServiceAccounts() ([]*iam.ServiceAccount, error) {
-	response, err := iamService.svc.Projects.ServiceAccounts.List("projects/" + iamService.projectId).
-		Do()
-	if err != nil {
-		return nil, fmt.Errorf("%s %w", ErrorFailedToListServiceAccounts, err)
+	var accounts []*iam.ServiceAccount
+
+	pageToken := ""
+	for {
+		page, nextPageToken, err := iamService.ListIamServiceAccountsPage(pageToken, 0)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, page...)
+
+		if nextPageToken == "" {
+			return accounts, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// ListIamServiceAccountsPage retrieves at most pageSize IAM service accounts of the project starting at
+// pageToken, an empty token starting at the first account, and a zero pageSize using the API default. It
+// returns the token of the next page, empty on the last page.
+func (iamService *IamSvc) ListIamServiceAccountsPage(pageToken string, pageSize int64) ([]*iam.ServiceAccount, string, error) {
+	call := iamService.svc.Projects.ServiceAccounts.List("projects/" + iamService.projectId).PageToken(pageToken)
+	if pageSize > 0 {
+		call = call.PageSize(pageSize)
 	}
 
-	for _, account := range response.Accounts {
-		log.Logger.Infof("Listing service account: %v\n", account.Name)
+	response, err := call.Do()
+	if err != nil {
+		return nil, "", fmt.Errorf("%s %w", ErrorFailedToListServiceAccounts, err)
 	}
 
-	return response.Accounts, nil
+	return response.Accounts, response.NextPageToken, nil
 }
 
 // EnableIamServiceAccount enables an IAM service account by its name.
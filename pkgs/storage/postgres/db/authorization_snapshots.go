@@ -0,0 +1,47 @@
+package db
+
+import (
+	"fmt"
+
+	uuid "github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/entites"
+)
+
+// SaveAuthorizationSnapshot stores an authorization snapshot.
+func SaveAuthorizationSnapshot(db *gorm.DB, snapshot *entites.AuthorizationSnapshots) error {
+	if err := db.Create(snapshot).Error; err != nil {
+		return fmt.Errorf("SaveAuthorizationSnapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuthorizationSnapshots lists the stored authorization snapshots of a provider account, newest first,
+// without their content.
+func ListAuthorizationSnapshots(db *gorm.DB, provider, accountID string) ([]entites.AuthorizationSnapshots, error) {
+	var snapshots []entites.AuthorizationSnapshots
+
+	err := db.Select("snapshot_id", "provider", "account_id", "taken_at", "user_count", "group_count", "role_count",
+		"policy_count", "created_at").
+		Where("provider = ? AND account_id = ?", provider, accountID).
+		Order("taken_at DESC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListAuthorizationSnapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// GetAuthorizationSnapshot returns a stored authorization snapshot of an account with its content.
+func GetAuthorizationSnapshot(db *gorm.DB, snapshotID uuid.UUID, accountID string) (*entites.AuthorizationSnapshots, error) {
+	var snapshot entites.AuthorizationSnapshots
+
+	if err := db.First(&snapshot, "snapshot_id = ? AND account_id = ?", snapshotID, accountID).Error; err != nil {
+		return nil, fmt.Errorf("GetAuthorizationSnapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
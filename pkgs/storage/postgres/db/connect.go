@@ -11,9 +11,8 @@ import (
 
 func ConnectDB(config *config.DbConfig) (*gorm.DB, error) {
 
-	dsnString := "host=%s port=%s user=%s password=%s dbname=%s sslmode=disable"
+	dsnString := "host=%s port=%d user=%s password=%s dbname=%s sslmode=disable"
 
-	fmt.Printf("%+v %+v", dsnString, config)
 	dsn := fmt.Sprintf(
 		dsnString,
 		config.Host,
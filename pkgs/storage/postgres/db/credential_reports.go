@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"gitea/pcp-inariam/inariam/pkgs/storage/postgres/entites"
+)
+
+// SaveCredentialReport stores a copy of a credential report.
+func SaveCredentialReport(db *gorm.DB, report *entites.CredentialReports) error {
+	if err := db.Create(report).Error; err != nil {
+		return fmt.Errorf("SaveCredentialReport: %w", err)
+	}
+
+	return nil
+}
+
+// FindCredentialReport returns the stored credential report of an account generated at the given time, without
+// its content, or nil when it is not stored.
+func FindCredentialReport(db *gorm.DB, accountID string, generatedTime time.Time) (*entites.CredentialReports, error) {
+	var reports []entites.CredentialReports
+
+	err := db.Select("report_id", "provider", "account_id", "generated_time", "row_count", "created_at").
+		Where("account_id = ? AND generated_time = ?", accountID, generatedTime).
+		Limit(1).
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("FindCredentialReport: %w", err)
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	return &reports[0], nil
+}
+
+// ListCredentialReports lists the stored credential reports of a provider account, newest first, without their
+// content.
+func ListCredentialReports(db *gorm.DB, provider string, accountID string) ([]entites.CredentialReports, error) {
+	var reports []entites.CredentialReports
+
+	err := db.Select("report_id", "provider", "account_id", "generated_time", "row_count", "created_at").
+		Where("provider = ? AND account_id = ?", provider, accountID).
+		Order("generated_time DESC").
+		Find(&reports).Error
+	if err != nil {
+		return nil, fmt.Errorf("ListCredentialReports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetCredentialReport returns a stored credential report of an account with its content.
+func GetCredentialReport(db *gorm.DB, reportID uuid.UUID, accountID string) (*entites.CredentialReports, error) {
+	var report entites.CredentialReports
+
+	if err := db.First(&report, "report_id = ? AND account_id = ?", reportID, accountID).Error; err != nil {
+		return nil, fmt.Errorf("GetCredentialReport: %w", err)
+	}
+
+	return &report, nil
+}
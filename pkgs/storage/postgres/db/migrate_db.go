@@ -15,6 +15,8 @@ func MigrateDB(db *gorm.DB) error {
 		&entites.Permissions{},
 		&entites.Teams{},
 		&entites.Accounts{},
+		&entites.CredentialReports{},
+		&entites.AuthorizationSnapshots{},
 	)
 
 	if err != nil {
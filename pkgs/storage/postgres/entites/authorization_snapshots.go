@@ -0,0 +1,28 @@
+package entites
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// AuthorizationSnapshots keeps point-in-time copies of every identity, policy and relationship of a cloud account,
+// for downstream analysis. AccountID is the account the snapshot was taken of and Content is the JSON snapshot.
+type AuthorizationSnapshots struct {
+	SnapshotID  uuid.UUID `gorm:"type:uuid;primary_key;"`
+	Provider    string    `gorm:"type:varchar(255);not null"`
+	AccountID   string    `gorm:"type:varchar(12);index;not null;default:''"`
+	TakenAt     time.Time `gorm:"index;not null"`
+	UserCount   int       `gorm:"not null"`
+	GroupCount  int       `gorm:"not null"`
+	RoleCount   int       `gorm:"not null"`
+	PolicyCount int       `gorm:"not null"`
+	Content     string    `gorm:"type:jsonb;not null"`
+	CreatedAt   time.Time
+}
+
+func (snapshot *AuthorizationSnapshots) BeforeCreate(*gorm.DB) error {
+	snapshot.SnapshotID = uuid.Must(uuid.NewV4())
+	return nil
+}
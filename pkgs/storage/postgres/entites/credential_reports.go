@@ -0,0 +1,26 @@
+package entites
+
+import (
+	"time"
+
+	uuid "github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// CredentialReports keeps a copy of every IAM credential report fetched, for historical comparison.
+// Content is the raw CSV report as returned by AWS, AccountID is the account the report was generated for. AWS
+// returns the same report until it is generated again, a report is stored once per account and generation time.
+type CredentialReports struct {
+	ReportID      uuid.UUID `gorm:"type:uuid;primary_key;"`
+	Provider      string    `gorm:"type:varchar(255);not null"`
+	AccountID     string    `gorm:"type:varchar(12);index;uniqueIndex:idx_credential_reports_account_generated;not null;default:''"`
+	GeneratedTime time.Time `gorm:"index;uniqueIndex:idx_credential_reports_account_generated;not null"`
+	RowCount      int       `gorm:"not null"`
+	Content       string    `gorm:"type:text;not null"`
+	CreatedAt     time.Time
+}
+
+func (report *CredentialReports) BeforeCreate(*gorm.DB) error {
+	report.ReportID = uuid.Must(uuid.NewV4())
+	return nil
+}